@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+
+	"github.com/go-park-mail-ru/2025_1_404/config"
+	repoOffer "github.com/go-park-mail-ru/2025_1_404/microservices/offer/repository"
+	usecaseOffer "github.com/go-park-mail-ru/2025_1_404/microservices/offer/usecase"
+	database "github.com/go-park-mail-ru/2025_1_404/pkg/database/postgres"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/database/s3"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/logger"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/utils"
+	"github.com/google/uuid"
+)
+
+// Разовая сверка бакетов с таблицей kvartirum.Image. Отчет печатается в stdout в JSON
+func main() {
+	dryRun := flag.Bool("dry-run", false, "только показать сирот, ничего не удаляя")
+	grace := flag.Duration("grace", 0, "не трогать объекты и записи моложе этого срока (по умолчанию из конфига)")
+	flag.Parse()
+
+	cfg, err := config.NewConfig()
+	if err != nil {
+		log.Fatalf("не удалось загрузить конфиг: %v", err)
+	}
+	if *grace > 0 {
+		cfg.App.ImageGC.GracePeriod = *grace
+	}
+
+	// Логгер
+	l, err := logger.NewZapLogger(cfg.App.Logger.Level)
+	if err != nil {
+		log.Fatalf("не удалось создать логгер: %v", err)
+	}
+	defer l.Close()
+
+	ctx := context.WithValue(context.Background(), utils.RequestIDKey, uuid.NewString())
+
+	// Инициализация подключения к БД
+	dbpool, err := database.NewPool(&cfg.Postgres, ctx)
+	if err != nil {
+		log.Fatalf("не удалось подключиться к базе данных: %v", err)
+	}
+	defer dbpool.Close()
+
+	// Хранилище файлов
+	s3repo, err := s3.New(&cfg.Minio, l)
+	if err != nil {
+		log.Fatalf("не удалось подключиться к s3: %v", err)
+	}
+
+	// Для сверки клиенты других сервисов, Redis и геокодер не нужны
+	offerRepo := repoOffer.NewOfferRepository(dbpool, l)
+	offerUC := usecaseOffer.NewOfferUsecase(offerRepo, l, s3repo, cfg, nil, nil, nil, nil)
+
+	report, err := offerUC.CollectImageGarbage(ctx, *dryRun)
+	if err != nil {
+		log.Fatalf("сверка изображений не удалась: %v", err)
+	}
+
+	data, err := report.MarshalJSON()
+	if err != nil {
+		log.Fatalf("не удалось сформировать отчет: %v", err)
+	}
+	os.Stdout.Write(append(data, '\n'))
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/go-park-mail-ru/2025_1_404/internal/metrics"
 	service "github.com/go-park-mail-ru/2025_1_404/microservices/offer/delivery/grpc"
 	deliveryOffer "github.com/go-park-mail-ru/2025_1_404/microservices/offer/delivery/http"
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/delivery/worker"
 	repoOffer "github.com/go-park-mail-ru/2025_1_404/microservices/offer/repository"
 	usecaseOffer "github.com/go-park-mail-ru/2025_1_404/microservices/offer/usecase"
 	"github.com/go-park-mail-ru/2025_1_404/pkg/api/yandex"
@@ -100,12 +101,18 @@ func main() {
 	r.Handle("/api/v1/offers",
 		middleware.SoftAuthHandler(l, cfg, http.HandlerFunc(offerHandler.GetOffersHandler))).
 		Methods(http.MethodGet)
+	r.Handle("/api/v1/offers/clusters",
+		middleware.SoftAuthHandler(l, cfg, http.HandlerFunc(offerHandler.GetOfferClusters))).
+		Methods(http.MethodGet)
 	r.Handle("/api/v1/offers/{id:[0-9]+}",
 		middleware.SoftAuthHandler(l, cfg, http.HandlerFunc(offerHandler.GetOfferByID))).
 		Methods(http.MethodGet)
 	r.Handle("/api/v1/offers",
 		middleware.AuthHandler(l, &cfg.App.CORS, middleware.CSRFMiddleware(l, cfg, http.HandlerFunc(offerHandler.CreateOffer)))).
 		Methods(http.MethodPost)
+	r.Handle("/api/v1/offers/import",
+		middleware.AuthHandler(l, &cfg.App.CORS, middleware.CSRFMiddleware(l, cfg, http.HandlerFunc(offerHandler.ImportOffers)))).
+		Methods(http.MethodPost)
 	r.Handle("/api/v1/offers/{id:[0-9]+}",
 		middleware.AuthHandler(l, &cfg.App.CORS, middleware.CSRFMiddleware(l, cfg, http.HandlerFunc(offerHandler.UpdateOffer)))).
 		Methods(http.MethodPut)
@@ -115,14 +122,55 @@ func main() {
 	r.Handle("/api/v1/offers/{id:[0-9]+}/publish",
 		middleware.AuthHandler(l, &cfg.App.CORS, middleware.CSRFMiddleware(l, cfg, http.HandlerFunc(offerHandler.PublishOffer)))).
 		Methods(http.MethodPost)
+	r.Handle("/api/v1/offers/{id:[0-9]+}/complete",
+		middleware.AuthHandler(l, &cfg.App.CORS, middleware.CSRFMiddleware(l, cfg, http.HandlerFunc(offerHandler.CompleteOffer)))).
+		Methods(http.MethodPost)
+	r.Handle("/api/v1/offers/{id:[0-9]+}/unpublish",
+		middleware.AuthHandler(l, &cfg.App.CORS, middleware.CSRFMiddleware(l, cfg, http.HandlerFunc(offerHandler.UnpublishOffer)))).
+		Methods(http.MethodPost)
+	r.Handle("/api/v1/offers/{id:[0-9]+}/republish",
+		middleware.AuthHandler(l, &cfg.App.CORS, middleware.CSRFMiddleware(l, cfg, http.HandlerFunc(offerHandler.RepublishOffer)))).
+		Methods(http.MethodPost)
+	r.Handle("/api/v1/offers/{id:[0-9]+}/extend",
+		middleware.AuthHandler(l, &cfg.App.CORS, middleware.CSRFMiddleware(l, cfg, http.HandlerFunc(offerHandler.ExtendOffer)))).
+		Methods(http.MethodPost)
+	r.Handle("/api/v1/offers/stats",
+		middleware.AuthHandler(l, &cfg.App.CORS, http.HandlerFunc(offerHandler.GetSellerStats))).
+		Methods(http.MethodGet)
+	r.Handle("/api/v1/offers/{id:[0-9]+}/stats",
+		middleware.AuthHandler(l, &cfg.App.CORS, http.HandlerFunc(offerHandler.GetOfferStats))).
+		Methods(http.MethodGet)
 	r.Handle("/api/v1/offers/{id:[0-9]+}/image",
 		middleware.AuthHandler(l, &cfg.App.CORS, middleware.CSRFMiddleware(l, cfg, http.HandlerFunc(offerHandler.UploadOfferImage)))).
 		Methods(http.MethodPost)
+	r.Handle("/api/v1/offers/{id:[0-9]+}/images",
+		middleware.AuthHandler(l, &cfg.App.CORS, middleware.CSRFMiddleware(l, cfg, http.HandlerFunc(offerHandler.UploadOfferImages)))).
+		Methods(http.MethodPost)
+	r.Handle("/api/v1/offers/{id:[0-9]+}/images/upload-url",
+		middleware.AuthHandler(l, &cfg.App.CORS, middleware.CSRFMiddleware(l, cfg, http.HandlerFunc(offerHandler.CreateImageUploadURL)))).
+		Methods(http.MethodPost)
+	r.Handle("/api/v1/offers/{id:[0-9]+}/images/confirm",
+		middleware.AuthHandler(l, &cfg.App.CORS, middleware.CSRFMiddleware(l, cfg, http.HandlerFunc(offerHandler.ConfirmImageUpload)))).
+		Methods(http.MethodPost)
+	r.Handle("/api/v1/offers/{id:[0-9]+}/images/order",
+		middleware.AuthHandler(l, &cfg.App.CORS, middleware.CSRFMiddleware(l, cfg, http.HandlerFunc(offerHandler.ReorderOfferImages)))).
+		Methods(http.MethodPut)
+	r.Handle("/api/v1/offers/{id:[0-9]+}/images/{image_id:[0-9]+}/cover",
+		middleware.AuthHandler(l, &cfg.App.CORS, middleware.CSRFMiddleware(l, cfg, http.HandlerFunc(offerHandler.SetOfferCover)))).
+		Methods(http.MethodPut)
 	r.Handle("/api/v1/images/{id:[0-9]+}",
 		middleware.AuthHandler(l, &cfg.App.CORS, middleware.CSRFMiddleware(l, cfg, http.HandlerFunc(offerHandler.DeleteOfferImage)))).
 		Methods(http.MethodDelete)
 	r.HandleFunc("/api/v1/offers/stations", offerHandler.GetStations).
 		Methods(http.MethodGet)
+	r.HandleFunc("/api/v1/dictionaries", offerHandler.GetDictionaries).
+		Methods(http.MethodGet)
+	r.HandleFunc("/api/v1/offers/{id:[0-9]+}/prices", offerHandler.GetOfferPrices).
+		Methods(http.MethodGet)
+	r.HandleFunc("/api/v1/feed/yandex.xml", offerHandler.GetYandexFeed).
+		Methods(http.MethodGet)
+	r.HandleFunc("/api/v1/feed/offers.json", offerHandler.GetJSONFeed).
+		Methods(http.MethodGet)
 	r.Handle("/api/v1/offers/like",
 		middleware.AuthHandler(l, &cfg.App.CORS, middleware.CSRFMiddleware(l, cfg, http.HandlerFunc(offerHandler.LikeOffer)))).
 		Methods(http.MethodPost)
@@ -138,6 +186,56 @@ func main() {
 	r.Handle("/api/v1/offers/favorites",
 		middleware.AuthHandler(l, &cfg.App.CORS, http.HandlerFunc(offerHandler.GetFavorites))).
 		Methods(http.MethodGet)
+	r.Handle("/api/v1/offers/favorites/collections",
+		middleware.AuthHandler(l, &cfg.App.CORS, http.HandlerFunc(offerHandler.GetCollections))).
+		Methods(http.MethodGet)
+	r.Handle("/api/v1/offers/favorites/collections",
+		middleware.AuthHandler(l, &cfg.App.CORS, middleware.CSRFMiddleware(l, cfg, http.HandlerFunc(offerHandler.CreateCollection)))).
+		Methods(http.MethodPost)
+	r.Handle("/api/v1/offers/favorites/collections/{id:[0-9]+}",
+		middleware.AuthHandler(l, &cfg.App.CORS, middleware.CSRFMiddleware(l, cfg, http.HandlerFunc(offerHandler.RenameCollection)))).
+		Methods(http.MethodPut)
+	r.Handle("/api/v1/offers/favorites/collections/{id:[0-9]+}",
+		middleware.AuthHandler(l, &cfg.App.CORS, middleware.CSRFMiddleware(l, cfg, http.HandlerFunc(offerHandler.DeleteCollection)))).
+		Methods(http.MethodDelete)
+	r.Handle("/api/v1/offers/favorites/collections/{id:[0-9]+}/offers",
+		middleware.AuthHandler(l, &cfg.App.CORS, middleware.CSRFMiddleware(l, cfg, http.HandlerFunc(offerHandler.AddToCollection)))).
+		Methods(http.MethodPost)
+	r.Handle("/api/v1/offers/favorites/collections/{id:[0-9]+}/offers/{offerId:[0-9]+}",
+		middleware.AuthHandler(l, &cfg.App.CORS, middleware.CSRFMiddleware(l, cfg, http.HandlerFunc(offerHandler.RemoveFromCollection)))).
+		Methods(http.MethodDelete)
+	r.Handle("/api/v1/offers/favorites/collections/{id:[0-9]+}/offers/{offerId:[0-9]+}/move",
+		middleware.AuthHandler(l, &cfg.App.CORS, middleware.CSRFMiddleware(l, cfg, http.HandlerFunc(offerHandler.MoveToCollection)))).
+		Methods(http.MethodPost)
+	r.Handle("/api/v1/offers/favorites/collections/{id:[0-9]+}/share",
+		middleware.AuthHandler(l, &cfg.App.CORS, middleware.CSRFMiddleware(l, cfg, http.HandlerFunc(offerHandler.ShareCollection)))).
+		Methods(http.MethodPost)
+	r.Handle("/api/v1/offers/favorites/collections/{id:[0-9]+}/share",
+		middleware.AuthHandler(l, &cfg.App.CORS, middleware.CSRFMiddleware(l, cfg, http.HandlerFunc(offerHandler.UnshareCollection)))).
+		Methods(http.MethodDelete)
+	r.HandleFunc("/api/v1/offers/favorites/shared/{token}", offerHandler.GetSharedCollection).
+		Methods(http.MethodGet)
+	r.Handle("/api/v1/offers/{id:[0-9]+}/favorite/note",
+		middleware.AuthHandler(l, &cfg.App.CORS, middleware.CSRFMiddleware(l, cfg, http.HandlerFunc(offerHandler.UpdateFavoriteNote)))).
+		Methods(http.MethodPut)
+	r.Handle("/api/v1/offers/favorites/price-alerts",
+		middleware.AuthHandler(l, &cfg.App.CORS, http.HandlerFunc(offerHandler.GetPriceAlertSettings))).
+		Methods(http.MethodGet)
+	r.Handle("/api/v1/offers/favorites/price-alerts",
+		middleware.AuthHandler(l, &cfg.App.CORS, middleware.CSRFMiddleware(l, cfg, http.HandlerFunc(offerHandler.UpdatePriceAlertSettings)))).
+		Methods(http.MethodPut)
+	r.Handle("/api/v1/offers/duplicates",
+		middleware.AuthHandler(l, &cfg.App.CORS, http.HandlerFunc(offerHandler.GetOfferDuplicates))).
+		Methods(http.MethodGet)
+	r.Handle("/api/v1/searches",
+		middleware.AuthHandler(l, &cfg.App.CORS, middleware.CSRFMiddleware(l, cfg, http.HandlerFunc(offerHandler.CreateSavedSearch)))).
+		Methods(http.MethodPost)
+	r.Handle("/api/v1/searches",
+		middleware.AuthHandler(l, &cfg.App.CORS, http.HandlerFunc(offerHandler.GetSavedSearches))).
+		Methods(http.MethodGet)
+	r.Handle("/api/v1/searches/{id:[0-9]+}",
+		middleware.AuthHandler(l, &cfg.App.CORS, middleware.CSRFMiddleware(l, cfg, http.HandlerFunc(offerHandler.DeleteSavedSearch)))).
+		Methods(http.MethodDelete)
 
 	// Метрики
 	metrics, reg := metrics.NewMetrics("offer")
@@ -166,6 +264,13 @@ func main() {
 		}
 	}()
 
+	// Уведомления по сохраненным поискам
+	go worker.NewSearchAlertsWorker(offerUC, l, cfg.App.SearchAlerts.Interval).Run(ctx)
+	// Снятие объявлений с истекшим сроком публикации
+	go worker.NewExpirationWorker(offerUC, l, cfg.App.Expiration.Interval).Run(ctx)
+	// Сверка бакетов с таблицей изображений
+	go worker.NewImageGCWorker(offerUC, l, cfg.App.ImageGC.Interval, cfg.App.ImageGC.DryRun).Run(ctx)
+
 	log.Println("Offers микросервис запущен")
 
 	// Запуск сервера
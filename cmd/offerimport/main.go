@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+
+	"github.com/go-park-mail-ru/2025_1_404/config"
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/importer"
+	repoOffer "github.com/go-park-mail-ru/2025_1_404/microservices/offer/repository"
+	usecaseOffer "github.com/go-park-mail-ru/2025_1_404/microservices/offer/usecase"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/api/yandex"
+	database "github.com/go-park-mail-ru/2025_1_404/pkg/database/postgres"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/database/s3"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/logger"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/utils"
+	"github.com/google/uuid"
+)
+
+// Импорт объявлений продавца из CSV или фида Яндекс.Недвижимости. Отчет печатается в stdout в JSON
+func main() {
+	sellerID := flag.Int("seller", 0, "id продавца, от имени которого создаются объявления")
+	format := flag.String("format", "", "формат файла: csv или yandex (по умолчанию по расширению)")
+	path := flag.String("file", "", "путь к файлу импорта")
+	flag.Parse()
+
+	if *sellerID <= 0 || *path == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *format == "" {
+		*format = importer.DetectFormat(*path)
+	}
+
+	cfg, err := config.NewConfig()
+	if err != nil {
+		log.Fatalf("не удалось загрузить конфиг: %v", err)
+	}
+
+	file, err := os.Open(*path)
+	if err != nil {
+		log.Fatalf("не удалось открыть файл: %v", err)
+	}
+	defer file.Close()
+
+	records, err := importer.Parse(*format, file, cfg.App.Import.MaxRows)
+	if err != nil {
+		log.Fatalf("не удалось разобрать файл: %v", err)
+	}
+
+	// Логгер
+	l, err := logger.NewZapLogger(cfg.App.Logger.Level)
+	if err != nil {
+		log.Fatalf("не удалось создать логгер: %v", err)
+	}
+	defer l.Close()
+
+	ctx := context.WithValue(context.Background(), utils.RequestIDKey, uuid.NewString())
+
+	// Инициализация подключения к БД
+	dbpool, err := database.NewPool(&cfg.Postgres, ctx)
+	if err != nil {
+		log.Fatalf("не удалось подключиться к базе данных: %v", err)
+	}
+	defer dbpool.Close()
+
+	// Хранилище файлов
+	s3repo, err := s3.New(&cfg.Minio, l)
+	if err != nil {
+		log.Fatalf("не удалось подключиться к s3: %v", err)
+	}
+
+	// Для импорта нужен геокодер, клиенты других сервисов и Redis не используются
+	offerRepo := repoOffer.NewOfferRepository(dbpool, l)
+	offerUC := usecaseOffer.NewOfferUsecase(offerRepo, l, s3repo, cfg, nil, nil, nil, yandex.New(&cfg.Yandex))
+
+	report, err := offerUC.ImportOffers(ctx, *sellerID, records)
+	if err != nil {
+		log.Fatalf("импорт не удался: %v", err)
+	}
+
+	data, err := report.MarshalJSON()
+	if err != nil {
+		log.Fatalf("не удалось сформировать отчет: %v", err)
+	}
+	os.Stdout.Write(append(data, '\n'))
+}
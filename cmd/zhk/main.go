@@ -13,6 +13,7 @@ import (
 	repoZhk "github.com/go-park-mail-ru/2025_1_404/microservices/zhk/repository"
 	usecaseZhk "github.com/go-park-mail-ru/2025_1_404/microservices/zhk/usecase"
 	database "github.com/go-park-mail-ru/2025_1_404/pkg/database/postgres"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/database/redis"
 	"github.com/go-park-mail-ru/2025_1_404/pkg/logger"
 	"github.com/go-park-mail-ru/2025_1_404/pkg/middleware"
 	"github.com/go-park-mail-ru/2025_1_404/pkg/utils"
@@ -45,7 +46,11 @@ func main() {
 	}
 	defer dbpool.Close()
 
-	
+	// Инициализация подключения к Redis
+	redisRepo, err := redis.New(&cfg.Redis, l)
+	if err != nil {
+		log.Fatalf("не удалось подключиться к Redis: %v", err)
+	}
 
 	// Хранилище файлов
 	basePath := "./internal/static/upload"
@@ -61,7 +66,7 @@ func main() {
 	offerService := offerpb.NewOfferServiceClient(conn)
 
 	zhkRepo := repoZhk.NewZhkRepository(dbpool, l)
-	zhkUC := usecaseZhk.NewZhkUsecase(zhkRepo, l, cfg, offerService)
+	zhkUC := usecaseZhk.NewZhkUsecase(zhkRepo, l, cfg, offerService, redisRepo)
 	zhkHandler := deliveryZhk.NewZhkHandler(zhkUC, cfg)
 
 	// Маршруты
@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -17,21 +18,104 @@ type Config struct {
 }
 
 type AppConfig struct {
-	Auth            AuthConfig      `yaml:"auth"`
-	Logger          LoggerConfig    `yaml:"logger"`
-	Promotion       PromotionConfig `yaml:"promotion"`
-	CORS            CORSConfig      `yaml:"cors"`
-	Http            HttpConfig      `yaml:"http"`
-	Grpc            GrpcConfig      `yaml:"grpc"`
-	Host            string          `yaml:"host"`
-	BaseDir         string          `yaml:"basePath"`
-	BaseFrontendDir string          `yaml:"baseFrontendPath"`
-	BaseImagesPath  string          `yaml:"baseImagesPath"`
+	Auth            AuthConfig         `yaml:"auth"`
+	Logger          LoggerConfig       `yaml:"logger"`
+	Promotion       PromotionConfig    `yaml:"promotion"`
+	SearchAlerts    SearchAlertsConfig `yaml:"searchAlerts"`
+	Expiration      ExpirationConfig   `yaml:"expiration"`
+	Images          ImagesConfig       `yaml:"images"`
+	ImageGC         ImageGCConfig      `yaml:"imageGC"`
+	Import          ImportConfig       `yaml:"import"`
+	Feed            FeedConfig         `yaml:"feed"`
+	PriceAlerts     PriceAlertsConfig  `yaml:"priceAlerts"`
+	Cache           CacheConfig        `yaml:"cache"`
+	CORS            CORSConfig         `yaml:"cors"`
+	Http            HttpConfig         `yaml:"http"`
+	Grpc            GrpcConfig         `yaml:"grpc"`
+	Host            string             `yaml:"host"`
+	BaseDir         string             `yaml:"basePath"`
+	BaseFrontendDir string             `yaml:"baseFrontendPath"`
+	BaseImagesPath  string             `yaml:"baseImagesPath"`
 }
 
 type PromotionConfig struct {
-	LikeScore      float32 `yaml:"likeScore"`
-	PromotionScore float32 `yaml:"promotionScore"`
+	PinPerPage int `yaml:"pinPerPage"`
+}
+
+// SearchAlertsConfig Воркер уведомлений по сохраненным поискам.
+// Нулевой interval отключает воркер
+type SearchAlertsConfig struct {
+	Interval     time.Duration `yaml:"interval"`
+	BatchSize    int           `yaml:"batchSize"`
+	MaxPerSearch int           `yaml:"maxPerSearch"`
+}
+
+// ExpirationConfig Снятие объявлений с истекшим сроком публикации.
+// Lifetimes задает срок для каждого типа объявления (ключ - id из kvartirum.OfferType),
+// типы без срока не снимаются. Нулевой interval отключает воркер
+type ExpirationConfig struct {
+	Interval     time.Duration         `yaml:"interval"`
+	NotifyBefore time.Duration         `yaml:"notifyBefore"`
+	BatchSize    int                   `yaml:"batchSize"`
+	Lifetimes    map[int]time.Duration `yaml:"lifetimes"`
+}
+
+// ImagesConfig Загрузка фотографий объявлений и уменьшенные копии, которые создаются при загрузке
+type ImagesConfig struct {
+	VariantWidths   []int         `yaml:"variantWidths"`
+	Quality         int           `yaml:"quality"`
+	MaxBatchFiles   int           `yaml:"maxBatchFiles"`
+	UploadURLExpiry time.Duration `yaml:"uploadUrlExpiry"`
+}
+
+// ImportConfig Загрузка объявлений из CSV и фида Яндекс.Недвижимости
+type ImportConfig struct {
+	MaxFileSize     int64         `yaml:"maxFileSize"`
+	MaxRows         int           `yaml:"maxRows"`
+	MaxImages       int           `yaml:"maxImages"`
+	DownloadTimeout time.Duration `yaml:"downloadTimeout"`
+}
+
+// FeedConfig Фид объявлений для площадок. Снимок в Redis дособирается не чаще RefreshInterval
+// и собирается заново через SnapshotTTL после прошлой полной сборки. Досборки срок не продлевают
+type FeedConfig struct {
+	RefreshInterval time.Duration `yaml:"refreshInterval"`
+	SnapshotTTL     time.Duration `yaml:"snapshotTTL"`
+}
+
+// CacheConfig Время жизни записей read-through кеша в Redis. Нулевое значение отключает кеш.
+// Карточки объявлений и сведения о ЖК сбрасываются при изменениях, TTL ограничивает
+// устаревание счетчика просмотров и данных продавца
+type CacheConfig struct {
+	Dictionaries time.Duration `yaml:"dictionaries"`
+	OfferCards   time.Duration `yaml:"offerCards"`
+	ZhkInfo      time.Duration `yaml:"zhkInfo"`
+}
+
+// PriceAlertsConfig Уведомления о снижении цены избранных объявлений. MinDropPercent - порог
+// по умолчанию для пользователей без своей настройки. Channel - канал доставки сверх
+// kvartirum.UserNotification: inapp (только запись) или email
+type PriceAlertsConfig struct {
+	MinDropPercent int        `yaml:"minDropPercent"`
+	Channel        string     `yaml:"channel"`
+	SMTP           SMTPConfig `yaml:"smtp"`
+}
+
+type SMTPConfig struct {
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+	From     string `yaml:"from"`
+}
+
+// ImageGCConfig Сверка объектов в бакетах с таблицей kvartirum.Image. Объекты и записи моложе
+// GracePeriod не трогаются: загрузка могла еще не дойти до записи в базу
+type ImageGCConfig struct {
+	Interval    time.Duration `yaml:"interval"`
+	GracePeriod time.Duration `yaml:"gracePeriod"`
+	Buckets     []string      `yaml:"buckets"`
+	DryRun      bool          `yaml:"dryRun"`
 }
 
 type LoggerConfig struct {
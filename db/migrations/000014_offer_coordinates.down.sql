@@ -0,0 +1,7 @@
+SET SEARCH_PATH = kvartirum;
+
+DROP INDEX IF EXISTS offer_geo_idx;
+
+ALTER TABLE offer
+DROP COLUMN geo_longitude,
+DROP COLUMN geo_latitude;
@@ -0,0 +1,13 @@
+SET SEARCH_PATH = kvartirum;
+
+-- Числовые координаты для поиска по карте. Вычисляются из текстовых,
+-- которые заполняются геокодером, поэтому остаются синхронными без изменений в коде записи
+ALTER TABLE offer
+ADD COLUMN geo_longitude DOUBLE PRECISION GENERATED ALWAYS AS (
+    CASE WHEN longitude ~ '^-?[0-9]+(\.[0-9]+)?$' THEN longitude::DOUBLE PRECISION END
+) STORED,
+ADD COLUMN geo_latitude DOUBLE PRECISION GENERATED ALWAYS AS (
+    CASE WHEN latitude ~ '^-?[0-9]+(\.[0-9]+)?$' THEN latitude::DOUBLE PRECISION END
+) STORED;
+
+CREATE INDEX offer_geo_idx ON offer (geo_latitude, geo_longitude);
@@ -0,0 +1,8 @@
+SET SEARCH_PATH = kvartirum;
+
+DROP INDEX IF EXISTS offer_search_idx;
+DROP TRIGGER IF EXISTS trg_offer_search_vector ON Offer;
+DROP FUNCTION IF EXISTS set_offer_search_vector();
+
+ALTER TABLE Offer
+DROP COLUMN search_vector;
@@ -0,0 +1,32 @@
+SET SEARCH_PATH = kvartirum;
+
+ALTER TABLE Offer
+ADD COLUMN search_vector TSVECTOR;
+
+-- Поисковый вектор по описанию, адресу, станции метро и названию ЖК.
+-- Адрес и названия весят больше описания
+CREATE OR REPLACE FUNCTION set_offer_search_vector()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.search_vector :=
+        setweight(to_tsvector('russian', coalesce(NEW.address, '')), 'A') ||
+        setweight(to_tsvector('russian', coalesce(
+            (SELECT name FROM kvartirum.MetroStation WHERE id = NEW.metro_station_id), '')), 'A') ||
+        setweight(to_tsvector('russian', coalesce(
+            (SELECT name FROM kvartirum.HousingComplex WHERE id = NEW.complex_id), '')), 'A') ||
+        setweight(to_tsvector('russian', coalesce(NEW.description, '')), 'B');
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_offer_search_vector
+BEFORE INSERT OR UPDATE OF description, address, metro_station_id, complex_id ON Offer
+FOR EACH ROW
+EXECUTE FUNCTION set_offer_search_vector();
+
+-- Заполняем вектор для существующих объявлений, не трогая updated_at
+ALTER TABLE Offer DISABLE TRIGGER set_updated_at_offer;
+UPDATE Offer SET description = description;
+ALTER TABLE Offer ENABLE TRIGGER set_updated_at_offer;
+
+CREATE INDEX offer_search_idx ON Offer USING GIN (search_vector);
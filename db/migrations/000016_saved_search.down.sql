@@ -0,0 +1,10 @@
+SET SEARCH_PATH = kvartirum;
+
+DROP TABLE IF EXISTS SavedSearch;
+
+DROP TRIGGER IF EXISTS trg_offer_published_at ON Offer;
+DROP FUNCTION IF EXISTS set_offer_published_at();
+DROP INDEX IF EXISTS offer_published_at_idx;
+
+ALTER TABLE Offer
+DROP COLUMN published_at;
@@ -0,0 +1,45 @@
+SET SEARCH_PATH = kvartirum;
+
+-- Время последней публикации объявления, по нему воркер ищет новые совпадения
+ALTER TABLE Offer
+ADD COLUMN published_at TIMESTAMP WITH TIME ZONE;
+
+ALTER TABLE Offer DISABLE TRIGGER set_updated_at_offer;
+UPDATE Offer SET published_at = updated_at WHERE offer_status_id = 1;
+ALTER TABLE Offer ENABLE TRIGGER set_updated_at_offer;
+
+CREATE INDEX offer_published_at_idx ON Offer (published_at);
+
+CREATE OR REPLACE FUNCTION set_offer_published_at()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.offer_status_id = 1 AND OLD.offer_status_id IS DISTINCT FROM 1 THEN
+        NEW.published_at := CURRENT_TIMESTAMP;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_offer_published_at
+BEFORE UPDATE OF offer_status_id ON Offer
+FOR EACH ROW
+EXECUTE FUNCTION set_offer_published_at();
+
+CREATE TABLE IF NOT EXISTS SavedSearch (
+    id BIGINT GENERATED ALWAYS AS IDENTITY
+    PRIMARY KEY,
+    user_id BIGINT NOT NULL
+    REFERENCES Users (id)
+    ON DELETE cascade
+    ON UPDATE cascade,
+    name TEXT NOT NULL
+    CONSTRAINT name_length CHECK (char_length(name) <= 64),
+    filter JSONB NOT NULL,
+    frequency TEXT NOT NULL
+    CONSTRAINT frequency_value CHECK (frequency IN ('instant', 'daily', 'weekly')),
+    last_checked_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE INDEX saved_search_user_idx ON SavedSearch (user_id);
+CREATE INDEX saved_search_checked_idx ON SavedSearch (last_checked_at);
@@ -0,0 +1,27 @@
+SET SEARCH_PATH = kvartirum;
+
+CREATE TABLE IF NOT EXISTS OfferStatusHistory (
+    id BIGINT GENERATED ALWAYS AS IDENTITY
+    PRIMARY KEY,
+    offer_id BIGINT NOT NULL
+    REFERENCES Offer (id)
+    ON DELETE cascade
+    ON UPDATE cascade,
+    from_status_id INT NOT NULL
+    REFERENCES OfferStatus (id)
+    ON DELETE cascade
+    ON UPDATE cascade,
+    to_status_id INT NOT NULL
+    REFERENCES OfferStatus (id)
+    ON DELETE cascade
+    ON UPDATE cascade,
+    changed_by BIGINT
+    REFERENCES Users (id)
+    ON DELETE set null
+    ON UPDATE cascade,
+    reason TEXT
+    CONSTRAINT reason_length CHECK (char_length(reason) <= 256),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE INDEX offer_status_history_offer_idx ON OfferStatusHistory (offer_id, created_at);
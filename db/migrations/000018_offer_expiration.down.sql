@@ -0,0 +1,17 @@
+SET SEARCH_PATH = kvartirum;
+
+CREATE OR REPLACE FUNCTION set_offer_published_at()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.offer_status_id = 1 AND OLD.offer_status_id IS DISTINCT FROM 1 THEN
+        NEW.published_at := CURRENT_TIMESTAMP;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP INDEX IF EXISTS offer_renewed_at_idx;
+
+ALTER TABLE Offer
+DROP COLUMN expiry_notified,
+DROP COLUMN renewed_at;
@@ -0,0 +1,24 @@
+SET SEARCH_PATH = kvartirum;
+
+-- Начало текущего срока публикации. Сбрасывается при публикации и продлении
+ALTER TABLE Offer
+ADD COLUMN renewed_at TIMESTAMP WITH TIME ZONE,
+ADD COLUMN expiry_notified BOOLEAN DEFAULT FALSE NOT NULL;
+
+ALTER TABLE Offer DISABLE TRIGGER set_updated_at_offer;
+UPDATE Offer SET renewed_at = COALESCE(published_at, updated_at) WHERE offer_status_id = 1;
+ALTER TABLE Offer ENABLE TRIGGER set_updated_at_offer;
+
+CREATE INDEX offer_renewed_at_idx ON Offer (offer_type_id, renewed_at) WHERE offer_status_id = 1;
+
+CREATE OR REPLACE FUNCTION set_offer_published_at()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.offer_status_id = 1 AND OLD.offer_status_id IS DISTINCT FROM 1 THEN
+        NEW.published_at := CURRENT_TIMESTAMP;
+        NEW.renewed_at := CURRENT_TIMESTAMP;
+        NEW.expiry_notified := FALSE;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
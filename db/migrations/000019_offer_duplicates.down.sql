@@ -0,0 +1,6 @@
+SET SEARCH_PATH = kvartirum;
+
+DROP TABLE IF EXISTS OfferDuplicate;
+
+ALTER TABLE Image
+DROP COLUMN phash;
@@ -0,0 +1,23 @@
+SET SEARCH_PATH = kvartirum;
+
+-- Перцептивный хеш изображения для поиска повторно загруженных фотографий
+ALTER TABLE Image
+ADD COLUMN phash BIGINT;
+
+-- Вероятные дубликаты объявлений других продавцов, ожидающие проверки модератором
+CREATE TABLE IF NOT EXISTS OfferDuplicate (
+    offer_id BIGINT NOT NULL
+    REFERENCES Offer (id)
+    ON DELETE cascade
+    ON UPDATE cascade,
+    duplicate_of BIGINT NOT NULL
+    REFERENCES Offer (id)
+    ON DELETE cascade
+    ON UPDATE cascade,
+    reason TEXT NOT NULL
+    CONSTRAINT reason_value CHECK (reason IN ('attributes', 'images')),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    PRIMARY KEY (offer_id, duplicate_of, reason)
+);
+
+CREATE INDEX offer_duplicate_created_idx ON OfferDuplicate (created_at);
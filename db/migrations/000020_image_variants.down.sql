@@ -0,0 +1,4 @@
+SET SEARCH_PATH = kvartirum;
+
+ALTER TABLE Image
+DROP COLUMN variants;
@@ -0,0 +1,5 @@
+SET SEARCH_PATH = kvartirum;
+
+-- Ширины уменьшенных копий, сохраненных рядом с оригиналом
+ALTER TABLE Image
+ADD COLUMN variants INT [] DEFAULT '{}' NOT NULL;
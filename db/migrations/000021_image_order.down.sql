@@ -0,0 +1,11 @@
+SET SEARCH_PATH = kvartirum;
+
+ALTER TABLE HousingComplexImages
+DROP COLUMN position;
+
+DROP INDEX IF EXISTS offer_images_position_idx;
+
+ALTER TABLE OfferImages
+DROP CONSTRAINT IF EXISTS offer_images_single_cover,
+DROP COLUMN is_cover,
+DROP COLUMN position;
@@ -0,0 +1,36 @@
+SET SEARCH_PATH = kvartirum;
+
+-- Порядок фотографий в галерее объявления и выбранная продавцом обложка
+ALTER TABLE OfferImages
+ADD COLUMN position INT DEFAULT 0 NOT NULL,
+ADD COLUMN is_cover BOOLEAN DEFAULT FALSE NOT NULL;
+
+-- Существующие галереи сохраняют порядок загрузки, обложкой становится первая фотография
+UPDATE OfferImages oi
+SET position = ordered.position,
+    is_cover = ordered.position = 0
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY offer_id ORDER BY created_at, id) - 1 AS position
+    FROM OfferImages
+) ordered
+WHERE oi.id = ordered.id;
+
+-- Смена обложки - одно UPDATE по всем фотографиям объявления, поэтому проверка откладывается до конца транзакции
+ALTER TABLE OfferImages
+ADD CONSTRAINT offer_images_single_cover
+EXCLUDE USING btree (offer_id WITH =) WHERE (is_cover)
+DEFERRABLE INITIALLY DEFERRED;
+
+CREATE INDEX offer_images_position_idx ON OfferImages (offer_id, position);
+
+-- Порядок фотографий жилого комплекса
+ALTER TABLE HousingComplexImages
+ADD COLUMN position INT DEFAULT 0 NOT NULL;
+
+UPDATE HousingComplexImages hci
+SET position = ordered.position
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY housing_complex_id ORDER BY created_at, id) - 1 AS position
+    FROM HousingComplexImages
+) ordered
+WHERE hci.id = ordered.id;
@@ -0,0 +1,6 @@
+SET SEARCH_PATH = kvartirum;
+
+DROP INDEX IF EXISTS offer_external_id_idx;
+
+ALTER TABLE Offer
+DROP COLUMN external_id;
@@ -0,0 +1,9 @@
+SET SEARCH_PATH = kvartirum;
+
+-- Идентификатор объявления во внешней системе продавца, по нему повторный импорт обновляет объявление
+ALTER TABLE Offer
+ADD COLUMN external_id TEXT DEFAULT NULL
+CONSTRAINT external_id_length CHECK (char_length(external_id) <= 64);
+
+CREATE UNIQUE INDEX offer_external_id_idx ON Offer (seller_id, external_id)
+WHERE external_id IS NOT NULL;
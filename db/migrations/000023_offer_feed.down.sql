@@ -0,0 +1,7 @@
+SET SEARCH_PATH = kvartirum;
+
+DROP INDEX IF EXISTS offer_updated_at_idx;
+
+DROP TRIGGER IF EXISTS touch_offer_on_image_change ON OfferImages;
+
+DROP FUNCTION IF EXISTS touch_offer_updated_at();
@@ -0,0 +1,18 @@
+SET SEARCH_PATH = kvartirum;
+
+-- Фид для площадок пересобирается по updated_at, поэтому изменение галереи тоже считается изменением объявления
+CREATE OR REPLACE FUNCTION touch_offer_updated_at()
+RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE kvartirum.Offer SET updated_at = CURRENT_TIMESTAMP
+    WHERE id = COALESCE(NEW.offer_id, OLD.offer_id);
+RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER touch_offer_on_image_change
+    AFTER INSERT OR UPDATE OR DELETE ON OfferImages
+    FOR EACH ROW
+    EXECUTE FUNCTION touch_offer_updated_at();
+
+CREATE INDEX IF NOT EXISTS offer_updated_at_idx ON Offer (updated_at);
@@ -0,0 +1,5 @@
+SET SEARCH_PATH = kvartirum;
+
+DROP INDEX IF EXISTS favourites_offer_created_idx;
+DROP INDEX IF EXISTS likes_offer_created_idx;
+DROP INDEX IF EXISTS views_offer_created_idx;
@@ -0,0 +1,6 @@
+SET SEARCH_PATH = kvartirum;
+
+-- Статистика продавца считается по объявлению за период
+CREATE INDEX IF NOT EXISTS views_offer_created_idx ON Views (offer_id, created_at);
+CREATE INDEX IF NOT EXISTS likes_offer_created_idx ON Likes (offer_id, created_at);
+CREATE INDEX IF NOT EXISTS favourites_offer_created_idx ON UserOfferFavourites (offer_id, created_at);
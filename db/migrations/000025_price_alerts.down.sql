@@ -0,0 +1,3 @@
+SET SEARCH_PATH = kvartirum;
+
+DROP TABLE IF EXISTS PriceAlertSettings;
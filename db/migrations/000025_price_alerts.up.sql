@@ -0,0 +1,14 @@
+SET SEARCH_PATH = kvartirum;
+
+-- Настройки уведомлений о снижении цены избранных объявлений. Пользователь без записи
+-- получает уведомления с порогом по умолчанию из конфига
+CREATE TABLE IF NOT EXISTS PriceAlertSettings (
+    user_id BIGINT PRIMARY KEY
+    REFERENCES Users (id)
+    ON DELETE cascade
+    ON UPDATE cascade,
+    enabled BOOLEAN DEFAULT TRUE NOT NULL,
+    min_drop_percent INT DEFAULT NULL
+    CONSTRAINT min_drop_percent_range CHECK (min_drop_percent BETWEEN 0 AND 100),
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
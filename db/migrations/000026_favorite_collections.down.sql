@@ -0,0 +1,9 @@
+SET SEARCH_PATH = kvartirum;
+
+DROP TABLE IF EXISTS FavoriteCollectionOffer;
+DROP TABLE IF EXISTS FavoriteCollection;
+
+DROP INDEX IF EXISTS user_offer_favourites_user_offer_idx;
+
+ALTER TABLE UserOfferFavourites
+DROP COLUMN IF EXISTS note;
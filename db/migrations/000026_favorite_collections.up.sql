@@ -0,0 +1,55 @@
+SET SEARCH_PATH = kvartirum;
+
+-- Подборки избранного. У каждого пользователя есть основная подборка, в нее попадает
+-- объявление, добавленное в избранное без выбора подборки
+CREATE TABLE IF NOT EXISTS FavoriteCollection (
+    id BIGINT GENERATED ALWAYS AS IDENTITY
+    PRIMARY KEY,
+    user_id BIGINT NOT NULL
+    REFERENCES Users (id)
+    ON DELETE cascade
+    ON UPDATE cascade,
+    name TEXT NOT NULL
+    CONSTRAINT name_length CHECK (char_length(name) <= 64),
+    is_default BOOLEAN DEFAULT FALSE NOT NULL,
+    share_token TEXT UNIQUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE INDEX favorite_collection_user_idx ON FavoriteCollection (user_id);
+CREATE UNIQUE INDEX favorite_collection_default_idx ON FavoriteCollection (user_id) WHERE is_default;
+
+CREATE TABLE IF NOT EXISTS FavoriteCollectionOffer (
+    collection_id BIGINT NOT NULL
+    REFERENCES FavoriteCollection (id)
+    ON DELETE cascade
+    ON UPDATE cascade,
+    offer_id BIGINT NOT NULL
+    REFERENCES Offer (id)
+    ON DELETE cascade
+    ON UPDATE cascade,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    PRIMARY KEY (collection_id, offer_id)
+);
+
+CREATE INDEX favorite_collection_offer_offer_idx ON FavoriteCollectionOffer (offer_id);
+
+-- Заметка видна только владельцу избранного и общая для всех его подборок
+ALTER TABLE UserOfferFavourites
+ADD COLUMN note TEXT
+CONSTRAINT note_length CHECK (char_length(note) <= 1000);
+
+DELETE FROM UserOfferFavourites a
+USING UserOfferFavourites b
+WHERE a.user_id = b.user_id AND a.offer_id = b.offer_id AND a.id > b.id;
+
+CREATE UNIQUE INDEX user_offer_favourites_user_offer_idx ON UserOfferFavourites (user_id, offer_id);
+
+-- Уже добавленное в избранное переносим в основные подборки
+INSERT INTO FavoriteCollection (user_id, name, is_default)
+SELECT DISTINCT user_id, 'Избранное', TRUE FROM UserOfferFavourites;
+
+INSERT INTO FavoriteCollectionOffer (collection_id, offer_id, created_at)
+SELECT c.id, f.offer_id, f.created_at
+FROM UserOfferFavourites f
+JOIN FavoriteCollection c ON c.user_id = f.user_id AND c.is_default;
@@ -0,0 +1,27 @@
+SET SEARCH_PATH = kvartirum;
+
+UPDATE OfferStatus SET name = 'Черновик' WHERE id = 1;
+UPDATE OfferStatus SET name = 'Активный' WHERE id = 2;
+
+CREATE OR REPLACE FUNCTION set_offer_defaults()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.offer_type_id IS NULL THEN
+        NEW.offer_type_id := (SELECT id FROM kvartirum.OfferType WHERE name = 'Продажа' LIMIT 1);
+    END IF;
+
+    IF NEW.property_type_id IS NULL THEN
+        NEW.property_type_id := (SELECT id FROM kvartirum.PropertyType WHERE name = 'Квартира' LIMIT 1);
+    END IF;
+
+    IF NEW.offer_status_id IS NULL THEN
+        NEW.offer_status_id := (SELECT id FROM kvartirum.OfferStatus WHERE name = 'Черновик' LIMIT 1);
+    END IF;
+
+    IF NEW.renovation_id IS NULL THEN
+        NEW.renovation_id := (SELECT id FROM kvartirum.OfferRenovation WHERE name = 'Черновая отделка' LIMIT 1);
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
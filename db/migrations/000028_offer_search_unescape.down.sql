@@ -0,0 +1,21 @@
+SET SEARCH_PATH = kvartirum;
+
+CREATE OR REPLACE FUNCTION set_offer_search_vector()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.search_vector :=
+        setweight(to_tsvector('russian', coalesce(NEW.address, '')), 'A') ||
+        setweight(to_tsvector('russian', coalesce(
+            (SELECT name FROM kvartirum.MetroStation WHERE id = NEW.metro_station_id), '')), 'A') ||
+        setweight(to_tsvector('russian', coalesce(
+            (SELECT name FROM kvartirum.HousingComplex WHERE id = NEW.complex_id), '')), 'A') ||
+        setweight(to_tsvector('russian', coalesce(NEW.description, '')), 'B');
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP FUNCTION IF EXISTS unescape_html(TEXT);
+
+ALTER TABLE Offer DISABLE TRIGGER set_updated_at_offer;
+UPDATE Offer SET description = description;
+ALTER TABLE Offer ENABLE TRIGGER set_updated_at_offer;
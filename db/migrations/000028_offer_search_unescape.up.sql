@@ -0,0 +1,28 @@
+SET SEARCH_PATH = kvartirum;
+
+-- Описание и адрес хранятся после html.EscapeString. Без обратной замены в вектор
+-- попадают слова вроде amp и 34 из сущностей, а & в запросе не находит текст.
+-- &amp; заменяется последним, чтобы введенное пользователем "&lt;" не превратилось в "<"
+CREATE OR REPLACE FUNCTION unescape_html(value TEXT)
+RETURNS TEXT AS $$
+    SELECT replace(replace(replace(replace(replace(value,
+        '&lt;', '<'), '&gt;', '>'), '&#34;', '"'), '&#39;', ''''), '&amp;', '&');
+$$ LANGUAGE sql IMMUTABLE;
+
+CREATE OR REPLACE FUNCTION set_offer_search_vector()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.search_vector :=
+        setweight(to_tsvector('russian', coalesce(unescape_html(NEW.address), '')), 'A') ||
+        setweight(to_tsvector('russian', coalesce(
+            (SELECT name FROM kvartirum.MetroStation WHERE id = NEW.metro_station_id), '')), 'A') ||
+        setweight(to_tsvector('russian', coalesce(
+            (SELECT name FROM kvartirum.HousingComplex WHERE id = NEW.complex_id), '')), 'A') ||
+        setweight(to_tsvector('russian', coalesce(unescape_html(NEW.description), '')), 'B');
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+ALTER TABLE Offer DISABLE TRIGGER set_updated_at_offer;
+UPDATE Offer SET description = description;
+ALTER TABLE Offer ENABLE TRIGGER set_updated_at_offer;
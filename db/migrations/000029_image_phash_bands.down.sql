@@ -0,0 +1,8 @@
+SET SEARCH_PATH = kvartirum;
+
+DROP INDEX IF EXISTS image_phash_bands_idx;
+
+ALTER TABLE Image
+DROP COLUMN phash_bands;
+
+DROP FUNCTION IF EXISTS phash_bands(BIGINT);
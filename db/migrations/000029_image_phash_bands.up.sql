@@ -0,0 +1,23 @@
+SET SEARCH_PATH = kvartirum;
+
+-- Полосы перцептивного хеша для поиска похожих изображений по индексу.
+-- 64 бита делятся на 7 полос (10 + 6 * 9 бит). Если хеши различаются не больше чем в 6 битах,
+-- хотя бы одна полоса совпадает целиком, поэтому кандидатов дает пересечение массивов,
+-- а точное расстояние считается только для них. Номер полосы входит в значение: k * 1024 + биты
+CREATE OR REPLACE FUNCTION phash_bands(phash BIGINT)
+RETURNS INT[] AS $$
+    SELECT ARRAY[
+        (phash & 1023)::int,
+        1 * 1024 + ((phash >> 10) & 511)::int,
+        2 * 1024 + ((phash >> 19) & 511)::int,
+        3 * 1024 + ((phash >> 28) & 511)::int,
+        4 * 1024 + ((phash >> 37) & 511)::int,
+        5 * 1024 + ((phash >> 46) & 511)::int,
+        6 * 1024 + ((phash >> 55) & 511)::int
+    ];
+$$ LANGUAGE sql IMMUTABLE STRICT;
+
+ALTER TABLE Image
+ADD COLUMN phash_bands INT[] GENERATED ALWAYS AS (phash_bands(phash)) STORED;
+
+CREATE INDEX image_phash_bands_idx ON Image USING GIN (phash_bands);
@@ -0,0 +1,10 @@
+SET SEARCH_PATH = kvartirum;
+
+DROP INDEX IF EXISTS offer_complex_id_idx;
+DROP INDEX IF EXISTS offer_metro_station_id_idx;
+
+DROP TRIGGER IF EXISTS touch_offers_on_housing_complex_rename ON HousingComplex;
+DROP TRIGGER IF EXISTS touch_offers_on_metro_station_rename ON MetroStation;
+
+DROP FUNCTION IF EXISTS touch_offers_on_housing_complex_rename();
+DROP FUNCTION IF EXISTS touch_offers_on_metro_station_rename();
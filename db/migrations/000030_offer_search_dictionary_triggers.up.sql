@@ -0,0 +1,38 @@
+SET SEARCH_PATH = kvartirum;
+
+-- Названия станций метро и ЖК входят в search_vector объявления, но триггер вектора
+-- срабатывает только на запись в Offer. При переименовании пересчитываем вектор зависимых
+-- объявлений: присваивание metro_station_id/complex_id самому себе запускает
+-- trg_offer_search_vector. updated_at тоже обновляется, и это нужно: название есть в фиде
+CREATE OR REPLACE FUNCTION touch_offers_on_metro_station_rename()
+RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE kvartirum.Offer SET metro_station_id = metro_station_id
+    WHERE metro_station_id = NEW.id;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION touch_offers_on_housing_complex_rename()
+RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE kvartirum.Offer SET complex_id = complex_id
+    WHERE complex_id = NEW.id;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER touch_offers_on_metro_station_rename
+    AFTER UPDATE OF name ON MetroStation
+    FOR EACH ROW
+    WHEN (OLD.name IS DISTINCT FROM NEW.name)
+    EXECUTE FUNCTION touch_offers_on_metro_station_rename();
+
+CREATE TRIGGER touch_offers_on_housing_complex_rename
+    AFTER UPDATE OF name ON HousingComplex
+    FOR EACH ROW
+    WHEN (OLD.name IS DISTINCT FROM NEW.name)
+    EXECUTE FUNCTION touch_offers_on_housing_complex_rename();
+
+CREATE INDEX IF NOT EXISTS offer_metro_station_id_idx ON Offer (metro_station_id);
+CREATE INDEX IF NOT EXISTS offer_complex_id_idx ON Offer (complex_id);
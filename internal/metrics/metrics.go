@@ -4,6 +4,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/go-park-mail-ru/2025_1_404/pkg/cache"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
@@ -42,6 +43,8 @@ func NewMetrics(serviceName string) (*Metrics, *prometheus.Registry) {
 	reg.MustRegister(m.requestCount)
 	reg.MustRegister(m.errorCount)
 	reg.MustRegister(m.requestDuration)
+	// Попадания и промахи кеша в Redis
+	prometheus.WrapRegistererWith(constLabels, reg).MustRegister(cache.Collector())
 
 	return m, reg
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/go-park-mail-ru/2025_1_404/microservices/auth"
+	"github.com/go-park-mail-ru/2025_1_404/microservices/auth/domain"
 	"github.com/go-park-mail-ru/2025_1_404/pkg/logger"
 	authpb "github.com/go-park-mail-ru/2025_1_404/proto/auth"
 	"google.golang.org/grpc/codes"
@@ -29,13 +30,38 @@ func (s *authService) GetUserById(ctx context.Context, r *authpb.GetUserRequest)
 		return nil, status.Errorf(codes.NotFound, "cannot find user by id: %v", err)
 	}
 	return &authpb.GetUserResponse{
-		User: &authpb.User{
-			Id:        int32(user.ID),
-			Email:     user.Email,
-			FirstName: user.FirstName,
-			LastName:  user.LastName,
-			Image:     user.Image,
-			CreatedAt: timestamppb.New(user.CreatedAt),
-		},
+		User: toProtoUser(user),
 	}, nil
 }
+
+// GetUsersByIds Пользователи по списку id за один вызов. Ненайденных пользователей
+// в ответе нет, ошибкой это не считается
+func (s *authService) GetUsersByIds(ctx context.Context, r *authpb.GetUsersByIdsRequest) (*authpb.GetUsersByIdsResponse, error) {
+	ids := make([]int, 0, len(r.GetIds()))
+	for _, id := range r.GetIds() {
+		ids = append(ids, int(id))
+	}
+
+	users, err := s.UC.GetUsersByIDs(ctx, ids)
+	if err != nil {
+		s.logger.Warn("failed to find users")
+		return nil, status.Errorf(codes.Internal, "cannot get users by ids: %v", err)
+	}
+
+	resp := &authpb.GetUsersByIdsResponse{Users: make([]*authpb.User, 0, len(users))}
+	for _, user := range users {
+		resp.Users = append(resp.Users, toProtoUser(user))
+	}
+	return resp, nil
+}
+
+func toProtoUser(user domain.User) *authpb.User {
+	return &authpb.User{
+		Id:        int32(user.ID),
+		Email:     user.Email,
+		FirstName: user.FirstName,
+		LastName:  user.LastName,
+		Image:     user.Image,
+		CreatedAt: timestamppb.New(user.CreatedAt),
+	}
+}
@@ -60,3 +60,26 @@ func TestAuthService_GetUserById_NotFound(t *testing.T) {
 	assert.Error(t, err)
 	assert.Equal(t, codes.NotFound, status.Code(err))
 }
+
+func TestAuthService_GetUsersByIds(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUC := mocks.NewMockAuthUsecase(ctrl)
+	svc := service.NewAuthService(mockUC, logger.NewStub())
+
+	mockUC.EXPECT().GetUsersByIDs(gomock.Any(), []int{1, 3}).
+		Return([]domain.User{{ID: 1, FirstName: "Ivan", CreatedAt: time.Now()}}, nil)
+
+	resp, err := svc.GetUsersByIds(context.Background(), &authpb.GetUsersByIdsRequest{Ids: []int32{1, 3}})
+	assert.NoError(t, err)
+	assert.Len(t, resp.Users, 1)
+	assert.Equal(t, int32(1), resp.Users[0].Id)
+	assert.Equal(t, "Ivan", resp.Users[0].FirstName)
+
+	mockUC.EXPECT().GetUsersByIDs(gomock.Any(), []int{2}).Return(nil, errors.New("db error"))
+
+	resp, err = svc.GetUsersByIds(context.Background(), &authpb.GetUsersByIdsRequest{Ids: []int32{2}})
+	assert.Nil(t, resp)
+	assert.Equal(t, codes.Internal, status.Code(err))
+}
@@ -216,8 +216,8 @@ func (h *AuthHandler) UploadImage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Проверяем подходит ли файл под условия и получаем его расширение
-	contentType, err := content.CheckImage(fileBytes)
+	// Проверяем файл и перекодируем его, чтобы убрать EXIF с геометками и учесть поворот снимка
+	sanitized, err := content.PrepareImage(fileBytes)
 	if err != nil {
 		utils.SendErrorResponse(w, "invalid file type or size", http.StatusBadRequest, &h.cfg.App.CORS)
 		return
@@ -225,10 +225,10 @@ func (h *AuthHandler) UploadImage(w http.ResponseWriter, r *http.Request) {
 
 	upload := s3.Upload{
 		Bucket:      "avatars",
-		Filename:    header.Filename,
-		Size:        header.Size,
-		File:        bytes.NewReader(fileBytes),
-		ContentType: contentType,
+		Filename:    content.SanitizedFilename(header.Filename),
+		Size:        int64(len(sanitized)),
+		File:        bytes.NewReader(sanitized),
+		ContentType: content.SanitizedContentType,
 	}
 
 	// Пытаемся загрузить картинку
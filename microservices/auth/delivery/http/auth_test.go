@@ -6,15 +6,21 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/go-park-mail-ru/2025_1_404/config"
+	"image"
+	"image/png"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/go-park-mail-ru/2025_1_404/microservices/auth/domain"
 	"github.com/go-park-mail-ru/2025_1_404/microservices/auth/mocks"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/content"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/database/s3"
 	"github.com/go-park-mail-ru/2025_1_404/pkg/utils"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -484,3 +490,56 @@ func TestDeleteImage(t *testing.T) {
 		assert.Equal(t, http.StatusInternalServerError, response.Result().StatusCode)
 	})
 }
+
+func TestUploadImage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUS := mocks.NewMockAuthUsecase(ctrl)
+	cfg := &config.Config{
+		App: config.AppConfig{
+			CORS: config.CORSConfig{AllowOrigin: "*"},
+		},
+	}
+	userHandlers := NewAuthHandler(mockUS, cfg)
+
+	newRequest := func(t *testing.T, filename string, data []byte) *http.Request {
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		part, err := writer.CreateFormFile("avatar", filename)
+		require.NoError(t, err)
+		_, err = part.Write(data)
+		require.NoError(t, err)
+		require.NoError(t, writer.Close())
+
+		ctx := context.WithValue(context.Background(), utils.UserIDKey, 1)
+		request := httptest.NewRequest(http.MethodPost, "/auth/image", &body).WithContext(ctx)
+		request.Header.Set("Content-Type", writer.FormDataContentType())
+		return request
+	}
+
+	t.Run("UploadImage ok", func(t *testing.T) {
+		var encoded bytes.Buffer
+		require.NoError(t, png.Encode(&encoded, image.NewRGBA(image.Rect(0, 0, 200, 200))))
+
+		mockUS.EXPECT().UploadImage(gomock.Any(), 1, gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ int, upload s3.Upload) (domain.User, error) {
+				assert.Equal(t, "avatars", upload.Bucket)
+				assert.Equal(t, "avatar.jpg", upload.Filename)
+				assert.Equal(t, content.SanitizedContentType, upload.ContentType)
+				return domain.User{Image: "avatar.jpg"}, nil
+			})
+
+		response := httptest.NewRecorder()
+		userHandlers.UploadImage(response, newRequest(t, "avatar.png", encoded.Bytes()))
+
+		assert.Equal(t, http.StatusOK, response.Result().StatusCode)
+	})
+
+	t.Run("not an image", func(t *testing.T) {
+		response := httptest.NewRecorder()
+		userHandlers.UploadImage(response, newRequest(t, "avatar.png", []byte("not an image")))
+
+		assert.Equal(t, http.StatusBadRequest, response.Result().StatusCode)
+	})
+}
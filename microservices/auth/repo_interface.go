@@ -14,6 +14,7 @@ type AuthRepository interface {
 	CreateUser(ctx context.Context, user repository.User) (int64, error)
 	GetUserByEmail(ctx context.Context, email string) (domain.User, error)
 	GetUserByID(ctx context.Context, id int64) (domain.User, error)
+	GetUsersByIDs(ctx context.Context, ids []int64) ([]domain.User, error)
 	UpdateUser(ctx context.Context, user domain.User) (domain.User, error)
 	DeleteUser(ctx context.Context, id int64) error
 	CreateImage(ctx context.Context, fileName string) error
@@ -62,6 +62,16 @@ const (
 		WHERE u.id = $1;
 	`
 
+	getUsersByIDsSQL = `
+		SELECT
+			u.id,
+			COALESCE(i.uuid, '') as image,
+			u.first_name, u.last_name, u.email, u.created_at, u.role
+		FROM kvartirum.Users u
+		LEFT JOIN kvartirum.Image i on u.image_id = i.id
+		WHERE u.id = ANY($1);
+	`
+
 	updateUserSQL = `
 		UPDATE kvartirum.Users
 		SET
@@ -133,6 +143,38 @@ func (r *authRepository) GetUserByID(ctx context.Context, id int64) (domain.User
 	return u, err
 }
 
+// GetUsersByIDs Пользователи по списку id одним запросом. Ненайденные id пропускаются,
+// пароль не выбирается
+func (r *authRepository) GetUsersByIDs(ctx context.Context, ids []int64) ([]domain.User, error) {
+	requestID := ctx.Value(utils.RequestIDKey)
+
+	rows, err := r.db.Query(ctx, getUsersByIDsSQL, ids)
+
+	logFields := logger.LoggerFields{"requestID": requestID, "query": getUsersByIDsSQL, "params": logger.LoggerFields{"count": len(ids)}, "success": err == nil}
+	if err != nil {
+		r.logger.WithFields(logFields).Error("SQL query GetUsersByIDs failed")
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := make([]domain.User, 0, len(ids))
+	for rows.Next() {
+		var u domain.User
+		if err := rows.Scan(&u.ID, &u.Image, &u.FirstName, &u.LastName, &u.Email, &u.CreatedAt, &u.Role); err != nil {
+			r.logger.WithFields(logFields).Error("SQL query GetUsersByIDs scan failed")
+			return nil, err
+		}
+		users = append(users, u)
+	}
+	if err := rows.Err(); err != nil {
+		r.logger.WithFields(logFields).Error("SQL query GetUsersByIDs failed")
+		return nil, err
+	}
+	r.logger.WithFields(logFields).Info("SQL query GetUsersByIDs succeeded")
+
+	return users, nil
+}
+
 func (r *authRepository) UpdateUser(ctx context.Context, u domain.User) (domain.User, error) {
 	requestID := ctx.Value(utils.RequestIDKey)
 
@@ -88,6 +88,26 @@ func TestRepository_GetUserByID(t *testing.T) {
 	require.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestRepository_GetUsersByIDs(t *testing.T) {
+	repo, mock := newTestRepo(t)
+	defer mock.Close()
+
+	createdAt := time.Now()
+
+	mock.ExpectQuery(`(?i)SELECT\s+u.id,\s+COALESCE\(i.uuid, ''\) as image,.*FROM kvartirum.Users u\s+LEFT JOIN kvartirum.Image i on u.image_id = i.id\s+WHERE u.id = ANY\(\$1\)`).
+		WithArgs([]int64{1, 2}).
+		WillReturnRows(pgxmock.NewRows([]string{
+			"id", "image", "first_name", "last_name", "email", "created_at", "role",
+		}).AddRow(1, "avatar.png", "Ivan", "Petrov", "user@example.com", createdAt, "user"))
+
+	users, err := repo.GetUsersByIDs(context.Background(), []int64{1, 2})
+	require.NoError(t, err)
+	require.Len(t, users, 1)
+	require.Equal(t, "Ivan", users[0].FirstName)
+	require.Empty(t, users[0].Password)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestRepository_UpdateUser(t *testing.T) {
 	repo, mock := newTestRepo(t)
 	defer mock.Close()
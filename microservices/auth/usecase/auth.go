@@ -101,6 +101,29 @@ func (u *authUsecase) GetUserByID(ctx context.Context, id int) (domain.User, err
 	return user, nil
 }
 
+func (u *authUsecase) GetUsersByIDs(ctx context.Context, ids []int) ([]domain.User, error) {
+	requestID := ctx.Value(utils.RequestIDKey)
+
+	repoIDs := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		repoIDs = append(repoIDs, int64(id))
+	}
+
+	users, err := u.repo.GetUsersByIDs(ctx, repoIDs)
+	if err != nil {
+		u.logger.WithFields(logger.LoggerFields{"requestID": requestID, "err": err.Error()}).Error("User usecase: get users by ids failed")
+		return nil, err
+	}
+
+	for i := range users {
+		if users[i].Image != "" {
+			users[i].Image = u.cfg.Minio.Path + u.cfg.Minio.AvatarsBucket + users[i].Image
+		}
+	}
+
+	return users, nil
+}
+
 func (u *authUsecase) UpdateUser(ctx context.Context, user domain.User) (domain.User, error) {
 	requestID := ctx.Value(utils.RequestIDKey)
 
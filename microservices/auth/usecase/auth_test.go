@@ -357,3 +357,33 @@ func TestDeleteImage(t *testing.T) {
 		assert.Equal(t, domain.User{}, result)
 	})
 }
+
+func TestGetUsersByIDs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockAuthRepository(ctrl)
+	cfg := &config.Config{Minio: config.MinioConfig{Path: Path, AvatarsBucket: Bucket}}
+	usecase := NewAuthUsecase(mockRepo, logger.NewStub(), mockS3.NewMockS3Repo(ctrl), cfg)
+	ctx := context.WithValue(context.Background(), utils.RequestIDKey, "test-request-id")
+
+	t.Run("avatars get bucket prefix", func(t *testing.T) {
+		mockRepo.EXPECT().GetUsersByIDs(ctx, []int64{1, 2}).
+			Return([]domain.User{{ID: 1, Image: "image.png"}, {ID: 2}}, nil)
+
+		users, err := usecase.GetUsersByIDs(ctx, []int{1, 2})
+
+		assert.NoError(t, err)
+		assert.Len(t, users, 2)
+		assert.Equal(t, Path+Bucket+"image.png", users[0].Image)
+		assert.Empty(t, users[1].Image)
+	})
+
+	t.Run("repository failed", func(t *testing.T) {
+		mockRepo.EXPECT().GetUsersByIDs(ctx, []int64{3}).Return(nil, errors.New("db error"))
+
+		_, err := usecase.GetUsersByIDs(ctx, []int{3})
+
+		assert.Error(t, err)
+	})
+}
@@ -14,6 +14,7 @@ type AuthUsecase interface {
 	CreateUser(ctx context.Context, email, password, firstName, lastName string) (domain.User, error)
 	GetUserByEmail(ctx context.Context, email string) (domain.User, error)
 	GetUserByID(ctx context.Context, id int) (domain.User, error)
+	GetUsersByIDs(ctx context.Context, ids []int) ([]domain.User, error)
 	UpdateUser(ctx context.Context, user domain.User) (domain.User, error)
 	UploadImage(ctx context.Context, id int, file s3.Upload) (domain.User, error)
 	DeleteImage(ctx context.Context, id int) (domain.User, error)
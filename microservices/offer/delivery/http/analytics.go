@@ -0,0 +1,68 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/domain"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/utils"
+	"github.com/gorilla/mux"
+)
+
+// GetOfferStats Статистика объявления для продавца: ?from=&to=&bucket=day|week|month
+func (h *OfferHandler) GetOfferStats(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(utils.UserIDKey).(int)
+	if !ok {
+		utils.SendErrorResponse(w, "UserID not found", http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+
+	offerID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || offerID <= 0 {
+		utils.SendErrorResponse(w, "Некорректный ID", http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+
+	period, err := parseStatsPeriod(r)
+	if err != nil {
+		utils.SendErrorResponse(w, "Некорректный период", http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+
+	stats, err := h.OfferUC.GetOfferStats(r.Context(), offerID, userID, period)
+	if err != nil {
+		utils.SendErrorResponse(w, err.Error(), http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+
+	utils.SendJSONResponse(w, stats, http.StatusOK, &h.cfg.App.CORS)
+}
+
+// GetSellerStats Сводка по всем объявлениям текущего пользователя
+func (h *OfferHandler) GetSellerStats(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(utils.UserIDKey).(int)
+	if !ok {
+		utils.SendErrorResponse(w, "UserID not found", http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+
+	period, err := parseStatsPeriod(r)
+	if err != nil {
+		utils.SendErrorResponse(w, "Некорректный период", http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+
+	stats, err := h.OfferUC.GetSellerStats(r.Context(), userID, period)
+	if err != nil {
+		utils.SendErrorResponse(w, "Ошибка при получении статистики", http.StatusInternalServerError, &h.cfg.App.CORS)
+		return
+	}
+
+	utils.SendJSONResponse(w, stats, http.StatusOK, &h.cfg.App.CORS)
+}
+
+func parseStatsPeriod(r *http.Request) (domain.StatsPeriod, error) {
+	query := r.URL.Query()
+	return domain.ParseStatsPeriod(query.Get("from"), query.Get("to"), query.Get("bucket"), time.Now())
+}
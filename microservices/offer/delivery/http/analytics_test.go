@@ -0,0 +1,115 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-park-mail-ru/2025_1_404/config"
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/domain"
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/mocks"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/utils"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetOfferStatsHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUC := mocks.NewMockOfferUsecase(ctrl)
+	cfg := &config.Config{
+		App: config.AppConfig{
+			CORS: config.CORSConfig{AllowOrigin: "*"},
+		},
+	}
+	handler := NewOfferHandler(mockUC, cfg)
+	ctx := context.WithValue(context.Background(), utils.UserIDKey, 10)
+
+	newRequest := func(query string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/offers/1/stats"+query, nil).WithContext(ctx)
+		return mux.SetURLVars(req, map[string]string{"id": "1"})
+	}
+
+	t.Run("ok", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		period := domain.StatsPeriod{
+			From:   time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC),
+			To:     time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+			Bucket: domain.StatsBucketWeek,
+		}
+		mockUC.EXPECT().GetOfferStats(gomock.Any(), 1, 10, period).Return(domain.OfferStats{OfferID: 1}, nil)
+
+		handler.GetOfferStats(rec, newRequest("?from=2025-05-01&to=2025-05-31&bucket=week"))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("invalid period", func(t *testing.T) {
+		for _, query := range []string{"?bucket=hour", "?from=01.05.2025", "?from=2025-06-01&to=2025-05-01", "?from=2023-01-01&to=2025-01-01"} {
+			rec := httptest.NewRecorder()
+
+			handler.GetOfferStats(rec, newRequest(query))
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code, query)
+		}
+	})
+
+	t.Run("not owner", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		mockUC.EXPECT().GetOfferStats(gomock.Any(), 1, 10, gomock.Any()).Return(domain.OfferStats{}, fmt.Errorf("нет доступа к этому объявлению"))
+
+		handler.GetOfferStats(rec, newRequest(""))
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("no user", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		handler.GetOfferStats(rec, httptest.NewRequest(http.MethodGet, "/offers/1/stats", nil))
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+func TestGetSellerStatsHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUC := mocks.NewMockOfferUsecase(ctrl)
+	cfg := &config.Config{
+		App: config.AppConfig{
+			CORS: config.CORSConfig{AllowOrigin: "*"},
+		},
+	}
+	handler := NewOfferHandler(mockUC, cfg)
+	ctx := context.WithValue(context.Background(), utils.UserIDKey, 10)
+
+	t.Run("default period", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		mockUC.EXPECT().GetSellerStats(gomock.Any(), 10, gomock.Any()).DoAndReturn(
+			func(_ context.Context, _ int, period domain.StatsPeriod) (domain.SellerStats, error) {
+				assert.Equal(t, domain.StatsBucketDay, period.Bucket)
+				assert.Equal(t, 30*24*time.Hour, period.To.Sub(period.From))
+				return domain.SellerStats{}, nil
+			})
+
+		handler.GetSellerStats(rec, httptest.NewRequest(http.MethodGet, "/offers/stats", nil).WithContext(ctx))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("usecase error", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		mockUC.EXPECT().GetSellerStats(gomock.Any(), 10, gomock.Any()).Return(domain.SellerStats{}, fmt.Errorf("db error"))
+
+		handler.GetSellerStats(rec, httptest.NewRequest(http.MethodGet, "/offers/stats", nil).WithContext(ctx))
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+}
@@ -0,0 +1,301 @@
+package http
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/domain"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/utils"
+	"github.com/gorilla/mux"
+)
+
+func (h *OfferHandler) GetCollections(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(utils.UserIDKey).(int)
+	if !ok {
+		utils.SendErrorResponse(w, "UserID not found", http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+
+	collections, err := h.OfferUC.GetCollections(r.Context(), userID)
+	if err != nil {
+		utils.SendErrorResponse(w, "Ошибка при получении подборок", http.StatusInternalServerError, &h.cfg.App.CORS)
+		return
+	}
+
+	utils.SendJSONResponse(w, collections, http.StatusOK, &h.cfg.App.CORS)
+}
+
+func (h *OfferHandler) CreateCollection(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(utils.UserIDKey).(int)
+	if !ok {
+		utils.SendErrorResponse(w, "UserID not found", http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+
+	name, err := parseCollectionName(r)
+	if err != nil {
+		utils.SendErrorResponse(w, err.Error(), http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+
+	collection, err := h.OfferUC.CreateCollection(r.Context(), userID, name)
+	if err != nil {
+		utils.SendErrorResponse(w, "Ошибка при создании подборки", http.StatusInternalServerError, &h.cfg.App.CORS)
+		return
+	}
+
+	utils.SendJSONResponse(w, collection, http.StatusCreated, &h.cfg.App.CORS)
+}
+
+func (h *OfferHandler) RenameCollection(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(utils.UserIDKey).(int)
+	if !ok {
+		utils.SendErrorResponse(w, "UserID not found", http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || id <= 0 {
+		utils.SendErrorResponse(w, "Некорректный ID", http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+
+	name, err := parseCollectionName(r)
+	if err != nil {
+		utils.SendErrorResponse(w, err.Error(), http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+
+	collection, err := h.OfferUC.RenameCollection(r.Context(), id, userID, name)
+	if err != nil {
+		utils.SendErrorResponse(w, err.Error(), http.StatusNotFound, &h.cfg.App.CORS)
+		return
+	}
+
+	utils.SendJSONResponse(w, collection, http.StatusOK, &h.cfg.App.CORS)
+}
+
+func (h *OfferHandler) DeleteCollection(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(utils.UserIDKey).(int)
+	if !ok {
+		utils.SendErrorResponse(w, "UserID not found", http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || id <= 0 {
+		utils.SendErrorResponse(w, "Некорректный ID", http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+
+	if err := h.OfferUC.DeleteCollection(r.Context(), id, userID); err != nil {
+		utils.SendErrorResponse(w, err.Error(), http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+
+	msg := utils.MessageResponse{Message: "Удалено"}
+	utils.SendJSONResponse(w, msg, http.StatusOK, &h.cfg.App.CORS)
+}
+
+// AddToCollection Добавляет объявление в подборку. Из других подборок оно не убирается,
+// так объявление копируют между подборками
+func (h *OfferHandler) AddToCollection(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(utils.UserIDKey).(int)
+	if !ok {
+		utils.SendErrorResponse(w, "UserID not found", http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || id <= 0 {
+		utils.SendErrorResponse(w, "Некорректный ID", http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+
+	var req domain.CollectionOfferRequest
+	data, _ := io.ReadAll(r.Body)
+	if err := req.UnmarshalJSON(data); err != nil || req.OfferID <= 0 {
+		utils.SendErrorResponse(w, "Ошибка в теле запроса", http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+
+	if err := h.OfferUC.AddToCollection(r.Context(), userID, id, req.OfferID); err != nil {
+		utils.SendErrorResponse(w, err.Error(), http.StatusNotFound, &h.cfg.App.CORS)
+		return
+	}
+
+	msg := utils.MessageResponse{Message: "Добавлено"}
+	utils.SendJSONResponse(w, msg, http.StatusOK, &h.cfg.App.CORS)
+}
+
+func (h *OfferHandler) MoveToCollection(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(utils.UserIDKey).(int)
+	if !ok {
+		utils.SendErrorResponse(w, "UserID not found", http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+
+	id, offerID, ok := parseCollectionOfferIDs(r)
+	if !ok {
+		utils.SendErrorResponse(w, "Некорректный ID", http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+
+	var req domain.MoveCollectionOfferRequest
+	data, _ := io.ReadAll(r.Body)
+	if err := req.UnmarshalJSON(data); err != nil || req.ToCollectionID <= 0 {
+		utils.SendErrorResponse(w, "Ошибка в теле запроса", http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+
+	if err := h.OfferUC.MoveToCollection(r.Context(), userID, id, req.ToCollectionID, offerID); err != nil {
+		utils.SendErrorResponse(w, err.Error(), http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+
+	msg := utils.MessageResponse{Message: "Перемещено"}
+	utils.SendJSONResponse(w, msg, http.StatusOK, &h.cfg.App.CORS)
+}
+
+func (h *OfferHandler) RemoveFromCollection(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(utils.UserIDKey).(int)
+	if !ok {
+		utils.SendErrorResponse(w, "UserID not found", http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+
+	id, offerID, ok := parseCollectionOfferIDs(r)
+	if !ok {
+		utils.SendErrorResponse(w, "Некорректный ID", http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+
+	if err := h.OfferUC.RemoveFromCollection(r.Context(), userID, id, offerID); err != nil {
+		utils.SendErrorResponse(w, err.Error(), http.StatusNotFound, &h.cfg.App.CORS)
+		return
+	}
+
+	msg := utils.MessageResponse{Message: "Удалено"}
+	utils.SendJSONResponse(w, msg, http.StatusOK, &h.cfg.App.CORS)
+}
+
+func (h *OfferHandler) UpdateFavoriteNote(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(utils.UserIDKey).(int)
+	if !ok {
+		utils.SendErrorResponse(w, "UserID not found", http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+
+	offerID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || offerID <= 0 {
+		utils.SendErrorResponse(w, "Некорректный ID", http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+
+	var req domain.FavoriteNoteRequest
+	data, _ := io.ReadAll(r.Body)
+	if err := req.UnmarshalJSON(data); err != nil {
+		utils.SendErrorResponse(w, "Ошибка в теле запроса", http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+
+	if err := h.OfferUC.UpdateFavoriteNote(r.Context(), userID, offerID, strings.TrimSpace(req.Note)); err != nil {
+		utils.SendErrorResponse(w, err.Error(), http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+
+	msg := utils.MessageResponse{Message: "Сохранено"}
+	utils.SendJSONResponse(w, msg, http.StatusOK, &h.cfg.App.CORS)
+}
+
+func (h *OfferHandler) ShareCollection(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(utils.UserIDKey).(int)
+	if !ok {
+		utils.SendErrorResponse(w, "UserID not found", http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || id <= 0 {
+		utils.SendErrorResponse(w, "Некорректный ID", http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+
+	collection, err := h.OfferUC.ShareCollection(r.Context(), id, userID)
+	if err != nil {
+		utils.SendErrorResponse(w, err.Error(), http.StatusNotFound, &h.cfg.App.CORS)
+		return
+	}
+
+	utils.SendJSONResponse(w, collection, http.StatusOK, &h.cfg.App.CORS)
+}
+
+func (h *OfferHandler) UnshareCollection(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(utils.UserIDKey).(int)
+	if !ok {
+		utils.SendErrorResponse(w, "UserID not found", http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || id <= 0 {
+		utils.SendErrorResponse(w, "Некорректный ID", http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+
+	if err := h.OfferUC.UnshareCollection(r.Context(), id, userID); err != nil {
+		utils.SendErrorResponse(w, err.Error(), http.StatusNotFound, &h.cfg.App.CORS)
+		return
+	}
+
+	msg := utils.MessageResponse{Message: "Доступ по ссылке закрыт"}
+	utils.SendJSONResponse(w, msg, http.StatusOK, &h.cfg.App.CORS)
+}
+
+// GetSharedCollection Подборка по ссылке, доступна без авторизации
+func (h *OfferHandler) GetSharedCollection(w http.ResponseWriter, r *http.Request) {
+	page, err := parsePagination(r)
+	if err != nil {
+		utils.SendErrorResponse(w, err.Error(), http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+
+	collection, err := h.OfferUC.GetSharedCollection(r.Context(), mux.Vars(r)["token"], page)
+	if err != nil {
+		utils.SendErrorResponse(w, "Подборка не найдена", http.StatusNotFound, &h.cfg.App.CORS)
+		return
+	}
+
+	utils.SendJSONResponse(w, collection, http.StatusOK, &h.cfg.App.CORS)
+}
+
+func parseCollectionName(r *http.Request) (string, error) {
+	var req domain.CollectionRequest
+	data, _ := io.ReadAll(r.Body)
+	if err := req.UnmarshalJSON(data); err != nil {
+		return "", fmt.Errorf("Ошибка в теле запроса")
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" || utf8.RuneCountInString(name) > domain.MaxCollectionNameLength {
+		return "", fmt.Errorf("Некорректное название подборки")
+	}
+	return name, nil
+}
+
+func parseCollectionOfferIDs(r *http.Request) (int, int, bool) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil || id <= 0 {
+		return 0, 0, false
+	}
+	offerID, err := strconv.Atoi(vars["offerId"])
+	if err != nil || offerID <= 0 {
+		return 0, 0, false
+	}
+	return id, offerID, true
+}
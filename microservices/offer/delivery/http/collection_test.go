@@ -0,0 +1,141 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-park-mail-ru/2025_1_404/config"
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/domain"
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/mocks"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/utils"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollectionHandlers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUC := mocks.NewMockOfferUsecase(ctrl)
+	cfg := &config.Config{
+		App: config.AppConfig{
+			CORS: config.CORSConfig{AllowOrigin: "*"},
+		},
+	}
+	handler := NewOfferHandler(mockUC, cfg)
+	ctx := context.WithValue(context.Background(), utils.UserIDKey, 10)
+
+	t.Run("create", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		mockUC.EXPECT().CreateCollection(gomock.Any(), 10, "Для родителей").
+			Return(domain.FavoriteCollection{ID: 2, Name: "Для родителей"}, nil)
+
+		req := httptest.NewRequest(http.MethodPost, "/offers/favorites/collections", strings.NewReader(`{"name":"  Для родителей "}`))
+		handler.CreateCollection(rec, req.WithContext(ctx))
+
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"share_token":null`)
+	})
+
+	t.Run("create with long name", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		body := fmt.Sprintf(`{"name":"%s"}`, strings.Repeat("я", domain.MaxCollectionNameLength+1))
+		req := httptest.NewRequest(http.MethodPost, "/offers/favorites/collections", strings.NewReader(body))
+		handler.CreateCollection(rec, req.WithContext(ctx))
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("delete default", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		mockUC.EXPECT().DeleteCollection(gomock.Any(), 1, 10).Return(fmt.Errorf("основную подборку нельзя удалить"))
+
+		req := httptest.NewRequest(http.MethodDelete, "/offers/favorites/collections/1", nil)
+		req = mux.SetURLVars(req.WithContext(ctx), map[string]string{"id": "1"})
+		handler.DeleteCollection(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "основную подборку нельзя удалить")
+	})
+
+	t.Run("move", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		mockUC.EXPECT().MoveToCollection(gomock.Any(), 10, 1, 3, 7).Return(nil)
+
+		req := httptest.NewRequest(http.MethodPost, "/offers/favorites/collections/1/offers/7/move", strings.NewReader(`{"to_collection_id":3}`))
+		req = mux.SetURLVars(req.WithContext(ctx), map[string]string{"id": "1", "offerId": "7"})
+		handler.MoveToCollection(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("add without offer", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		req := httptest.NewRequest(http.MethodPost, "/offers/favorites/collections/1/offers", strings.NewReader(`{}`))
+		req = mux.SetURLVars(req.WithContext(ctx), map[string]string{"id": "1"})
+		handler.AddToCollection(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("note", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		mockUC.EXPECT().UpdateFavoriteNote(gomock.Any(), 10, 7, "рядом школа").Return(nil)
+
+		req := httptest.NewRequest(http.MethodPut, "/offers/7/favorite/note", strings.NewReader(`{"note":" рядом школа "}`))
+		req = mux.SetURLVars(req.WithContext(ctx), map[string]string{"id": "7"})
+		handler.UpdateFavoriteNote(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("shared", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		mockUC.EXPECT().GetSharedCollection(gomock.Any(), "token", domain.Pagination{Limit: 20}).
+			Return(domain.SharedCollection{Name: "Для родителей", Offers: domain.OffersPage{Offers: domain.OffersInfo{}}}, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/offers/favorites/shared/token", nil)
+		req = mux.SetURLVars(req, map[string]string{"token": "token"})
+		handler.GetSharedCollection(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"name":"Для родителей"`)
+	})
+
+	t.Run("shared revoked", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		mockUC.EXPECT().GetSharedCollection(gomock.Any(), "revoked", gomock.Any()).
+			Return(domain.SharedCollection{}, fmt.Errorf("подборка не найдена"))
+
+		req := httptest.NewRequest(http.MethodGet, "/offers/favorites/shared/revoked", nil)
+		req = mux.SetURLVars(req, map[string]string{"token": "revoked"})
+		handler.GetSharedCollection(rec, req)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("favorites by collection", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		collectionID := 2
+		mockUC.EXPECT().GetFavorites(gomock.Any(), 10, nil, &collectionID, gomock.Any()).Return(domain.OffersPage{}, nil)
+
+		handler.GetFavorites(rec, httptest.NewRequest(http.MethodGet, "/offers/favorites?collection_id=2", nil).WithContext(ctx))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("no user", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		handler.GetCollections(rec, httptest.NewRequest(http.MethodGet, "/offers/favorites/collections", nil))
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
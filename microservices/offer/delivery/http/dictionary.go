@@ -0,0 +1,30 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/go-park-mail-ru/2025_1_404/pkg/utils"
+)
+
+// GetDictionaries Все справочники объявления. Версия отдается как ETag, поэтому клиент может
+// хранить справочники у себя и перепроверять их без загрузки тела
+func (h *OfferHandler) GetDictionaries(w http.ResponseWriter, r *http.Request) {
+	dicts, err := h.OfferUC.GetDictionaries(r.Context())
+	if err != nil {
+		utils.SendErrorResponse(w, "Ошибка при получении справочников", http.StatusInternalServerError, &h.cfg.App.CORS)
+		return
+	}
+
+	etag := fmt.Sprintf(`"%s"`, dicts.Version)
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "no-cache")
+
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		utils.EnableCORS(w, &h.cfg.App.CORS)
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	utils.SendJSONResponse(w, dicts, http.StatusOK, &h.cfg.App.CORS)
+}
@@ -0,0 +1,86 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-park-mail-ru/2025_1_404/config"
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/domain"
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/mocks"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/utils"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetDictionaries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUC := mocks.NewMockOfferUsecase(ctrl)
+	cfg := &config.Config{
+		App: config.AppConfig{
+			CORS: config.CORSConfig{AllowOrigin: "*"},
+		},
+	}
+	handler := NewOfferHandler(mockUC, cfg)
+	dicts := domain.Dictionaries{
+		Version:    "abc",
+		OfferTypes: []domain.DictionaryItem{{ID: 1, Name: "Продажа"}},
+		MetroLines: []domain.MetroLine{{ID: 4, Name: "Замоскворецкая", Color: "007D3C", Stations: []domain.DictionaryItem{{ID: 15, Name: "Тверская"}}}},
+	}
+
+	t.Run("ok", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		mockUC.EXPECT().GetDictionaries(gomock.Any()).Return(dicts, nil)
+
+		handler.GetDictionaries(rec, httptest.NewRequest(http.MethodGet, "/dictionaries", nil))
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, `"abc"`, rec.Header().Get("ETag"))
+		assert.Contains(t, rec.Body.String(), `"metro_lines":[{"id":4,"name":"Замоскворецкая","color":"007D3C","stations":[{"id":15,"name":"Тверская"}]}]`)
+	})
+
+	t.Run("not modified", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		mockUC.EXPECT().GetDictionaries(gomock.Any()).Return(dicts, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/dictionaries", nil)
+		req.Header.Set("If-None-Match", `"abc"`)
+		handler.GetDictionaries(rec, req)
+
+		assert.Equal(t, http.StatusNotModified, rec.Code)
+		assert.Empty(t, rec.Body.String())
+	})
+
+	t.Run("error", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		mockUC.EXPECT().GetDictionaries(gomock.Any()).Return(domain.Dictionaries{}, errors.New("db error"))
+
+		handler.GetDictionaries(rec, httptest.NewRequest(http.MethodGet, "/dictionaries", nil))
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+}
+
+func TestCreateOfferInvalidReference(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUC := mocks.NewMockOfferUsecase(ctrl)
+	handler := NewOfferHandler(mockUC, &config.Config{})
+	rec := httptest.NewRecorder()
+
+	mockUC.EXPECT().CreateOffer(gomock.Any(), gomock.Any()).
+		Return(0, &domain.InvalidReferenceError{Field: "renovation_id", ID: 42})
+
+	req := httptest.NewRequest(http.MethodPost, "/offers", strings.NewReader(`{"renovation_id":42}`))
+	handler.CreateOffer(rec, req.WithContext(context.WithValue(context.Background(), utils.UserIDKey, 10)))
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "некорректное значение renovation_id: 42")
+}
@@ -0,0 +1,54 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/domain"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/utils"
+)
+
+// sendDuplicateError Отвечает 409 со списком совпавших объявлений, если err - отказ из-за дубликата
+func (h *OfferHandler) sendDuplicateError(w http.ResponseWriter, err error) bool {
+	var dupErr *domain.DuplicateOfferError
+	if !errors.As(err, &dupErr) {
+		return false
+	}
+
+	resp := domain.DuplicateResponse{Message: dupErr.Error(), OfferIDs: dupErr.OfferIDs}
+	utils.SendJSONResponse(w, resp, http.StatusConflict, &h.cfg.App.CORS)
+	return true
+}
+
+func (h *OfferHandler) GetOfferDuplicates(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(utils.UserIDKey).(int)
+	if !ok {
+		utils.SendErrorResponse(w, "UserID not found", http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+
+	isModerator, err := h.OfferUC.IsModerator(r.Context(), userID)
+	if err != nil || !isModerator {
+		utils.SendErrorResponse(w, "Доступ запрещён", http.StatusForbidden, &h.cfg.App.CORS)
+		return
+	}
+
+	var offerID *int
+	if raw := r.URL.Query().Get("offer_id"); raw != "" {
+		id, err := strconv.Atoi(raw)
+		if err != nil || id <= 0 {
+			utils.SendErrorResponse(w, "Некорректный ID", http.StatusBadRequest, &h.cfg.App.CORS)
+			return
+		}
+		offerID = &id
+	}
+
+	duplicates, err := h.OfferUC.GetOfferDuplicates(r.Context(), offerID)
+	if err != nil {
+		utils.SendErrorResponse(w, "Ошибка при получении дубликатов", http.StatusInternalServerError, &h.cfg.App.CORS)
+		return
+	}
+
+	utils.SendJSONResponse(w, duplicates, http.StatusOK, &h.cfg.App.CORS)
+}
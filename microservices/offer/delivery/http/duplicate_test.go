@@ -0,0 +1,87 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-park-mail-ru/2025_1_404/config"
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/domain"
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/mocks"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/utils"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetOfferDuplicates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUC := mocks.NewMockOfferUsecase(ctrl)
+	cfg := &config.Config{
+		App: config.AppConfig{
+			CORS: config.CORSConfig{AllowOrigin: "*"},
+		},
+	}
+	handler := NewOfferHandler(mockUC, cfg)
+	ctx := context.WithValue(context.Background(), utils.UserIDKey, 1)
+
+	t.Run("GetOfferDuplicates ok", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/offers/duplicates?offer_id=7", nil).WithContext(ctx)
+		rec := httptest.NewRecorder()
+		offerID := 7
+		mockUC.EXPECT().IsModerator(gomock.Any(), 1).Return(true, nil)
+		mockUC.EXPECT().GetOfferDuplicates(gomock.Any(), &offerID).
+			Return(domain.OfferDuplicates{{OfferID: 7, DuplicateOf: 4, Reason: domain.DuplicateByImages}}, nil)
+
+		handler.GetOfferDuplicates(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"duplicate_of":4`)
+	})
+
+	t.Run("not moderator", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/offers/duplicates", nil).WithContext(ctx)
+		rec := httptest.NewRecorder()
+		mockUC.EXPECT().IsModerator(gomock.Any(), 1).Return(false, nil)
+
+		handler.GetOfferDuplicates(rec, req)
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+
+	t.Run("invalid offer id", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/offers/duplicates?offer_id=abc", nil).WithContext(ctx)
+		rec := httptest.NewRecorder()
+		mockUC.EXPECT().IsModerator(gomock.Any(), 1).Return(true, nil)
+
+		handler.GetOfferDuplicates(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+func TestPublishOfferDuplicate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUC := mocks.NewMockOfferUsecase(ctrl)
+	cfg := &config.Config{
+		App: config.AppConfig{
+			CORS: config.CORSConfig{AllowOrigin: "*"},
+		},
+	}
+	handler := NewOfferHandler(mockUC, cfg)
+
+	ctx := context.WithValue(context.Background(), utils.UserIDKey, 10)
+	req := mux.SetURLVars(httptest.NewRequest(http.MethodPost, "/offers/1/publish", nil).WithContext(ctx), map[string]string{"id": "1"})
+	rec := httptest.NewRecorder()
+	mockUC.EXPECT().PublishOffer(gomock.Any(), 1, 10).Return(&domain.DuplicateOfferError{OfferIDs: []int{3, 5}})
+
+	handler.PublishOffer(rec, req)
+
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"offer_ids":[3,5]`)
+}
@@ -0,0 +1,90 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/domain"
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/feed"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/utils"
+)
+
+// GetYandexFeed Фид активных объявлений в формате Яндекс.Недвижимости
+func (h *OfferHandler) GetYandexFeed(w http.ResponseWriter, r *http.Request) {
+	h.serveFeed(w, r, domain.FeedFormatYandex)
+}
+
+// GetJSONFeed Тот же фид в JSON
+func (h *OfferHandler) GetJSONFeed(w http.ResponseWriter, r *http.Request) {
+	h.serveFeed(w, r, domain.FeedFormatJSON)
+}
+
+// serveFeed Отдает фид с ETag. Площадки опрашивают его регулярно, поэтому при совпадении
+// If-None-Match тело не формируется и отдается 304
+func (h *OfferHandler) serveFeed(w http.ResponseWriter, r *http.Request, format string) {
+	filter, err := parseFeedFilter(r)
+	if err != nil {
+		utils.SendErrorResponse(w, "Некорректный ID", http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+
+	offerFeed, err := h.OfferUC.GetOfferFeed(r.Context(), filter)
+	if err != nil {
+		utils.SendErrorResponse(w, "Ошибка при получении фида", http.StatusInternalServerError, &h.cfg.App.CORS)
+		return
+	}
+
+	etag := fmt.Sprintf(`"%s-%s"`, offerFeed.ETag, format)
+	utils.EnableCORS(w, &h.cfg.App.CORS)
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "no-cache")
+
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	switch format {
+	case domain.FeedFormatYandex:
+		w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		_ = feed.WriteYandexFeed(w, offerFeed)
+	default:
+		data, err := offerFeed.MarshalJSON()
+		if err != nil {
+			utils.SendErrorResponse(w, "Ошибка при получении фида", http.StatusInternalServerError, &h.cfg.App.CORS)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(data)
+	}
+}
+
+// parseFeedFilter Продавцы передаются повторяющимся параметром seller_id или списком через запятую
+func parseFeedFilter(r *http.Request) (domain.FeedFilter, error) {
+	var filter domain.FeedFilter
+	for _, value := range r.URL.Query()["seller_id"] {
+		for _, part := range strings.Split(value, ",") {
+			id, err := strconv.Atoi(strings.TrimSpace(part))
+			if err != nil || id <= 0 {
+				return domain.FeedFilter{}, fmt.Errorf("некорректный seller_id")
+			}
+			filter.SellerIDs = append(filter.SellerIDs, id)
+		}
+	}
+	return filter, nil
+}
+
+// etagMatches Разбирает If-None-Match: список тегов через запятую, слабые теги и *
+func etagMatches(header string, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
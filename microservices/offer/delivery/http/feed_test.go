@@ -0,0 +1,85 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-park-mail-ru/2025_1_404/config"
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/domain"
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOfferFeedHandlers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUC := mocks.NewMockOfferUsecase(ctrl)
+	cfg := &config.Config{
+		App: config.AppConfig{
+			CORS: config.CORSConfig{AllowOrigin: "*"},
+		},
+	}
+	handler := NewOfferHandler(mockUC, cfg)
+	offerFeed := domain.OfferFeed{ETag: "abc", Offers: []domain.FeedOffer{{ID: 1, OfferType: "Продажа", PropertyType: "Квартира"}}}
+
+	t.Run("yandex xml", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		mockUC.EXPECT().GetOfferFeed(gomock.Any(), domain.FeedFilter{SellerIDs: []int{3, 4, 5}}).Return(offerFeed, nil)
+
+		handler.GetYandexFeed(rec, httptest.NewRequest(http.MethodGet, "/feed/yandex.xml?seller_id=3,4&seller_id=5", nil))
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, `"abc-yandex"`, rec.Header().Get("ETag"))
+		assert.Equal(t, "application/xml; charset=utf-8", rec.Header().Get("Content-Type"))
+		assert.True(t, strings.Contains(rec.Body.String(), `<offer internal-id="1">`))
+	})
+
+	t.Run("json not modified", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		mockUC.EXPECT().GetOfferFeed(gomock.Any(), domain.FeedFilter{}).Return(offerFeed, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/feed/offers.json", nil)
+		req.Header.Set("If-None-Match", `"old-json", W/"abc-json"`)
+		handler.GetJSONFeed(rec, req)
+
+		assert.Equal(t, http.StatusNotModified, rec.Code)
+		assert.Empty(t, rec.Body.String())
+	})
+
+	t.Run("json changed", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		mockUC.EXPECT().GetOfferFeed(gomock.Any(), domain.FeedFilter{}).Return(offerFeed, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/feed/offers.json", nil)
+		req.Header.Set("If-None-Match", `"abc-yandex"`)
+		handler.GetJSONFeed(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		var resp domain.OfferFeed
+		require.NoError(t, resp.UnmarshalJSON(rec.Body.Bytes()))
+		assert.Len(t, resp.Offers, 1)
+	})
+
+	t.Run("bad seller id", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		handler.GetJSONFeed(rec, httptest.NewRequest(http.MethodGet, "/feed/offers.json?seller_id=abc", nil))
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("usecase error", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		mockUC.EXPECT().GetOfferFeed(gomock.Any(), domain.FeedFilter{}).Return(domain.OfferFeed{}, errors.New("db error"))
+
+		handler.GetJSONFeed(rec, httptest.NewRequest(http.MethodGet, "/feed/offers.json", nil))
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+}
@@ -0,0 +1,225 @@
+package http
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/domain"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/content"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/database/s3"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/utils"
+	"github.com/gorilla/mux"
+)
+
+// Файлы пакетной загрузки больше этого размера сохраняются во временные файлы, а не в память
+const multipartMemory = 8 << 20
+
+// readImageFile Читает файл не больше допустимого размера, проверяет его и перекодирует,
+// чтобы убрать EXIF с геометками и учесть поворот снимка
+func readImageFile(file io.Reader, filename string) (s3.Upload, error) {
+	fileBytes, err := io.ReadAll(io.LimitReader(file, content.MAX_SIZE+1))
+	if err != nil {
+		return s3.Upload{}, fmt.Errorf("Не удалось прочитать файл")
+	}
+
+	sanitized, err := content.PrepareImage(fileBytes)
+	if err != nil {
+		return s3.Upload{}, fmt.Errorf("Недопустимый формат изображения")
+	}
+
+	return s3.Upload{
+		Bucket:      "offers",
+		Filename:    content.SanitizedFilename(filename),
+		Size:        int64(len(sanitized)),
+		File:        bytes.NewReader(sanitized),
+		ContentType: content.SanitizedContentType,
+	}, nil
+}
+
+// UploadOfferImages Загружает несколько фотографий из поля images и отвечает результатом по каждому файлу.
+// Ошибка в одном файле не мешает сохранить остальные
+func (h *OfferHandler) UploadOfferImages(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(utils.UserIDKey).(int)
+	if !ok {
+		utils.SendErrorResponse(w, "UserID not found", http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+
+	offerID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || offerID <= 0 {
+		utils.SendErrorResponse(w, "Некорректный ID", http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+
+	if err := h.OfferUC.CheckAccessToOffer(r.Context(), offerID, userID); err != nil {
+		utils.SendErrorResponse(w, "Доступ запрещён", http.StatusForbidden, &h.cfg.App.CORS)
+		return
+	}
+
+	maxFiles := max(h.cfg.App.Images.MaxBatchFiles, 1)
+	r.Body = http.MaxBytesReader(w, r.Body, int64(maxFiles)*(content.MAX_SIZE+multipartMemory))
+	if err := r.ParseMultipartForm(multipartMemory); err != nil {
+		utils.SendErrorResponse(w, "Ошибка в теле запроса", http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	headers := r.MultipartForm.File["images"]
+	if len(headers) == 0 {
+		utils.SendErrorResponse(w, "Файл не найден", http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+	if len(headers) > maxFiles {
+		utils.SendErrorResponse(w, fmt.Sprintf("За один раз можно загрузить не больше %d файлов", maxFiles), http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+
+	resp := domain.ImageUploadResults{Results: make([]domain.ImageUploadResult, 0, len(headers))}
+	saved := 0
+	for _, header := range headers {
+		result := domain.ImageUploadResult{Filename: header.Filename}
+
+		file, err := header.Open()
+		if err != nil {
+			result.Error = "Не удалось прочитать файл"
+			resp.Results = append(resp.Results, result)
+			continue
+		}
+		upload, err := readImageFile(file, header.Filename)
+		file.Close()
+		if err != nil {
+			result.Error = err.Error()
+			resp.Results = append(resp.Results, result)
+			continue
+		}
+
+		imageID, err := h.OfferUC.SaveOfferImage(r.Context(), offerID, upload)
+		if err != nil {
+			result.Error = "Ошибка при сохранении"
+		} else {
+			result.ImageID = imageID
+			saved++
+		}
+		resp.Results = append(resp.Results, result)
+	}
+
+	code := http.StatusCreated
+	if saved == 0 {
+		code = http.StatusBadRequest
+	}
+	utils.SendJSONResponse(w, resp, code, &h.cfg.App.CORS)
+}
+
+// CreateImageUploadURL Выдает подписанную форму для загрузки фотографии напрямую в хранилище
+func (h *OfferHandler) CreateImageUploadURL(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(utils.UserIDKey).(int)
+	if !ok {
+		utils.SendErrorResponse(w, "UserID not found", http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+
+	offerID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || offerID <= 0 {
+		utils.SendErrorResponse(w, "Некорректный ID", http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+
+	uploadURL, err := h.OfferUC.CreateImageUploadURL(r.Context(), offerID, userID)
+	if err != nil {
+		utils.SendErrorResponse(w, err.Error(), http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+
+	utils.SendJSONResponse(w, uploadURL, http.StatusCreated, &h.cfg.App.CORS)
+}
+
+// ConfirmImageUpload Сохраняет фотографию, загруженную по выданной ссылке
+func (h *OfferHandler) ConfirmImageUpload(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(utils.UserIDKey).(int)
+	if !ok {
+		utils.SendErrorResponse(w, "UserID not found", http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+
+	offerID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || offerID <= 0 {
+		utils.SendErrorResponse(w, "Некорректный ID", http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+
+	var req domain.ImageUploadConfirm
+	data, err := io.ReadAll(r.Body)
+	if err != nil || req.UnmarshalJSON(data) != nil || req.ObjectName == "" {
+		utils.SendErrorResponse(w, "Ошибка в теле запроса", http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+
+	imageID, err := h.OfferUC.ConfirmImageUpload(r.Context(), offerID, userID, req.ObjectName)
+	if err != nil {
+		utils.SendErrorResponse(w, err.Error(), http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+
+	resp := domain.ImageID{ImageID: imageID}
+	utils.SendJSONResponse(w, resp, http.StatusCreated, &h.cfg.App.CORS)
+}
+
+func (h *OfferHandler) ReorderOfferImages(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(utils.UserIDKey).(int)
+	if !ok {
+		utils.SendErrorResponse(w, "UserID not found", http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+
+	offerID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || offerID <= 0 {
+		utils.SendErrorResponse(w, "Некорректный ID", http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+
+	var req domain.ImageOrderRequest
+	data, err := io.ReadAll(r.Body)
+	if err != nil || req.UnmarshalJSON(data) != nil {
+		utils.SendErrorResponse(w, "Ошибка в теле запроса", http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+
+	if err := h.OfferUC.ReorderOfferImages(r.Context(), offerID, userID, req.ImageIDs); err != nil {
+		utils.SendErrorResponse(w, err.Error(), http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+
+	msg := utils.MessageResponse{Message: "Порядок изображений обновлен"}
+	utils.SendJSONResponse(w, msg, http.StatusOK, &h.cfg.App.CORS)
+}
+
+func (h *OfferHandler) SetOfferCover(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(utils.UserIDKey).(int)
+	if !ok {
+		utils.SendErrorResponse(w, "UserID not found", http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+
+	vars := mux.Vars(r)
+	offerID, err := strconv.Atoi(vars["id"])
+	if err != nil || offerID <= 0 {
+		utils.SendErrorResponse(w, "Некорректный ID", http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+	imageID, err := strconv.ParseInt(vars["image_id"], 10, 64)
+	if err != nil || imageID <= 0 {
+		utils.SendErrorResponse(w, "Некорректный ID", http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+
+	if err := h.OfferUC.SetOfferCover(r.Context(), offerID, userID, imageID); err != nil {
+		utils.SendErrorResponse(w, err.Error(), http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+
+	msg := utils.MessageResponse{Message: "Обложка обновлена"}
+	utils.SendJSONResponse(w, msg, http.StatusOK, &h.cfg.App.CORS)
+}
@@ -0,0 +1,272 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/png"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-park-mail-ru/2025_1_404/config"
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/domain"
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/mocks"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/utils"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReorderOfferImagesHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUC := mocks.NewMockOfferUsecase(ctrl)
+	cfg := &config.Config{
+		App: config.AppConfig{
+			CORS: config.CORSConfig{AllowOrigin: "*"},
+		},
+	}
+	handler := NewOfferHandler(mockUC, cfg)
+	ctx := context.WithValue(context.Background(), utils.UserIDKey, 10)
+
+	newRequest := func(body string) *http.Request {
+		req := httptest.NewRequest(http.MethodPut, "/offers/1/images/order", strings.NewReader(body)).WithContext(ctx)
+		return mux.SetURLVars(req, map[string]string{"id": "1"})
+	}
+
+	t.Run("ReorderOfferImages ok", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		mockUC.EXPECT().ReorderOfferImages(gomock.Any(), 1, 10, []int64{3, 1, 2}).Return(nil)
+
+		handler.ReorderOfferImages(rec, newRequest(`{"image_ids":[3,1,2]}`))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("invalid body", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		handler.ReorderOfferImages(rec, newRequest(`{"image_ids":`))
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("usecase error", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		mockUC.EXPECT().ReorderOfferImages(gomock.Any(), 1, 10, []int64{1}).
+			Return(fmt.Errorf("список изображений не совпадает с изображениями объявления"))
+
+		handler.ReorderOfferImages(rec, newRequest(`{"image_ids":[1]}`))
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("no user", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPut, "/offers/1/images/order", strings.NewReader(`{}`))
+
+		handler.ReorderOfferImages(rec, mux.SetURLVars(req, map[string]string{"id": "1"}))
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+func TestSetOfferCoverHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUC := mocks.NewMockOfferUsecase(ctrl)
+	cfg := &config.Config{
+		App: config.AppConfig{
+			CORS: config.CORSConfig{AllowOrigin: "*"},
+		},
+	}
+	handler := NewOfferHandler(mockUC, cfg)
+	ctx := context.WithValue(context.Background(), utils.UserIDKey, 10)
+
+	newRequest := func(imageID string) *http.Request {
+		req := httptest.NewRequest(http.MethodPut, "/offers/1/images/"+imageID+"/cover", nil).WithContext(ctx)
+		return mux.SetURLVars(req, map[string]string{"id": "1", "image_id": imageID})
+	}
+
+	t.Run("SetOfferCover ok", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		mockUC.EXPECT().SetOfferCover(gomock.Any(), 1, 10, int64(2)).Return(nil)
+
+		handler.SetOfferCover(rec, newRequest("2"))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("invalid image id", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		handler.SetOfferCover(rec, newRequest("0"))
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("image not found", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		mockUC.EXPECT().SetOfferCover(gomock.Any(), 1, 10, int64(9)).Return(fmt.Errorf("изображение не найдено"))
+
+		handler.SetOfferCover(rec, newRequest("9"))
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+func TestUploadOfferImagesHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUC := mocks.NewMockOfferUsecase(ctrl)
+	cfg := &config.Config{
+		App: config.AppConfig{
+			CORS:   config.CORSConfig{AllowOrigin: "*"},
+			Images: config.ImagesConfig{MaxBatchFiles: 2},
+		},
+	}
+	handler := NewOfferHandler(mockUC, cfg)
+	ctx := context.WithValue(context.Background(), utils.UserIDKey, 10)
+
+	buf := new(bytes.Buffer)
+	require.NoError(t, png.Encode(buf, image.NewRGBA(image.Rect(0, 0, 200, 200))))
+	validImage := buf.Bytes()
+
+	newRequest := func(files map[string][]byte) *http.Request {
+		body := new(bytes.Buffer)
+		writer := multipart.NewWriter(body)
+		for name, data := range files {
+			part, err := writer.CreateFormFile("images", name)
+			require.NoError(t, err)
+			_, err = part.Write(data)
+			require.NoError(t, err)
+		}
+		require.NoError(t, writer.Close())
+
+		req := httptest.NewRequest(http.MethodPost, "/offers/1/images", body).WithContext(ctx)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		return mux.SetURLVars(req, map[string]string{"id": "1"})
+	}
+
+	t.Run("partial success", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		mockUC.EXPECT().CheckAccessToOffer(gomock.Any(), 1, 10).Return(nil)
+		mockUC.EXPECT().SaveOfferImage(gomock.Any(), 1, gomock.Any()).Return(int64(42), nil)
+
+		handler.UploadOfferImages(rec, newRequest(map[string][]byte{"flat.png": validImage, "notes.txt": []byte("not an image")}))
+
+		require.Equal(t, http.StatusCreated, rec.Code)
+		var resp domain.ImageUploadResults
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+		require.Len(t, resp.Results, 2)
+		for _, result := range resp.Results {
+			if result.Filename == "flat.png" {
+				assert.Equal(t, int64(42), result.ImageID)
+				assert.Empty(t, result.Error)
+			} else {
+				assert.Zero(t, result.ImageID)
+				assert.NotEmpty(t, result.Error)
+			}
+		}
+	})
+
+	t.Run("all files failed", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		mockUC.EXPECT().CheckAccessToOffer(gomock.Any(), 1, 10).Return(nil)
+
+		handler.UploadOfferImages(rec, newRequest(map[string][]byte{"notes.txt": []byte("not an image")}))
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("too many files", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		mockUC.EXPECT().CheckAccessToOffer(gomock.Any(), 1, 10).Return(nil)
+
+		handler.UploadOfferImages(rec, newRequest(map[string][]byte{"a.png": validImage, "b.png": validImage, "c.png": validImage}))
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("not owner", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		mockUC.EXPECT().CheckAccessToOffer(gomock.Any(), 1, 10).Return(fmt.Errorf("нет доступа к этому объявлению"))
+
+		handler.UploadOfferImages(rec, newRequest(map[string][]byte{"a.png": validImage}))
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+}
+
+func TestImageUploadURLHandlers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUC := mocks.NewMockOfferUsecase(ctrl)
+	cfg := &config.Config{
+		App: config.AppConfig{
+			CORS: config.CORSConfig{AllowOrigin: "*"},
+		},
+	}
+	handler := NewOfferHandler(mockUC, cfg)
+	ctx := context.WithValue(context.Background(), utils.UserIDKey, 10)
+
+	newRequest := func(body string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/offers/1/images/confirm", strings.NewReader(body)).WithContext(ctx)
+		return mux.SetURLVars(req, map[string]string{"id": "1"})
+	}
+
+	t.Run("CreateImageUploadURL ok", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		mockUC.EXPECT().CreateImageUploadURL(gomock.Any(), 1, 10).Return(domain.ImageUploadURL{
+			URL:        "http://minio/offers",
+			Fields:     map[string]string{"key": "pending/1/abc", "x-amz-signature": "sig"},
+			ObjectName: "pending/1/abc",
+			ExpiresAt:  time.Now().Add(15 * time.Minute),
+		}, nil)
+
+		handler.CreateImageUploadURL(rec, newRequest(""))
+
+		require.Equal(t, http.StatusCreated, rec.Code)
+		var resp domain.ImageUploadURL
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+		assert.Equal(t, "pending/1/abc", resp.ObjectName)
+		assert.Equal(t, "pending/1/abc", resp.Fields["key"])
+	})
+
+	t.Run("ConfirmImageUpload ok", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		mockUC.EXPECT().ConfirmImageUpload(gomock.Any(), 1, 10, "pending/1/abc").Return(int64(7), nil)
+
+		handler.ConfirmImageUpload(rec, newRequest(`{"object_name":"pending/1/abc"}`))
+
+		assert.Equal(t, http.StatusCreated, rec.Code)
+	})
+
+	t.Run("ConfirmImageUpload without object", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		handler.ConfirmImageUpload(rec, newRequest(`{}`))
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("ConfirmImageUpload invalid image", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		mockUC.EXPECT().ConfirmImageUpload(gomock.Any(), 1, 10, "pending/1/abc").Return(int64(0), fmt.Errorf("неверный формат файла"))
+
+		handler.ConfirmImageUpload(rec, newRequest(`{"object_name":"pending/1/abc"}`))
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
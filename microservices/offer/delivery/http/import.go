@@ -0,0 +1,51 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/importer"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/utils"
+)
+
+// ImportOffers Импортирует объявления из файла в поле file. Формат берется из параметра format
+// (csv или yandex), а если он не указан - из расширения файла. Отвечает отчетом по каждой строке
+func (h *OfferHandler) ImportOffers(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(utils.UserIDKey).(int)
+	if !ok {
+		utils.SendErrorResponse(w, "UserID not found", http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+
+	importCfg := h.cfg.App.Import
+	r.Body = http.MaxBytesReader(w, r.Body, importCfg.MaxFileSize)
+	if err := r.ParseMultipartForm(multipartMemory); err != nil {
+		utils.SendErrorResponse(w, "Файл слишком большой или поврежден", http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		utils.SendErrorResponse(w, "Не удалось получить файл", http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+	defer file.Close()
+
+	format := r.FormValue("format")
+	if format == "" {
+		format = importer.DetectFormat(header.Filename)
+	}
+
+	records, err := importer.Parse(format, file, importCfg.MaxRows)
+	if err != nil {
+		utils.SendErrorResponse(w, err.Error(), http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+
+	report, err := h.OfferUC.ImportOffers(r.Context(), userID, records)
+	if err != nil {
+		utils.SendErrorResponse(w, "Ошибка при импорте объявлений", http.StatusInternalServerError, &h.cfg.App.CORS)
+		return
+	}
+
+	utils.SendJSONResponse(w, report, http.StatusOK, &h.cfg.App.CORS)
+}
@@ -0,0 +1,83 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-park-mail-ru/2025_1_404/config"
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/domain"
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/mocks"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/utils"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportOffersHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUC := mocks.NewMockOfferUsecase(ctrl)
+	cfg := &config.Config{
+		App: config.AppConfig{
+			CORS:   config.CORSConfig{AllowOrigin: "*"},
+			Import: config.ImportConfig{MaxFileSize: 1 << 20, MaxRows: 10},
+		},
+	}
+	handler := NewOfferHandler(mockUC, cfg)
+	ctx := context.WithValue(context.Background(), utils.UserIDKey, 10)
+
+	newRequest := func(filename string, data string, format string) *http.Request {
+		body := new(bytes.Buffer)
+		writer := multipart.NewWriter(body)
+		part, err := writer.CreateFormFile("file", filename)
+		require.NoError(t, err)
+		_, err = part.Write([]byte(data))
+		require.NoError(t, err)
+		if format != "" {
+			require.NoError(t, writer.WriteField("format", format))
+		}
+		require.NoError(t, writer.Close())
+
+		req := httptest.NewRequest(http.MethodPost, "/offers/import", body).WithContext(ctx)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		return req
+	}
+
+	csvData := "external_id,offer_type,property_type,renovation,address\nA-1,Продажа,Квартира,Современный ремонт,Москва\n"
+
+	t.Run("csv by extension", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		mockUC.EXPECT().ImportOffers(gomock.Any(), 10, gomock.Len(1)).
+			Return(domain.ImportReport{Created: 1, Rows: []domain.ImportRowResult{{Row: 2, ExternalID: "A-1", OfferID: 5, Action: domain.ImportActionCreated}}}, nil)
+
+		handler.ImportOffers(rec, newRequest("offers.csv", csvData, ""))
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		var resp domain.ImportReport
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+		assert.Equal(t, 1, resp.Created)
+		assert.Equal(t, 5, resp.Rows[0].OfferID)
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		handler.ImportOffers(rec, newRequest("offers.txt", csvData, ""))
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("broken feed", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		handler.ImportOffers(rec, newRequest("offers", "<realty-feed><offer>", domain.ImportFormatYandex))
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
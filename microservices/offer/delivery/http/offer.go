@@ -1,16 +1,17 @@
 package http
 
 import (
-	"bytes"
+	"fmt"
 	"io"
 	"net"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/go-park-mail-ru/2025_1_404/config"
 	"github.com/go-park-mail-ru/2025_1_404/microservices/offer"
-	"github.com/go-park-mail-ru/2025_1_404/pkg/content"
-	"github.com/go-park-mail-ru/2025_1_404/pkg/database/s3"
 
 	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/domain"
 	"github.com/go-park-mail-ru/2025_1_404/pkg/utils"
@@ -26,18 +27,45 @@ func NewOfferHandler(uc offer.OfferUsecase, cfg *config.Config) *OfferHandler {
 	return &OfferHandler{OfferUC: uc, cfg: cfg}
 }
 
-func parseOfferFilter(r *http.Request) domain.OfferFilter {
-	q := r.URL.Query()
+// parseOfferFilter Разбирает параметры фильтра ленты.
+// Списки передаются через запятую (rooms=2,3), некорректные значения возвращаются ошибкой
+func parseOfferFilter(q url.Values) (domain.OfferFilter, error) {
+	var parseErr error
+	fail := func(key string) {
+		if parseErr == nil {
+			parseErr = fmt.Errorf("некорректное значение параметра %s", key)
+		}
+	}
 
 	getInt := func(key string) *int {
 		if val := q.Get(key); val != "" {
-			if parsed, err := strconv.Atoi(val); err == nil {
-				return &parsed
+			parsed, err := strconv.Atoi(val)
+			if err != nil || parsed < 0 {
+				fail(key)
+				return nil
 			}
+			return &parsed
 		}
 		return nil
 	}
 
+	getInts := func(key string) []int {
+		val := q.Get(key)
+		if val == "" {
+			return nil
+		}
+		var values []int
+		for _, part := range strings.Split(val, ",") {
+			parsed, err := strconv.Atoi(strings.TrimSpace(part))
+			if err != nil || parsed < 0 {
+				fail(key)
+				return nil
+			}
+			values = append(values, parsed)
+		}
+		return values
+	}
+
 	getString := func(key string) *string {
 		if val := q.Get(key); val != "" {
 			return &val
@@ -54,62 +82,239 @@ func parseOfferFilter(r *http.Request) domain.OfferFilter {
 				b := false
 				return &b
 			}
+			fail(key)
 		}
 		return nil
 	}
 
-	return domain.OfferFilter{
-		MinArea:        getInt("min_area"),
-		MaxArea:        getInt("max_area"),
-		MinPrice:       getInt("min_price"),
-		MaxPrice:       getInt("max_price"),
-		Floor:          getInt("floor"),
-		Rooms:          getInt("rooms"),
-		Address:        getString("address"),
-		RenovationID:   getInt("renovation_id"),
-		PropertyTypeID: getInt("property_type_id"),
-		PurchaseTypeID: getInt("purchase_type_id"),
-		RentTypeID:     getInt("rent_type_id"),
-		OfferTypeID:    getInt("offer_type_id"),
-		NewBuilding:    getBool("new_building"),
-		SellerID:       getInt("seller_id"),
-		OnlyMe:         getBool("me"),
+	isTrue := func(key string) bool {
+		b := getBool(key)
+		return b != nil && *b
+	}
+
+	filter := domain.OfferFilter{
+		MinArea:          getInt("min_area"),
+		MaxArea:          getInt("max_area"),
+		MinPrice:         getInt("min_price"),
+		MaxPrice:         getInt("max_price"),
+		Floor:            getInt("floor"),
+		FloorMin:         getInt("floor_min"),
+		FloorMax:         getInt("floor_max"),
+		NotFirstFloor:    isTrue("not_first_floor"),
+		NotLastFloor:     isTrue("not_last_floor"),
+		Rooms:            getInts("rooms"),
+		Address:          getString("address"),
+		RenovationID:     getInts("renovation_id"),
+		PropertyTypeID:   getInts("property_type_id"),
+		PurchaseTypeID:   getInts("purchase_type_id"),
+		RentTypeID:       getInts("rent_type_id"),
+		OfferTypeID:      getInt("offer_type_id"),
+		MetroStationID:   getInts("metro_station_id"),
+		ComplexID:        getInt("complex_id"),
+		MinCeilingHeight: getInt("min_ceiling_height"),
+		NewBuilding:      getBool("new_building"),
+		PriceDropped:     getBool("price_dropped"),
+		SellerID:         getInt("seller_id"),
+		OnlyMe:           getBool("me"),
+		Query:            getString("q"),
+		Sort:             domain.OfferSort(q.Get("sort")),
+	}
+	if parseErr != nil {
+		return filter, parseErr
+	}
+
+	for _, rng := range []struct {
+		name     string
+		min, max *int
+	}{
+		{"area", filter.MinArea, filter.MaxArea},
+		{"price", filter.MinPrice, filter.MaxPrice},
+		{"floor", filter.FloorMin, filter.FloorMax},
+	} {
+		if rng.min != nil && rng.max != nil && *rng.min > *rng.max {
+			return filter, fmt.Errorf("некорректный диапазон %s: минимум больше максимума", rng.name)
+		}
+	}
+
+	if filter.Query != nil {
+		if utf8.RuneCountInString(*filter.Query) > maxSearchQueryLength {
+			return filter, fmt.Errorf("слишком длинный поисковый запрос")
+		}
+		// по умолчанию результаты поиска упорядочены по релевантности
+		if filter.Sort == "" {
+			filter.Sort = domain.SortRelevance
+		}
+	}
+	if !filter.Sort.IsValid() || (filter.Sort == domain.SortRelevance && filter.Query == nil) {
+		return filter, fmt.Errorf("некорректная сортировка")
+	}
+
+	if err := parseGeoFilter(q, &filter); err != nil {
+		return filter, err
 	}
+
+	return filter, nil
+}
+
+// maxSearchQueryLength Ограничение длины поискового запроса q
+const maxSearchQueryLength = 256
+
+// parseCoordinates Разбирает список координат через запятую
+func parseCoordinates(val string, n int) ([]float64, bool) {
+	parts := strings.Split(val, ",")
+	if len(parts) != n {
+		return nil, false
+	}
+
+	coords := make([]float64, 0, n)
+	for _, part := range parts {
+		c, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return nil, false
+		}
+		coords = append(coords, c)
+	}
+	return coords, true
+}
+
+func validPoint(longitude, latitude float64) bool {
+	return longitude >= -180 && longitude <= 180 && latitude >= -90 && latitude <= 90
+}
+
+// parseGeoFilter Разбирает поиск по карте: bbox=minLon,minLat,maxLon,maxLat
+// или near=lon,lat и radius в метрах
+func parseGeoFilter(q url.Values, filter *domain.OfferFilter) error {
+	if val := q.Get("bbox"); val != "" {
+		c, ok := parseCoordinates(val, 4)
+		if !ok || !validPoint(c[0], c[1]) || !validPoint(c[2], c[3]) || c[0] > c[2] || c[1] > c[3] {
+			return fmt.Errorf("некорректный bbox")
+		}
+		filter.BBox = &domain.BBox{MinLongitude: c[0], MinLatitude: c[1], MaxLongitude: c[2], MaxLatitude: c[3]}
+	}
+
+	if val := q.Get("near"); val != "" {
+		c, ok := parseCoordinates(val, 2)
+		if !ok || !validPoint(c[0], c[1]) {
+			return fmt.Errorf("некорректная точка near")
+		}
+		filter.Near = &domain.GeoPoint{Longitude: c[0], Latitude: c[1]}
+	}
+
+	if val := q.Get("radius"); val != "" {
+		if filter.Near == nil {
+			return fmt.Errorf("radius задается вместе с near")
+		}
+		radius, err := strconv.Atoi(val)
+		if err != nil || radius <= 0 || radius > domain.MaxSearchRadius {
+			return fmt.Errorf("некорректный radius")
+		}
+		filter.Radius = &radius
+	}
+
+	return nil
+}
+
+func parsePagination(r *http.Request) (domain.Pagination, error) {
+	q := r.URL.Query()
+
+	page := domain.Pagination{Limit: domain.DefaultPageLimit}
+
+	if val := q.Get("limit"); val != "" {
+		limit, err := strconv.Atoi(val)
+		if err != nil || limit <= 0 {
+			return page, fmt.Errorf("некорректный limit")
+		}
+		page.Limit = min(limit, domain.MaxPageLimit)
+	}
+
+	if val := q.Get("cursor"); val != "" {
+		cursor, err := domain.DecodeCursor(val)
+		if err != nil {
+			return page, err
+		}
+		page.Cursor = &cursor
+	}
+
+	return page, nil
 }
 
 func hasFilter(f domain.OfferFilter) bool {
 	return f.MinArea != nil || f.MaxArea != nil ||
 		f.MinPrice != nil || f.MaxPrice != nil ||
-		f.Floor != nil || f.Rooms != nil || f.Address != nil ||
+		f.Floor != nil || f.FloorMin != nil || f.FloorMax != nil ||
+		f.NotFirstFloor || f.NotLastFloor ||
+		f.Rooms != nil || f.Address != nil ||
 		f.RenovationID != nil || f.PropertyTypeID != nil ||
 		f.PurchaseTypeID != nil || f.RentTypeID != nil ||
-		f.OfferTypeID != nil || f.NewBuilding != nil || f.SellerID != nil || f.OnlyMe != nil
+		f.MetroStationID != nil || f.ComplexID != nil || f.MinCeilingHeight != nil ||
+		f.OfferTypeID != nil || f.NewBuilding != nil || f.PriceDropped != nil || f.SellerID != nil || f.OnlyMe != nil ||
+		f.Sort != "" || f.BBox != nil || f.Near != nil || f.Query != nil
 }
 
 func (h *OfferHandler) GetOffersHandler(w http.ResponseWriter, r *http.Request) {
 	userID, _ := r.Context().Value(utils.SoftUserIDKey).(*int)
-	filter := parseOfferFilter(r)
+	filter, err := parseOfferFilter(r.URL.Query())
+	if err != nil {
+		utils.SendErrorResponse(w, err.Error(), http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+
+	page, err := parsePagination(r)
+	if err != nil {
+		utils.SendErrorResponse(w, err.Error(), http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+	// курсор с другой страницы ленты нельзя применить к другой сортировке
+	if page.Cursor != nil && page.Cursor.Sort.OrDefault() != filter.Sort.OrDefault() {
+		utils.SendErrorResponse(w, "Курсор не соответствует сортировке", http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
 
 	// если хотя бы один фильтр задан — ищем по фильтру
 	if hasFilter(filter) {
-		offers, err := h.OfferUC.GetOffersByFilter(r.Context(), filter, userID)
+		offers, err := h.OfferUC.GetOffersByFilter(r.Context(), filter, page, userID)
 		if err != nil {
 			utils.SendErrorResponse(w, "Ошибка при фильтрации объявлений", http.StatusInternalServerError, &h.cfg.App.CORS)
 			return
 		}
-		var offersInfo domain.OffersInfo = offers
-		utils.SendJSONResponse(w, offersInfo, http.StatusOK, &h.cfg.App.CORS)
+		utils.SendJSONResponse(w, offers, http.StatusOK, &h.cfg.App.CORS)
 		return
 	}
 
 	// иначе — возвращаем все
-	offers, err := h.OfferUC.GetOffers(r.Context(), userID)
+	offers, err := h.OfferUC.GetOffers(r.Context(), page, userID)
 	if err != nil {
 		utils.SendErrorResponse(w, "Ошибка при получении объявлений", http.StatusInternalServerError, &h.cfg.App.CORS)
 		return
 	}
-	var offersInfo domain.OffersInfo = offers
-	utils.SendJSONResponse(w, offersInfo, http.StatusOK, &h.cfg.App.CORS)
+	utils.SendJSONResponse(w, offers, http.StatusOK, &h.cfg.App.CORS)
+}
+
+func (h *OfferHandler) GetOfferClusters(w http.ResponseWriter, r *http.Request) {
+	userID, _ := r.Context().Value(utils.SoftUserIDKey).(*int)
+	filter, err := parseOfferFilter(r.URL.Query())
+	if err != nil {
+		utils.SendErrorResponse(w, err.Error(), http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+	if filter.BBox == nil {
+		utils.SendErrorResponse(w, "Не указана область карты bbox", http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+
+	zoom, err := strconv.Atoi(r.URL.Query().Get("zoom"))
+	if err != nil || zoom < domain.MinClusterZoom || zoom > domain.MaxClusterZoom {
+		utils.SendErrorResponse(w, "Некорректный zoom", http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+
+	clusters, err := h.OfferUC.GetOfferClusters(r.Context(), filter, zoom, userID)
+	if err != nil {
+		utils.SendErrorResponse(w, "Ошибка при получении кластеров", http.StatusInternalServerError, &h.cfg.App.CORS)
+		return
+	}
+
+	utils.SendJSONResponse(w, clusters, http.StatusOK, &h.cfg.App.CORS)
 }
 
 func (h *OfferHandler) GetOfferByID(w http.ResponseWriter, r *http.Request) {
@@ -157,6 +362,9 @@ func (h *OfferHandler) CreateOffer(w http.ResponseWriter, r *http.Request) {
 
 	id, err := h.OfferUC.CreateOffer(r.Context(), offer)
 	if err != nil {
+		if h.sendDuplicateError(w, err) || h.sendValidationError(w, err) {
+			return
+		}
 		utils.SendErrorResponse(w, "Ошибка при создании", http.StatusInternalServerError, &h.cfg.App.CORS)
 		return
 	}
@@ -196,6 +404,9 @@ func (h *OfferHandler) UpdateOffer(w http.ResponseWriter, r *http.Request) {
 	offer.SellerID = userID // Защита от подмены
 
 	if err := h.OfferUC.UpdateOffer(r.Context(), offer); err != nil {
+		if h.sendValidationError(w, err) {
+			return
+		}
 		utils.SendErrorResponse(w, "Ошибка при обновлении", http.StatusInternalServerError, &h.cfg.App.CORS)
 		return
 	}
@@ -249,6 +460,9 @@ func (h *OfferHandler) PublishOffer(w http.ResponseWriter, r *http.Request) {
 
 	err = h.OfferUC.PublishOffer(r.Context(), offerID, userID)
 	if err != nil {
+		if h.sendDuplicateError(w, err) || h.sendValidationError(w, err) {
+			return
+		}
 		utils.SendErrorResponse(w, err.Error(), http.StatusBadRequest, &h.cfg.App.CORS)
 		return
 	}
@@ -284,26 +498,12 @@ func (h *OfferHandler) UploadOfferImage(w http.ResponseWriter, r *http.Request)
 	}
 	defer file.Close()
 
-	fileBytes, err := io.ReadAll(file)
-	if err != nil {
-		utils.SendErrorResponse(w, "Не удалось прочитать файл", http.StatusBadRequest, &h.cfg.App.CORS)
-		return
-	}
-
-	contentType, err := content.CheckImage(fileBytes)
+	upload, err := readImageFile(file, header.Filename)
 	if err != nil {
-		utils.SendErrorResponse(w, "Недопустимый формат изображения", http.StatusBadRequest, &h.cfg.App.CORS)
+		utils.SendErrorResponse(w, err.Error(), http.StatusBadRequest, &h.cfg.App.CORS)
 		return
 	}
 
-	upload := s3.Upload{
-		Bucket:      "offers",
-		Filename:    header.Filename,
-		Size:        header.Size,
-		File:        bytes.NewReader(fileBytes),
-		ContentType: contentType,
-	}
-
 	imageID, err := h.OfferUC.SaveOfferImage(r.Context(), offerID, upload)
 	if err != nil {
 		utils.SendErrorResponse(w, "Ошибка при сохранении", http.StatusInternalServerError, &h.cfg.App.CORS)
@@ -413,14 +613,29 @@ func (h *OfferHandler) GetFavorites(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	favorites, err := h.OfferUC.GetFavorites(r.Context(), userID, offerTypeID)
+	var collectionID *int
+	if val := r.URL.Query().Get("collection_id"); val != "" {
+		parsed, err := strconv.Atoi(val)
+		if err != nil || parsed <= 0 {
+			utils.SendErrorResponse(w, "Некорректный ID подборки", http.StatusBadRequest, &h.cfg.App.CORS)
+			return
+		}
+		collectionID = &parsed
+	}
+
+	page, err := parsePagination(r)
+	if err != nil {
+		utils.SendErrorResponse(w, err.Error(), http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+
+	favorites, err := h.OfferUC.GetFavorites(r.Context(), userID, offerTypeID, collectionID, page)
 	if err != nil {
 		utils.SendErrorResponse(w, "Ошибка при получении избранных", http.StatusInternalServerError, &h.cfg.App.CORS)
 		return
 	}
 
-	var favoritesResp domain.OffersInfo = favorites
-	utils.SendJSONResponse(w, favoritesResp, http.StatusOK, &h.cfg.App.CORS)
+	utils.SendJSONResponse(w, favorites, http.StatusOK, &h.cfg.App.CORS)
 }
 
 func (h *OfferHandler) PromoteCheckOffer(w http.ResponseWriter, r *http.Request) {
@@ -11,6 +11,7 @@ import (
 	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/go-park-mail-ru/2025_1_404/config"
@@ -57,14 +58,15 @@ func TestGetOffersHandler(t *testing.T) {
 	//})
 
 	t.Run("GetOffersWithFilter ok", func(t *testing.T) {
-		expectedOffers := []domain.OfferInfo{
-			{Offer: domain.Offer{ID: 1}},
+		expectedOffers := domain.OffersPage{
+			Offers: domain.OffersInfo{{Offer: domain.Offer{ID: 1}}},
+			Total:  1,
 		}
 
 		minPrice := 1000000
 		mockUC.EXPECT().GetOffersByFilter(gomock.Any(), gomock.Eq(domain.OfferFilter{
 			MinPrice: &minPrice,
-		}), gomock.Any()).Return(expectedOffers, nil)
+		}), gomock.Eq(domain.Pagination{Limit: domain.DefaultPageLimit}), gomock.Any()).Return(expectedOffers, nil)
 
 		request := httptest.NewRequest(http.MethodGet, "/offers?min_price=1000000", nil)
 		response := httptest.NewRecorder()
@@ -75,7 +77,7 @@ func TestGetOffersHandler(t *testing.T) {
 	})
 
 	t.Run("GerOffers error", func(t *testing.T) {
-		mockUC.EXPECT().GetOffers(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("filter error"))
+		mockUC.EXPECT().GetOffers(gomock.Any(), gomock.Any(), gomock.Any()).Return(domain.OffersPage{}, fmt.Errorf("filter error"))
 
 		request := httptest.NewRequest(http.MethodGet, "/offers", nil)
 		response := httptest.NewRecorder()
@@ -92,8 +94,8 @@ func TestGetOffersHandler(t *testing.T) {
 
 	t.Run("GetOffersByFilter error", func(t *testing.T) {
 		mockUC.EXPECT().
-			GetOffersByFilter(gomock.Any(), gomock.Any(), gomock.Any()).
-			Return(nil, fmt.Errorf("filter error"))
+			GetOffersByFilter(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(domain.OffersPage{}, fmt.Errorf("filter error"))
 
 		request := httptest.NewRequest(http.MethodGet, "/offers?min_price=1000000", nil)
 		response := httptest.NewRecorder()
@@ -107,6 +109,93 @@ func TestGetOffersHandler(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, "Ошибка при фильтрации объявлений", errResp["error"])
 	})
+
+	t.Run("GetOffers with cursor", func(t *testing.T) {
+		cursor, err := domain.EncodeCursor(domain.Cursor{ID: 42})
+		assert.NoError(t, err)
+
+		mockUC.EXPECT().
+			GetOffers(gomock.Any(), gomock.Eq(domain.Pagination{Limit: domain.MaxPageLimit, Cursor: &domain.Cursor{ID: 42}}), gomock.Any()).
+			Return(domain.OffersPage{}, nil)
+
+		request := httptest.NewRequest(http.MethodGet, "/offers?limit=500&cursor="+cursor, nil)
+		response := httptest.NewRecorder()
+
+		offerHandlers.GetOffersHandler(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Result().StatusCode)
+	})
+
+	t.Run("GetOffers near point", func(t *testing.T) {
+		radius := 2000
+		mockUC.EXPECT().
+			GetOffersByFilter(gomock.Any(), gomock.Eq(domain.OfferFilter{
+				Near:   &domain.GeoPoint{Longitude: 37.6173, Latitude: 55.7558},
+				Radius: &radius,
+			}), gomock.Any(), gomock.Any()).
+			Return(domain.OffersPage{}, nil)
+
+		request := httptest.NewRequest(http.MethodGet, "/offers?near=37.6173,55.7558&radius=2000", nil)
+		response := httptest.NewRecorder()
+
+		offerHandlers.GetOffersHandler(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Result().StatusCode)
+	})
+
+	t.Run("GetOffers multi-value and range filters", func(t *testing.T) {
+		floorMin, floorMax, ceiling := 3, 10, 3
+		mockUC.EXPECT().
+			GetOffersByFilter(gomock.Any(), gomock.Eq(domain.OfferFilter{
+				Rooms:            []int{2, 3},
+				MetroStationID:   []int{1, 5, 9},
+				FloorMin:         &floorMin,
+				FloorMax:         &floorMax,
+				NotFirstFloor:    true,
+				NotLastFloor:     true,
+				MinCeilingHeight: &ceiling,
+			}), gomock.Any(), gomock.Any()).
+			Return(domain.OffersPage{}, nil)
+
+		request := httptest.NewRequest(http.MethodGet,
+			"/offers?rooms=2,3&metro_station_id=1,5,9&floor_min=3&floor_max=10&not_first_floor=true&not_last_floor=true&min_ceiling_height=3", nil)
+		response := httptest.NewRecorder()
+
+		offerHandlers.GetOffersHandler(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Result().StatusCode)
+	})
+
+	t.Run("GetOffers search is ranked by relevance", func(t *testing.T) {
+		query := "студия"
+		mockUC.EXPECT().
+			GetOffersByFilter(gomock.Any(), gomock.Eq(domain.OfferFilter{Query: &query, Sort: domain.SortRelevance}), gomock.Any(), gomock.Any()).
+			Return(domain.OffersPage{}, nil)
+
+		request := httptest.NewRequest(http.MethodGet, "/offers?q=%D1%81%D1%82%D1%83%D0%B4%D0%B8%D1%8F", nil)
+		response := httptest.NewRecorder()
+
+		offerHandlers.GetOffersHandler(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Result().StatusCode)
+	})
+
+	t.Run("invalid query params", func(t *testing.T) {
+		priceCursor, err := domain.EncodeCursor(domain.Cursor{ID: 3, Sort: domain.SortPriceAsc, Value: new(float64)})
+		assert.NoError(t, err)
+
+		for _, query := range []string{"limit=abc", "limit=0", "cursor=!!!", "sort=cheap", "sort=area_desc&cursor=" + priceCursor,
+			"bbox=37,55,38", "bbox=38,55,37,56", "near=200,55", "radius=500", "near=37.6,55.7&radius=100000",
+			"sort=relevance", "q=" + strings.Repeat("a", 257),
+			"rooms=2,x", "min_price=abc", "floor_min=-1", "new_building=yes", "min_area=80&max_area=40", "floor_min=10&floor_max=3"} {
+			request := httptest.NewRequest(http.MethodGet, "/offers?"+query, nil)
+			response := httptest.NewRecorder()
+
+			offerHandlers.GetOffersHandler(response, request)
+
+			assert.Equal(t, http.StatusBadRequest, response.Result().StatusCode, query)
+		}
+	})
 }
 
 //func TestGetOfferByID(t *testing.T) {
@@ -1124,6 +1213,54 @@ func TestPromoteCheckOffer(t *testing.T) {
     })
 }
 
+func TestGetOfferClusters(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUC := mocks.NewMockOfferUsecase(ctrl)
+	cfg := &config.Config{}
+	handler := NewOfferHandler(mockUC, cfg)
+
+	t.Run("GetOfferClusters ok", func(t *testing.T) {
+		filter := domain.OfferFilter{
+			Rooms: []int{2},
+			BBox:  &domain.BBox{MinLongitude: 37, MinLatitude: 55, MaxLongitude: 38, MaxLatitude: 56},
+		}
+		mockUC.EXPECT().GetOfferClusters(gomock.Any(), filter, 12, gomock.Any()).
+			Return(domain.OfferClusters{{Count: 2, OfferID: 1}}, nil)
+
+		request := httptest.NewRequest(http.MethodGet, "/offers/clusters?bbox=37,55,38,56&zoom=12&rooms=2", nil)
+		response := httptest.NewRecorder()
+
+		handler.GetOfferClusters(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+
+	t.Run("GetOfferClusters bad request", func(t *testing.T) {
+		for _, query := range []string{"zoom=12", "bbox=37,55,38,56", "bbox=37,55,38,56&zoom=30", "bbox=1,2&zoom=3"} {
+			request := httptest.NewRequest(http.MethodGet, "/offers/clusters?"+query, nil)
+			response := httptest.NewRecorder()
+
+			handler.GetOfferClusters(response, request)
+
+			assert.Equal(t, http.StatusBadRequest, response.Code, query)
+		}
+	})
+
+	t.Run("GetOfferClusters error", func(t *testing.T) {
+		mockUC.EXPECT().GetOfferClusters(gomock.Any(), gomock.Any(), 5, gomock.Any()).
+			Return(nil, fmt.Errorf("db error"))
+
+		request := httptest.NewRequest(http.MethodGet, "/offers/clusters?bbox=37,55,38,56&zoom=5", nil)
+		response := httptest.NewRecorder()
+
+		handler.GetOfferClusters(response, request)
+
+		assert.Equal(t, http.StatusInternalServerError, response.Code)
+	})
+}
+
 func TestFavoriteOffer(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -0,0 +1,26 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-park-mail-ru/2025_1_404/pkg/utils"
+	"github.com/gorilla/mux"
+)
+
+// GetOfferPrices Полная история цены объявления с изменениями между записями
+func (h *OfferHandler) GetOfferPrices(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || id <= 0 {
+		utils.SendErrorResponse(w, "Некорректный ID", http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+
+	prices, err := h.OfferUC.GetOfferPrices(r.Context(), id)
+	if err != nil {
+		utils.SendErrorResponse(w, "Объявление не найдено", http.StatusNotFound, &h.cfg.App.CORS)
+		return
+	}
+
+	utils.SendJSONResponse(w, prices, http.StatusOK, &h.cfg.App.CORS)
+}
@@ -0,0 +1,60 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-park-mail-ru/2025_1_404/config"
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/domain"
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetOfferPricesHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUC := mocks.NewMockOfferUsecase(ctrl)
+	cfg := &config.Config{
+		App: config.AppConfig{
+			CORS: config.CORSConfig{AllowOrigin: "*"},
+		},
+	}
+	handler := NewOfferHandler(mockUC, cfg)
+
+	newRequest := func(id string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/offers/"+id+"/prices", nil)
+		return mux.SetURLVars(req, map[string]string{"id": id})
+	}
+
+	t.Run("ok", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		mockUC.EXPECT().GetOfferPrices(gomock.Any(), 1).Return(domain.OfferPrices{OfferID: 1, PriceDrop: true}, nil)
+
+		handler.GetOfferPrices(rec, newRequest("1"))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"price_drop":true`)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		mockUC.EXPECT().GetOfferPrices(gomock.Any(), 2).Return(domain.OfferPrices{}, fmt.Errorf("объявление не найдено"))
+
+		handler.GetOfferPrices(rec, newRequest("2"))
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("invalid id", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		handler.GetOfferPrices(rec, newRequest("0"))
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
@@ -0,0 +1,50 @@
+package http
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/domain"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/utils"
+)
+
+// GetPriceAlertSettings Настройки уведомлений о снижении цены избранных объявлений
+func (h *OfferHandler) GetPriceAlertSettings(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(utils.UserIDKey).(int)
+	if !ok {
+		utils.SendErrorResponse(w, "UserID not found", http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+
+	settings, err := h.OfferUC.GetPriceAlertSettings(r.Context(), userID)
+	if err != nil {
+		utils.SendErrorResponse(w, "Ошибка при получении настроек", http.StatusInternalServerError, &h.cfg.App.CORS)
+		return
+	}
+
+	utils.SendJSONResponse(w, settings, http.StatusOK, &h.cfg.App.CORS)
+}
+
+// UpdatePriceAlertSettings Отписка от уведомлений и собственный порог снижения цены в процентах
+func (h *OfferHandler) UpdatePriceAlertSettings(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(utils.UserIDKey).(int)
+	if !ok {
+		utils.SendErrorResponse(w, "UserID not found", http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+
+	var settings domain.PriceAlertSettings
+	data, _ := io.ReadAll(r.Body)
+	if err := settings.UnmarshalJSON(data); err != nil {
+		utils.SendErrorResponse(w, "Ошибка в теле запроса", http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+
+	updated, err := h.OfferUC.UpdatePriceAlertSettings(r.Context(), userID, settings)
+	if err != nil {
+		utils.SendErrorResponse(w, err.Error(), http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+
+	utils.SendJSONResponse(w, updated, http.StatusOK, &h.cfg.App.CORS)
+}
@@ -0,0 +1,82 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-park-mail-ru/2025_1_404/config"
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/domain"
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/mocks"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/utils"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPriceAlertSettingsHandlers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUC := mocks.NewMockOfferUsecase(ctrl)
+	cfg := &config.Config{
+		App: config.AppConfig{
+			CORS: config.CORSConfig{AllowOrigin: "*"},
+		},
+	}
+	handler := NewOfferHandler(mockUC, cfg)
+	ctx := context.WithValue(context.Background(), utils.UserIDKey, 10)
+	threshold := 5
+
+	t.Run("get", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		mockUC.EXPECT().GetPriceAlertSettings(gomock.Any(), 10).
+			Return(domain.PriceAlertSettings{Enabled: true, DefaultMinDropPercent: 1}, nil)
+
+		handler.GetPriceAlertSettings(rec, httptest.NewRequest(http.MethodGet, "/offers/favorites/price-alerts", nil).WithContext(ctx))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"min_drop_percent":null`)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		settings := domain.PriceAlertSettings{Enabled: true, MinDropPercent: &threshold}
+		mockUC.EXPECT().UpdatePriceAlertSettings(gomock.Any(), 10, settings).Return(settings, nil)
+
+		req := httptest.NewRequest(http.MethodPut, "/offers/favorites/price-alerts", strings.NewReader(`{"enabled":true,"min_drop_percent":5}`))
+		handler.UpdatePriceAlertSettings(rec, req.WithContext(ctx))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("update invalid threshold", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		mockUC.EXPECT().UpdatePriceAlertSettings(gomock.Any(), 10, gomock.Any()).
+			Return(domain.PriceAlertSettings{}, fmt.Errorf("порог снижения цены должен быть от 0 до 100 процентов"))
+
+		req := httptest.NewRequest(http.MethodPut, "/offers/favorites/price-alerts", strings.NewReader(`{"enabled":true,"min_drop_percent":150}`))
+		handler.UpdatePriceAlertSettings(rec, req.WithContext(ctx))
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("invalid body", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		req := httptest.NewRequest(http.MethodPut, "/offers/favorites/price-alerts", strings.NewReader(`{"enabled":`))
+		handler.UpdatePriceAlertSettings(rec, req.WithContext(ctx))
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("no user", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		handler.GetPriceAlertSettings(rec, httptest.NewRequest(http.MethodGet, "/offers/favorites/price-alerts", nil))
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
@@ -0,0 +1,104 @@
+package http
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/domain"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/utils"
+	"github.com/gorilla/mux"
+)
+
+func (h *OfferHandler) CreateSavedSearch(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(utils.UserIDKey).(int)
+	if !ok {
+		utils.SendErrorResponse(w, "UserID not found", http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+
+	var req domain.SavedSearchRequest
+	data, _ := io.ReadAll(r.Body)
+	if err := req.UnmarshalJSON(data); err != nil {
+		utils.SendErrorResponse(w, "Ошибка в теле запроса", http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" || utf8.RuneCountInString(req.Name) > domain.MaxSavedSearchNameLength {
+		utils.SendErrorResponse(w, "Некорректное название поиска", http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+	if req.Frequency == "" {
+		req.Frequency = domain.FrequencyDaily
+	}
+	if !req.Frequency.IsValid() {
+		utils.SendErrorResponse(w, "Некорректная частота уведомлений", http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+
+	params, err := url.ParseQuery(strings.TrimPrefix(req.Params, "?"))
+	if err != nil {
+		utils.SendErrorResponse(w, "Некорректные параметры поиска", http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+	filter, err := parseOfferFilter(params)
+	if err != nil {
+		utils.SendErrorResponse(w, err.Error(), http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+
+	search, err := h.OfferUC.CreateSavedSearch(r.Context(), domain.SavedSearch{
+		UserID:    userID,
+		Name:      req.Name,
+		Frequency: req.Frequency,
+		Filter:    filter,
+	})
+	if err != nil {
+		utils.SendErrorResponse(w, "Ошибка при сохранении поиска", http.StatusInternalServerError, &h.cfg.App.CORS)
+		return
+	}
+
+	utils.SendJSONResponse(w, search, http.StatusCreated, &h.cfg.App.CORS)
+}
+
+func (h *OfferHandler) GetSavedSearches(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(utils.UserIDKey).(int)
+	if !ok {
+		utils.SendErrorResponse(w, "UserID not found", http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+
+	searches, err := h.OfferUC.GetSavedSearches(r.Context(), userID)
+	if err != nil {
+		utils.SendErrorResponse(w, "Ошибка при получении сохраненных поисков", http.StatusInternalServerError, &h.cfg.App.CORS)
+		return
+	}
+
+	utils.SendJSONResponse(w, searches, http.StatusOK, &h.cfg.App.CORS)
+}
+
+func (h *OfferHandler) DeleteSavedSearch(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(utils.UserIDKey).(int)
+	if !ok {
+		utils.SendErrorResponse(w, "UserID not found", http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || id <= 0 {
+		utils.SendErrorResponse(w, "Некорректный ID", http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+
+	if err := h.OfferUC.DeleteSavedSearch(r.Context(), id, userID); err != nil {
+		utils.SendErrorResponse(w, err.Error(), http.StatusNotFound, &h.cfg.App.CORS)
+		return
+	}
+
+	msg := utils.MessageResponse{Message: "Удалено"}
+	utils.SendJSONResponse(w, msg, http.StatusOK, &h.cfg.App.CORS)
+}
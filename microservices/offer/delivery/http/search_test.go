@@ -0,0 +1,155 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-park-mail-ru/2025_1_404/config"
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/domain"
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/mocks"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/utils"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateSavedSearch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUS := mocks.NewMockOfferUsecase(ctrl)
+	cfg := &config.Config{
+		App: config.AppConfig{
+			CORS: config.CORSConfig{AllowOrigin: "*"},
+		},
+	}
+	offerHandlers := NewOfferHandler(mockUS, cfg)
+	ctx := context.WithValue(context.Background(), utils.UserIDKey, 1)
+
+	t.Run("CreateSavedSearch ok", func(t *testing.T) {
+		body := `{"name":"Двушки","params":"rooms=2&min_price=100000"}`
+		request := httptest.NewRequest(http.MethodPost, "/searches", strings.NewReader(body)).WithContext(ctx)
+		response := httptest.NewRecorder()
+
+		minPrice := 100000
+		mockUS.EXPECT().CreateSavedSearch(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, s domain.SavedSearch) (domain.SavedSearch, error) {
+				assert.Equal(t, 1, s.UserID)
+				assert.Equal(t, domain.FrequencyDaily, s.Frequency)
+				assert.Equal(t, []int{2}, s.Filter.Rooms)
+				assert.Equal(t, &minPrice, s.Filter.MinPrice)
+				s.ID = 1
+				return s, nil
+			})
+
+		offerHandlers.CreateSavedSearch(response, request)
+
+		assert.Equal(t, http.StatusCreated, response.Result().StatusCode)
+	})
+
+	t.Run("userID not found", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodPost, "/searches", strings.NewReader(`{}`))
+		response := httptest.NewRecorder()
+
+		offerHandlers.CreateSavedSearch(response, request)
+
+		assert.Equal(t, http.StatusBadRequest, response.Result().StatusCode)
+	})
+
+	badBodies := map[string]string{
+		"invalid body":      `{"name":`,
+		"empty name":        `{"name":"  ","params":""}`,
+		"invalid frequency": `{"name":"Поиск","frequency":"hourly"}`,
+		"invalid params":    `{"name":"Поиск","params":"min_price=abc"}`,
+	}
+	for name, body := range badBodies {
+		t.Run(name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodPost, "/searches", strings.NewReader(body)).WithContext(ctx)
+			response := httptest.NewRecorder()
+
+			offerHandlers.CreateSavedSearch(response, request)
+
+			assert.Equal(t, http.StatusBadRequest, response.Result().StatusCode)
+		})
+	}
+
+	t.Run("CreateSavedSearch usecase failed", func(t *testing.T) {
+		body := `{"name":"Поиск","frequency":"weekly","params":""}`
+		request := httptest.NewRequest(http.MethodPost, "/searches", strings.NewReader(body)).WithContext(ctx)
+		response := httptest.NewRecorder()
+
+		mockUS.EXPECT().CreateSavedSearch(gomock.Any(), gomock.Any()).Return(domain.SavedSearch{}, fmt.Errorf("db error"))
+
+		offerHandlers.CreateSavedSearch(response, request)
+
+		assert.Equal(t, http.StatusInternalServerError, response.Result().StatusCode)
+	})
+}
+
+func TestGetSavedSearches(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUS := mocks.NewMockOfferUsecase(ctrl)
+	cfg := &config.Config{
+		App: config.AppConfig{
+			CORS: config.CORSConfig{AllowOrigin: "*"},
+		},
+	}
+	offerHandlers := NewOfferHandler(mockUS, cfg)
+
+	t.Run("GetSavedSearches ok", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), utils.UserIDKey, 1)
+		request := httptest.NewRequest(http.MethodGet, "/searches", nil).WithContext(ctx)
+		response := httptest.NewRecorder()
+
+		mockUS.EXPECT().GetSavedSearches(gomock.Any(), 1).Return(domain.SavedSearches{{ID: 1, Name: "Поиск"}}, nil)
+
+		offerHandlers.GetSavedSearches(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Result().StatusCode)
+		assert.Contains(t, response.Body.String(), `"name":"Поиск"`)
+	})
+}
+
+func TestDeleteSavedSearch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUS := mocks.NewMockOfferUsecase(ctrl)
+	cfg := &config.Config{
+		App: config.AppConfig{
+			CORS: config.CORSConfig{AllowOrigin: "*"},
+		},
+	}
+	offerHandlers := NewOfferHandler(mockUS, cfg)
+	ctx := context.WithValue(context.Background(), utils.UserIDKey, 1)
+
+	t.Run("DeleteSavedSearch ok", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodDelete, "/searches/3", nil).WithContext(ctx)
+		request = mux.SetURLVars(request, map[string]string{"id": "3"})
+		response := httptest.NewRecorder()
+
+		mockUS.EXPECT().DeleteSavedSearch(gomock.Any(), 3, 1).Return(nil)
+
+		offerHandlers.DeleteSavedSearch(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Result().StatusCode)
+	})
+
+	t.Run("search not found", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodDelete, "/searches/3", nil).WithContext(ctx)
+		request = mux.SetURLVars(request, map[string]string{"id": "3"})
+		response := httptest.NewRecorder()
+
+		mockUS.EXPECT().DeleteSavedSearch(gomock.Any(), 3, 1).Return(fmt.Errorf("сохраненный поиск не найден"))
+
+		offerHandlers.DeleteSavedSearch(response, request)
+
+		assert.Equal(t, http.StatusNotFound, response.Result().StatusCode)
+	})
+}
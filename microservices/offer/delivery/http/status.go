@@ -0,0 +1,86 @@
+package http
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/domain"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/utils"
+	"github.com/gorilla/mux"
+)
+
+func (h *OfferHandler) CompleteOffer(w http.ResponseWriter, r *http.Request) {
+	h.changeOfferStatus(w, r, domain.TransitionComplete, "Объявление завершено")
+}
+
+func (h *OfferHandler) UnpublishOffer(w http.ResponseWriter, r *http.Request) {
+	h.changeOfferStatus(w, r, domain.TransitionUnpublish, "Объявление снято с публикации")
+}
+
+func (h *OfferHandler) RepublishOffer(w http.ResponseWriter, r *http.Request) {
+	h.changeOfferStatus(w, r, domain.TransitionRepublish, "Объявление опубликовано повторно")
+}
+
+// changeOfferStatus Общая обработка запросов на смену статуса. Тело с причиной необязательно
+func (h *OfferHandler) changeOfferStatus(w http.ResponseWriter, r *http.Request, transition domain.OfferTransition, message string) {
+	userID, ok := r.Context().Value(utils.UserIDKey).(int)
+	if !ok {
+		utils.SendErrorResponse(w, "UserID not found", http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+
+	offerID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || offerID <= 0 {
+		utils.SendErrorResponse(w, "Некорректный ID", http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+
+	var req domain.StatusChangeRequest
+	if data, _ := io.ReadAll(r.Body); len(data) > 0 {
+		if err := req.UnmarshalJSON(data); err != nil {
+			utils.SendErrorResponse(w, "Ошибка в теле запроса", http.StatusBadRequest, &h.cfg.App.CORS)
+			return
+		}
+	}
+	req.Reason = strings.TrimSpace(req.Reason)
+	if utf8.RuneCountInString(req.Reason) > domain.MaxStatusReasonLength {
+		utils.SendErrorResponse(w, "Слишком длинная причина", http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+
+	if err := h.OfferUC.ChangeOfferStatus(r.Context(), offerID, userID, transition, req.Reason); err != nil {
+		if h.sendDuplicateError(w, err) || h.sendValidationError(w, err) {
+			return
+		}
+		utils.SendErrorResponse(w, err.Error(), http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+
+	msg := utils.MessageResponse{Message: message}
+	utils.SendJSONResponse(w, msg, http.StatusOK, &h.cfg.App.CORS)
+}
+
+func (h *OfferHandler) ExtendOffer(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(utils.UserIDKey).(int)
+	if !ok {
+		utils.SendErrorResponse(w, "UserID not found", http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+
+	offerID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || offerID <= 0 {
+		utils.SendErrorResponse(w, "Некорректный ID", http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+
+	if err := h.OfferUC.ExtendOffer(r.Context(), offerID, userID); err != nil {
+		utils.SendErrorResponse(w, err.Error(), http.StatusBadRequest, &h.cfg.App.CORS)
+		return
+	}
+
+	msg := utils.MessageResponse{Message: "Срок публикации продлен"}
+	utils.SendJSONResponse(w, msg, http.StatusOK, &h.cfg.App.CORS)
+}
@@ -0,0 +1,133 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-park-mail-ru/2025_1_404/config"
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/domain"
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/mocks"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/utils"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChangeOfferStatusHandlers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUC := mocks.NewMockOfferUsecase(ctrl)
+	cfg := &config.Config{
+		App: config.AppConfig{
+			CORS: config.CORSConfig{AllowOrigin: "*"},
+		},
+	}
+	handler := NewOfferHandler(mockUC, cfg)
+	ctx := context.WithValue(context.Background(), utils.UserIDKey, 10)
+
+	newRequest := func(body string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/offers/1/complete", strings.NewReader(body)).WithContext(ctx)
+		return mux.SetURLVars(req, map[string]string{"id": "1"})
+	}
+
+	t.Run("CompleteOffer ok", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		mockUC.EXPECT().ChangeOfferStatus(gomock.Any(), 1, 10, domain.TransitionComplete, "Продано").Return(nil)
+
+		handler.CompleteOffer(rec, newRequest(`{"reason":" Продано "}`))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("UnpublishOffer without body", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		mockUC.EXPECT().ChangeOfferStatus(gomock.Any(), 1, 10, domain.TransitionUnpublish, "").Return(nil)
+
+		handler.UnpublishOffer(rec, newRequest(""))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("RepublishOffer invalid transition", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		mockUC.EXPECT().ChangeOfferStatus(gomock.Any(), 1, 10, domain.TransitionRepublish, "").
+			Return(fmt.Errorf("недопустимое изменение статуса объявления"))
+
+		handler.RepublishOffer(rec, newRequest(""))
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("invalid body", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		handler.CompleteOffer(rec, newRequest(`{"reason":`))
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("reason too long", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		body := fmt.Sprintf(`{"reason":"%s"}`, strings.Repeat("а", domain.MaxStatusReasonLength+1))
+
+		handler.CompleteOffer(rec, newRequest(body))
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("invalid id", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		req := mux.SetURLVars(httptest.NewRequest(http.MethodPost, "/offers/abc/complete", nil).WithContext(ctx), map[string]string{"id": "abc"})
+
+		handler.CompleteOffer(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+func TestExtendOffer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUC := mocks.NewMockOfferUsecase(ctrl)
+	cfg := &config.Config{
+		App: config.AppConfig{
+			CORS: config.CORSConfig{AllowOrigin: "*"},
+		},
+	}
+	handler := NewOfferHandler(mockUC, cfg)
+	ctx := context.WithValue(context.Background(), utils.UserIDKey, 10)
+
+	t.Run("ExtendOffer ok", func(t *testing.T) {
+		req := mux.SetURLVars(httptest.NewRequest(http.MethodPost, "/offers/1/extend", nil).WithContext(ctx), map[string]string{"id": "1"})
+		rec := httptest.NewRecorder()
+		mockUC.EXPECT().ExtendOffer(gomock.Any(), 1, 10).Return(nil)
+
+		handler.ExtendOffer(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("ExtendOffer usecase error", func(t *testing.T) {
+		req := mux.SetURLVars(httptest.NewRequest(http.MethodPost, "/offers/1/extend", nil).WithContext(ctx), map[string]string{"id": "1"})
+		rec := httptest.NewRecorder()
+		mockUC.EXPECT().ExtendOffer(gomock.Any(), 1, 10).Return(fmt.Errorf("продлить можно только активное объявление"))
+
+		handler.ExtendOffer(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("userID not found", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		handler.ExtendOffer(rec, httptest.NewRequest(http.MethodPost, "/offers/1/extend", nil))
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
@@ -0,0 +1,29 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/domain"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/utils"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/validation"
+)
+
+// sendValidationError Отвечает 400 со списком ошибок по полям. Ссылка на несуществующее
+// справочное значение отдается так же, как ошибка одного поля
+func (h *OfferHandler) sendValidationError(w http.ResponseWriter, err error) bool {
+	var fieldErrors validation.FieldErrors
+	var refErr *domain.InvalidReferenceError
+
+	switch {
+	case errors.As(err, &fieldErrors):
+	case errors.As(err, &refErr):
+		fieldErrors = validation.FieldErrors{{Field: refErr.Field, Rule: "dictionary", Message: refErr.Error()}}
+	default:
+		return false
+	}
+
+	resp := domain.ValidationErrorResponse{Error: "Некорректные данные объявления", Fields: fieldErrors}
+	utils.SendJSONResponse(w, resp, http.StatusBadRequest, &h.cfg.App.CORS)
+	return true
+}
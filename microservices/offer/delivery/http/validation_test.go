@@ -0,0 +1,71 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-park-mail-ru/2025_1_404/config"
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/domain"
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/mocks"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/utils"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/validation"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPublishOfferValidation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUC := mocks.NewMockOfferUsecase(ctrl)
+	handler := NewOfferHandler(mockUC, &config.Config{})
+	ctx := context.WithValue(context.Background(), utils.UserIDKey, 10)
+
+	t.Run("field errors", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		mockUC.EXPECT().PublishOffer(gomock.Any(), 7, 10).Return(validation.FieldErrors{
+			{Field: "floor", Rule: "ltefield", Message: "должно быть не больше total_floors"},
+			{Field: "price", Rule: "required", Message: "обязательное поле"},
+		})
+
+		req := mux.SetURLVars(httptest.NewRequest(http.MethodPost, "/offers/7/publish", nil).WithContext(ctx), map[string]string{"id": "7"})
+		handler.PublishOffer(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.JSONEq(t, `{
+			"error": "Некорректные данные объявления",
+			"fields": [
+				{"field": "floor", "rule": "ltefield", "message": "должно быть не больше total_floors"},
+				{"field": "price", "rule": "required", "message": "обязательное поле"}
+			]
+		}`, rec.Body.String())
+	})
+
+	t.Run("other error", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		mockUC.EXPECT().PublishOffer(gomock.Any(), 7, 10).Return(errors.New("объявление уже активно или завершено"))
+
+		req := mux.SetURLVars(httptest.NewRequest(http.MethodPost, "/offers/7/publish", nil).WithContext(ctx), map[string]string{"id": "7"})
+		handler.PublishOffer(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.JSONEq(t, `{"error": "объявление уже активно или завершено"}`, rec.Body.String())
+	})
+
+	t.Run("unknown reference on update", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		mockUC.EXPECT().CheckAccessToOffer(gomock.Any(), 7, 10).Return(nil)
+		mockUC.EXPECT().UpdateOffer(gomock.Any(), gomock.Any()).Return(&domain.InvalidReferenceError{Field: "metro_station_id", ID: 999})
+
+		req := mux.SetURLVars(httptest.NewRequest(http.MethodPut, "/offers/7", strings.NewReader(`{"metro_station_id":999}`)).WithContext(ctx), map[string]string{"id": "7"})
+		handler.UpdateOffer(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), `{"field":"metro_station_id","rule":"dictionary","message":"некорректное значение metro_station_id: 999"}`)
+	})
+}
@@ -0,0 +1,25 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/logger"
+)
+
+// ExpirationWorker Периодически предупреждает продавцов об истечении срока публикации и снимает устаревшие объявления
+type ExpirationWorker struct {
+	OfferUC  offer.OfferUsecase
+	logger   logger.Logger
+	interval time.Duration
+}
+
+func NewExpirationWorker(uc offer.OfferUsecase, l logger.Logger, interval time.Duration) *ExpirationWorker {
+	return &ExpirationWorker{OfferUC: uc, logger: l, interval: interval}
+}
+
+// Run Блокирует до отмены контекста, поэтому запускается в отдельной горутине
+func (w *ExpirationWorker) Run(ctx context.Context) {
+	runPeriodic(ctx, w.logger, "Expiration worker", w.interval, w.OfferUC.ExpireOffers)
+}
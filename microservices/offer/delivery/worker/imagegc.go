@@ -0,0 +1,29 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/logger"
+)
+
+// ImageGCWorker Периодически ищет и удаляет изображения, потерявшие объект в бакете или запись в базе
+type ImageGCWorker struct {
+	OfferUC  offer.OfferUsecase
+	logger   logger.Logger
+	interval time.Duration
+	dryRun   bool
+}
+
+func NewImageGCWorker(uc offer.OfferUsecase, l logger.Logger, interval time.Duration, dryRun bool) *ImageGCWorker {
+	return &ImageGCWorker{OfferUC: uc, logger: l, interval: interval, dryRun: dryRun}
+}
+
+// Run Блокирует до отмены контекста, поэтому запускается в отдельной горутине
+func (w *ImageGCWorker) Run(ctx context.Context) {
+	runPeriodic(ctx, w.logger, "Image GC worker", w.interval, func(ctx context.Context) error {
+		_, err := w.OfferUC.CollectImageGarbage(ctx, w.dryRun)
+		return err
+	})
+}
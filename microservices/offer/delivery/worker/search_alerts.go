@@ -0,0 +1,25 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/logger"
+)
+
+// SearchAlertsWorker Периодически проверяет сохраненные поиски и рассылает уведомления о новых совпадениях
+type SearchAlertsWorker struct {
+	OfferUC  offer.OfferUsecase
+	logger   logger.Logger
+	interval time.Duration
+}
+
+func NewSearchAlertsWorker(uc offer.OfferUsecase, l logger.Logger, interval time.Duration) *SearchAlertsWorker {
+	return &SearchAlertsWorker{OfferUC: uc, logger: l, interval: interval}
+}
+
+// Run Блокирует до отмены контекста, поэтому запускается в отдельной горутине
+func (w *SearchAlertsWorker) Run(ctx context.Context) {
+	runPeriodic(ctx, w.logger, "Search alerts worker", w.interval, w.OfferUC.ProcessSavedSearches)
+}
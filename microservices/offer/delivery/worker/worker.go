@@ -0,0 +1,33 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-park-mail-ru/2025_1_404/pkg/logger"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/utils"
+	"github.com/google/uuid"
+)
+
+// runPeriodic Вызывает job раз в interval до отмены контекста. Каждый запуск получает свой requestID
+func runPeriodic(ctx context.Context, l logger.Logger, name string, interval time.Duration, job func(context.Context) error) {
+	if interval <= 0 {
+		l.Warn(name + ": interval is not set, worker disabled")
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			requestID := uuid.NewString()
+			if err := job(context.WithValue(ctx, utils.RequestIDKey, requestID)); err != nil {
+				l.WithFields(logger.LoggerFields{"requestID": requestID, "err": err.Error()}).Error(name + ": job failed")
+			}
+		}
+	}
+}
@@ -0,0 +1,136 @@
+//go:generate easyjson -all
+
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	StatsBucketDay   = "day"
+	StatsBucketWeek  = "week"
+	StatsBucketMonth = "month"
+
+	statsDateLayout    = "2006-01-02"
+	statsDefaultPeriod = 30
+	statsMaxDays       = 366
+)
+
+var ErrInvalidStatsPeriod = errors.New("некорректный период")
+
+// StatsPeriod Период статистики: From включительно, To не включительно. Bucket - шаг ряда
+type StatsPeriod struct {
+	From   time.Time
+	To     time.Time
+	Bucket string
+}
+
+// ParseStatsPeriod Разбирает параметры from, to (ГГГГ-ММ-ДД, обе даты включительно) и bucket.
+// По умолчанию - последние 30 дней по дням
+func ParseStatsPeriod(from, to, bucket string, now time.Time) (StatsPeriod, error) {
+	period := StatsPeriod{Bucket: bucket}
+	if period.Bucket == "" {
+		period.Bucket = StatsBucketDay
+	}
+	switch period.Bucket {
+	case StatsBucketDay, StatsBucketWeek, StatsBucketMonth:
+	default:
+		return StatsPeriod{}, ErrInvalidStatsPeriod
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	period.To = today.AddDate(0, 0, 1)
+	if to != "" {
+		date, err := time.Parse(statsDateLayout, to)
+		if err != nil {
+			return StatsPeriod{}, ErrInvalidStatsPeriod
+		}
+		period.To = date.AddDate(0, 0, 1)
+	}
+
+	period.From = period.To.AddDate(0, 0, -statsDefaultPeriod)
+	if from != "" {
+		date, err := time.Parse(statsDateLayout, from)
+		if err != nil {
+			return StatsPeriod{}, ErrInvalidStatsPeriod
+		}
+		period.From = date
+	}
+
+	if !period.From.Before(period.To) || period.To.Sub(period.From) > statsMaxDays*24*time.Hour {
+		return StatsPeriod{}, ErrInvalidStatsPeriod
+	}
+
+	return period, nil
+}
+
+//easyjson:json
+type StatsPoint struct {
+	Date      time.Time `json:"date"`
+	Views     int       `json:"views"`
+	Likes     int       `json:"likes"`
+	Favorites int       `json:"favorites"`
+}
+
+//easyjson:json
+type StatsTotals struct {
+	Views     int `json:"views"`
+	Likes     int `json:"likes"`
+	Favorites int `json:"favorites"`
+}
+
+// StatsConversion Доли просмотров, закончившихся лайком и добавлением в избранное
+//
+//easyjson:json
+type StatsConversion struct {
+	LikeRate     float64 `json:"like_rate"`
+	FavoriteRate float64 `json:"favorite_rate"`
+}
+
+// Conversion Считает конверсию по суммам за период, без просмотров она нулевая
+func (t StatsTotals) Conversion() StatsConversion {
+	if t.Views == 0 {
+		return StatsConversion{}
+	}
+	return StatsConversion{
+		LikeRate:     float64(t.Likes) / float64(t.Views),
+		FavoriteRate: float64(t.Favorites) / float64(t.Views),
+	}
+}
+
+//easyjson:json
+type OfferStats struct {
+	OfferID    int             `json:"offer_id"`
+	From       time.Time       `json:"from"`
+	To         time.Time       `json:"to"`
+	Bucket     string          `json:"bucket"`
+	Points     []StatsPoint    `json:"points"`
+	Totals     StatsTotals     `json:"totals"`
+	Conversion StatsConversion `json:"conversion"`
+}
+
+// SellerOfferStats Показатели объявления продавца рядом со средними по похожим объявлениям
+// других продавцов: тот же тип сделки, тип недвижимости и число комнат
+//
+//easyjson:json
+type SellerOfferStats struct {
+	OfferID          int             `json:"offer_id"`
+	StatusID         int             `json:"status_id"`
+	Price            int             `json:"price"`
+	Totals           StatsTotals     `json:"totals"`
+	Conversion       StatsConversion `json:"conversion"`
+	SimilarCount     int             `json:"similar_count"`
+	SimilarViews     float64         `json:"similar_avg_views"`
+	SimilarLikes     float64         `json:"similar_avg_likes"`
+	SimilarFavorites float64         `json:"similar_avg_favorites"`
+	ViewsVsSimilar   *float64        `json:"views_vs_similar"`
+}
+
+//easyjson:json
+type SellerStats struct {
+	From   time.Time          `json:"from"`
+	To     time.Time          `json:"to"`
+	Totals StatsTotals        `json:"totals"`
+	Offers []SellerOfferStats `json:"offers"`
+}
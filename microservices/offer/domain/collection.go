@@ -0,0 +1,57 @@
+//go:generate easyjson -all
+
+package domain
+
+import (
+	"time"
+)
+
+const (
+	MaxCollectionNameLength = 64
+	MaxFavoriteNoteLength   = 1000
+)
+
+// FavoriteCollection Подборка избранного. ShareToken заполнен, пока по подборке
+// открыта ссылка только для чтения
+//
+//easyjson:json
+type FavoriteCollection struct {
+	ID          int       `json:"id"`
+	UserID      int       `json:"-"`
+	Name        string    `json:"name"`
+	IsDefault   bool      `json:"is_default"`
+	ShareToken  *string   `json:"share_token"`
+	OffersCount int       `json:"offers_count"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+//easyjson:json
+type FavoriteCollections []FavoriteCollection
+
+//easyjson:json
+type CollectionRequest struct {
+	Name string `json:"name"`
+}
+
+//easyjson:json
+type CollectionOfferRequest struct {
+	OfferID int `json:"offer_id"`
+}
+
+//easyjson:json
+type MoveCollectionOfferRequest struct {
+	ToCollectionID int `json:"to_collection_id"`
+}
+
+//easyjson:json
+type FavoriteNoteRequest struct {
+	Note string `json:"note"`
+}
+
+// SharedCollection Подборка, открытая по ссылке. Заметки владельца в нее не попадают
+//
+//easyjson:json
+type SharedCollection struct {
+	Name   string     `json:"name"`
+	Offers OffersPage `json:"offers"`
+}
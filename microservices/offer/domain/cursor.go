@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"encoding/base64"
+	"fmt"
+)
+
+// EncodeCursor Кодирует курсор в непрозрачную строку для клиента
+func EncodeCursor(c Cursor) (string, error) {
+	data, err := c.MarshalJSON()
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// DecodeCursor Восстанавливает курсор из строки, полученной от клиента
+func DecodeCursor(s string) (Cursor, error) {
+	var c Cursor
+
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, fmt.Errorf("некорректный курсор")
+	}
+	if err := c.UnmarshalJSON(data); err != nil || c.ID <= 0 {
+		return c, fmt.Errorf("некорректный курсор")
+	}
+	if !c.Sort.IsValid() || (c.Sort.OrDefault() != SortNewest && c.Value == nil) {
+		return c, fmt.Errorf("некорректный курсор")
+	}
+
+	return c, nil
+}
@@ -0,0 +1,101 @@
+//go:generate easyjson -all
+
+package domain
+
+import "fmt"
+
+//easyjson:json
+type DictionaryItem struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+//easyjson:json
+type MetroLine struct {
+	ID       int              `json:"id"`
+	Name     string           `json:"name"`
+	Color    string           `json:"color"`
+	Stations []DictionaryItem `json:"stations"`
+}
+
+// Dictionaries Все справочники, на которые ссылается объявление. Version меняется вместе
+// с содержимым и отдается клиенту как ETag
+//
+//easyjson:json
+type Dictionaries struct {
+	Version               string           `json:"version"`
+	OfferTypes            []DictionaryItem `json:"offer_types"`
+	RentTypes             []DictionaryItem `json:"rent_types"`
+	PurchaseTypes         []DictionaryItem `json:"purchase_types"`
+	PropertyTypes         []DictionaryItem `json:"property_types"`
+	Renovations           []DictionaryItem `json:"renovations"`
+	OfferStatuses         []DictionaryItem `json:"offer_statuses"`
+	HousingComplexClasses []DictionaryItem `json:"housing_complex_classes"`
+	MetroLines            []MetroLine      `json:"metro_lines"`
+}
+
+// InvalidReferenceError Объявление ссылается на значение, которого нет в справочнике
+type InvalidReferenceError struct {
+	Field string
+	ID    int
+}
+
+func (e *InvalidReferenceError) Error() string {
+	return fmt.Sprintf("некорректное значение %s: %d", e.Field, e.ID)
+}
+
+// CheckOfferReferences Проверяет id справочных значений объявления. Необязательные поля
+// проверяются, только если указаны
+func (d Dictionaries) CheckOfferReferences(offer Offer) error {
+	required := []struct {
+		field string
+		id    int
+		items []DictionaryItem
+	}{
+		{"offer_type_id", offer.OfferTypeID, d.OfferTypes},
+		{"property_type_id", offer.PropertyTypeID, d.PropertyTypes},
+		{"renovation_id", offer.RenovationID, d.Renovations},
+	}
+	for _, ref := range required {
+		if !containsDictionaryItem(ref.items, ref.id) {
+			return &InvalidReferenceError{Field: ref.field, ID: ref.id}
+		}
+	}
+
+	optional := []struct {
+		field string
+		id    *int
+		items []DictionaryItem
+	}{
+		{"rent_type_id", offer.RentTypeID, d.RentTypes},
+		{"purchase_type_id", offer.PurchaseTypeID, d.PurchaseTypes},
+	}
+	for _, ref := range optional {
+		if ref.id != nil && !containsDictionaryItem(ref.items, *ref.id) {
+			return &InvalidReferenceError{Field: ref.field, ID: *ref.id}
+		}
+	}
+
+	if offer.MetroStationID != nil && !d.hasMetroStation(*offer.MetroStationID) {
+		return &InvalidReferenceError{Field: "metro_station_id", ID: *offer.MetroStationID}
+	}
+	return nil
+}
+
+func (d Dictionaries) hasMetroStation(id int) bool {
+	for _, line := range d.MetroLines {
+		if containsDictionaryItem(line.Stations, id) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsDictionaryItem(items []DictionaryItem, id int) bool {
+	for _, item := range items {
+		if item.ID == id {
+			return true
+		}
+	}
+	return false
+}
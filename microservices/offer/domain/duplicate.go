@@ -0,0 +1,62 @@
+//go:generate easyjson -all
+
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Признаки, по которым объявление сочтено дубликатом
+const (
+	DuplicateByAttributes = "attributes"
+	DuplicateByImages     = "images"
+)
+
+// RoleModerator Роль пользователя из kvartirum.Users, которой доступна проверка дубликатов
+const RoleModerator = "moderator"
+
+// DuplicateCandidate Параметры объявления, которое проверяется на дубликаты.
+// OfferID исключается из поиска, по его изображениям ищутся похожие
+type DuplicateCandidate struct {
+	OfferID   int
+	Rooms     int
+	Floor     int
+	Flat      int
+	Area      int
+	Latitude  *float64
+	Longitude *float64
+}
+
+//easyjson:json
+type OfferDuplicate struct {
+	OfferID     int       `json:"offer_id"`
+	DuplicateOf int       `json:"duplicate_of"`
+	SellerID    int       `json:"-"`
+	Reason      string    `json:"reason"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+//easyjson:json
+type OfferDuplicates []OfferDuplicate
+
+// DuplicateOfferError Объявление повторяет активное объявление того же продавца
+type DuplicateOfferError struct {
+	OfferIDs []int
+}
+
+func (e *DuplicateOfferError) Error() string {
+	ids := make([]string, 0, len(e.OfferIDs))
+	for _, id := range e.OfferIDs {
+		ids = append(ids, strconv.Itoa(id))
+	}
+	return fmt.Sprintf("объявление дублирует ваше активное объявление №%s", strings.Join(ids, ", №"))
+}
+
+//easyjson:json
+type DuplicateResponse struct {
+	Message  string `json:"message"`
+	OfferIDs []int  `json:"offer_ids"`
+}
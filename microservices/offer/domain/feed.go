@@ -0,0 +1,99 @@
+//go:generate easyjson -all
+
+package domain
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	FeedFormatYandex = "yandex"
+	FeedFormatJSON   = "json"
+)
+
+// FeedFilter Фид строится по всем активным объявлениям или по объявлениям перечисленных
+// продавцов. Агентство выгружает фид по id своих сотрудников
+type FeedFilter struct {
+	SellerIDs []int
+}
+
+// CacheKey Ключ снимка фида в Redis, не зависит от порядка и повторов продавцов
+func (f FeedFilter) CacheKey() string {
+	if len(f.SellerIDs) == 0 {
+		return "offer_feed:all"
+	}
+	ids := append([]int(nil), f.SellerIDs...)
+	sort.Ints(ids)
+
+	parts := make([]string, 0, len(ids))
+	for i, id := range ids {
+		if i > 0 && id == ids[i-1] {
+			continue
+		}
+		parts = append(parts, strconv.Itoa(id))
+	}
+	return "offer_feed:" + strings.Join(parts, ",")
+}
+
+//easyjson:json
+type FeedSeller struct {
+	ID        int    `json:"id"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	Email     string `json:"email"`
+}
+
+// FeedOffer Объявление в том виде, в котором оно уходит на площадки: справочники названиями,
+// ссылки на фотографии и страницу объявления абсолютные
+//
+//easyjson:json
+type FeedOffer struct {
+	ID            int        `json:"id"`
+	URL           string     `json:"url"`
+	OfferType     string     `json:"offer_type"`
+	RentType      *string    `json:"rent_type,omitempty"`
+	PurchaseType  *string    `json:"purchase_type,omitempty"`
+	PropertyType  string     `json:"property_type"`
+	Renovation    string     `json:"renovation"`
+	MetroStation  *string    `json:"metro_station,omitempty"`
+	Price         int        `json:"price"`
+	Description   *string    `json:"description,omitempty"`
+	Floor         int        `json:"floor"`
+	TotalFloors   int        `json:"total_floors"`
+	Rooms         int        `json:"rooms"`
+	Address       *string    `json:"address,omitempty"`
+	Flat          int        `json:"flat"`
+	Area          int        `json:"area"`
+	CeilingHeight int        `json:"ceiling_height"`
+	Longitude     string     `json:"longitude"`
+	Latitude      string     `json:"latitude"`
+	Images        []string   `json:"images"`
+	Seller        FeedSeller `json:"seller"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	StatusID      int        `json:"-"`
+}
+
+//easyjson:json
+type OfferFeed struct {
+	GeneratedAt time.Time   `json:"generated_at"`
+	ETag        string      `json:"-"`
+	Offers      []FeedOffer `json:"offers"`
+}
+
+// FeedSnapshot Сохраненный в Redis фид. Watermark - наибольший updated_at среди учтенных
+// объявлений, следующая сборка запрашивает только то, что изменилось после него.
+// BuiltAt - время последней полной сборки
+//
+//easyjson:json
+type FeedSnapshot struct {
+	Watermark   time.Time   `json:"watermark"`
+	BuiltAt     time.Time   `json:"built_at"`
+	CheckedAt   time.Time   `json:"checked_at"`
+	GeneratedAt time.Time   `json:"generated_at"`
+	ETag        string      `json:"etag"`
+	Offers      []FeedOffer `json:"offers"`
+}
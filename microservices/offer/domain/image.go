@@ -0,0 +1,50 @@
+//go:generate easyjson -all
+
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// PendingImagesPrefix Каталог бакета, куда клиент загружает фотографии по подписанной ссылке
+// до подтверждения. Неподтвержденные объекты остаются здесь
+const PendingImagesPrefix = "pending/"
+
+// PendingImageObject Имя объекта для прямой загрузки фотографии объявления
+func PendingImageObject(offerID int, id string) string {
+	return fmt.Sprintf("%s%d/%s", PendingImagesPrefix, offerID, id)
+}
+
+// IsPendingImageObject Проверяет, что объект выдан для загрузки фотографии этого объявления
+func IsPendingImageObject(offerID int, objectName string) bool {
+	name, ok := strings.CutPrefix(objectName, PendingImageObject(offerID, ""))
+	return ok && name != "" && !strings.Contains(name, "/")
+}
+
+//easyjson:json
+type ImageUploadURL struct {
+	URL string `json:"url"`
+	// Fields Поля multipart-формы, которые клиент отправляет POST-запросом на URL вместе с файлом
+	Fields     map[string]string `json:"fields"`
+	ObjectName string            `json:"object_name"`
+	ExpiresAt  time.Time         `json:"expires_at"`
+}
+
+//easyjson:json
+type ImageUploadConfirm struct {
+	ObjectName string `json:"object_name"`
+}
+
+//easyjson:json
+type ImageUploadResult struct {
+	Filename string `json:"filename"`
+	ImageID  int64  `json:"image_id,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+//easyjson:json
+type ImageUploadResults struct {
+	Results []ImageUploadResult `json:"results"`
+}
@@ -0,0 +1,34 @@
+//go:generate easyjson -all
+
+package domain
+
+import "time"
+
+// StoredImage Запись kvartirum.Image с бакетом, в котором лежит объект.
+// Bucket пустой, если на изображение никто не ссылается
+type StoredImage struct {
+	ID        int64
+	UUID      string
+	Variants  []int
+	Bucket    string
+	CreatedAt time.Time
+}
+
+//easyjson:json
+type ImageGCObject struct {
+	Bucket string `json:"bucket"`
+	Name   string `json:"name"`
+}
+
+//easyjson:json
+type ImageGCReport struct {
+	DryRun bool `json:"dry_run"`
+	// OrphanObjects Объекты, для которых нет записи в kvartirum.Image
+	OrphanObjects []ImageGCObject `json:"orphan_objects"`
+	// UnreferencedImages Записи, на которые не ссылаются ни объявления, ни пользователи, ни ЖК
+	UnreferencedImages []int64 `json:"unreferenced_images"`
+	// MissingObjectImages Записи, объект которых пропал из бакета
+	MissingObjectImages []int64 `json:"missing_object_images"`
+	DeletedObjects      int     `json:"deleted_objects"`
+	DeletedImages       int     `json:"deleted_images"`
+}
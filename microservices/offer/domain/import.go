@@ -0,0 +1,75 @@
+//go:generate easyjson -all
+
+package domain
+
+import "strings"
+
+const (
+	ImportFormatCSV    = "csv"
+	ImportFormatYandex = "yandex"
+)
+
+const (
+	ImportActionCreated = "created"
+	ImportActionUpdated = "updated"
+)
+
+// ImportRecord Объявление из файла импорта до сопоставления со справочниками.
+// Справочные поля хранят названия, Error заполняется, если строку не удалось разобрать
+type ImportRecord struct {
+	Row           int
+	ExternalID    string
+	OfferType     string
+	RentType      string
+	PurchaseType  string
+	PropertyType  string
+	Renovation    string
+	MetroStation  string
+	Address       string
+	Description   string
+	Flat          int
+	Price         int
+	Area          int
+	Rooms         int
+	Floor         int
+	TotalFloors   int
+	CeilingHeight int
+	ImageURLs     []string
+	Error         string
+}
+
+// ImportDictionaries Идентификаторы справочных значений по нормализованному названию
+type ImportDictionaries struct {
+	OfferTypes    map[string]int
+	RentTypes     map[string]int
+	PurchaseTypes map[string]int
+	PropertyTypes map[string]int
+	Renovations   map[string]int
+	MetroStations map[string]int
+}
+
+// NormalizeDictionaryName Приводит название к виду, в котором оно ищется в справочнике:
+// без регистра, лишних пробелов и различия между «е» и «ё»
+func NormalizeDictionaryName(name string) string {
+	name = strings.ToLower(strings.Join(strings.Fields(name), " "))
+	return strings.ReplaceAll(name, "ё", "е")
+}
+
+//easyjson:json
+type ImportRowResult struct {
+	Row        int    `json:"row"`
+	ExternalID string `json:"external_id,omitempty"`
+	OfferID    int    `json:"offer_id,omitempty"`
+	Action     string `json:"action,omitempty"`
+	Error      string `json:"error,omitempty"`
+	// ImageErrors Фотографии, которые не удалось скачать или сохранить. Объявление при этом создается
+	ImageErrors []string `json:"image_errors,omitempty"`
+}
+
+//easyjson:json
+type ImportReport struct {
+	Created int               `json:"created"`
+	Updated int               `json:"updated"`
+	Failed  int               `json:"failed"`
+	Rows    []ImportRowResult `json:"rows"`
+}
@@ -3,13 +3,24 @@
 package domain
 
 import (
+	"fmt"
 	"time"
 )
 
 //easyjson:json
 type OfferInfo struct {
-	Offer     Offer     `json:"offer"`
-	OfferData OfferData `json:"offer_data"`
+	Offer     Offer           `json:"offer"`
+	OfferData OfferData       `json:"offer_data"`
+	Highlight *OfferHighlight `json:"highlight,omitempty"`
+}
+
+// OfferHighlight Фрагменты текста объявления, совпавшие с поисковым запросом q.
+// Совпадения обрамлены тегом <mark>, остальной текст экранирован
+//
+//easyjson:json
+type OfferHighlight struct {
+	Description *string `json:"description,omitempty"`
+	Address     *string `json:"address,omitempty"`
 }
 
 //easyjson:json
@@ -20,60 +31,156 @@ type OfferPromotion struct {
 
 //easyjson:json
 type OfferData struct {
-	Images         []OfferImage        `json:"offer_images"`
-	Seller         OfferSeller         `json:"seller"`
-	Metro          Metro               `json:"metro"`
-	OfferStat      OfferStat           `json:"offer_stat"`
-	Prices         []OfferPriceHistory `json:"offer_prices"`
-	Promotion      *OfferPromotion     `json:"offer_promotion"`
-	PromotionScore float32             `json:"-"`
+	Images            []OfferImage        `json:"offer_images"`
+	Seller            OfferSeller         `json:"seller"`
+	Metro             Metro               `json:"metro"`
+	OfferStat         OfferStat           `json:"offer_stat"`
+	Prices            []OfferPriceHistory `json:"offer_prices"`
+	PriceDrop         bool                `json:"price_drop"`
+	PriceReducedSince *time.Time          `json:"price_reduced_since"`
+	Promotion         *OfferPromotion     `json:"offer_promotion"`
 }
 
 //easyjson:json
 type Offer struct {
 	ID             int        `json:"id"`
 	SellerID       int        `json:"seller_id"`
-	OfferTypeID    int        `json:"offer_type_id"`
+	OfferTypeID    int        `json:"offer_type_id" validate:"required"`
 	MetroStationID *int       `json:"metro_station_id,omitempty"`
 	RentTypeID     *int       `json:"rent_type_id,omitempty"`
 	PurchaseTypeID *int       `json:"purchase_type_id,omitempty"`
-	PropertyTypeID int        `json:"property_type_id"`
+	PropertyTypeID int        `json:"property_type_id" validate:"required"`
 	StatusID       int        `json:"-"`
-	RenovationID   int        `json:"renovation_id"`
+	RenovationID   int        `json:"renovation_id" validate:"required"`
 	ComplexID      *int       `json:"complex_id,omitempty"`
-	Price          int        `json:"price"`
-	Description    *string    `json:"description,omitempty"`
-	Floor          int        `json:"floor"`
-	TotalFloors    int        `json:"total_floors"`
-	Rooms          int        `json:"rooms"`
-	Address        *string    `json:"address,omitempty"`
-	Flat           int        `json:"flat"`
-	Area           int        `json:"area"`
-	CeilingHeight  int        `json:"ceiling_height"`
+	Price          int        `json:"price" validate:"min=0,max=2147483647"`
+	Description    *string    `json:"description,omitempty" validate:"omitempty,max=512,escaped_max=512"`
+	Floor          int        `json:"floor" validate:"min=0,max=100"`
+	TotalFloors    int        `json:"total_floors" validate:"min=0,max=100"`
+	Rooms          int        `json:"rooms" validate:"min=0,max=100"`
+	Address        *string    `json:"address,omitempty" validate:"omitempty,max=512,escaped_max=512"`
+	Flat           int        `json:"flat" validate:"min=0,max=1000"`
+	Area           int        `json:"area" validate:"min=0,max=1000"`
+	CeilingHeight  int        `json:"ceiling_height" validate:"min=0,max=100"`
 	Longitude      string     `json:"logitude"`
 	Latitude       string     `json:"latitude"`
 	PromotesUntil  *time.Time `json:"promotes_until,omitempty"`
 	CreatedAt      time.Time  `json:"created_at"`
 	UpdatedAt      time.Time  `json:"updated_at"`
+	// Distance Расстояние в метрах до точки поиска near
+	Distance *int `json:"distance,omitempty"`
 }
 
 //easyjson:json
 type OfferFilter struct {
-	MinArea        *int    `json:"min_area"`
-	MaxArea        *int    `json:"max_area"`
-	MinPrice       *int    `json:"min_price"`
-	MaxPrice       *int    `json:"max_price"`
-	Floor          *int    `json:"floor"`
-	Rooms          *int    `json:"rooms"`
-	Address        *string `json:"address"`
-	RenovationID   *int    `json:"renovation_id"`
-	PropertyTypeID *int    `json:"property_type_id"`
-	PurchaseTypeID *int    `json:"purchase_type_id"`
-	RentTypeID     *int    `json:"rent_type_id"`
-	OfferTypeID    *int    `json:"offer_type_id"`
-	NewBuilding    *bool   `json:"new_building"`
-	SellerID       *int    `json:"seller_id"`
-	OnlyMe         *bool   `json:"me"`
+	MinArea          *int       `json:"min_area"`
+	MaxArea          *int       `json:"max_area"`
+	MinPrice         *int       `json:"min_price"`
+	MaxPrice         *int       `json:"max_price"`
+	Floor            *int       `json:"floor"`
+	FloorMin         *int       `json:"floor_min"`
+	FloorMax         *int       `json:"floor_max"`
+	NotFirstFloor    bool       `json:"not_first_floor"`
+	NotLastFloor     bool       `json:"not_last_floor"`
+	Rooms            []int      `json:"rooms"`
+	Address          *string    `json:"address"`
+	RenovationID     []int      `json:"renovation_id"`
+	PropertyTypeID   []int      `json:"property_type_id"`
+	PurchaseTypeID   []int      `json:"purchase_type_id"`
+	RentTypeID       []int      `json:"rent_type_id"`
+	OfferTypeID      *int       `json:"offer_type_id"`
+	MetroStationID   []int      `json:"metro_station_id"`
+	ComplexID        *int       `json:"complex_id"`
+	MinCeilingHeight *int       `json:"min_ceiling_height"`
+	NewBuilding      *bool      `json:"new_building"`
+	SellerID         *int       `json:"seller_id"`
+	OnlyMe           *bool      `json:"me"`
+	Query            *string    `json:"q"`
+	Sort             OfferSort  `json:"sort"`
+	BBox             *BBox      `json:"bbox"`
+	Near             *GeoPoint  `json:"near"`
+	Radius           *int       `json:"radius"`
+	PriceDropped     *bool      `json:"price_dropped"`
+	PublishedAfter   *time.Time `json:"-"`
+	PublishedBefore  *time.Time `json:"-"`
+}
+
+//easyjson:json
+type GeoPoint struct {
+	Longitude float64 `json:"longitude"`
+	Latitude  float64 `json:"latitude"`
+}
+
+// BBox Видимая область карты
+//
+//easyjson:json
+type BBox struct {
+	MinLongitude float64 `json:"min_longitude"`
+	MinLatitude  float64 `json:"min_latitude"`
+	MaxLongitude float64 `json:"max_longitude"`
+	MaxLatitude  float64 `json:"max_latitude"`
+}
+
+const (
+	DefaultSearchRadius = 1000
+	MaxSearchRadius     = 50000
+)
+
+// OfferCluster Группа объявлений в одной ячейке сетки карты
+//
+//easyjson:json
+type OfferCluster struct {
+	Count     int     `json:"count"`
+	Longitude float64 `json:"longitude"`
+	Latitude  float64 `json:"latitude"`
+	MinPrice  int     `json:"min_price"`
+	MaxPrice  int     `json:"max_price"`
+	OfferID   int     `json:"offer_id"`
+}
+
+//easyjson:json
+type OfferClusters []OfferCluster
+
+const (
+	MinClusterZoom = 0
+	MaxClusterZoom = 21
+	// clusterCellsPerTile Сколько ячеек сетки приходится на сторону тайла карты
+	clusterCellsPerTile = 4
+)
+
+// ClusterCellSize Размер ячейки сетки в градусах для масштаба карты.
+// На масштабе zoom мир делится на 2^zoom тайлов по долготе
+func ClusterCellSize(zoom int) float64 {
+	return 360 / float64(int(1)<<zoom) / clusterCellsPerTile
+}
+
+// OfferSort Порядок выдачи объявлений в ленте
+type OfferSort string
+
+const (
+	SortNewest     OfferSort = "new"
+	SortPriceAsc   OfferSort = "price_asc"
+	SortPriceDesc  OfferSort = "price_desc"
+	SortPricePerM2 OfferSort = "price_per_m2"
+	SortAreaDesc   OfferSort = "area_desc"
+	// SortRelevance По релевантности поисковому запросу q
+	SortRelevance OfferSort = "relevance"
+)
+
+// OrDefault Пустая сортировка означает сначала новые
+func (s OfferSort) OrDefault() OfferSort {
+	if s == "" {
+		return SortNewest
+	}
+	return s
+}
+
+func (s OfferSort) IsValid() bool {
+	switch s.OrDefault() {
+	case SortNewest, SortPriceAsc, SortPriceDesc, SortPricePerM2, SortAreaDesc, SortRelevance:
+		return true
+	}
+	return false
 }
 
 //easyjson:json
@@ -87,6 +194,20 @@ type Metro struct {
 type OfferImage struct {
 	ID    int    `json:"id"`
 	Image string `json:"image"`
+	// Variants Ссылки на уменьшенные копии по ширине в пикселях
+	Variants map[string]string `json:"variants,omitempty"`
+	IsCover  bool              `json:"is_cover"`
+}
+
+// ImageMeta Сведения об изображении, вычисленные при загрузке
+type ImageMeta struct {
+	PHash    *int64
+	Variants []int
+}
+
+// VariantObjectName Имя уменьшенной копии изображения в бакете
+func VariantObjectName(objectName string, width int) string {
+	return fmt.Sprintf("%s_%dw.jpg", objectName, width)
 }
 
 //easyjson:json
@@ -116,7 +237,18 @@ type LikeRequest struct {
 	UserId  int `json:"user_id"`
 }
 
-const OfferStatusDraft = 2
+// Статусы из kvartirum.OfferStatus
+const (
+	OfferStatusActive    = 1
+	OfferStatusDraft     = 2
+	OfferStatusCompleted = 3
+)
+
+// Типы сделки из kvartirum.OfferType
+const (
+	OfferTypeSale = 1
+	OfferTypeRent = 2
+)
 
 //easyjson:json
 type OfferPriceHistory struct {
@@ -132,8 +264,17 @@ type FavoriteRequest struct {
 
 //easyjson:json
 type FavoriteStat struct {
-	IsFavorited bool `json:"is_favorited"`
-	Amount      int  `json:"amount"`
+	IsFavorited bool    `json:"is_favorited"`
+	Amount      int     `json:"amount"`
+	Note        *string `json:"note,omitempty"`
+}
+
+// OfferUserStat Отметки пользователя на объявлении. В кеш карточек не попадают,
+// читаются на каждый запрос
+type OfferUserStat struct {
+	IsLiked     bool
+	IsFavorited bool
+	Note        *string
 }
 
 //easyjson:json
@@ -163,6 +304,34 @@ type PaymentPeriods struct {
 
 //easyjson:json
 type OffersInfo []OfferInfo
+
+//easyjson:json
+type OffersPage struct {
+	Offers     OffersInfo `json:"offers"`
+	NextCursor *string    `json:"next_cursor"`
+	Total      int        `json:"total"`
+}
+
+// Cursor Позиция в ленте, на которой закончилась предыдущая страница.
+// Для сортировок по цене и площади хранит значение ключа сортировки последней записи
+//
+//easyjson:json
+type Cursor struct {
+	ID    int       `json:"id"`
+	Sort  OfferSort `json:"sort,omitempty"`
+	Value *float64  `json:"value,omitempty"`
+}
+
+type Pagination struct {
+	Limit  int
+	Cursor *Cursor
+}
+
+const (
+	DefaultPageLimit = 20
+	MaxPageLimit     = 100
+)
+
 //easyjson:json
 type Stations []Metro
 
@@ -174,4 +343,9 @@ type OfferID struct {
 //easyjson:json
 type ImageID struct {
 	ImageID int64 `json:"image_id"`
-}
\ No newline at end of file
+}
+
+//easyjson:json
+type ImageOrderRequest struct {
+	ImageIDs []int64 `json:"image_ids"`
+}
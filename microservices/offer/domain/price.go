@@ -0,0 +1,89 @@
+//go:generate easyjson -all
+
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+// PricePoint Запись истории цены с изменением относительно предыдущей записи
+//
+//easyjson:json
+type PricePoint struct {
+	Price         int       `json:"price"`
+	Date          time.Time `json:"date"`
+	Change        int       `json:"change"`
+	ChangePercent float64   `json:"change_percent"`
+}
+
+//easyjson:json
+type OfferPrices struct {
+	OfferID           int          `json:"offer_id"`
+	Prices            []PricePoint `json:"prices"`
+	PriceDrop         bool         `json:"price_drop"`
+	PriceReducedSince *time.Time   `json:"price_reduced_since"`
+}
+
+// NewPricePoints Считает изменения между соседними записями истории, упорядоченной от старых к новым
+func NewPricePoints(history []OfferPriceHistory) []PricePoint {
+	points := make([]PricePoint, 0, len(history))
+	for i, record := range history {
+		point := PricePoint{Price: record.Price, Date: record.Date}
+		if i > 0 {
+			prev := history[i-1].Price
+			point.Change = record.Price - prev
+			if prev > 0 {
+				point.ChangePercent = math.Round(float64(point.Change)/float64(prev)*10000) / 100
+			}
+		}
+		points = append(points, point)
+	}
+	return points
+}
+
+// PriceReducedSince Если последнее изменение цены было снижением, возвращает дату первого снижения
+// в непрерывной серии снижений. История упорядочена от новых записей к старым
+func PriceReducedSince(history []OfferPriceHistory) *time.Time {
+	var since *time.Time
+	for i := 0; i+1 < len(history); i++ {
+		if history[i].Price >= history[i+1].Price {
+			break
+		}
+		date := history[i].Date
+		since = &date
+	}
+	return since
+}
+
+// PriceDropAlert Снижение цены объявления, о котором сообщается пользователям, добавившим его в избранное
+type PriceDropAlert struct {
+	OfferID  int
+	SellerID int
+	OldPrice int
+	NewPrice int
+}
+
+// DropPercent Снижение в процентах от прежней цены
+func (a PriceDropAlert) DropPercent() float64 {
+	if a.OldPrice <= 0 {
+		return 0
+	}
+	return float64(a.OldPrice-a.NewPrice) / float64(a.OldPrice) * 100
+}
+
+// PriceDropRecipient Пользователь, которому записано уведомление о снижении цены
+type PriceDropRecipient struct {
+	UserID int
+	Email  string
+}
+
+// PriceAlertSettings Настройки уведомлений о снижении цены. MinDropPercent не задан -
+// действует порог по умолчанию
+//
+//easyjson:json
+type PriceAlertSettings struct {
+	Enabled               bool `json:"enabled"`
+	MinDropPercent        *int `json:"min_drop_percent"`
+	DefaultMinDropPercent int  `json:"default_min_drop_percent"`
+}
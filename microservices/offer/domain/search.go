@@ -0,0 +1,68 @@
+//go:generate easyjson -all
+
+package domain
+
+import (
+	"time"
+)
+
+// SearchFrequency Как часто пользователь хочет получать уведомления по сохраненному поиску
+type SearchFrequency string
+
+const (
+	FrequencyInstant SearchFrequency = "instant"
+	FrequencyDaily   SearchFrequency = "daily"
+	FrequencyWeekly  SearchFrequency = "weekly"
+)
+
+func (f SearchFrequency) IsValid() bool {
+	switch f {
+	case FrequencyInstant, FrequencyDaily, FrequencyWeekly:
+		return true
+	}
+	return false
+}
+
+// Period Минимальный промежуток между проверками поиска.
+// Мгновенные поиски проверяются на каждом запуске воркера
+func (f SearchFrequency) Period() time.Duration {
+	switch f {
+	case FrequencyDaily:
+		return 24 * time.Hour
+	case FrequencyWeekly:
+		return 7 * 24 * time.Hour
+	}
+	return 0
+}
+
+const MaxSavedSearchNameLength = 64
+
+//easyjson:json
+type SavedSearch struct {
+	ID            int             `json:"id"`
+	UserID        int             `json:"-"`
+	Name          string          `json:"name"`
+	Frequency     SearchFrequency `json:"frequency"`
+	Filter        OfferFilter     `json:"filter"`
+	LastCheckedAt time.Time       `json:"last_checked_at"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+//easyjson:json
+type SavedSearches []SavedSearch
+
+// SavedSearchRequest Фильтр передается строкой запроса ленты, например rooms=2,3&max_price=5000000
+//
+//easyjson:json
+type SavedSearchRequest struct {
+	Name      string          `json:"name"`
+	Frequency SearchFrequency `json:"frequency"`
+	Params    string          `json:"params"`
+}
+
+// Notification Запись в kvartirum.UserNotification. Пустой RedirectURI сохраняется как NULL
+type Notification struct {
+	UserID      int
+	Message     string
+	RedirectURI string
+}
@@ -0,0 +1,42 @@
+//go:generate easyjson -all
+
+package domain
+
+// MaxStatusReasonLength Ограничение kvartirum.OfferStatusHistory.reason
+const MaxStatusReasonLength = 256
+
+// OfferTransition Переход объявления между статусами
+type OfferTransition string
+
+const (
+	TransitionPublish   OfferTransition = "publish"
+	TransitionComplete  OfferTransition = "complete"
+	TransitionUnpublish OfferTransition = "unpublish"
+	TransitionRepublish OfferTransition = "republish"
+)
+
+type transitionRule struct {
+	from int
+	to   int
+}
+
+var offerTransitions = map[OfferTransition]transitionRule{
+	TransitionPublish:   {from: OfferStatusDraft, to: OfferStatusActive},
+	TransitionComplete:  {from: OfferStatusActive, to: OfferStatusCompleted},
+	TransitionUnpublish: {from: OfferStatusActive, to: OfferStatusDraft},
+	TransitionRepublish: {from: OfferStatusCompleted, to: OfferStatusActive},
+}
+
+// Target Статус, в который переходит объявление из статуса from. false, если переход недопустим
+func (t OfferTransition) Target(from int) (int, bool) {
+	rule, ok := offerTransitions[t]
+	if !ok || rule.from != from {
+		return 0, false
+	}
+	return rule.to, true
+}
+
+//easyjson:json
+type StatusChangeRequest struct {
+	Reason string `json:"reason"`
+}
@@ -0,0 +1,104 @@
+//go:generate easyjson -all
+
+package domain
+
+import (
+	"github.com/go-park-mail-ru/2025_1_404/pkg/validation"
+	"github.com/go-playground/validator/v10"
+)
+
+// ValidationErrorResponse Ответ 400 со списком ошибок по полям
+//
+//easyjson:json
+type ValidationErrorResponse struct {
+	Error  string                 `json:"error"`
+	Fields validation.FieldErrors `json:"fields"`
+}
+
+// publishRequirements Поля, без которых черновик нельзя опубликовать
+type publishRequirements struct {
+	Price       int    `json:"price" validate:"required"`
+	Area        int    `json:"area" validate:"required"`
+	Floor       int    `json:"floor" validate:"required"`
+	TotalFloors int    `json:"total_floors" validate:"required"`
+	Rooms       int    `json:"rooms" validate:"required"`
+	Address     string `json:"address" validate:"required"`
+}
+
+// NewOfferValidator Валидатор с правилами объявления, которые зависят от нескольких полей
+func NewOfferValidator() *validator.Validate {
+	validate := validation.GetFieldValidator()
+	validate.RegisterStructValidation(offerStructLevel, Offer{})
+	return validate
+}
+
+// ValidateOffer Проверяет объявление перед сохранением. Черновик может быть заполнен
+// не полностью, но заполненные поля должны быть корректными
+func ValidateOffer(validate *validator.Validate, offer Offer) error {
+	return validation.GetFieldErrors(validate.Struct(offer))
+}
+
+// ValidateOfferForPublish Вдобавок к ValidateOffer требует поля, обязательные для публикации
+func ValidateOfferForPublish(validate *validator.Validate, offer Offer) error {
+	var fieldErrors validation.FieldErrors
+
+	for _, err := range []error{
+		ValidateOffer(validate, offer),
+		validation.GetFieldErrors(validate.Struct(publishRequirements{
+			Price:       offer.Price,
+			Area:        offer.Area,
+			Floor:       offer.Floor,
+			TotalFloors: offer.TotalFloors,
+			Rooms:       offer.Rooms,
+			Address:     stringValue(offer.Address),
+		})),
+	} {
+		if err == nil {
+			continue
+		}
+		errs, ok := err.(validation.FieldErrors)
+		if !ok {
+			return err
+		}
+		fieldErrors = append(fieldErrors, errs...)
+	}
+
+	if len(fieldErrors) > 0 {
+		return fieldErrors
+	}
+	return nil
+}
+
+// offerStructLevel Этаж не выше этажности дома, если она указана. Тип аренды указывается
+// только у аренды, тип покупки только у продажи
+func offerStructLevel(sl validator.StructLevel) {
+	offer := sl.Current().Interface().(Offer)
+
+	if offer.TotalFloors > 0 && offer.Floor > offer.TotalFloors {
+		sl.ReportError(offer.Floor, "floor", "Floor", "ltefield", "total_floors")
+	}
+
+	switch offer.OfferTypeID {
+	case OfferTypeRent:
+		if offer.RentTypeID == nil {
+			sl.ReportError(offer.RentTypeID, "rent_type_id", "RentTypeID", "required_if", "offer_type_id=2")
+		}
+		if offer.PurchaseTypeID != nil {
+			sl.ReportError(offer.PurchaseTypeID, "purchase_type_id", "PurchaseTypeID", "excluded_if", "offer_type_id=2")
+		}
+	case OfferTypeSale:
+		if offer.PurchaseTypeID == nil {
+			sl.ReportError(offer.PurchaseTypeID, "purchase_type_id", "PurchaseTypeID", "required_if", "offer_type_id=1")
+		}
+		if offer.RentTypeID != nil {
+			sl.ReportError(offer.RentTypeID, "rent_type_id", "RentTypeID", "excluded_if", "offer_type_id=1")
+		}
+	}
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
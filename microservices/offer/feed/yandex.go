@@ -0,0 +1,190 @@
+package feed
+
+import (
+	"encoding/xml"
+	"io"
+	"time"
+
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/domain"
+)
+
+const (
+	yandexNamespace  = "http://webmaster.yandex.ru/schemas/feed/realty/2010-06"
+	yandexTimeLayout = "2006-01-02T15:04:05-07:00"
+)
+
+// Наши справочные значения в терминах фида Яндекс.Недвижимости
+var (
+	yandexOfferTypes = map[string]string{
+		"Продажа": "продажа",
+		"Аренда":  "аренда",
+	}
+
+	yandexCategories = map[string]string{
+		"Квартира":    "квартира",
+		"Апартаменты": "квартира",
+		"Дом":         "дом",
+	}
+
+	yandexRenovations = map[string]string{
+		"Современный ремонт":     "евро",
+		"Косметический ремонт":   "косметический",
+		"Черновая отделка":       "черновая отделка",
+		"Улучшенная черновая":    "предчистовая",
+		"Нужен полный ремонт":    "требует ремонта",
+		"Нужен частичный ремонт": "частичный ремонт",
+	}
+
+	yandexRentPeriods = map[string]string{
+		"Посуточно": "день",
+		"Долгосрок": "месяц",
+	}
+)
+
+type yandexFeed struct {
+	XMLName        xml.Name      `xml:"realty-feed"`
+	Namespace      string        `xml:"xmlns,attr"`
+	GenerationDate string        `xml:"generation-date"`
+	Offers         []yandexOffer `xml:"offer"`
+}
+
+type yandexOffer struct {
+	InternalID     int            `xml:"internal-id,attr"`
+	Type           string         `xml:"type"`
+	PropertyType   string         `xml:"property-type"`
+	Category       string         `xml:"category"`
+	URL            string         `xml:"url"`
+	CreationDate   string         `xml:"creation-date"`
+	LastUpdateDate string         `xml:"last-update-date"`
+	Location       yandexLocation `xml:"location"`
+	SalesAgent     yandexAgent    `xml:"sales-agent"`
+	Price          yandexPrice    `xml:"price"`
+	Area           yandexArea     `xml:"area"`
+	Rooms          int            `xml:"rooms,omitempty"`
+	Floor          int            `xml:"floor,omitempty"`
+	FloorsTotal    int            `xml:"floors-total,omitempty"`
+	CeilingHeight  int            `xml:"ceiling-height,omitempty"`
+	Renovation     string         `xml:"renovation,omitempty"`
+	Apartments     string         `xml:"apartments,omitempty"`
+	NewFlat        string         `xml:"new-flat,omitempty"`
+	Description    string         `xml:"description,omitempty"`
+	Images         []string       `xml:"image"`
+}
+
+type yandexLocation struct {
+	Country   string       `xml:"country"`
+	Address   string       `xml:"address,omitempty"`
+	Apartment int          `xml:"apartment,omitempty"`
+	Latitude  string       `xml:"latitude,omitempty"`
+	Longitude string       `xml:"longitude,omitempty"`
+	Metro     *yandexMetro `xml:"metro"`
+}
+
+type yandexMetro struct {
+	Name string `xml:"name"`
+}
+
+type yandexAgent struct {
+	Name     string `xml:"name"`
+	Email    string `xml:"email,omitempty"`
+	Category string `xml:"category"`
+}
+
+type yandexPrice struct {
+	Value    int    `xml:"value"`
+	Currency string `xml:"currency"`
+	Period   string `xml:"period,omitempty"`
+}
+
+type yandexArea struct {
+	Value int    `xml:"value"`
+	Unit  string `xml:"unit"`
+}
+
+// WriteYandexFeed Пишет фид в формате Яндекс.Недвижимости
+func WriteYandexFeed(w io.Writer, feed domain.OfferFeed) error {
+	doc := yandexFeed{
+		Namespace:      yandexNamespace,
+		GenerationDate: formatYandexTime(feed.GeneratedAt),
+		Offers:         make([]yandexOffer, 0, len(feed.Offers)),
+	}
+	for _, offer := range feed.Offers {
+		doc.Offers = append(doc.Offers, toYandexOffer(offer))
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func toYandexOffer(offer domain.FeedOffer) yandexOffer {
+	result := yandexOffer{
+		InternalID:     offer.ID,
+		Type:           yandexValue(yandexOfferTypes, offer.OfferType),
+		PropertyType:   "жилая",
+		Category:       yandexValue(yandexCategories, offer.PropertyType),
+		URL:            offer.URL,
+		CreationDate:   formatYandexTime(offer.CreatedAt),
+		LastUpdateDate: formatYandexTime(offer.UpdatedAt),
+		Location: yandexLocation{
+			Country:   "Россия",
+			Apartment: offer.Flat,
+			Latitude:  offer.Latitude,
+			Longitude: offer.Longitude,
+		},
+		SalesAgent: yandexAgent{
+			Name:     offer.Seller.FirstName + " " + offer.Seller.LastName,
+			Email:    offer.Seller.Email,
+			Category: "владелец",
+		},
+		Price:         yandexPrice{Value: offer.Price, Currency: "RUR"},
+		Area:          yandexArea{Value: offer.Area, Unit: "кв. м"},
+		Rooms:         offer.Rooms,
+		Floor:         offer.Floor,
+		FloorsTotal:   offer.TotalFloors,
+		CeilingHeight: offer.CeilingHeight,
+		Renovation:    yandexValue(yandexRenovations, offer.Renovation),
+		Images:        offer.Images,
+	}
+
+	if offer.Address != nil {
+		result.Location.Address = *offer.Address
+	}
+	if offer.MetroStation != nil {
+		result.Location.Metro = &yandexMetro{Name: *offer.MetroStation}
+	}
+	if offer.Description != nil {
+		result.Description = *offer.Description
+	}
+	if offer.PropertyType == "Апартаменты" {
+		result.Apartments = "да"
+	}
+	if offer.PurchaseType != nil && *offer.PurchaseType == "Новостройка" {
+		result.NewFlat = "да"
+	}
+	if offer.RentType != nil {
+		result.Price.Period = yandexValue(yandexRentPeriods, *offer.RentType)
+	}
+
+	return result
+}
+
+// yandexValue Значения без соответствия передаются как есть
+func yandexValue(values map[string]string, value string) string {
+	if mapped, ok := values[value]; ok {
+		return mapped
+	}
+	return value
+}
+
+// formatYandexTime Фид требует время в ISO 8601 с часовым поясом
+func formatYandexTime(t time.Time) string {
+	return t.Format(yandexTimeLayout)
+}
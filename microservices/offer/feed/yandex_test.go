@@ -0,0 +1,87 @@
+package feed
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/domain"
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/importer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteYandexFeed(t *testing.T) {
+	strPtr := func(s string) *string { return &s }
+	generated := time.Date(2026, 10, 1, 12, 0, 0, 0, time.FixedZone("MSK", 3*60*60))
+
+	offerFeed := domain.OfferFeed{
+		GeneratedAt: generated,
+		Offers: []domain.FeedOffer{
+			{
+				ID:            1,
+				URL:           "https://kvartirum.ru/offer/1",
+				OfferType:     "Продажа",
+				PurchaseType:  strPtr("Новостройка"),
+				PropertyType:  "Апартаменты",
+				Renovation:    "Улучшенная черновая",
+				MetroStation:  strPtr("Тверская"),
+				Price:         12500000,
+				Description:   strPtr("Вид на парк & реку"),
+				Floor:         4,
+				TotalFloors:   17,
+				Rooms:         2,
+				Address:       strPtr("Москва, Тверская, 7"),
+				Flat:          12,
+				Area:          55,
+				CeilingHeight: 3,
+				Longitude:     "37.6",
+				Latitude:      "55.7",
+				Images:        []string{"https://s3/offers/a.jpg", "https://s3/offers/b.jpg"},
+				Seller:        domain.FeedSeller{ID: 7, FirstName: "Иван", LastName: "Петров", Email: "ivan@mail.ru"},
+				CreatedAt:     generated,
+				UpdatedAt:     generated,
+			},
+			{
+				ID:           2,
+				OfferType:    "Аренда",
+				RentType:     strPtr("Посуточно"),
+				PropertyType: "Дом",
+				Renovation:   "Современный ремонт",
+				Price:        5000,
+				Address:      strPtr("Москва, Арбат, 1"),
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteYandexFeed(&buf, offerFeed))
+
+	body := buf.String()
+	assert.Contains(t, body, `<realty-feed xmlns="http://webmaster.yandex.ru/schemas/feed/realty/2010-06">`)
+	assert.Contains(t, body, "<generation-date>2026-10-01T12:00:00+03:00</generation-date>")
+	assert.Contains(t, body, "<name>Иван Петров</name>")
+	assert.Contains(t, body, "<period>день</period>")
+	assert.Contains(t, body, "Вид на парк &amp; реку")
+
+	// Фид читается нашим же импортом без потерь
+	records, err := importer.ParseYandexFeed(&buf, 10)
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+
+	sale := records[0]
+	assert.Empty(t, sale.Error)
+	assert.Equal(t, "1", sale.ExternalID)
+	assert.Equal(t, "Апартаменты", sale.PropertyType)
+	assert.Equal(t, "Новостройка", sale.PurchaseType)
+	assert.Equal(t, "Улучшенная черновая", sale.Renovation)
+	assert.Equal(t, "Тверская", sale.MetroStation)
+	assert.Equal(t, 12500000, sale.Price)
+	assert.Equal(t, 12, sale.Flat)
+	assert.Equal(t, []string{"https://s3/offers/a.jpg", "https://s3/offers/b.jpg"}, sale.ImageURLs)
+
+	rent := records[1]
+	assert.Equal(t, "Дом", rent.PropertyType)
+	assert.Equal(t, "Посуточно", rent.RentType)
+	assert.Equal(t, "Современный ремонт", rent.Renovation)
+}
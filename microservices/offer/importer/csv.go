@@ -0,0 +1,147 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/domain"
+)
+
+// Колонки CSV. Порядок произвольный, неизвестные колонки пропускаются
+const (
+	columnExternalID    = "external_id"
+	columnOfferType     = "offer_type"
+	columnRentType      = "rent_type"
+	columnPurchaseType  = "purchase_type"
+	columnPropertyType  = "property_type"
+	columnRenovation    = "renovation"
+	columnMetroStation  = "metro_station"
+	columnAddress       = "address"
+	columnDescription   = "description"
+	columnFlat          = "flat"
+	columnPrice         = "price"
+	columnArea          = "area"
+	columnRooms         = "rooms"
+	columnFloor         = "floor"
+	columnTotalFloors   = "total_floors"
+	columnCeilingHeight = "ceiling_height"
+	columnImages        = "images"
+)
+
+var requiredColumns = []string{columnExternalID, columnOfferType, columnPropertyType, columnRenovation, columnAddress}
+
+// ParseCSV Разбирает CSV с заголовком в первой строке. Разделитель - запятая или точка с запятой
+// (так сохраняет Excel в русской локали). Row в результате - номер строки в файле
+func ParseCSV(r io.Reader, maxRows int) ([]domain.ImportRecord, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось прочитать файл")
+	}
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = detectDelimiter(data)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("не удалось прочитать заголовок CSV")
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range requiredColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("в файле нет колонки %s", name)
+		}
+	}
+
+	var records []domain.ImportRecord
+	for {
+		fields, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		var parseErr *csv.ParseError
+		if err != nil && !errors.As(err, &parseErr) {
+			return nil, fmt.Errorf("не удалось прочитать файл")
+		}
+		if len(records) == maxRows {
+			return nil, fmt.Errorf("в файле больше %d объявлений", maxRows)
+		}
+		if err != nil {
+			// Строка с незакрытой кавычкой и т.п. не мешает разобрать остальные
+			records = append(records, domain.ImportRecord{Row: parseErr.Line, Error: "некорректная строка CSV"})
+			continue
+		}
+		line, _ := reader.FieldPos(0)
+		records = append(records, csvRecord(line, columns, fields))
+	}
+
+	return records, nil
+}
+
+func csvRecord(line int, columns map[string]int, fields []string) domain.ImportRecord {
+	get := func(name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(fields) {
+			return ""
+		}
+		return strings.TrimSpace(fields[i])
+	}
+
+	record := domain.ImportRecord{
+		Row:          line,
+		ExternalID:   get(columnExternalID),
+		OfferType:    get(columnOfferType),
+		RentType:     get(columnRentType),
+		PurchaseType: get(columnPurchaseType),
+		PropertyType: get(columnPropertyType),
+		Renovation:   get(columnRenovation),
+		MetroStation: get(columnMetroStation),
+		Address:      get(columnAddress),
+		Description:  get(columnDescription),
+		ImageURLs:    splitImageURLs(get(columnImages)),
+	}
+
+	numbers := []struct {
+		column string
+		dst    *int
+	}{
+		{columnFlat, &record.Flat},
+		{columnPrice, &record.Price},
+		{columnArea, &record.Area},
+		{columnRooms, &record.Rooms},
+		{columnFloor, &record.Floor},
+		{columnTotalFloors, &record.TotalFloors},
+		{columnCeilingHeight, &record.CeilingHeight},
+	}
+	for _, n := range numbers {
+		value, err := parseNumber(get(n.column))
+		if err != nil {
+			record.Error = fmt.Sprintf("некорректное значение в колонке %s", n.column)
+			return record
+		}
+		*n.dst = value
+	}
+
+	return record
+}
+
+// detectDelimiter Выбирает разделитель по заголовку: точка с запятой, если в нем нет запятых
+func detectDelimiter(data []byte) rune {
+	header := data
+	if i := bytes.IndexByte(data, '\n'); i >= 0 {
+		header = data[:i]
+	}
+	if bytes.IndexByte(header, ';') >= 0 && bytes.IndexByte(header, ',') < 0 {
+		return ';'
+	}
+	return ','
+}
@@ -0,0 +1,118 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+
+	"github.com/go-park-mail-ru/2025_1_404/pkg/content"
+)
+
+// maxRedirects Сколько переадресаций допускается при скачивании фотографии
+const maxRedirects = 3
+
+var errForbiddenAddress = errors.New("адрес недоступен для скачивания")
+
+// sharedAddressSpace Адреса операторского NAT (RFC 6598), net.IP.IsPrivate их не включает
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// ImageDownloader Скачивает фотографию объявления по ссылке из файла импорта.
+// В тестах подменяется заглушкой, чтобы не ходить в сеть
+type ImageDownloader interface {
+	Download(ctx context.Context, rawURL string) ([]byte, error)
+}
+
+type httpDownloader struct {
+	client *http.Client
+}
+
+// NewHTTPDownloader Ссылки присылает продавец, поэтому скачивание идет только с публичных адресов:
+// иначе через импорт можно обратиться к minio, Redis и другим внутренним сервисам
+func NewHTTPDownloader(timeout time.Duration) ImageDownloader {
+	return newHTTPDownloader(timeout, isPublicIP)
+}
+
+// newHTTPDownloader Адрес проверяется при установке соединения, уже после разрешения имени,
+// поэтому проверка действует на каждую переадресацию и не обходится через DNS
+func newHTTPDownloader(timeout time.Duration, allowed func(net.IP) bool) *httpDownloader {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return errForbiddenAddress
+			}
+			ip := net.ParseIP(host)
+			if ip == nil || !allowed(ip) {
+				return errForbiddenAddress
+			}
+			return nil
+		},
+	}
+
+	return &httpDownloader{client: &http.Client{
+		Timeout: timeout,
+		// Прокси из окружения не используется, иначе проверялся бы адрес прокси, а не источника
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: timeout,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxRedirects {
+				return fmt.Errorf("слишком много переадресаций")
+			}
+			if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+				return fmt.Errorf("некорректная ссылка")
+			}
+			return nil
+		},
+	}}
+}
+
+func isPublicIP(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() ||
+		ip.IsMulticast() || sharedAddressSpace.Contains(ip))
+}
+
+// Download Скачивает файл по http(s) не больше допустимого размера изображения
+func (d *httpDownloader) Download(ctx context.Context, rawURL string) ([]byte, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("некорректная ссылка")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("некорректная ссылка")
+	}
+
+	resp, err := d.client.Do(req)
+	if errors.Is(err, errForbiddenAddress) {
+		return nil, fmt.Errorf("ссылка ведет на недоступный адрес")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("не удалось скачать файл")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("не удалось скачать файл: статус %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, content.MAX_SIZE+1))
+	if err != nil {
+		return nil, fmt.Errorf("не удалось скачать файл")
+	}
+	if len(data) > content.MAX_SIZE {
+		return nil, fmt.Errorf("файл слишком большой")
+	}
+
+	return data, nil
+}
@@ -0,0 +1,61 @@
+package importer
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/domain"
+)
+
+// Parse Разбирает файл импорта в указанном формате. Ошибка возвращается, только если файл
+// нельзя прочитать целиком, ошибки отдельных объявлений попадают в ImportRecord.Error
+func Parse(format string, r io.Reader, maxRows int) ([]domain.ImportRecord, error) {
+	switch format {
+	case domain.ImportFormatCSV:
+		return ParseCSV(r, maxRows)
+	case domain.ImportFormatYandex:
+		return ParseYandexFeed(r, maxRows)
+	default:
+		return nil, fmt.Errorf("неизвестный формат импорта")
+	}
+}
+
+// DetectFormat Определяет формат по расширению файла, если он не указан явно
+func DetectFormat(filename string) string {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return domain.ImportFormatCSV
+	case ".xml":
+		return domain.ImportFormatYandex
+	default:
+		return ""
+	}
+}
+
+// parseNumber Читает целое число, допуская пробелы между разрядами и дробную часть,
+// которая округляется. Пустая строка считается нулем
+func parseNumber(value string) (int, error) {
+	value = strings.Join(strings.Fields(value), "")
+	if value == "" {
+		return 0, nil
+	}
+	if n, err := strconv.Atoi(value); err == nil {
+		return n, nil
+	}
+	f, err := strconv.ParseFloat(strings.ReplaceAll(value, ",", "."), 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) || math.Abs(f) > math.MaxInt32 {
+		return 0, fmt.Errorf("некорректное число %q", value)
+	}
+	return int(math.Round(f)), nil
+}
+
+// splitImageURLs Ссылки на фотографии разделяются вертикальной чертой или пробельными символами
+func splitImageURLs(value string) []string {
+	return strings.FieldsFunc(value, func(r rune) bool {
+		return r == '|' || r == ' ' || r == '\t' || r == '\n' || r == '\r'
+	})
+}
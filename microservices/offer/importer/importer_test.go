@@ -0,0 +1,151 @@
+package importer
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/domain"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/content"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCSV(t *testing.T) {
+	data := "\xef\xbb\xbfexternal_id;offer_type;property_type;renovation;address;price;area;floor;images;comment\n" +
+		"A-1;Продажа;Квартира;Современный ремонт;Москва, Тверская, 7;\"5 000 000\";45,5;3;https://example.com/1.jpg|https://example.com/2.jpg;лишняя колонка\n" +
+		"A-2;Аренда;Дом;Черновая отделка;Москва, Арбат, 1;много;40;1;;\n"
+
+	records, err := ParseCSV(strings.NewReader(data), 10)
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+
+	first := records[0]
+	assert.Equal(t, 2, first.Row)
+	assert.Equal(t, "A-1", first.ExternalID)
+	assert.Equal(t, "Москва, Тверская, 7", first.Address)
+	assert.Equal(t, 5000000, first.Price)
+	assert.Equal(t, 46, first.Area)
+	assert.Equal(t, []string{"https://example.com/1.jpg", "https://example.com/2.jpg"}, first.ImageURLs)
+	assert.Empty(t, first.Error)
+
+	assert.Equal(t, 3, records[1].Row)
+	assert.Equal(t, "некорректное значение в колонке price", records[1].Error)
+}
+
+func TestParseCSVErrors(t *testing.T) {
+	_, err := ParseCSV(strings.NewReader("external_id,offer_type\nA-1,Продажа\n"), 10)
+	assert.EqualError(t, err, "в файле нет колонки property_type")
+
+	header := "external_id,offer_type,property_type,renovation,address\n"
+	_, err = ParseCSV(strings.NewReader(header+"1,a,b,c,d\n2,a,b,c,d\n"), 1)
+	assert.EqualError(t, err, "в файле больше 1 объявлений")
+}
+
+func TestParseYandexFeed(t *testing.T) {
+	file, err := os.Open("testdata/feed.xml")
+	require.NoError(t, err)
+	defer file.Close()
+
+	records, err := ParseYandexFeed(file, 10)
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+
+	sale := records[0]
+	assert.Equal(t, domain.ImportRecord{
+		Row:           1,
+		ExternalID:    "A-1",
+		OfferType:     "продажа",
+		PurchaseType:  "Новостройка",
+		PropertyType:  "Квартира",
+		Renovation:    "Современный ремонт",
+		MetroStation:  "Ленинский проспект",
+		Address:       "Москва, Ленинский проспект, 30",
+		Description:   "Светлая квартира у метро",
+		Flat:          12,
+		Price:         12500000,
+		Area:          55,
+		Rooms:         2,
+		Floor:         4,
+		TotalFloors:   17,
+		CeilingHeight: 3,
+		ImageURLs:     []string{"https://example.com/a1/1.jpg", "https://example.com/a1/2.jpg"},
+	}, sale)
+
+	rent := records[1]
+	assert.Equal(t, "A-2", rent.ExternalID)
+	assert.Equal(t, "Апартаменты", rent.PropertyType)
+	assert.Equal(t, "Долгосрок", rent.RentType)
+	assert.Empty(t, rent.PurchaseType)
+	assert.Equal(t, "Москва, улица Тверская, 7", rent.Address)
+	assert.Equal(t, "некорректное значение в элементе floor", rent.Error)
+
+	_, err = ParseYandexFeed(strings.NewReader("<realty-feed><offer>"), 10)
+	assert.Error(t, err)
+}
+
+func TestParseFormat(t *testing.T) {
+	assert.Equal(t, domain.ImportFormatCSV, DetectFormat("offers.CSV"))
+	assert.Equal(t, domain.ImportFormatYandex, DetectFormat("feed.xml"))
+	assert.Empty(t, DetectFormat("offers.xlsx"))
+
+	_, err := Parse("xlsx", strings.NewReader(""), 10)
+	assert.EqualError(t, err, "неизвестный формат импорта")
+}
+
+func TestHTTPDownloader(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/photo.jpg":
+			w.Write([]byte("image"))
+		case "/large.jpg":
+			w.Write(make([]byte, content.MAX_SIZE+1))
+		case "/loop.jpg":
+			http.Redirect(w, r, "/loop.jpg", http.StatusFound)
+		case "/internal.jpg":
+			http.Redirect(w, r, "http://10.0.0.1/photo.jpg", http.StatusFound)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	// Тестовый сервер слушает loopback, поэтому разрешен только он
+	downloader := newHTTPDownloader(time.Second, func(ip net.IP) bool { return ip.IsLoopback() })
+
+	data, err := downloader.Download(context.Background(), server.URL+"/photo.jpg")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("image"), data)
+
+	_, err = downloader.Download(context.Background(), server.URL+"/missing.jpg")
+	assert.EqualError(t, err, "не удалось скачать файл: статус 404")
+
+	_, err = downloader.Download(context.Background(), server.URL+"/large.jpg")
+	assert.EqualError(t, err, "файл слишком большой")
+
+	_, err = downloader.Download(context.Background(), "file:///etc/passwd")
+	assert.EqualError(t, err, "некорректная ссылка")
+
+	_, err = downloader.Download(context.Background(), server.URL+"/loop.jpg")
+	assert.EqualError(t, err, "не удалось скачать файл")
+
+	_, err = downloader.Download(context.Background(), server.URL+"/internal.jpg")
+	assert.EqualError(t, err, "ссылка ведет на недоступный адрес")
+
+	_, err = NewHTTPDownloader(time.Second).Download(context.Background(), server.URL+"/photo.jpg")
+	assert.EqualError(t, err, "ссылка ведет на недоступный адрес")
+}
+
+func TestIsPublicIP(t *testing.T) {
+	for _, addr := range []string{"127.0.0.1", "::1", "10.1.2.3", "172.16.0.5", "192.168.1.1", "169.254.169.254", "fe80::1", "fd00::1", "0.0.0.0", "100.64.0.1", "::ffff:127.0.0.1"} {
+		assert.False(t, isPublicIP(net.ParseIP(addr)), addr)
+	}
+	for _, addr := range []string{"8.8.8.8", "77.88.55.60", "2a02:6b8::2:242"} {
+		assert.True(t, isPublicIP(net.ParseIP(addr)), addr)
+	}
+}
@@ -0,0 +1,206 @@
+package importer
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/domain"
+)
+
+// yandexOffer Поля элемента <offer> фида Яндекс.Недвижимости, которые есть в нашей модели.
+// Числа читаются строками, чтобы ошибка в одном объявлении не прерывала разбор фида
+type yandexOffer struct {
+	InternalID string `xml:"internal-id,attr"`
+	Type       string `xml:"type"`
+	Category   string `xml:"category"`
+	Apartments string `xml:"apartments"`
+	NewFlat    string `xml:"new-flat"`
+	Location   struct {
+		LocalityName string `xml:"locality-name"`
+		Address      string `xml:"address"`
+		Apartment    string `xml:"apartment"`
+		Metro        []struct {
+			Name string `xml:"name"`
+		} `xml:"metro"`
+	} `xml:"location"`
+	Price struct {
+		Value  string `xml:"value"`
+		Period string `xml:"period"`
+	} `xml:"price"`
+	Area struct {
+		Value string `xml:"value"`
+	} `xml:"area"`
+	Rooms         string   `xml:"rooms"`
+	Floor         string   `xml:"floor"`
+	FloorsTotal   string   `xml:"floors-total"`
+	CeilingHeight string   `xml:"ceiling-height"`
+	Renovation    string   `xml:"renovation"`
+	Description   string   `xml:"description"`
+	Images        []string `xml:"image"`
+}
+
+// Значения фида, которые называются не так, как в наших справочниках. Остальные
+// передаются как есть и сопоставляются по названию
+var (
+	yandexCategories = map[string]string{
+		"квартира":   "Квартира",
+		"flat":       "Квартира",
+		"дом":        "Дом",
+		"house":      "Дом",
+		"коттедж":    "Дом",
+		"cottage":    "Дом",
+		"таунхаус":   "Дом",
+		"townhouse":  "Дом",
+		"дача":       "Дом",
+		"часть дома": "Дом",
+	}
+
+	yandexRenovations = map[string]string{
+		"евро":               "Современный ремонт",
+		"евроремонт":         "Современный ремонт",
+		"дизайнерский":       "Современный ремонт",
+		"хороший":            "Современный ремонт",
+		"с отделкой":         "Современный ремонт",
+		"косметический":      "Косметический ремонт",
+		"черновая отделка":   "Черновая отделка",
+		"без отделки":        "Черновая отделка",
+		"предчистовая":       "Улучшенная черновая",
+		"чистовая отделка":   "Улучшенная черновая",
+		"требует ремонта":    "Нужен полный ремонт",
+		"частичный ремонт":   "Нужен частичный ремонт",
+		"требует частичного": "Нужен частичный ремонт",
+	}
+
+	yandexRentPeriods = map[string]string{
+		"день":  "Посуточно",
+		"day":   "Посуточно",
+		"месяц": "Долгосрок",
+		"month": "Долгосрок",
+	}
+)
+
+// ParseYandexFeed Разбирает XML-фид Яндекс.Недвижимости. Row в результате - порядковый
+// номер элемента <offer> в фиде, ExternalID - его атрибут internal-id
+func ParseYandexFeed(r io.Reader, maxRows int) ([]domain.ImportRecord, error) {
+	decoder := xml.NewDecoder(r)
+
+	var records []domain.ImportRecord
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("некорректный XML фида")
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "offer" {
+			continue
+		}
+		if len(records) == maxRows {
+			return nil, fmt.Errorf("в файле больше %d объявлений", maxRows)
+		}
+
+		var offer yandexOffer
+		if err := decoder.DecodeElement(&offer, &start); err != nil {
+			return nil, fmt.Errorf("некорректный XML фида")
+		}
+		records = append(records, yandexRecord(len(records)+1, offer))
+	}
+
+	if len(records) == 0 {
+		return nil, fmt.Errorf("в фиде нет объявлений")
+	}
+
+	return records, nil
+}
+
+func yandexRecord(row int, offer yandexOffer) domain.ImportRecord {
+	record := domain.ImportRecord{
+		Row:          row,
+		ExternalID:   strings.TrimSpace(offer.InternalID),
+		OfferType:    strings.TrimSpace(offer.Type),
+		PropertyType: yandexAlias(yandexCategories, offer.Category),
+		Renovation:   yandexAlias(yandexRenovations, offer.Renovation),
+		Address:      yandexAddress(offer.Location.LocalityName, offer.Location.Address),
+		Description:  strings.TrimSpace(offer.Description),
+	}
+	if record.PropertyType == "Квартира" && yandexBool(offer.Apartments) {
+		record.PropertyType = "Апартаменты"
+	}
+	if len(offer.Location.Metro) > 0 {
+		record.MetroStation = strings.TrimSpace(offer.Location.Metro[0].Name)
+	}
+
+	switch domain.NormalizeDictionaryName(offer.Type) {
+	case "продажа":
+		record.PurchaseType = "Вторичка"
+		if yandexBool(offer.NewFlat) {
+			record.PurchaseType = "Новостройка"
+		}
+	case "аренда":
+		record.RentType = yandexAlias(yandexRentPeriods, offer.Price.Period)
+	}
+
+	for _, url := range offer.Images {
+		if url = strings.TrimSpace(url); url != "" {
+			record.ImageURLs = append(record.ImageURLs, url)
+		}
+	}
+
+	numbers := []struct {
+		element string
+		value   string
+		dst     *int
+	}{
+		{"apartment", offer.Location.Apartment, &record.Flat},
+		{"price", offer.Price.Value, &record.Price},
+		{"area", offer.Area.Value, &record.Area},
+		{"rooms", offer.Rooms, &record.Rooms},
+		{"floor", offer.Floor, &record.Floor},
+		{"floors-total", offer.FloorsTotal, &record.TotalFloors},
+		{"ceiling-height", offer.CeilingHeight, &record.CeilingHeight},
+	}
+	for _, n := range numbers {
+		value, err := parseNumber(n.value)
+		if err != nil {
+			record.Error = fmt.Sprintf("некорректное значение в элементе %s", n.element)
+			return record
+		}
+		*n.dst = value
+	}
+
+	return record
+}
+
+func yandexAlias(aliases map[string]string, value string) string {
+	if alias, ok := aliases[domain.NormalizeDictionaryName(value)]; ok {
+		return alias
+	}
+	return strings.TrimSpace(value)
+}
+
+// yandexAddress В фиде город и улица с домом лежат в разных элементах, геокодеру нужен полный адрес
+func yandexAddress(locality, address string) string {
+	locality, address = strings.TrimSpace(locality), strings.TrimSpace(address)
+	if locality == "" || strings.Contains(address, locality) {
+		return address
+	}
+	if address == "" {
+		return ""
+	}
+	return locality + ", " + address
+}
+
+func yandexBool(value string) bool {
+	switch domain.NormalizeDictionaryName(value) {
+	case "да", "true", "1", "+":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,51 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-park-mail-ru/2025_1_404/config"
+)
+
+const (
+	ChannelInApp = "inapp"
+	ChannelEmail = "email"
+)
+
+// Message Уведомление для доставки одному пользователю
+type Message struct {
+	UserID  int
+	Email   string
+	Subject string
+	Text    string
+	URL     string
+}
+
+// Channel Канал доставки уведомлений. Запись в kvartirum.UserNotification делается всегда,
+// канал отвечает только за доставку сверх нее
+type Channel interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// NewChannel Канал по настройке channel из конфига, пустое значение - inapp
+func NewChannel(cfg config.PriceAlertsConfig) (Channel, error) {
+	switch cfg.Channel {
+	case "", ChannelInApp:
+		return NewInAppChannel(), nil
+	case ChannelEmail:
+		return NewSMTPChannel(cfg.SMTP), nil
+	default:
+		return nil, fmt.Errorf("неизвестный канал уведомлений %q", cfg.Channel)
+	}
+}
+
+type inAppChannel struct{}
+
+// NewInAppChannel Фронтенд сам забирает уведомления из kvartirum.UserNotification, доставлять нечего
+func NewInAppChannel() Channel {
+	return inAppChannel{}
+}
+
+func (inAppChannel) Send(context.Context, Message) error {
+	return nil
+}
@@ -0,0 +1,70 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"net/smtp"
+	"strings"
+	"testing"
+
+	"github.com/go-park-mail-ru/2025_1_404/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewChannel(t *testing.T) {
+	ch, err := NewChannel(config.PriceAlertsConfig{})
+	require.NoError(t, err)
+	assert.IsType(t, inAppChannel{}, ch)
+	assert.NoError(t, ch.Send(context.Background(), Message{UserID: 1}))
+
+	ch, err = NewChannel(config.PriceAlertsConfig{Channel: ChannelEmail, SMTP: config.SMTPConfig{Host: "mailhog", Port: 1025}})
+	require.NoError(t, err)
+	assert.Equal(t, "mailhog:1025", ch.(*smtpChannel).addr)
+
+	_, err = NewChannel(config.PriceAlertsConfig{Channel: "sms"})
+	assert.Error(t, err)
+}
+
+func TestSMTPChannel(t *testing.T) {
+	ch := NewSMTPChannel(config.SMTPConfig{Host: "mailhog", Port: 1025, From: "noreply@kvartirum.ru"}).(*smtpChannel)
+
+	var (
+		sentTo  []string
+		sentMsg string
+		calls   int
+	)
+	ch.sendMail = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		calls++
+		assert.Equal(t, "mailhog:1025", addr)
+		assert.Nil(t, a)
+		assert.Equal(t, "noreply@kvartirum.ru", from)
+		sentTo = to
+		sentMsg = string(msg)
+		return nil
+	}
+
+	err := ch.Send(context.Background(), Message{
+		Email:   "ivan@mail.ru",
+		Subject: "Цена снижена",
+		Text:    "Цена снижена: 5 000 000 → 4 500 000 ₽",
+		URL:     "https://kvartirum.ru/offer/1",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ivan@mail.ru"}, sentTo)
+	assert.Contains(t, sentMsg, "Subject: =?utf-8?q?")
+	assert.Contains(t, sentMsg, "Content-Type: text/plain; charset=utf-8\r\n")
+	assert.True(t, strings.HasSuffix(sentMsg, "4 500 000 ₽\r\n\r\nhttps://kvartirum.ru/offer/1\r\n"))
+
+	t.Run("no email", func(t *testing.T) {
+		require.NoError(t, ch.Send(context.Background(), Message{UserID: 2}))
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("send error", func(t *testing.T) {
+		ch.sendMail = func(string, smtp.Auth, string, []string, []byte) error {
+			return errors.New("connection refused")
+		}
+		assert.Error(t, ch.Send(context.Background(), Message{Email: "ivan@mail.ru"}))
+	})
+}
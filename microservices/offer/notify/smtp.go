@@ -0,0 +1,66 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"time"
+
+	"github.com/go-park-mail-ru/2025_1_404/config"
+)
+
+type sendMailFunc func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+
+type smtpChannel struct {
+	addr     string
+	from     string
+	auth     smtp.Auth
+	sendMail sendMailFunc
+}
+
+// NewSMTPChannel Письма через SMTP. Без имени пользователя отправляет без авторизации,
+// как принимает локальный mailhog
+func NewSMTPChannel(cfg config.SMTPConfig) Channel {
+	c := &smtpChannel{
+		addr:     net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port)),
+		from:     cfg.From,
+		sendMail: smtp.SendMail,
+	}
+	if cfg.Username != "" {
+		c.auth = smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)
+	}
+	return c
+}
+
+func (c *smtpChannel) Send(ctx context.Context, msg Message) error {
+	if msg.Email == "" {
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return c.sendMail(c.addr, c.auth, c.from, []string{msg.Email}, c.buildMessage(msg))
+}
+
+func (c *smtpChannel) buildMessage(msg Message) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", c.from)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.Email)
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(msg.Text)
+	if msg.URL != "" {
+		b.WriteString("\r\n\r\n")
+		b.WriteString(msg.URL)
+	}
+	b.WriteString("\r\n")
+	return b.Bytes()
+}
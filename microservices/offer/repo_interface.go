@@ -12,18 +12,26 @@ import (
 type OfferRepository interface {
 	CreateOffer(ctx context.Context, offer repository.Offer) (int64, error)
 	GetOfferByID(ctx context.Context, id int64) (repository.Offer, error)
-	GetOffersBySellerID(ctx context.Context, sellerID int64) ([]repository.Offer, error)
-	GetAllOffers(ctx context.Context) ([]repository.Offer, error)
-	GetOffersByFilter(ctx context.Context, f domain.OfferFilter, pUserId *int) ([]repository.Offer, error)
+	GetOffersBySellerID(ctx context.Context, sellerID int64, page domain.Pagination) ([]repository.Offer, int, error)
+	GetAllOffers(ctx context.Context, page domain.Pagination) ([]repository.Offer, int, error)
+	GetOffersByFilter(ctx context.Context, f domain.OfferFilter, page domain.Pagination, pUserId *int) ([]repository.Offer, int, error)
+	GetOfferClusters(ctx context.Context, f domain.OfferFilter, cellSize float64, pUserId *int) ([]domain.OfferCluster, error)
 	UpdateOffer(ctx context.Context, offer repository.Offer) error
 	DeleteOffer(ctx context.Context, id int64) error
-	CreateImageAndBindToOffer(ctx context.Context, offerID int, uuid string) (int64, error)
-	UpdateOfferStatus(ctx context.Context, offerID int, statusID int) error
-	GetOfferData(ctx context.Context, offer domain.Offer, userID *int) (domain.OfferData, error)
-	GetOfferImageWithUUID(ctx context.Context, imageID int64) (int64, string, error)
+	CreateImageAndBindToOffer(ctx context.Context, offerID int, uuid string, meta domain.ImageMeta) (int64, error)
+	ChangeOfferStatus(ctx context.Context, offerID int, from int, to int, userID int, reason string) (bool, error)
+	GetOffersData(ctx context.Context, offerIDs []int) (map[int]domain.OfferData, error)
+	GetOffersUserStat(ctx context.Context, offerIDs []int, userID int) (map[int]domain.OfferUserStat, error)
+	GetOfferImageWithUUID(ctx context.Context, imageID int64) (int64, string, []int, error)
 	DeleteOfferImage(ctx context.Context, imageID int64) error
+	ReorderOfferImages(ctx context.Context, offerID int, imageIDs []int64) (bool, error)
+	SetOfferCover(ctx context.Context, offerID int, imageID int64) (bool, error)
+	GetStoredImages(ctx context.Context) ([]domain.StoredImage, error)
+	DeleteImages(ctx context.Context, ids []int64) (int, error)
 	GetOffersByZhkId(ctx context.Context, zhkId int) ([]domain.Offer, error)
 	GetStations(ctx context.Context) ([]domain.Metro, error)
+	GetDictionaries(ctx context.Context) (domain.Dictionaries, error)
+	HousingComplexExists(ctx context.Context, id int) (bool, error)
 	IsOfferLiked(ctx context.Context, like domain.LikeRequest) (bool, error)
 	CreateLike(ctx context.Context, like domain.LikeRequest) error
 	DeleteLike(ctx context.Context, like domain.LikeRequest) error
@@ -31,11 +39,45 @@ type OfferRepository interface {
 	IncrementView(ctx context.Context, id int) error
 	AddOrUpdatePriceHistory(ctx context.Context, offerID int64, price int) error
 	DeletePriceHistory(ctx context.Context, offerID int64) error
-	GetPriceHistory(ctx context.Context, offerID int64, limit int) ([]domain.OfferPriceHistory, error)
+	GetPriceHistories(ctx context.Context, offerIDs []int, limit int) (map[int][]domain.OfferPriceHistory, error)
+	GetFullPriceHistory(ctx context.Context, offerID int64) ([]domain.OfferPriceHistory, error)
+	SavePriceDropNotifications(ctx context.Context, alert domain.PriceDropAlert, defaultMinPercent int, notification domain.Notification) ([]domain.PriceDropRecipient, error)
+	GetPriceAlertSettings(ctx context.Context, userID int) (domain.PriceAlertSettings, error)
+	SavePriceAlertSettings(ctx context.Context, userID int, settings domain.PriceAlertSettings) error
 	AddFavorite(ctx context.Context, userID, offerID int) error
 	RemoveFavorite(ctx context.Context, userID, offerID int) error
-	GetFavorites(ctx context.Context, userID int64, offerTypeID *int) ([]repository.Offer, error)
+	GetFavorites(ctx context.Context, userID int64, offerTypeID *int, collectionID *int, statusID *int, page domain.Pagination) ([]repository.Offer, int, error)
 	IsFavorite(ctx context.Context, userID, offerID int) (bool, error)
 	GetFavoriteStat(ctx context.Context, req domain.FavoriteRequest) (int, error)
 	SetPromotesUntil(ctx context.Context, id int, until time.Time) error
+	CreateSavedSearch(ctx context.Context, search domain.SavedSearch) (domain.SavedSearch, error)
+	GetSavedSearches(ctx context.Context, userID int) ([]domain.SavedSearch, error)
+	DeleteSavedSearch(ctx context.Context, id int, userID int) error
+	GetDueSavedSearches(ctx context.Context, now time.Time, limit int) ([]domain.SavedSearch, error)
+	SaveSearchAlerts(ctx context.Context, search domain.SavedSearch, checkedAt time.Time, notifications []domain.Notification) error
+	NotifyExpiringOffers(ctx context.Context, offerTypeID int, renewedBefore time.Time, limit int, message string) (int, error)
+	ExpireOffers(ctx context.Context, offerTypeID int, renewedBefore time.Time, limit int, reason string, message string) (int, error)
+	ExtendOffer(ctx context.Context, offerID int, renewedAt time.Time) (bool, error)
+	FindDuplicateOffers(ctx context.Context, c domain.DuplicateCandidate) ([]domain.OfferDuplicate, error)
+	SaveOfferDuplicates(ctx context.Context, offerID int, matches []domain.OfferDuplicate) error
+	GetOfferDuplicates(ctx context.Context, offerID *int, limit int) ([]domain.OfferDuplicate, error)
+	GetUserRole(ctx context.Context, userID int) (string, error)
+	GetImportDictionaries(ctx context.Context) (domain.ImportDictionaries, error)
+	GetOfferIDByExternalID(ctx context.Context, sellerID int, externalID string) (int, error)
+	SetOfferExternalID(ctx context.Context, offerID int, externalID string) error
+	GetFeedOffers(ctx context.Context, sellerIDs []int, updatedAfter time.Time, activeOnly bool) ([]domain.FeedOffer, error)
+	GetActiveOfferIDs(ctx context.Context, sellerIDs []int) ([]int, error)
+	GetOfferStats(ctx context.Context, offerID int, period domain.StatsPeriod) ([]domain.StatsPoint, error)
+	GetSellerStats(ctx context.Context, sellerID int, period domain.StatsPeriod) ([]domain.SellerOfferStats, error)
+	GetDefaultCollectionID(ctx context.Context, userID int) (int, error)
+	GetCollections(ctx context.Context, userID int) ([]domain.FavoriteCollection, error)
+	GetCollectionByShareToken(ctx context.Context, token string) (domain.FavoriteCollection, error)
+	CreateCollection(ctx context.Context, collection domain.FavoriteCollection) (domain.FavoriteCollection, error)
+	RenameCollection(ctx context.Context, id int, userID int, name string) (domain.FavoriteCollection, error)
+	SetCollectionShareToken(ctx context.Context, id int, userID int, token *string) (domain.FavoriteCollection, error)
+	DeleteCollection(ctx context.Context, id int, userID int, defaultID int) error
+	AddToCollection(ctx context.Context, userID int, collectionID int, offerID int) error
+	MoveToCollection(ctx context.Context, userID int, fromID int, toID int, offerID int) error
+	RemoveFromCollection(ctx context.Context, userID int, collectionID int, offerID int) error
+	UpdateFavoriteNote(ctx context.Context, userID int, offerID int, note *string) error
 }
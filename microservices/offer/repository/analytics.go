@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/domain"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/logger"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/utils"
+)
+
+const (
+	// getOfferStatsSQL Ряд по интервалам периода, пустые интервалы возвращаются с нулями.
+	// Лайк удаляется при снятии, поэтому в ряд попадают только действующие лайки
+	getOfferStatsSQL = `
+		WITH buckets AS (
+			SELECT generate_series(
+				date_trunc($4::text, $2::timestamp),
+				$3::timestamp - interval '1 microsecond',
+				('1 ' || $4::text)::interval
+			) AS bucket
+		),
+		views AS (
+			SELECT date_trunc($4::text, created_at) AS bucket, COUNT(*) AS cnt
+			FROM kvartirum.Views
+			WHERE offer_id = $1 AND created_at >= $2 AND created_at < $3
+			GROUP BY 1
+		),
+		likes AS (
+			SELECT date_trunc($4::text, created_at) AS bucket, COUNT(*) AS cnt
+			FROM kvartirum.Likes
+			WHERE offer_id = $1 AND created_at >= $2 AND created_at < $3
+			GROUP BY 1
+		),
+		favorites AS (
+			SELECT date_trunc($4::text, created_at) AS bucket, COUNT(*) AS cnt
+			FROM kvartirum.UserOfferFavourites
+			WHERE offer_id = $1 AND created_at >= $2 AND created_at < $3
+			GROUP BY 1
+		)
+		SELECT b.bucket, COALESCE(v.cnt, 0), COALESCE(l.cnt, 0), COALESCE(f.cnt, 0)
+		FROM buckets b
+		LEFT JOIN views v ON v.bucket = b.bucket
+		LEFT JOIN likes l ON l.bucket = b.bucket
+		LEFT JOIN favorites f ON f.bucket = b.bucket
+		ORDER BY b.bucket;
+	`
+
+	// getSellerStatsSQL Итоги по каждому объявлению продавца за период и средние по активным
+	// объявлениям других продавцов с тем же типом сделки, типом недвижимости и числом комнат
+	getSellerStatsSQL = `
+		WITH own AS (
+			SELECT id, offer_status_id, price, offer_type_id, property_type_id, rooms
+			FROM kvartirum.Offer
+			WHERE seller_id = $1
+		),
+		candidates AS (
+			SELECT o.id, o.seller_id, o.offer_type_id, o.property_type_id, o.rooms
+			FROM kvartirum.Offer o
+			WHERE o.seller_id = $1
+				OR (o.offer_status_id = 1 AND EXISTS (
+					SELECT 1 FROM own
+					WHERE own.offer_type_id = o.offer_type_id
+						AND own.property_type_id = o.property_type_id
+						AND own.rooms = o.rooms
+				))
+		),
+		stats AS (
+			SELECT c.id, c.seller_id, c.offer_type_id, c.property_type_id, c.rooms,
+				(SELECT COUNT(*) FROM kvartirum.Views v
+					WHERE v.offer_id = c.id AND v.created_at >= $2 AND v.created_at < $3) AS views,
+				(SELECT COUNT(*) FROM kvartirum.Likes l
+					WHERE l.offer_id = c.id AND l.created_at >= $2 AND l.created_at < $3) AS likes,
+				(SELECT COUNT(*) FROM kvartirum.UserOfferFavourites f
+					WHERE f.offer_id = c.id AND f.created_at >= $2 AND f.created_at < $3) AS favorites
+			FROM candidates c
+		),
+		similar AS (
+			SELECT offer_type_id, property_type_id, rooms, COUNT(*) AS cnt,
+				AVG(views) AS views, AVG(likes) AS likes, AVG(favorites) AS favorites
+			FROM stats
+			WHERE seller_id <> $1
+			GROUP BY offer_type_id, property_type_id, rooms
+		)
+		SELECT o.id, o.offer_status_id, o.price, s.views, s.likes, s.favorites,
+			COALESCE(sim.cnt, 0), COALESCE(sim.views, 0)::float8,
+			COALESCE(sim.likes, 0)::float8, COALESCE(sim.favorites, 0)::float8
+		FROM own o
+		JOIN stats s ON s.id = o.id
+		LEFT JOIN similar sim ON sim.offer_type_id = o.offer_type_id
+			AND sim.property_type_id = o.property_type_id
+			AND sim.rooms = o.rooms
+		ORDER BY o.id;
+	`
+)
+
+func (r *offerRepository) GetOfferStats(ctx context.Context, offerID int, period domain.StatsPeriod) ([]domain.StatsPoint, error) {
+	requestID := ctx.Value(utils.RequestIDKey)
+
+	rows, err := r.db.Query(ctx, getOfferStatsSQL, offerID, period.From, period.To, period.Bucket)
+
+	logFields := logger.LoggerFields{"requestID": requestID, "query": getOfferStatsSQL, "params": logger.LoggerFields{"offer_id": offerID, "from": period.From, "to": period.To, "bucket": period.Bucket}, "success": err == nil}
+	if err != nil {
+		r.logger.WithFields(logFields).Error("SQL query GetOfferStats failed")
+		return nil, err
+	}
+	defer rows.Close()
+
+	var points []domain.StatsPoint
+	for rows.Next() {
+		var p domain.StatsPoint
+		if err := rows.Scan(&p.Date, &p.Views, &p.Likes, &p.Favorites); err != nil {
+			r.logger.WithFields(logFields).Error("SQL query GetOfferStats scan failed")
+			return nil, err
+		}
+		points = append(points, p)
+	}
+	if err := rows.Err(); err != nil {
+		r.logger.WithFields(logFields).Error("SQL query GetOfferStats failed")
+		return nil, err
+	}
+	r.logger.WithFields(logFields).Info("SQL query GetOfferStats succeeded")
+
+	return points, nil
+}
+
+func (r *offerRepository) GetSellerStats(ctx context.Context, sellerID int, period domain.StatsPeriod) ([]domain.SellerOfferStats, error) {
+	requestID := ctx.Value(utils.RequestIDKey)
+
+	rows, err := r.db.Query(ctx, getSellerStatsSQL, sellerID, period.From, period.To)
+
+	logFields := logger.LoggerFields{"requestID": requestID, "query": getSellerStatsSQL, "params": logger.LoggerFields{"seller_id": sellerID, "from": period.From, "to": period.To}, "success": err == nil}
+	if err != nil {
+		r.logger.WithFields(logFields).Error("SQL query GetSellerStats failed")
+		return nil, err
+	}
+	defer rows.Close()
+
+	var offers []domain.SellerOfferStats
+	for rows.Next() {
+		var s domain.SellerOfferStats
+		if err := rows.Scan(
+			&s.OfferID, &s.StatusID, &s.Price, &s.Totals.Views, &s.Totals.Likes, &s.Totals.Favorites,
+			&s.SimilarCount, &s.SimilarViews, &s.SimilarLikes, &s.SimilarFavorites,
+		); err != nil {
+			r.logger.WithFields(logFields).Error("SQL query GetSellerStats scan failed")
+			return nil, err
+		}
+		offers = append(offers, s)
+	}
+	if err := rows.Err(); err != nil {
+		r.logger.WithFields(logFields).Error("SQL query GetSellerStats failed")
+		return nil, err
+	}
+	r.logger.WithFields(logFields).Info("SQL query GetSellerStats succeeded")
+
+	return offers, nil
+}
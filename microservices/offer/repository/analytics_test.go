@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/domain"
+	pgxmock "github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepository_GetOfferStats(t *testing.T) {
+	repo, mock := newTestRepo(t)
+	defer mock.Close()
+
+	from := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+	period := domain.StatsPeriod{From: from, To: from.AddDate(0, 0, 2), Bucket: domain.StatsBucketDay}
+
+	mock.ExpectQuery(`(?i)WITH buckets AS .*generate_series.*FROM kvartirum.Views`).
+		WithArgs(3, period.From, period.To, period.Bucket).
+		WillReturnRows(pgxmock.NewRows([]string{"bucket", "views", "likes", "favorites"}).
+			AddRow(from, 10, 2, 1).
+			AddRow(from.AddDate(0, 0, 1), 0, 0, 0))
+
+	points, err := repo.GetOfferStats(context.Background(), 3, period)
+	require.NoError(t, err)
+	require.Len(t, points, 2)
+	require.Equal(t, domain.StatsPoint{Date: from, Views: 10, Likes: 2, Favorites: 1}, points[0])
+
+	mock.ExpectQuery(`(?i)WITH buckets AS`).
+		WithArgs(3, period.From, period.To, period.Bucket).
+		WillReturnError(errors.New("db error"))
+
+	_, err = repo.GetOfferStats(context.Background(), 3, period)
+	require.Error(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_GetSellerStats(t *testing.T) {
+	repo, mock := newTestRepo(t)
+	defer mock.Close()
+
+	from := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+	period := domain.StatsPeriod{From: from, To: from.AddDate(0, 0, 30), Bucket: domain.StatsBucketDay}
+
+	mock.ExpectQuery(`(?i)WITH own AS .*WHERE seller_id = \$1.*similar AS`).
+		WithArgs(7, period.From, period.To).
+		WillReturnRows(pgxmock.NewRows([]string{
+			"id", "offer_status_id", "price", "views", "likes", "favorites",
+			"similar_count", "similar_views", "similar_likes", "similar_favorites",
+		}).
+			AddRow(1, 1, 5000000, 40, 4, 2, 3, 20.0, 1.5, 1.0).
+			AddRow(2, 2, 7000000, 0, 0, 0, 0, 0.0, 0.0, 0.0))
+
+	offers, err := repo.GetSellerStats(context.Background(), 7, period)
+	require.NoError(t, err)
+	require.Len(t, offers, 2)
+	require.Equal(t, 40, offers[0].Totals.Views)
+	require.Equal(t, 3, offers[0].SimilarCount)
+	require.Equal(t, 20.0, offers[0].SimilarViews)
+
+	mock.ExpectQuery(`(?i)WITH own AS`).
+		WithArgs(7, period.From, period.To).
+		WillReturnError(errors.New("db error"))
+
+	_, err = repo.GetSellerStats(context.Background(), 7, period)
+	require.Error(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
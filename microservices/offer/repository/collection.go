@@ -0,0 +1,338 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/domain"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/logger"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/utils"
+)
+
+const (
+	// upsertDefaultCollectionSQL Возвращает основную подборку пользователя, создавая ее при
+	// первом обращении. DO UPDATE нужен, чтобы RETURNING отдал id уже существующей подборки
+	upsertDefaultCollectionSQL = `
+		INSERT INTO kvartirum.FavoriteCollection (user_id, name, is_default)
+		VALUES ($1, 'Избранное', TRUE)
+		ON CONFLICT (user_id) WHERE is_default DO UPDATE SET is_default = TRUE
+		RETURNING id`
+
+	collectionColumns = `c.id, c.user_id, c.name, c.is_default, c.share_token, c.created_at,
+		(SELECT COUNT(*) FROM kvartirum.FavoriteCollectionOffer co WHERE co.collection_id = c.id)`
+
+	getCollectionsSQL = `
+		SELECT ` + collectionColumns + `
+		FROM kvartirum.FavoriteCollection c
+		WHERE c.user_id = $1
+		ORDER BY c.is_default DESC, c.id;
+	`
+
+	getCollectionByShareTokenSQL = `
+		SELECT ` + collectionColumns + `
+		FROM kvartirum.FavoriteCollection c
+		WHERE c.share_token = $1;
+	`
+
+	createCollectionSQL = `
+		INSERT INTO kvartirum.FavoriteCollection (user_id, name)
+		VALUES ($1, $2)
+		RETURNING id, created_at;
+	`
+
+	renameCollectionSQL = `
+		UPDATE kvartirum.FavoriteCollection c
+		SET name = $3
+		WHERE c.id = $1 AND c.user_id = $2
+		RETURNING ` + collectionColumns + `;
+	`
+
+	setCollectionShareTokenSQL = `
+		UPDATE kvartirum.FavoriteCollection c
+		SET share_token = $3
+		WHERE c.id = $1 AND c.user_id = $2
+		RETURNING ` + collectionColumns + `;
+	`
+
+	// deleteCollectionSQL Основную подборку удалить нельзя. Объявления, которых больше нет
+	// ни в одной подборке, переезжают в основную ($3) и остаются в избранном
+	deleteCollectionSQL = `
+		WITH deleted AS (
+			DELETE FROM kvartirum.FavoriteCollection
+			WHERE id = $1 AND user_id = $2 AND NOT is_default
+			RETURNING id
+		),
+		moved AS (
+			INSERT INTO kvartirum.FavoriteCollectionOffer (collection_id, offer_id)
+			SELECT $3, co.offer_id
+			FROM kvartirum.FavoriteCollectionOffer co
+			JOIN deleted d ON d.id = co.collection_id
+			WHERE NOT EXISTS (
+				SELECT 1 FROM kvartirum.FavoriteCollectionOffer other
+				JOIN kvartirum.FavoriteCollection c ON c.id = other.collection_id
+				WHERE c.user_id = $2 AND other.offer_id = co.offer_id AND other.collection_id <> co.collection_id
+			)
+			ON CONFLICT DO NOTHING
+		)
+		SELECT id FROM deleted;
+	`
+
+	// addToCollectionSQL Добавление в подборку заодно добавляет объявление в избранное
+	addToCollectionSQL = `
+		WITH collection AS (
+			SELECT id FROM kvartirum.FavoriteCollection WHERE id = $2 AND user_id = $1
+		),
+		favorite AS (
+			INSERT INTO kvartirum.UserOfferFavourites (user_id, offer_id)
+			SELECT $1, $3 FROM collection
+			ON CONFLICT DO NOTHING
+		),
+		added AS (
+			INSERT INTO kvartirum.FavoriteCollectionOffer (collection_id, offer_id)
+			SELECT id, $3 FROM collection
+			ON CONFLICT DO NOTHING
+		)
+		SELECT id FROM collection;
+	`
+
+	moveToCollectionSQL = `
+		WITH target AS (
+			SELECT id FROM kvartirum.FavoriteCollection WHERE id = $3 AND user_id = $1
+		),
+		moved AS (
+			DELETE FROM kvartirum.FavoriteCollectionOffer co
+			USING kvartirum.FavoriteCollection c, target t
+			WHERE c.id = co.collection_id AND c.user_id = $1 AND co.collection_id = $2 AND co.offer_id = $4
+			RETURNING co.offer_id, t.id AS target_id
+		),
+		added AS (
+			INSERT INTO kvartirum.FavoriteCollectionOffer (collection_id, offer_id)
+			SELECT target_id, offer_id FROM moved
+			ON CONFLICT DO NOTHING
+		)
+		SELECT offer_id FROM moved;
+	`
+
+	// removeFromCollectionSQL Объявление, убранное из последней подборки, пропадает из избранного
+	removeFromCollectionSQL = `
+		WITH removed AS (
+			DELETE FROM kvartirum.FavoriteCollectionOffer co
+			USING kvartirum.FavoriteCollection c
+			WHERE c.id = co.collection_id AND c.user_id = $1 AND co.collection_id = $2 AND co.offer_id = $3
+			RETURNING co.offer_id
+		),
+		unfavorited AS (
+			DELETE FROM kvartirum.UserOfferFavourites f
+			USING removed r
+			WHERE f.user_id = $1 AND f.offer_id = r.offer_id
+				AND NOT EXISTS (
+					SELECT 1 FROM kvartirum.FavoriteCollectionOffer co
+					JOIN kvartirum.FavoriteCollection c ON c.id = co.collection_id
+					WHERE c.user_id = $1 AND co.offer_id = r.offer_id AND co.collection_id <> $2
+				)
+		)
+		SELECT offer_id FROM removed;
+	`
+
+	updateFavoriteNoteSQL = `
+		UPDATE kvartirum.UserOfferFavourites
+		SET note = $3
+		WHERE user_id = $1 AND offer_id = $2
+		RETURNING offer_id;
+	`
+)
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanCollection(row rowScanner) (domain.FavoriteCollection, error) {
+	var c domain.FavoriteCollection
+	err := row.Scan(&c.ID, &c.UserID, &c.Name, &c.IsDefault, &c.ShareToken, &c.CreatedAt, &c.OffersCount)
+	return c, err
+}
+
+func (r *offerRepository) GetDefaultCollectionID(ctx context.Context, userID int) (int, error) {
+	requestID := ctx.Value(utils.RequestIDKey)
+
+	var id int
+	err := r.db.QueryRow(ctx, upsertDefaultCollectionSQL, userID).Scan(&id)
+
+	logFields := logger.LoggerFields{"requestID": requestID, "query": upsertDefaultCollectionSQL, "params": logger.LoggerFields{"user_id": userID}, "success": err == nil}
+	if err != nil {
+		r.logger.WithFields(logFields).Error("SQL query GetDefaultCollectionID failed")
+		return 0, err
+	}
+	r.logger.WithFields(logFields).Info("SQL query GetDefaultCollectionID succeeded")
+
+	return id, nil
+}
+
+func (r *offerRepository) GetCollections(ctx context.Context, userID int) ([]domain.FavoriteCollection, error) {
+	requestID := ctx.Value(utils.RequestIDKey)
+
+	rows, err := r.db.Query(ctx, getCollectionsSQL, userID)
+
+	logFields := logger.LoggerFields{"requestID": requestID, "query": getCollectionsSQL, "params": logger.LoggerFields{"user_id": userID}, "success": err == nil}
+	if err != nil {
+		r.logger.WithFields(logFields).Error("SQL query GetCollections failed")
+		return nil, err
+	}
+	defer rows.Close()
+
+	collections := make([]domain.FavoriteCollection, 0)
+	for rows.Next() {
+		c, err := scanCollection(rows)
+		if err != nil {
+			r.logger.WithFields(logFields).Error("SQL query GetCollections scan failed")
+			return nil, err
+		}
+		collections = append(collections, c)
+	}
+	if err := rows.Err(); err != nil {
+		r.logger.WithFields(logFields).Error("SQL query GetCollections failed")
+		return nil, err
+	}
+	r.logger.WithFields(logFields).Info("SQL query GetCollections succeeded")
+
+	return collections, nil
+}
+
+func (r *offerRepository) GetCollectionByShareToken(ctx context.Context, token string) (domain.FavoriteCollection, error) {
+	requestID := ctx.Value(utils.RequestIDKey)
+
+	c, err := scanCollection(r.db.QueryRow(ctx, getCollectionByShareTokenSQL, token))
+
+	logFields := logger.LoggerFields{"requestID": requestID, "query": getCollectionByShareTokenSQL, "success": err == nil}
+	if err != nil {
+		r.logger.WithFields(logFields).Warn("SQL query GetCollectionByShareToken failed")
+		return domain.FavoriteCollection{}, err
+	}
+	r.logger.WithFields(logFields).Info("SQL query GetCollectionByShareToken succeeded")
+
+	return c, nil
+}
+
+func (r *offerRepository) CreateCollection(ctx context.Context, collection domain.FavoriteCollection) (domain.FavoriteCollection, error) {
+	requestID := ctx.Value(utils.RequestIDKey)
+
+	err := r.db.QueryRow(ctx, createCollectionSQL, collection.UserID, collection.Name).Scan(&collection.ID, &collection.CreatedAt)
+
+	logFields := logger.LoggerFields{"requestID": requestID, "query": createCollectionSQL, "params": logger.LoggerFields{"user_id": collection.UserID, "name": collection.Name}, "success": err == nil}
+	if err != nil {
+		r.logger.WithFields(logFields).Error("SQL query CreateCollection failed")
+		return domain.FavoriteCollection{}, err
+	}
+	r.logger.WithFields(logFields).Info("SQL query CreateCollection succeeded")
+
+	return collection, nil
+}
+
+func (r *offerRepository) RenameCollection(ctx context.Context, id int, userID int, name string) (domain.FavoriteCollection, error) {
+	requestID := ctx.Value(utils.RequestIDKey)
+
+	c, err := scanCollection(r.db.QueryRow(ctx, renameCollectionSQL, id, userID, name))
+
+	logFields := logger.LoggerFields{"requestID": requestID, "query": renameCollectionSQL, "params": logger.LoggerFields{"id": id, "user_id": userID, "name": name}, "success": err == nil}
+	if err != nil {
+		r.logger.WithFields(logFields).Error("SQL query RenameCollection failed")
+		return domain.FavoriteCollection{}, err
+	}
+	r.logger.WithFields(logFields).Info("SQL query RenameCollection succeeded")
+
+	return c, nil
+}
+
+func (r *offerRepository) SetCollectionShareToken(ctx context.Context, id int, userID int, token *string) (domain.FavoriteCollection, error) {
+	requestID := ctx.Value(utils.RequestIDKey)
+
+	c, err := scanCollection(r.db.QueryRow(ctx, setCollectionShareTokenSQL, id, userID, token))
+
+	logFields := logger.LoggerFields{"requestID": requestID, "query": setCollectionShareTokenSQL, "params": logger.LoggerFields{"id": id, "user_id": userID, "shared": token != nil}, "success": err == nil}
+	if err != nil {
+		r.logger.WithFields(logFields).Error("SQL query SetCollectionShareToken failed")
+		return domain.FavoriteCollection{}, err
+	}
+	r.logger.WithFields(logFields).Info("SQL query SetCollectionShareToken succeeded")
+
+	return c, nil
+}
+
+func (r *offerRepository) DeleteCollection(ctx context.Context, id int, userID int, defaultID int) error {
+	requestID := ctx.Value(utils.RequestIDKey)
+
+	var deletedID int
+	err := r.db.QueryRow(ctx, deleteCollectionSQL, id, userID, defaultID).Scan(&deletedID)
+
+	logFields := logger.LoggerFields{"requestID": requestID, "query": deleteCollectionSQL, "params": logger.LoggerFields{"id": id, "user_id": userID, "default_id": defaultID}, "success": err == nil}
+	if err != nil {
+		r.logger.WithFields(logFields).Error("SQL query DeleteCollection failed")
+		return err
+	}
+	r.logger.WithFields(logFields).Info("SQL query DeleteCollection succeeded")
+
+	return nil
+}
+
+func (r *offerRepository) AddToCollection(ctx context.Context, userID int, collectionID int, offerID int) error {
+	requestID := ctx.Value(utils.RequestIDKey)
+
+	var id int
+	err := r.db.QueryRow(ctx, addToCollectionSQL, userID, collectionID, offerID).Scan(&id)
+
+	logFields := logger.LoggerFields{"requestID": requestID, "query": addToCollectionSQL, "params": logger.LoggerFields{"user_id": userID, "collection_id": collectionID, "offer_id": offerID}, "success": err == nil}
+	if err != nil {
+		r.logger.WithFields(logFields).Error("SQL query AddToCollection failed")
+		return err
+	}
+	r.logger.WithFields(logFields).Info("SQL query AddToCollection succeeded")
+
+	return nil
+}
+
+func (r *offerRepository) MoveToCollection(ctx context.Context, userID int, fromID int, toID int, offerID int) error {
+	requestID := ctx.Value(utils.RequestIDKey)
+
+	var movedID int
+	err := r.db.QueryRow(ctx, moveToCollectionSQL, userID, fromID, toID, offerID).Scan(&movedID)
+
+	logFields := logger.LoggerFields{"requestID": requestID, "query": moveToCollectionSQL, "params": logger.LoggerFields{"user_id": userID, "from_id": fromID, "to_id": toID, "offer_id": offerID}, "success": err == nil}
+	if err != nil {
+		r.logger.WithFields(logFields).Error("SQL query MoveToCollection failed")
+		return err
+	}
+	r.logger.WithFields(logFields).Info("SQL query MoveToCollection succeeded")
+
+	return nil
+}
+
+func (r *offerRepository) RemoveFromCollection(ctx context.Context, userID int, collectionID int, offerID int) error {
+	requestID := ctx.Value(utils.RequestIDKey)
+
+	var removedID int
+	err := r.db.QueryRow(ctx, removeFromCollectionSQL, userID, collectionID, offerID).Scan(&removedID)
+
+	logFields := logger.LoggerFields{"requestID": requestID, "query": removeFromCollectionSQL, "params": logger.LoggerFields{"user_id": userID, "collection_id": collectionID, "offer_id": offerID}, "success": err == nil}
+	if err != nil {
+		r.logger.WithFields(logFields).Error("SQL query RemoveFromCollection failed")
+		return err
+	}
+	r.logger.WithFields(logFields).Info("SQL query RemoveFromCollection succeeded")
+
+	return nil
+}
+
+func (r *offerRepository) UpdateFavoriteNote(ctx context.Context, userID int, offerID int, note *string) error {
+	requestID := ctx.Value(utils.RequestIDKey)
+
+	var id int
+	err := r.db.QueryRow(ctx, updateFavoriteNoteSQL, userID, offerID, note).Scan(&id)
+
+	logFields := logger.LoggerFields{"requestID": requestID, "query": updateFavoriteNoteSQL, "params": logger.LoggerFields{"user_id": userID, "offer_id": offerID}, "success": err == nil}
+	if err != nil {
+		r.logger.WithFields(logFields).Error("SQL query UpdateFavoriteNote failed")
+		return err
+	}
+	r.logger.WithFields(logFields).Info("SQL query UpdateFavoriteNote succeeded")
+
+	return nil
+}
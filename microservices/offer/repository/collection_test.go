@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/domain"
+	pgxmock "github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/require"
+)
+
+var collectionRowColumns = []string{"id", "user_id", "name", "is_default", "share_token", "created_at", "count"}
+
+func TestRepository_GetCollections(t *testing.T) {
+	repo, mock := newTestRepo(t)
+	defer mock.Close()
+
+	now := time.Now()
+	token := "token"
+
+	mock.ExpectQuery(`(?i)INSERT INTO kvartirum.FavoriteCollection .*ON CONFLICT \(user_id\) WHERE is_default DO UPDATE`).
+		WithArgs(7).
+		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(1))
+
+	defaultID, err := repo.GetDefaultCollectionID(context.Background(), 7)
+	require.NoError(t, err)
+	require.Equal(t, 1, defaultID)
+
+	mock.ExpectQuery(`(?i)SELECT c.id, .*FROM kvartirum.FavoriteCollection c WHERE c.user_id = \$1 ORDER BY c.is_default DESC`).
+		WithArgs(7).
+		WillReturnRows(pgxmock.NewRows(collectionRowColumns).
+			AddRow(1, 7, "Избранное", true, nil, now, 3).
+			AddRow(2, 7, "Для родителей", false, &token, now, 1))
+
+	collections, err := repo.GetCollections(context.Background(), 7)
+	require.NoError(t, err)
+	require.Len(t, collections, 2)
+	require.Equal(t, domain.FavoriteCollection{ID: 1, UserID: 7, Name: "Избранное", IsDefault: true, OffersCount: 3, CreatedAt: now}, collections[0])
+	require.Equal(t, "token", *collections[1].ShareToken)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_CreateAndRenameCollection(t *testing.T) {
+	repo, mock := newTestRepo(t)
+	defer mock.Close()
+
+	now := time.Now()
+
+	mock.ExpectQuery(`(?i)INSERT INTO kvartirum.FavoriteCollection \(user_id, name\)`).
+		WithArgs(7, "Рядом с работой").
+		WillReturnRows(pgxmock.NewRows([]string{"id", "created_at"}).AddRow(4, now))
+
+	created, err := repo.CreateCollection(context.Background(), domain.FavoriteCollection{UserID: 7, Name: "Рядом с работой"})
+	require.NoError(t, err)
+	require.Equal(t, 4, created.ID)
+	require.Equal(t, now, created.CreatedAt)
+
+	mock.ExpectQuery(`(?i)UPDATE kvartirum.FavoriteCollection c SET name = \$3 WHERE c.id = \$1 AND c.user_id = \$2 RETURNING`).
+		WithArgs(4, 7, "У метро").
+		WillReturnRows(pgxmock.NewRows(collectionRowColumns).AddRow(4, 7, "У метро", false, nil, now, 0))
+
+	renamed, err := repo.RenameCollection(context.Background(), 4, 7, "У метро")
+	require.NoError(t, err)
+	require.Equal(t, "У метро", renamed.Name)
+
+	mock.ExpectQuery(`(?i)UPDATE kvartirum.FavoriteCollection c SET name`).
+		WithArgs(4, 8, "Чужая").
+		WillReturnError(errors.New("no rows in result set"))
+
+	_, err = repo.RenameCollection(context.Background(), 4, 8, "Чужая")
+	require.Error(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_ShareCollection(t *testing.T) {
+	repo, mock := newTestRepo(t)
+	defer mock.Close()
+
+	now := time.Now()
+	token := "b1c2"
+
+	mock.ExpectQuery(`(?i)UPDATE kvartirum.FavoriteCollection c SET share_token = \$3`).
+		WithArgs(4, 7, &token).
+		WillReturnRows(pgxmock.NewRows(collectionRowColumns).AddRow(4, 7, "У метро", false, &token, now, 2))
+
+	shared, err := repo.SetCollectionShareToken(context.Background(), 4, 7, &token)
+	require.NoError(t, err)
+	require.Equal(t, token, *shared.ShareToken)
+
+	mock.ExpectQuery(`(?i)FROM kvartirum.FavoriteCollection c WHERE c.share_token = \$1`).
+		WithArgs(token).
+		WillReturnRows(pgxmock.NewRows(collectionRowColumns).AddRow(4, 7, "У метро", false, &token, now, 2))
+
+	found, err := repo.GetCollectionByShareToken(context.Background(), token)
+	require.NoError(t, err)
+	require.Equal(t, 4, found.ID)
+	require.Equal(t, 7, found.UserID)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_DeleteCollection(t *testing.T) {
+	repo, mock := newTestRepo(t)
+	defer mock.Close()
+
+	mock.ExpectQuery(`(?i)WITH deleted AS \( DELETE FROM kvartirum.FavoriteCollection WHERE id = \$1 AND user_id = \$2 AND NOT is_default.*INSERT INTO kvartirum.FavoriteCollectionOffer`).
+		WithArgs(4, 7, 1).
+		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(4))
+
+	require.NoError(t, repo.DeleteCollection(context.Background(), 4, 7, 1))
+
+	mock.ExpectQuery(`(?i)WITH deleted AS`).
+		WithArgs(5, 7, 1).
+		WillReturnError(errors.New("no rows in result set"))
+
+	require.Error(t, repo.DeleteCollection(context.Background(), 5, 7, 1))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_CollectionOffers(t *testing.T) {
+	repo, mock := newTestRepo(t)
+	defer mock.Close()
+
+	mock.ExpectQuery(`(?i)WITH collection AS .*INSERT INTO kvartirum.UserOfferFavourites.*INSERT INTO kvartirum.FavoriteCollectionOffer`).
+		WithArgs(7, 4, 10).
+		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(4))
+
+	require.NoError(t, repo.AddToCollection(context.Background(), 7, 4, 10))
+
+	mock.ExpectQuery(`(?i)WITH target AS .*DELETE FROM kvartirum.FavoriteCollectionOffer co`).
+		WithArgs(7, 4, 5, 10).
+		WillReturnRows(pgxmock.NewRows([]string{"offer_id"}).AddRow(10))
+
+	require.NoError(t, repo.MoveToCollection(context.Background(), 7, 4, 5, 10))
+
+	mock.ExpectQuery(`(?i)WITH removed AS .*DELETE FROM kvartirum.UserOfferFavourites f`).
+		WithArgs(7, 5, 10).
+		WillReturnError(errors.New("no rows in result set"))
+
+	require.Error(t, repo.RemoveFromCollection(context.Background(), 7, 5, 10))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_UpdateFavoriteNote(t *testing.T) {
+	repo, mock := newTestRepo(t)
+	defer mock.Close()
+
+	note := "спросить про парковку"
+	mock.ExpectQuery(`(?i)UPDATE kvartirum.UserOfferFavourites SET note = \$3 WHERE user_id = \$1 AND offer_id = \$2`).
+		WithArgs(7, 10, &note).
+		WillReturnRows(pgxmock.NewRows([]string{"offer_id"}).AddRow(10))
+
+	require.NoError(t, repo.UpdateFavoriteNote(context.Background(), 7, 10, &note))
+
+	mock.ExpectQuery(`(?i)UPDATE kvartirum.UserOfferFavourites SET note`).
+		WithArgs(7, 11, (*string)(nil)).
+		WillReturnError(errors.New("no rows in result set"))
+
+	require.Error(t, repo.UpdateFavoriteNote(context.Background(), 7, 11, nil))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
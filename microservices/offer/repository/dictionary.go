@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/domain"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/logger"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/utils"
+)
+
+const (
+	getDictionariesSQL = `
+		SELECT 'offer_type', id, name FROM kvartirum.OfferType
+		UNION ALL
+		SELECT 'rent_type', id, name FROM kvartirum.RentType
+		UNION ALL
+		SELECT 'purchase_type', id, name FROM kvartirum.PurchaseType
+		UNION ALL
+		SELECT 'property_type', id, name FROM kvartirum.PropertyType
+		UNION ALL
+		SELECT 'renovation', id, name FROM kvartirum.OfferRenovation
+		UNION ALL
+		SELECT 'offer_status', id, name FROM kvartirum.OfferStatus
+		UNION ALL
+		SELECT 'complex_class', id, name FROM kvartirum.HousingComplexClass
+		ORDER BY 1, 2;
+	`
+
+	getMetroLinesSQL = `
+		SELECT ml.id, ml.name, ml.color, ms.id, ms.name
+		FROM kvartirum.MetroLine ml
+		JOIN kvartirum.MetroStation ms ON ms.metro_line_id = ml.id
+		ORDER BY ml.id, ms.id;
+	`
+
+	housingComplexExistsSQL = `
+		SELECT EXISTS (SELECT 1 FROM kvartirum.HousingComplex WHERE id = $1);
+	`
+)
+
+// GetDictionaries Справочники без версии, ее считает usecase. Станции сгруппированы по линиям,
+// линии без станций не попадают в ответ
+func (r *offerRepository) GetDictionaries(ctx context.Context) (domain.Dictionaries, error) {
+	requestID := ctx.Value(utils.RequestIDKey)
+
+	var dicts domain.Dictionaries
+	byKind := map[string]*[]domain.DictionaryItem{
+		"offer_type":    &dicts.OfferTypes,
+		"rent_type":     &dicts.RentTypes,
+		"purchase_type": &dicts.PurchaseTypes,
+		"property_type": &dicts.PropertyTypes,
+		"renovation":    &dicts.Renovations,
+		"offer_status":  &dicts.OfferStatuses,
+		"complex_class": &dicts.HousingComplexClasses,
+	}
+
+	rows, err := r.db.Query(ctx, getDictionariesSQL)
+
+	logFields := logger.LoggerFields{"requestID": requestID, "query": getDictionariesSQL, "success": err == nil}
+	if err != nil {
+		r.logger.WithFields(logFields).Error("SQL query GetDictionaries failed")
+		return domain.Dictionaries{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var kind string
+		var item domain.DictionaryItem
+		if err := rows.Scan(&kind, &item.ID, &item.Name); err != nil {
+			r.logger.WithFields(logFields).Error("SQL query GetDictionaries scan failed")
+			return domain.Dictionaries{}, err
+		}
+		if items, ok := byKind[kind]; ok {
+			*items = append(*items, item)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		r.logger.WithFields(logFields).Error("SQL query GetDictionaries failed")
+		return domain.Dictionaries{}, err
+	}
+	r.logger.WithFields(logFields).Info("SQL query GetDictionaries succeeded")
+
+	dicts.MetroLines, err = r.getMetroLines(ctx)
+	if err != nil {
+		return domain.Dictionaries{}, err
+	}
+
+	return dicts, nil
+}
+
+func (r *offerRepository) getMetroLines(ctx context.Context) ([]domain.MetroLine, error) {
+	requestID := ctx.Value(utils.RequestIDKey)
+
+	rows, err := r.db.Query(ctx, getMetroLinesSQL)
+
+	logFields := logger.LoggerFields{"requestID": requestID, "query": getMetroLinesSQL, "success": err == nil}
+	if err != nil {
+		r.logger.WithFields(logFields).Error("SQL query GetMetroLines failed")
+		return nil, err
+	}
+	defer rows.Close()
+
+	var lines []domain.MetroLine
+	for rows.Next() {
+		var line domain.MetroLine
+		var station domain.DictionaryItem
+		if err := rows.Scan(&line.ID, &line.Name, &line.Color, &station.ID, &station.Name); err != nil {
+			r.logger.WithFields(logFields).Error("SQL query GetMetroLines scan failed")
+			return nil, err
+		}
+		if len(lines) == 0 || lines[len(lines)-1].ID != line.ID {
+			lines = append(lines, line)
+		}
+		last := &lines[len(lines)-1]
+		last.Stations = append(last.Stations, station)
+	}
+	if err := rows.Err(); err != nil {
+		r.logger.WithFields(logFields).Error("SQL query GetMetroLines failed")
+		return nil, err
+	}
+	r.logger.WithFields(logFields).Info("SQL query GetMetroLines succeeded")
+
+	return lines, nil
+}
+
+// HousingComplexExists ЖК не справочник, их список растет, поэтому проверяется отдельным запросом
+func (r *offerRepository) HousingComplexExists(ctx context.Context, id int) (bool, error) {
+	requestID := ctx.Value(utils.RequestIDKey)
+
+	var exists bool
+	err := r.db.QueryRow(ctx, housingComplexExistsSQL, id).Scan(&exists)
+
+	logFields := logger.LoggerFields{"requestID": requestID, "query": housingComplexExistsSQL, "params": logger.LoggerFields{"complex_id": id}, "success": err == nil}
+	if err != nil {
+		r.logger.WithFields(logFields).Error("SQL query HousingComplexExists failed")
+		return false, err
+	}
+	r.logger.WithFields(logFields).Info("SQL query HousingComplexExists succeeded")
+
+	return exists, nil
+}
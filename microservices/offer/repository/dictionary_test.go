@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/domain"
+	pgxmock "github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepository_GetDictionaries(t *testing.T) {
+	repo, mock := newTestRepo(t)
+	defer mock.Close()
+
+	mock.ExpectQuery(`(?i)SELECT 'offer_type', id, name FROM kvartirum.OfferType\s+UNION ALL.*FROM kvartirum.HousingComplexClass`).
+		WillReturnRows(pgxmock.NewRows([]string{"kind", "id", "name"}).
+			AddRow("complex_class", 1, "Комфорт").
+			AddRow("offer_status", 1, "Активный").
+			AddRow("offer_type", 1, "Продажа").
+			AddRow("offer_type", 2, "Аренда"))
+	mock.ExpectQuery(`(?i)FROM kvartirum.MetroLine ml JOIN kvartirum.MetroStation ms ON ms.metro_line_id = ml.id ORDER BY ml.id, ms.id`).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "name", "color", "station_id", "station_name"}).
+			AddRow(1, "Арбатско-Покровская", "0033A0", 3, "Арбатская").
+			AddRow(1, "Арбатско-Покровская", "0033A0", 4, "Смоленская").
+			AddRow(4, "Замоскворецкая", "007D3C", 10, "Тверская"))
+
+	dicts, err := repo.GetDictionaries(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []domain.DictionaryItem{{ID: 1, Name: "Продажа"}, {ID: 2, Name: "Аренда"}}, dicts.OfferTypes)
+	require.Equal(t, []domain.DictionaryItem{{ID: 1, Name: "Комфорт"}}, dicts.HousingComplexClasses)
+	require.Empty(t, dicts.RentTypes)
+	require.Equal(t, []domain.MetroLine{
+		{ID: 1, Name: "Арбатско-Покровская", Color: "0033A0", Stations: []domain.DictionaryItem{{ID: 3, Name: "Арбатская"}, {ID: 4, Name: "Смоленская"}}},
+		{ID: 4, Name: "Замоскворецкая", Color: "007D3C", Stations: []domain.DictionaryItem{{ID: 10, Name: "Тверская"}}},
+	}, dicts.MetroLines)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_GetDictionariesError(t *testing.T) {
+	repo, mock := newTestRepo(t)
+	defer mock.Close()
+
+	mock.ExpectQuery(`(?i)SELECT 'offer_type', id, name FROM kvartirum.OfferType`).
+		WillReturnRows(pgxmock.NewRows([]string{"kind", "id", "name"}).AddRow("offer_type", 1, "Продажа"))
+	mock.ExpectQuery(`(?i)FROM kvartirum.MetroLine ml`).
+		WillReturnError(errors.New("db error"))
+
+	_, err := repo.GetDictionaries(context.Background())
+	require.Error(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_HousingComplexExists(t *testing.T) {
+	repo, mock := newTestRepo(t)
+	defer mock.Close()
+
+	mock.ExpectQuery(`(?i)SELECT EXISTS \(SELECT 1 FROM kvartirum.HousingComplex WHERE id = \$1\)`).
+		WithArgs(5).
+		WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(false))
+
+	exists, err := repo.HousingComplexExists(context.Background(), 5)
+	require.NoError(t, err)
+	require.False(t, exists)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
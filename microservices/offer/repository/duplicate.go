@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"context"
+	"math"
+
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/domain"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/logger"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/utils"
+)
+
+// Пороги, при которых объявление считается дубликатом
+const (
+	duplicateRadius        = 50.0 // метров между координатами
+	duplicateAreaTolerance = 0.05 // доля от площади
+	duplicateHashDistance  = 6    // различающихся бит перцептивного хеша, не больше числа полос минус один (000029)
+	maxDuplicates          = 20
+)
+
+const (
+	// findDuplicateOffersSQL Активные объявления с той же квартирой рядом с точкой
+	// либо с изображениями, похожими на изображения объявления $1.
+	// Похожие изображения выбираются по GIN-индексу на полосах хеша: на каждую фотографию
+	// объявления приходится несколько строк с общей полосой, и расстояние считается только для них,
+	// а не для всей таблицы Image
+	findDuplicateOffersSQL = `
+		SELECT o.id, o.seller_id, 'attributes' AS reason
+		FROM kvartirum.Offer o
+		WHERE o.offer_status_id = 1 AND o.id <> $1
+			AND o.rooms = $2 AND o.floor = $3 AND o.flat = $4
+			AND ABS(o.area - $5) <= $5 * $8::float8
+			AND o.geo_latitude BETWEEN $6::float8 - $9::float8 AND $6::float8 + $9::float8
+			AND o.geo_longitude BETWEEN $7::float8 - $10::float8 AND $7::float8 + $10::float8
+		UNION
+		SELECT o.id, o.seller_id, 'images' AS reason
+		FROM kvartirum.OfferImages soi
+		JOIN kvartirum.Image si ON si.id = soi.image_id AND si.phash IS NOT NULL
+		JOIN kvartirum.Image i ON i.phash_bands && si.phash_bands AND i.id <> si.id
+			AND length(replace(((i.phash # si.phash)::bit(64))::text, '0', '')) <= $11
+		JOIN kvartirum.OfferImages oi ON oi.image_id = i.id AND oi.offer_id <> soi.offer_id
+		JOIN kvartirum.Offer o ON o.id = oi.offer_id AND o.offer_status_id = 1
+		WHERE soi.offer_id = $1
+		ORDER BY 1
+		LIMIT $12;
+	`
+
+	saveOfferDuplicatesSQL = `
+		INSERT INTO kvartirum.OfferDuplicate (offer_id, duplicate_of, reason)
+		SELECT $1, d.duplicate_of, d.reason
+		FROM unnest($2::bigint[], $3::text[]) AS d(duplicate_of, reason)
+		ON CONFLICT DO NOTHING;
+	`
+
+	getOfferDuplicatesSQL = `
+		SELECT offer_id, duplicate_of, reason, created_at
+		FROM kvartirum.OfferDuplicate
+		WHERE $1::bigint IS NULL OR offer_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2;
+	`
+
+	getUserRoleSQL = `
+		SELECT role FROM kvartirum.Users WHERE id = $1;
+	`
+)
+
+func (r *offerRepository) FindDuplicateOffers(ctx context.Context, c domain.DuplicateCandidate) ([]domain.OfferDuplicate, error) {
+	requestID := ctx.Value(utils.RequestIDKey)
+
+	// Без координат совпадение по атрибутам не ищется: NULL не пройдет сравнение
+	var deltaLat, deltaLon *float64
+	if c.Latitude != nil && c.Longitude != nil {
+		lat := duplicateRadius / metersPerDegree
+		lon := lat / math.Max(math.Cos(*c.Latitude*math.Pi/180), 0.01)
+		deltaLat, deltaLon = &lat, &lon
+	}
+
+	args := []any{
+		c.OfferID, c.Rooms, c.Floor, c.Flat, c.Area, c.Latitude, c.Longitude,
+		duplicateAreaTolerance, deltaLat, deltaLon, duplicateHashDistance, maxDuplicates,
+	}
+	rows, err := r.db.Query(ctx, findDuplicateOffersSQL, args...)
+	if err != nil {
+		r.logger.WithFields(logger.LoggerFields{"requestID": requestID, "query": findDuplicateOffersSQL, "params": args, "success": false, "err": err.Error()}).Error("SQL query FindDuplicateOffers failed")
+		return nil, err
+	}
+	defer rows.Close()
+
+	matches := make([]domain.OfferDuplicate, 0)
+	for rows.Next() {
+		d := domain.OfferDuplicate{OfferID: c.OfferID}
+		if err := rows.Scan(&d.DuplicateOf, &d.SellerID, &d.Reason); err != nil {
+			r.logger.WithFields(logger.LoggerFields{"requestID": requestID, "query": findDuplicateOffersSQL, "success": false, "err": err.Error()}).Error("SQL query FindDuplicateOffers scan failed")
+			return nil, err
+		}
+		matches = append(matches, d)
+	}
+
+	r.logger.WithFields(logger.LoggerFields{"requestID": requestID, "query": findDuplicateOffersSQL, "params": args, "success": true, "count": len(matches)}).Info("SQL query FindDuplicateOffers succeeded")
+
+	return matches, nil
+}
+
+func (r *offerRepository) SaveOfferDuplicates(ctx context.Context, offerID int, matches []domain.OfferDuplicate) error {
+	requestID := ctx.Value(utils.RequestIDKey)
+
+	duplicateOf := make([]int64, 0, len(matches))
+	reasons := make([]string, 0, len(matches))
+	for _, m := range matches {
+		duplicateOf = append(duplicateOf, int64(m.DuplicateOf))
+		reasons = append(reasons, m.Reason)
+	}
+
+	_, err := r.db.Exec(ctx, saveOfferDuplicatesSQL, offerID, duplicateOf, reasons)
+
+	logFields := logger.LoggerFields{"requestID": requestID, "query": saveOfferDuplicatesSQL, "params": logger.LoggerFields{"offer_id": offerID, "count": len(matches)}, "success": err == nil}
+	if err != nil {
+		r.logger.WithFields(logFields).Error("SQL query SaveOfferDuplicates failed")
+		return err
+	}
+	r.logger.WithFields(logFields).Info("SQL query SaveOfferDuplicates succeeded")
+
+	return nil
+}
+
+func (r *offerRepository) GetOfferDuplicates(ctx context.Context, offerID *int, limit int) ([]domain.OfferDuplicate, error) {
+	requestID := ctx.Value(utils.RequestIDKey)
+
+	rows, err := r.db.Query(ctx, getOfferDuplicatesSQL, offerID, limit)
+	if err != nil {
+		r.logger.WithFields(logger.LoggerFields{"requestID": requestID, "query": getOfferDuplicatesSQL, "success": false, "err": err.Error()}).Error("SQL query GetOfferDuplicates failed")
+		return nil, err
+	}
+	defer rows.Close()
+
+	duplicates := make([]domain.OfferDuplicate, 0)
+	for rows.Next() {
+		var d domain.OfferDuplicate
+		if err := rows.Scan(&d.OfferID, &d.DuplicateOf, &d.Reason, &d.CreatedAt); err != nil {
+			r.logger.WithFields(logger.LoggerFields{"requestID": requestID, "query": getOfferDuplicatesSQL, "success": false, "err": err.Error()}).Error("SQL query GetOfferDuplicates scan failed")
+			return nil, err
+		}
+		duplicates = append(duplicates, d)
+	}
+
+	r.logger.WithFields(logger.LoggerFields{"requestID": requestID, "query": getOfferDuplicatesSQL, "success": true, "count": len(duplicates)}).Info("SQL query GetOfferDuplicates succeeded")
+
+	return duplicates, nil
+}
+
+func (r *offerRepository) GetUserRole(ctx context.Context, userID int) (string, error) {
+	requestID := ctx.Value(utils.RequestIDKey)
+
+	var role string
+	err := r.db.QueryRow(ctx, getUserRoleSQL, userID).Scan(&role)
+
+	logFields := logger.LoggerFields{"requestID": requestID, "query": getUserRoleSQL, "params": logger.LoggerFields{"user_id": userID}, "success": err == nil}
+	if err != nil {
+		r.logger.WithFields(logFields).Error("SQL query GetUserRole failed")
+		return "", err
+	}
+	r.logger.WithFields(logFields).Info("SQL query GetUserRole succeeded")
+
+	return role, nil
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/domain"
+	pgxmock "github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepository_FindDuplicateOffers(t *testing.T) {
+	repo, mock := newTestRepo(t)
+	defer mock.Close()
+
+	lat, lon := 55.75, 37.61
+	c := domain.DuplicateCandidate{OfferID: 7, Rooms: 2, Floor: 3, Flat: 12, Area: 40, Latitude: &lat, Longitude: &lon}
+
+	mock.ExpectQuery(`(?i)SELECT o.id, o.seller_id, 'attributes' AS reason.*UNION.*'images' AS reason.*i.phash_bands && si.phash_bands`).
+		WithArgs(7, 2, 3, 12, 40, &lat, &lon, duplicateAreaTolerance, pgxmock.AnyArg(), pgxmock.AnyArg(), duplicateHashDistance, maxDuplicates).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "seller_id", "reason"}).
+			AddRow(3, 5, domain.DuplicateByAttributes).
+			AddRow(4, 8, domain.DuplicateByImages))
+
+	matches, err := repo.FindDuplicateOffers(context.Background(), c)
+	require.NoError(t, err)
+	require.Equal(t, []domain.OfferDuplicate{
+		{OfferID: 7, DuplicateOf: 3, SellerID: 5, Reason: domain.DuplicateByAttributes},
+		{OfferID: 7, DuplicateOf: 4, SellerID: 8, Reason: domain.DuplicateByImages},
+	}, matches)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_SaveOfferDuplicates(t *testing.T) {
+	repo, mock := newTestRepo(t)
+	defer mock.Close()
+
+	mock.ExpectExec(`(?i)INSERT INTO kvartirum.OfferDuplicate`).
+		WithArgs(7, []int64{4}, []string{domain.DuplicateByImages}).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+	err := repo.SaveOfferDuplicates(context.Background(), 7, []domain.OfferDuplicate{{DuplicateOf: 4, Reason: domain.DuplicateByImages}})
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_GetOfferDuplicates(t *testing.T) {
+	repo, mock := newTestRepo(t)
+	defer mock.Close()
+
+	offerID := 7
+	now := time.Now()
+	mock.ExpectQuery(`(?i)FROM kvartirum.OfferDuplicate`).
+		WithArgs(&offerID, 100).
+		WillReturnRows(pgxmock.NewRows([]string{"offer_id", "duplicate_of", "reason", "created_at"}).
+			AddRow(7, 4, domain.DuplicateByImages, now))
+
+	duplicates, err := repo.GetOfferDuplicates(context.Background(), &offerID, 100)
+	require.NoError(t, err)
+	require.Len(t, duplicates, 1)
+	require.Equal(t, 4, duplicates[0].DuplicateOf)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_GetUserRole(t *testing.T) {
+	repo, mock := newTestRepo(t)
+	defer mock.Close()
+
+	mock.ExpectQuery(`(?i)SELECT role FROM kvartirum.Users WHERE id = \$1`).
+		WithArgs(1).
+		WillReturnRows(pgxmock.NewRows([]string{"role"}).AddRow(domain.RoleModerator))
+
+	role, err := repo.GetUserRole(context.Background(), 1)
+	require.NoError(t, err)
+	require.Equal(t, domain.RoleModerator, role)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-park-mail-ru/2025_1_404/pkg/logger"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/utils"
+)
+
+const (
+	// notifyExpiringOffersSQL Отмечает объявления как предупрежденные и пишет продавцам уведомления
+	notifyExpiringOffersSQL = `
+		WITH due AS (
+			UPDATE kvartirum.Offer SET expiry_notified = TRUE
+			WHERE id IN (
+				SELECT id FROM kvartirum.Offer
+				WHERE offer_status_id = 1 AND offer_type_id = $1 AND renewed_at <= $2 AND NOT expiry_notified
+				ORDER BY renewed_at
+				LIMIT $3
+			)
+			RETURNING id, seller_id
+		)
+		INSERT INTO kvartirum.UserNotification (user_id, message, redirect_uri)
+		SELECT seller_id, $4, '/offer/' || id FROM due;
+	`
+
+	// expireOffersSQL Завершает объявления, записывает переход в историю и уведомляет продавцов
+	expireOffersSQL = `
+		WITH expired AS (
+			UPDATE kvartirum.Offer SET offer_status_id = 3
+			WHERE id IN (
+				SELECT id FROM kvartirum.Offer
+				WHERE offer_status_id = 1 AND offer_type_id = $1 AND renewed_at <= $2
+				ORDER BY renewed_at
+				LIMIT $3
+			)
+			RETURNING id, seller_id
+		), history AS (
+			INSERT INTO kvartirum.OfferStatusHistory (offer_id, from_status_id, to_status_id, reason)
+			SELECT id, 1, 3, $4 FROM expired
+		)
+		INSERT INTO kvartirum.UserNotification (user_id, message, redirect_uri)
+		SELECT seller_id, $5, '/offer/' || id FROM expired;
+	`
+
+	extendOfferSQL = `
+		UPDATE kvartirum.Offer SET renewed_at = $2, expiry_notified = FALSE
+		WHERE id = $1 AND offer_status_id = 1;
+	`
+)
+
+// NotifyExpiringOffers Предупреждает продавцов объявлений типа offerTypeID, продленных не позже renewedBefore.
+// Возвращает число отправленных уведомлений
+func (r *offerRepository) NotifyExpiringOffers(ctx context.Context, offerTypeID int, renewedBefore time.Time, limit int, message string) (int, error) {
+	requestID := ctx.Value(utils.RequestIDKey)
+
+	tag, err := r.db.Exec(ctx, notifyExpiringOffersSQL, offerTypeID, renewedBefore, limit, message)
+
+	logFields := logger.LoggerFields{"requestID": requestID, "query": notifyExpiringOffersSQL, "params": logger.LoggerFields{"offer_type_id": offerTypeID, "renewed_before": renewedBefore}, "success": err == nil}
+	if err != nil {
+		r.logger.WithFields(logFields).Error("SQL query NotifyExpiringOffers failed")
+		return 0, err
+	}
+	r.logger.WithFields(logFields).Info("SQL query NotifyExpiringOffers succeeded")
+
+	return int(tag.RowsAffected()), nil
+}
+
+// ExpireOffers Завершает объявления типа offerTypeID, продленные не позже renewedBefore.
+// Возвращает число снятых объявлений
+func (r *offerRepository) ExpireOffers(ctx context.Context, offerTypeID int, renewedBefore time.Time, limit int, reason string, message string) (int, error) {
+	requestID := ctx.Value(utils.RequestIDKey)
+
+	tag, err := r.db.Exec(ctx, expireOffersSQL, offerTypeID, renewedBefore, limit, reason, message)
+
+	logFields := logger.LoggerFields{"requestID": requestID, "query": expireOffersSQL, "params": logger.LoggerFields{"offer_type_id": offerTypeID, "renewed_before": renewedBefore}, "success": err == nil}
+	if err != nil {
+		r.logger.WithFields(logFields).Error("SQL query ExpireOffers failed")
+		return 0, err
+	}
+	r.logger.WithFields(logFields).Info("SQL query ExpireOffers succeeded")
+
+	return int(tag.RowsAffected()), nil
+}
+
+// ExtendOffer Начинает срок публикации активного объявления заново. false, если объявление не активно
+func (r *offerRepository) ExtendOffer(ctx context.Context, offerID int, renewedAt time.Time) (bool, error) {
+	requestID := ctx.Value(utils.RequestIDKey)
+
+	tag, err := r.db.Exec(ctx, extendOfferSQL, offerID, renewedAt)
+
+	logFields := logger.LoggerFields{"requestID": requestID, "query": extendOfferSQL, "params": logger.LoggerFields{"offer_id": offerID}, "success": err == nil}
+	if err != nil {
+		r.logger.WithFields(logFields).Error("SQL query ExtendOffer failed")
+		return false, err
+	}
+	r.logger.WithFields(logFields).Info("SQL query ExtendOffer succeeded")
+
+	return tag.RowsAffected() > 0, nil
+}
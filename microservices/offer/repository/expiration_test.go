@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	pgxmock "github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepository_NotifyExpiringOffers(t *testing.T) {
+	repo, mock := newTestRepo(t)
+	defer mock.Close()
+
+	before := time.Now()
+	mock.ExpectExec(`(?i)UPDATE kvartirum.Offer SET expiry_notified = TRUE.*INSERT INTO kvartirum.UserNotification`).
+		WithArgs(2, before, 100, "Скоро истечет").
+		WillReturnResult(pgxmock.NewResult("INSERT", 3))
+
+	notified, err := repo.NotifyExpiringOffers(context.Background(), 2, before, 100, "Скоро истечет")
+	require.NoError(t, err)
+	require.Equal(t, 3, notified)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_ExpireOffers(t *testing.T) {
+	repo, mock := newTestRepo(t)
+	defer mock.Close()
+
+	before := time.Now()
+	mock.ExpectExec(`(?i)UPDATE kvartirum.Offer SET offer_status_id = 3.*INSERT INTO kvartirum.OfferStatusHistory.*INSERT INTO kvartirum.UserNotification`).
+		WithArgs(1, before, 100, "Срок истек", "Объявление снято").
+		WillReturnResult(pgxmock.NewResult("INSERT", 2))
+
+	expired, err := repo.ExpireOffers(context.Background(), 1, before, 100, "Срок истек", "Объявление снято")
+	require.NoError(t, err)
+	require.Equal(t, 2, expired)
+
+	mock.ExpectExec(`(?i)UPDATE kvartirum.Offer SET offer_status_id = 3`).
+		WithArgs(1, before, 100, "Срок истек", "Объявление снято").
+		WillReturnError(errors.New("db error"))
+
+	_, err = repo.ExpireOffers(context.Background(), 1, before, 100, "Срок истек", "Объявление снято")
+	require.Error(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_ExtendOffer(t *testing.T) {
+	repo, mock := newTestRepo(t)
+	defer mock.Close()
+
+	now := time.Now()
+	mock.ExpectExec(`(?i)UPDATE kvartirum.Offer SET renewed_at = \$2, expiry_notified = FALSE\s+WHERE id = \$1 AND offer_status_id = 1`).
+		WithArgs(1, now).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+	extended, err := repo.ExtendOffer(context.Background(), 1, now)
+	require.NoError(t, err)
+	require.True(t, extended)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/domain"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/logger"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/utils"
+)
+
+const (
+	// getFeedOffersSQL Объявления, измененные после $1. При полной сборке ($3) берутся только
+	// активные, при досборке - все, чтобы снятые с публикации удалились из фида
+	getFeedOffersSQL = `
+		SELECT o.id, o.offer_status_id, ot.name, rt.name, pt.name, prt.name, r.name, ms.name,
+			o.price, o.description, o.floor, o.total_floors, o.rooms, o.address, o.flat, o.area,
+			o.ceiling_height, o.longitude, o.latitude, o.created_at, o.updated_at,
+			u.id, u.first_name, u.last_name, u.email,
+			ARRAY(
+				SELECT i.uuid FROM kvartirum.OfferImages oi
+				JOIN kvartirum.Image i ON i.id = oi.image_id
+				WHERE oi.offer_id = o.id
+				ORDER BY oi.is_cover DESC, oi.position, oi.id
+			)
+		FROM kvartirum.Offer o
+		JOIN kvartirum.OfferType ot ON ot.id = o.offer_type_id
+		LEFT JOIN kvartirum.RentType rt ON rt.id = o.rent_type_id
+		LEFT JOIN kvartirum.PurchaseType pt ON pt.id = o.purchase_type_id
+		JOIN kvartirum.PropertyType prt ON prt.id = o.property_type_id
+		JOIN kvartirum.OfferRenovation r ON r.id = o.renovation_id
+		LEFT JOIN kvartirum.MetroStation ms ON ms.id = o.metro_station_id
+		JOIN kvartirum.Users u ON u.id = o.seller_id
+		WHERE o.updated_at > $1
+			AND (COALESCE(cardinality($2::bigint[]), 0) = 0 OR o.seller_id = ANY($2::bigint[]))
+			AND (NOT $3::boolean OR o.offer_status_id = 1)
+		ORDER BY o.id;
+	`
+
+	getActiveOfferIDsSQL = `
+		SELECT id FROM kvartirum.Offer
+		WHERE offer_status_id = 1
+			AND (COALESCE(cardinality($1::bigint[]), 0) = 0 OR seller_id = ANY($1::bigint[]));
+	`
+)
+
+func (r *offerRepository) GetFeedOffers(ctx context.Context, sellerIDs []int, updatedAfter time.Time, activeOnly bool) ([]domain.FeedOffer, error) {
+	requestID := ctx.Value(utils.RequestIDKey)
+
+	rows, err := r.db.Query(ctx, getFeedOffersSQL, updatedAfter, sellerIDs, activeOnly)
+
+	logFields := logger.LoggerFields{"requestID": requestID, "query": getFeedOffersSQL, "params": logger.LoggerFields{"updated_after": updatedAfter, "seller_ids": sellerIDs, "active_only": activeOnly}, "success": err == nil}
+	if err != nil {
+		r.logger.WithFields(logFields).Error("SQL query GetFeedOffers failed")
+		return nil, err
+	}
+	defer rows.Close()
+
+	var offers []domain.FeedOffer
+	for rows.Next() {
+		var o domain.FeedOffer
+		if err := rows.Scan(
+			&o.ID, &o.StatusID, &o.OfferType, &o.RentType, &o.PurchaseType, &o.PropertyType, &o.Renovation, &o.MetroStation,
+			&o.Price, &o.Description, &o.Floor, &o.TotalFloors, &o.Rooms, &o.Address, &o.Flat, &o.Area,
+			&o.CeilingHeight, &o.Longitude, &o.Latitude, &o.CreatedAt, &o.UpdatedAt,
+			&o.Seller.ID, &o.Seller.FirstName, &o.Seller.LastName, &o.Seller.Email,
+			&o.Images,
+		); err != nil {
+			r.logger.WithFields(logFields).Error("SQL query GetFeedOffers scan failed")
+			return nil, err
+		}
+		offers = append(offers, o)
+	}
+	if err := rows.Err(); err != nil {
+		r.logger.WithFields(logFields).Error("SQL query GetFeedOffers failed")
+		return nil, err
+	}
+	r.logger.WithFields(logFields).Info("SQL query GetFeedOffers succeeded")
+
+	return offers, nil
+}
+
+func (r *offerRepository) GetActiveOfferIDs(ctx context.Context, sellerIDs []int) ([]int, error) {
+	requestID := ctx.Value(utils.RequestIDKey)
+
+	rows, err := r.db.Query(ctx, getActiveOfferIDsSQL, sellerIDs)
+
+	logFields := logger.LoggerFields{"requestID": requestID, "query": getActiveOfferIDsSQL, "params": logger.LoggerFields{"seller_ids": sellerIDs}, "success": err == nil}
+	if err != nil {
+		r.logger.WithFields(logFields).Error("SQL query GetActiveOfferIDs failed")
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			r.logger.WithFields(logFields).Error("SQL query GetActiveOfferIDs scan failed")
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		r.logger.WithFields(logFields).Error("SQL query GetActiveOfferIDs failed")
+		return nil, err
+	}
+	r.logger.WithFields(logFields).Info("SQL query GetActiveOfferIDs succeeded")
+
+	return ids, nil
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	pgxmock "github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepository_GetFeedOffers(t *testing.T) {
+	repo, mock := newTestRepo(t)
+	defer mock.Close()
+
+	now := time.Now()
+	metro := "Тверская"
+	mock.ExpectQuery(`(?i)SELECT o.id, o.offer_status_id, ot.name.*FROM kvartirum.Offer o.*WHERE o.updated_at > \$1`).
+		WithArgs(time.Time{}, []int{7}, true).
+		WillReturnRows(pgxmock.NewRows([]string{
+			"id", "offer_status_id", "offer_type", "rent_type", "purchase_type", "property_type", "renovation", "metro",
+			"price", "description", "floor", "total_floors", "rooms", "address", "flat", "area",
+			"ceiling_height", "longitude", "latitude", "created_at", "updated_at",
+			"seller_id", "first_name", "last_name", "email", "images",
+		}).AddRow(
+			1, 1, "Продажа", nil, nil, "Квартира", "Современный ремонт", &metro,
+			5000000, nil, 3, 9, 2, nil, 12, 45,
+			3, "37.6", "55.7", now, now,
+			7, "Иван", "Петров", "ivan@mail.ru", []string{"a.jpg", "b.jpg"},
+		))
+
+	offers, err := repo.GetFeedOffers(context.Background(), []int{7}, time.Time{}, true)
+	require.NoError(t, err)
+	require.Len(t, offers, 1)
+	require.Equal(t, "Тверская", *offers[0].MetroStation)
+	require.Nil(t, offers[0].RentType)
+	require.Equal(t, "ivan@mail.ru", offers[0].Seller.Email)
+	require.Equal(t, []string{"a.jpg", "b.jpg"}, offers[0].Images)
+
+	mock.ExpectQuery(`(?i)FROM kvartirum.Offer o`).
+		WithArgs(now, []int(nil), false).
+		WillReturnError(errors.New("db error"))
+
+	_, err = repo.GetFeedOffers(context.Background(), nil, now, false)
+	require.Error(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_GetActiveOfferIDs(t *testing.T) {
+	repo, mock := newTestRepo(t)
+	defer mock.Close()
+
+	mock.ExpectQuery(`(?i)SELECT id FROM kvartirum.Offer\s+WHERE offer_status_id = 1`).
+		WithArgs([]int(nil)).
+		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(1).AddRow(5))
+
+	ids, err := repo.GetActiveOfferIDs(context.Background(), nil)
+	require.NoError(t, err)
+	require.Equal(t, []int{1, 5}, ids)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
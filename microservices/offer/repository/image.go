@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/go-park-mail-ru/2025_1_404/pkg/logger"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/utils"
+)
+
+const (
+	// reorderOfferImagesSQL Позиция фотографии - ее индекс в $2. Порядок меняется, только если
+	// в $2 перечислены ровно все фотографии объявления
+	reorderOfferImagesSQL = `
+		UPDATE kvartirum.OfferImages oi
+		SET position = o.ord - 1, updated_at = CURRENT_TIMESTAMP
+		FROM unnest($2::bigint[]) WITH ORDINALITY AS o(image_id, ord)
+		WHERE oi.offer_id = $1 AND oi.image_id = o.image_id
+			AND (SELECT COUNT(*) FROM kvartirum.OfferImages WHERE offer_id = $1) = cardinality($2::bigint[])
+			AND NOT EXISTS (
+				SELECT 1 FROM unnest($2::bigint[]) AS ids(image_id)
+				WHERE ids.image_id NOT IN (SELECT image_id FROM kvartirum.OfferImages WHERE offer_id = $1)
+			);
+	`
+
+	setOfferCoverSQL = `
+		UPDATE kvartirum.OfferImages
+		SET is_cover = (image_id = $2), updated_at = CURRENT_TIMESTAMP
+		WHERE offer_id = $1
+			AND EXISTS (SELECT 1 FROM kvartirum.OfferImages WHERE offer_id = $1 AND image_id = $2);
+	`
+)
+
+// ReorderOfferImages Расставляет фотографии объявления в порядке imageIDs.
+// false, если список не совпадает с набором фотографий объявления
+func (r *offerRepository) ReorderOfferImages(ctx context.Context, offerID int, imageIDs []int64) (bool, error) {
+	requestID := ctx.Value(utils.RequestIDKey)
+
+	tag, err := r.db.Exec(ctx, reorderOfferImagesSQL, offerID, imageIDs)
+
+	logFields := logger.LoggerFields{"requestID": requestID, "query": reorderOfferImagesSQL, "params": logger.LoggerFields{"offer_id": offerID, "image_ids": imageIDs}, "success": err == nil}
+	if err != nil {
+		r.logger.WithFields(logFields).Error("SQL query ReorderOfferImages failed")
+		return false, err
+	}
+	r.logger.WithFields(logFields).Info("SQL query ReorderOfferImages succeeded")
+
+	return tag.RowsAffected() == int64(len(imageIDs)), nil
+}
+
+// SetOfferCover Делает фотографию обложкой объявления. false, если фотография не относится к объявлению
+func (r *offerRepository) SetOfferCover(ctx context.Context, offerID int, imageID int64) (bool, error) {
+	requestID := ctx.Value(utils.RequestIDKey)
+
+	tag, err := r.db.Exec(ctx, setOfferCoverSQL, offerID, imageID)
+
+	logFields := logger.LoggerFields{"requestID": requestID, "query": setOfferCoverSQL, "params": logger.LoggerFields{"offer_id": offerID, "image_id": imageID}, "success": err == nil}
+	if err != nil {
+		r.logger.WithFields(logFields).Error("SQL query SetOfferCover failed")
+		return false, err
+	}
+	r.logger.WithFields(logFields).Info("SQL query SetOfferCover succeeded")
+
+	return tag.RowsAffected() > 0, nil
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	pgxmock "github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepository_ReorderOfferImages(t *testing.T) {
+	repo, mock := newTestRepo(t)
+	defer mock.Close()
+
+	ids := []int64{3, 1, 2}
+	mock.ExpectExec(`(?i)UPDATE kvartirum.OfferImages oi\s+SET position = o.ord - 1.*unnest\(\$2::bigint\[\]\) WITH ORDINALITY`).
+		WithArgs(7, ids).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 3))
+
+	reordered, err := repo.ReorderOfferImages(context.Background(), 7, ids)
+	require.NoError(t, err)
+	require.True(t, reordered)
+
+	// Список не совпал с фотографиями объявления - ни одна строка не обновлена
+	mock.ExpectExec(`(?i)UPDATE kvartirum.OfferImages oi`).
+		WithArgs(7, ids).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+
+	reordered, err = repo.ReorderOfferImages(context.Background(), 7, ids)
+	require.NoError(t, err)
+	require.False(t, reordered)
+
+	mock.ExpectExec(`(?i)UPDATE kvartirum.OfferImages oi`).
+		WithArgs(7, ids).
+		WillReturnError(errors.New("db error"))
+
+	_, err = repo.ReorderOfferImages(context.Background(), 7, ids)
+	require.Error(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_SetOfferCover(t *testing.T) {
+	repo, mock := newTestRepo(t)
+	defer mock.Close()
+
+	mock.ExpectExec(`(?i)UPDATE kvartirum.OfferImages\s+SET is_cover = \(image_id = \$2\)`).
+		WithArgs(7, int64(2)).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 3))
+
+	updated, err := repo.SetOfferCover(context.Background(), 7, 2)
+	require.NoError(t, err)
+	require.True(t, updated)
+
+	mock.ExpectExec(`(?i)UPDATE kvartirum.OfferImages`).
+		WithArgs(7, int64(99)).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+
+	updated, err = repo.SetOfferCover(context.Background(), 7, 99)
+	require.NoError(t, err)
+	require.False(t, updated)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
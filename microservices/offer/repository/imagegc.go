@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/domain"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/logger"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/utils"
+)
+
+const (
+	// getStoredImagesSQL Бакет определяется по тому, кто ссылается на изображение
+	getStoredImagesSQL = `
+		SELECT i.id, i.uuid, i.variants, i.created_at,
+			CASE
+				WHEN EXISTS (SELECT 1 FROM kvartirum.OfferImages oi WHERE oi.image_id = i.id) THEN 'offers'
+				WHEN EXISTS (SELECT 1 FROM kvartirum.Users u WHERE u.image_id = i.id) THEN 'avatars'
+				WHEN EXISTS (SELECT 1 FROM kvartirum.HousingComplexImages hci WHERE hci.image_id = i.id) THEN 'images'
+				ELSE ''
+			END AS bucket
+		FROM kvartirum.Image i;
+	`
+
+	deleteImagesSQL = `
+		DELETE FROM kvartirum.Image WHERE id = ANY($1::bigint[]);
+	`
+)
+
+func (r *offerRepository) GetStoredImages(ctx context.Context) ([]domain.StoredImage, error) {
+	requestID := ctx.Value(utils.RequestIDKey)
+
+	rows, err := r.db.Query(ctx, getStoredImagesSQL)
+
+	logFields := logger.LoggerFields{"requestID": requestID, "query": getStoredImagesSQL, "success": err == nil}
+	if err != nil {
+		r.logger.WithFields(logFields).Error("SQL query GetStoredImages failed")
+		return nil, err
+	}
+	defer rows.Close()
+
+	var images []domain.StoredImage
+	for rows.Next() {
+		var image domain.StoredImage
+		if err := rows.Scan(&image.ID, &image.UUID, &image.Variants, &image.CreatedAt, &image.Bucket); err != nil {
+			r.logger.WithFields(logFields).Error("SQL query GetStoredImages scan failed")
+			return nil, err
+		}
+		images = append(images, image)
+	}
+	if err := rows.Err(); err != nil {
+		r.logger.WithFields(logFields).Error("SQL query GetStoredImages failed")
+		return nil, err
+	}
+	r.logger.WithFields(logFields).Info("SQL query GetStoredImages succeeded")
+
+	return images, nil
+}
+
+// DeleteImages Удаляет записи изображений, привязки к объявлениям удаляются каскадно,
+// у пользователей аватар обнуляется
+func (r *offerRepository) DeleteImages(ctx context.Context, ids []int64) (int, error) {
+	requestID := ctx.Value(utils.RequestIDKey)
+
+	tag, err := r.db.Exec(ctx, deleteImagesSQL, ids)
+
+	logFields := logger.LoggerFields{"requestID": requestID, "query": deleteImagesSQL, "params": logger.LoggerFields{"ids": ids}, "success": err == nil}
+	if err != nil {
+		r.logger.WithFields(logFields).Error("SQL query DeleteImages failed")
+		return 0, err
+	}
+	r.logger.WithFields(logFields).Info("SQL query DeleteImages succeeded")
+
+	return int(tag.RowsAffected()), nil
+}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	pgxmock "github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepository_GetStoredImages(t *testing.T) {
+	repo, mock := newTestRepo(t)
+	defer mock.Close()
+
+	now := time.Now()
+	mock.ExpectQuery(`(?i)SELECT i.id, i.uuid, i.variants, i.created_at,\s+CASE.*FROM kvartirum.Image i`).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "uuid", "variants", "created_at", "bucket"}).
+			AddRow(int64(1), "flat.jpg", []int{320}, now, "offers").
+			AddRow(int64(2), "orphan.jpg", []int{}, now, ""))
+
+	images, err := repo.GetStoredImages(context.Background())
+	require.NoError(t, err)
+	require.Len(t, images, 2)
+	require.Equal(t, "offers", images[0].Bucket)
+	require.Equal(t, []int{320}, images[0].Variants)
+	require.Empty(t, images[1].Bucket)
+
+	mock.ExpectQuery(`(?i)FROM kvartirum.Image i`).WillReturnError(errors.New("db error"))
+
+	_, err = repo.GetStoredImages(context.Background())
+	require.Error(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_DeleteImages(t *testing.T) {
+	repo, mock := newTestRepo(t)
+	defer mock.Close()
+
+	ids := []int64{3, 2}
+	mock.ExpectExec(`(?i)DELETE FROM kvartirum.Image WHERE id = ANY\(\$1::bigint\[\]\)`).
+		WithArgs(ids).
+		WillReturnResult(pgxmock.NewResult("DELETE", 2))
+
+	deleted, err := repo.DeleteImages(context.Background(), ids)
+	require.NoError(t, err)
+	require.Equal(t, 2, deleted)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
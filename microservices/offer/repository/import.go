@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/domain"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/logger"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/utils"
+)
+
+const (
+	// getImportDictionariesSQL Все справочники, на которые ссылается объявление, одним запросом
+	getImportDictionariesSQL = `
+		SELECT 'offer_type', id, name FROM kvartirum.OfferType
+		UNION ALL
+		SELECT 'rent_type', id, name FROM kvartirum.RentType
+		UNION ALL
+		SELECT 'purchase_type', id, name FROM kvartirum.PurchaseType
+		UNION ALL
+		SELECT 'property_type', id, name FROM kvartirum.PropertyType
+		UNION ALL
+		SELECT 'renovation', id, name FROM kvartirum.OfferRenovation
+		UNION ALL
+		SELECT 'metro_station', id, name FROM kvartirum.MetroStation
+		ORDER BY 1, 2;
+	`
+
+	getOfferIDByExternalIDSQL = `
+		SELECT COALESCE((
+			SELECT id FROM kvartirum.Offer WHERE seller_id = $1 AND external_id = $2
+		), 0);
+	`
+
+	setOfferExternalIDSQL = `
+		UPDATE kvartirum.Offer SET external_id = $2 WHERE id = $1;
+	`
+)
+
+// GetImportDictionaries Названия справочных значений нормализуются. Если названия совпадают
+// (станции на разных линиях), остается значение с меньшим id
+func (r *offerRepository) GetImportDictionaries(ctx context.Context) (domain.ImportDictionaries, error) {
+	requestID := ctx.Value(utils.RequestIDKey)
+
+	dicts := domain.ImportDictionaries{
+		OfferTypes:    make(map[string]int),
+		RentTypes:     make(map[string]int),
+		PurchaseTypes: make(map[string]int),
+		PropertyTypes: make(map[string]int),
+		Renovations:   make(map[string]int),
+		MetroStations: make(map[string]int),
+	}
+	byKind := map[string]map[string]int{
+		"offer_type":    dicts.OfferTypes,
+		"rent_type":     dicts.RentTypes,
+		"purchase_type": dicts.PurchaseTypes,
+		"property_type": dicts.PropertyTypes,
+		"renovation":    dicts.Renovations,
+		"metro_station": dicts.MetroStations,
+	}
+
+	rows, err := r.db.Query(ctx, getImportDictionariesSQL)
+
+	logFields := logger.LoggerFields{"requestID": requestID, "query": getImportDictionariesSQL, "success": err == nil}
+	if err != nil {
+		r.logger.WithFields(logFields).Error("SQL query GetImportDictionaries failed")
+		return dicts, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var kind, name string
+		var id int
+		if err := rows.Scan(&kind, &id, &name); err != nil {
+			r.logger.WithFields(logFields).Error("SQL query GetImportDictionaries scan failed")
+			return dicts, err
+		}
+		key := domain.NormalizeDictionaryName(name)
+		if _, ok := byKind[kind][key]; !ok {
+			byKind[kind][key] = id
+		}
+	}
+	if err := rows.Err(); err != nil {
+		r.logger.WithFields(logFields).Error("SQL query GetImportDictionaries failed")
+		return dicts, err
+	}
+	r.logger.WithFields(logFields).Info("SQL query GetImportDictionaries succeeded")
+
+	return dicts, nil
+}
+
+// GetOfferIDByExternalID Возвращает 0, если продавец еще не импортировал объявление с таким идентификатором
+func (r *offerRepository) GetOfferIDByExternalID(ctx context.Context, sellerID int, externalID string) (int, error) {
+	requestID := ctx.Value(utils.RequestIDKey)
+
+	var id int64
+	err := r.db.QueryRow(ctx, getOfferIDByExternalIDSQL, sellerID, externalID).Scan(&id)
+
+	logFields := logger.LoggerFields{"requestID": requestID, "query": getOfferIDByExternalIDSQL, "params": logger.LoggerFields{"seller_id": sellerID, "external_id": externalID}, "success": err == nil}
+	if err != nil {
+		r.logger.WithFields(logFields).Error("SQL query GetOfferIDByExternalID failed")
+		return 0, err
+	}
+	r.logger.WithFields(logFields).Info("SQL query GetOfferIDByExternalID succeeded")
+
+	return int(id), nil
+}
+
+func (r *offerRepository) SetOfferExternalID(ctx context.Context, offerID int, externalID string) error {
+	requestID := ctx.Value(utils.RequestIDKey)
+
+	_, err := r.db.Exec(ctx, setOfferExternalIDSQL, offerID, externalID)
+
+	logFields := logger.LoggerFields{"requestID": requestID, "query": setOfferExternalIDSQL, "params": logger.LoggerFields{"offer_id": offerID, "external_id": externalID}, "success": err == nil}
+	if err != nil {
+		r.logger.WithFields(logFields).Error("SQL query SetOfferExternalID failed")
+		return err
+	}
+	r.logger.WithFields(logFields).Info("SQL query SetOfferExternalID succeeded")
+
+	return nil
+}
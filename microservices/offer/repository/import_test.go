@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	pgxmock "github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepository_GetImportDictionaries(t *testing.T) {
+	repo, mock := newTestRepo(t)
+	defer mock.Close()
+
+	mock.ExpectQuery(`(?i)SELECT 'offer_type', id, name FROM kvartirum.OfferType\s+UNION ALL.*FROM kvartirum.MetroStation`).
+		WillReturnRows(pgxmock.NewRows([]string{"kind", "id", "name"}).
+			AddRow("metro_station", 3, "Арбатская").
+			AddRow("metro_station", 40, "Арбатская").
+			AddRow("offer_type", 1, "Продажа").
+			AddRow("renovation", 2, "Косметический  ремонт"))
+
+	dicts, err := repo.GetImportDictionaries(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, map[string]int{"продажа": 1}, dicts.OfferTypes)
+	require.Equal(t, map[string]int{"арбатская": 3}, dicts.MetroStations)
+	require.Equal(t, map[string]int{"косметический ремонт": 2}, dicts.Renovations)
+	require.Empty(t, dicts.RentTypes)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_GetOfferIDByExternalID(t *testing.T) {
+	repo, mock := newTestRepo(t)
+	defer mock.Close()
+
+	mock.ExpectQuery(`(?i)SELECT COALESCE\(\(\s+SELECT id FROM kvartirum.Offer WHERE seller_id = \$1 AND external_id = \$2`).
+		WithArgs(42, "A-1").
+		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(int64(10)))
+
+	id, err := repo.GetOfferIDByExternalID(context.Background(), 42, "A-1")
+	require.NoError(t, err)
+	require.Equal(t, 10, id)
+
+	mock.ExpectQuery(`(?i)FROM kvartirum.Offer WHERE seller_id`).
+		WithArgs(42, "A-2").
+		WillReturnError(errors.New("db error"))
+
+	_, err = repo.GetOfferIDByExternalID(context.Background(), 42, "A-2")
+	require.Error(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_SetOfferExternalID(t *testing.T) {
+	repo, mock := newTestRepo(t)
+	defer mock.Close()
+
+	mock.ExpectExec(`(?i)UPDATE kvartirum.Offer SET external_id = \$2 WHERE id = \$1`).
+		WithArgs(10, "A-1").
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+	require.NoError(t, repo.SetOfferExternalID(context.Background(), 10, "A-1"))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/domain"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/logger"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/utils"
+)
+
+const (
+	// savePriceDropNotificationsSQL Уведомления получают все, у кого объявление в избранном, кроме
+	// самого продавца, отписавшихся и тех, для кого снижение меньше их порога ($3 - порог по умолчанию)
+	savePriceDropNotificationsSQL = `
+		WITH recipients AS (
+			SELECT f.user_id, u.email
+			FROM kvartirum.UserOfferFavourites f
+			JOIN kvartirum.Users u ON u.id = f.user_id
+			LEFT JOIN kvartirum.PriceAlertSettings s ON s.user_id = f.user_id
+			WHERE f.offer_id = $1 AND f.user_id <> $2
+				AND COALESCE(s.enabled, TRUE)
+				AND COALESCE(s.min_drop_percent, $3) <= $4::float8
+		),
+		inserted AS (
+			INSERT INTO kvartirum.UserNotification (user_id, message, redirect_uri)
+			SELECT user_id, $5, $6 FROM recipients
+		)
+		SELECT user_id, email FROM recipients;
+	`
+
+	getPriceAlertSettingsSQL = `
+		SELECT COALESCE((SELECT enabled FROM kvartirum.PriceAlertSettings WHERE user_id = $1), TRUE),
+			(SELECT min_drop_percent FROM kvartirum.PriceAlertSettings WHERE user_id = $1);
+	`
+
+	savePriceAlertSettingsSQL = `
+		INSERT INTO kvartirum.PriceAlertSettings (user_id, enabled, min_drop_percent)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id) DO UPDATE
+		SET enabled = EXCLUDED.enabled, min_drop_percent = EXCLUDED.min_drop_percent, updated_at = CURRENT_TIMESTAMP;
+	`
+)
+
+func (r *offerRepository) SavePriceDropNotifications(ctx context.Context, alert domain.PriceDropAlert, defaultMinPercent int, notification domain.Notification) ([]domain.PriceDropRecipient, error) {
+	requestID := ctx.Value(utils.RequestIDKey)
+
+	dropPercent := alert.DropPercent()
+	rows, err := r.db.Query(ctx, savePriceDropNotificationsSQL, alert.OfferID, alert.SellerID, defaultMinPercent, dropPercent, notification.Message, notification.RedirectURI)
+
+	logFields := logger.LoggerFields{"requestID": requestID, "query": savePriceDropNotificationsSQL, "params": logger.LoggerFields{"offer_id": alert.OfferID, "drop_percent": dropPercent, "default_min_percent": defaultMinPercent}, "success": err == nil}
+	if err != nil {
+		r.logger.WithFields(logFields).Error("SQL query SavePriceDropNotifications failed")
+		return nil, err
+	}
+	defer rows.Close()
+
+	var recipients []domain.PriceDropRecipient
+	for rows.Next() {
+		var recipient domain.PriceDropRecipient
+		if err := rows.Scan(&recipient.UserID, &recipient.Email); err != nil {
+			r.logger.WithFields(logFields).Error("SQL query SavePriceDropNotifications scan failed")
+			return nil, err
+		}
+		recipients = append(recipients, recipient)
+	}
+	if err := rows.Err(); err != nil {
+		r.logger.WithFields(logFields).Error("SQL query SavePriceDropNotifications failed")
+		return nil, err
+	}
+	r.logger.WithFields(logFields).Info("SQL query SavePriceDropNotifications succeeded")
+
+	return recipients, nil
+}
+
+func (r *offerRepository) GetPriceAlertSettings(ctx context.Context, userID int) (domain.PriceAlertSettings, error) {
+	requestID := ctx.Value(utils.RequestIDKey)
+
+	var settings domain.PriceAlertSettings
+	err := r.db.QueryRow(ctx, getPriceAlertSettingsSQL, userID).Scan(&settings.Enabled, &settings.MinDropPercent)
+
+	logFields := logger.LoggerFields{"requestID": requestID, "query": getPriceAlertSettingsSQL, "params": logger.LoggerFields{"user_id": userID}, "success": err == nil}
+	if err != nil {
+		r.logger.WithFields(logFields).Error("SQL query GetPriceAlertSettings failed")
+		return domain.PriceAlertSettings{}, err
+	}
+	r.logger.WithFields(logFields).Info("SQL query GetPriceAlertSettings succeeded")
+
+	return settings, nil
+}
+
+func (r *offerRepository) SavePriceAlertSettings(ctx context.Context, userID int, settings domain.PriceAlertSettings) error {
+	requestID := ctx.Value(utils.RequestIDKey)
+
+	_, err := r.db.Exec(ctx, savePriceAlertSettingsSQL, userID, settings.Enabled, settings.MinDropPercent)
+
+	logFields := logger.LoggerFields{"requestID": requestID, "query": savePriceAlertSettingsSQL, "params": logger.LoggerFields{"user_id": userID, "enabled": settings.Enabled, "min_drop_percent": settings.MinDropPercent}, "success": err == nil}
+	if err != nil {
+		r.logger.WithFields(logFields).Error("SQL query SavePriceAlertSettings failed")
+		return err
+	}
+	r.logger.WithFields(logFields).Info("SQL query SavePriceAlertSettings succeeded")
+
+	return nil
+}
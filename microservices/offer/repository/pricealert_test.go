@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/domain"
+	pgxmock "github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepository_SavePriceDropNotifications(t *testing.T) {
+	repo, mock := newTestRepo(t)
+	defer mock.Close()
+
+	alert := domain.PriceDropAlert{OfferID: 1, SellerID: 3, OldPrice: 5000000, NewPrice: 4500000}
+	notification := domain.Notification{Message: "Цена снижена: 5 000 000 → 4 500 000 ₽", RedirectURI: "/offer/1"}
+
+	mock.ExpectQuery(`(?i)WITH recipients AS .*FROM kvartirum.UserOfferFavourites f.*LEFT JOIN kvartirum.PriceAlertSettings.*INSERT INTO kvartirum.UserNotification`).
+		WithArgs(1, 3, 2, 10.0, notification.Message, notification.RedirectURI).
+		WillReturnRows(pgxmock.NewRows([]string{"user_id", "email"}).AddRow(7, "a@mail.ru"))
+
+	recipients, err := repo.SavePriceDropNotifications(context.Background(), alert, 2, notification)
+	require.NoError(t, err)
+	require.Equal(t, []domain.PriceDropRecipient{{UserID: 7, Email: "a@mail.ru"}}, recipients)
+
+	mock.ExpectQuery(`(?i)WITH recipients AS`).
+		WithArgs(1, 3, 2, 10.0, notification.Message, notification.RedirectURI).
+		WillReturnError(errors.New("db error"))
+
+	_, err = repo.SavePriceDropNotifications(context.Background(), alert, 2, notification)
+	require.Error(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_PriceAlertSettings(t *testing.T) {
+	repo, mock := newTestRepo(t)
+	defer mock.Close()
+
+	threshold := 5
+	mock.ExpectQuery(`(?i)SELECT COALESCE\(\(SELECT enabled FROM kvartirum.PriceAlertSettings`).
+		WithArgs(7).
+		WillReturnRows(pgxmock.NewRows([]string{"enabled", "min_drop_percent"}).AddRow(false, &threshold))
+
+	settings, err := repo.GetPriceAlertSettings(context.Background(), 7)
+	require.NoError(t, err)
+	require.False(t, settings.Enabled)
+	require.Equal(t, 5, *settings.MinDropPercent)
+
+	mock.ExpectExec(`(?i)INSERT INTO kvartirum.PriceAlertSettings .*ON CONFLICT \(user_id\) DO UPDATE`).
+		WithArgs(7, true, &threshold).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+	require.NoError(t, repo.SavePriceAlertSettings(context.Background(), 7, domain.PriceAlertSettings{Enabled: true, MinDropPercent: &threshold}))
+
+	mock.ExpectExec(`(?i)INSERT INTO kvartirum.PriceAlertSettings`).
+		WithArgs(7, false, (*int)(nil)).
+		WillReturnError(errors.New("db error"))
+
+	require.Error(t, repo.SavePriceAlertSettings(context.Background(), 7, domain.PriceAlertSettings{}))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
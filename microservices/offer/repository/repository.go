@@ -3,6 +3,9 @@ package repository
 import (
 	"context"
 	"fmt"
+	"html"
+	"math"
+	"strconv"
 	"strings"
 	"time"
 
@@ -37,8 +40,16 @@ type Offer struct {
 	CreatedAt      time.Time
 	UpdatedAt      time.Time
 	PromotesUntil  *time.Time
+	Distance       *float64
+	Rank           *float64
+	// Фрагменты с подсвеченными совпадениями поиска, уже экранированные для HTML
+	DescriptionHighlight *string
+	AddressHighlight     *string
 }
 
+// metersPerDegree Длина одного градуса широты
+const metersPerDegree = 111320.0
+
 type offerRepository struct {
 	db     database.DB
 	logger logger.Logger
@@ -87,13 +98,57 @@ const (
 		FROM kvartirum.Offer;
 	`
 
-	getNotDraftOffersSQL = `
+	filterOffersSQL = `
+		SELECT id, seller_id, offer_type_id, metro_station_id, rent_type_id,
+			purchase_type_id, property_type_id, offer_status_id, renovation_id,
+			complex_id, price, description, floor, total_floors, rooms,
+			address, flat, area, ceiling_height, longitude, latitude, created_at, updated_at, promotes_until,
+			%s AS distance, %s AS rank, %s AS description_highlight, %s AS address_highlight
+		FROM kvartirum.Offer
+	`
+
+	searchQuerySQL = `websearch_to_tsquery('russian', $%d)`
+	rankSQL        = `ts_rank(search_vector, %s)::float8`
+	// headlineSQL Совпадения обрамляются управляющими символами, которые после экранирования
+	// заменяются на теги подсветки, чтобы текст пользователя не попал в ответ как HTML
+	headlineSQL = `ts_headline('russian', %s, %s,
+		'StartSel=' || chr(1) || ', StopSel=' || chr(2) || ', MaxFragments=2, MaxWords=15, MinWords=5')`
+
+	// distanceSQL Расстояние в метрах от точки по формуле гаверсинусов.
+	// Первый параметр - широта точки, второй - долгота
+	distanceSQL = `(2 * 6371000 * ASIN(LEAST(1, SQRT(
+		POWER(SIN(RADIANS(geo_latitude - $%[1]d) / 2), 2) +
+		COS(RADIANS($%[1]d)) * COS(RADIANS(geo_latitude)) * POWER(SIN(RADIANS(geo_longitude - $%[2]d) / 2), 2)
+	))))`
+
+	// offerClustersSQL Группирует объявления по ячейкам сетки заданного размера в градусах.
+	// Представитель кластера - самое дешевое объявление ячейки
+	offerClustersSQL = `
+		SELECT COUNT(*), AVG(geo_longitude), AVG(geo_latitude), MIN(price), MAX(price),
+			(ARRAY_AGG(id ORDER BY price, id))[1]
+		FROM kvartirum.Offer%s AND geo_longitude IS NOT NULL AND geo_latitude IS NOT NULL
+		GROUP BY FLOOR(geo_longitude / $%[2]d), FLOOR(geo_latitude / $%[2]d);
+	`
+
+	getActiveOffersSQL = `
 		SELECT id, seller_id, offer_type_id, metro_station_id, rent_type_id,
 			purchase_type_id, property_type_id, offer_status_id, renovation_id,
 			complex_id, price, description, floor, total_floors, rooms,
 			address, flat, area, ceiling_height, longitude, latitude, created_at, updated_at, promotes_until
 		FROM kvartirum.Offer
-		WHERE offer_status_id != 2;
+		WHERE offer_status_id = 1;
+	`
+
+	countActiveOffersSQL = `
+		SELECT COUNT(*) FROM kvartirum.Offer WHERE offer_status_id = 1;
+	`
+
+	countOffersBySellerSQL = `
+		SELECT COUNT(*) FROM kvartirum.Offer WHERE seller_id = $1;
+	`
+
+	countOffersSQL = `
+		SELECT COUNT(*) FROM kvartirum.Offer
 	`
 
 	updateOfferSQL = `
@@ -116,7 +171,18 @@ const (
 			complex_id, price, description, floor, total_floors, rooms,
 			address, flat, area, ceiling_height, longitude, latitude, created_at, updated_at, promotes_until
 		FROM kvartirum.Offer
-		WHERE complex_id = $1;
+		WHERE complex_id = $1 AND offer_status_id = 1;
+	`
+
+	// changeOfferStatusSQL Статус меняется, только если объявление все еще в статусе $2
+	changeOfferStatusSQL = `
+		WITH changed AS (
+			UPDATE kvartirum.Offer SET offer_status_id = $3
+			WHERE id = $1 AND offer_status_id = $2
+			RETURNING id
+		)
+		INSERT INTO kvartirum.OfferStatusHistory (offer_id, from_status_id, to_status_id, changed_by, reason)
+		SELECT id, $2, $3, NULLIF($4, 0), NULLIF($5, '') FROM changed;
 	`
 
 	getStations = `
@@ -146,10 +212,6 @@ const (
 		INSERT INTO kvartirum.Views (offer_id)  VALUES ($1);
 	`
 
-	countView = `
-		SELECT COUNT (*) FROM kvartirum.Views WHERE offer_id = $1;
-	`
-
 	insertPriceHistorySQL = `
 		INSERT INTO kvartirum.OfferPriceHistory (offer_id, price, recorded_at)
 		VALUES ($1, $2, CURRENT_TIMESTAMP);
@@ -159,13 +221,85 @@ const (
 		DELETE FROM kvartirum.OfferPriceHistory WHERE offer_id = $1;
 	`
 
+	// getPriceHistoriesSQL Последние limit записей истории цен по каждому из объявлений
+	getPriceHistoriesSQL = `
+		SELECT offer_id, price, recorded_at
+		FROM (
+			SELECT offer_id, price, recorded_at,
+				ROW_NUMBER() OVER (PARTITION BY offer_id ORDER BY recorded_at DESC, id DESC) AS rn
+			FROM kvartirum.OfferPriceHistory
+			WHERE offer_id = ANY($1)
+		) h
+		WHERE h.rn <= $2
+		ORDER BY offer_id, rn;
+	`
+
+	getOffersImagesSQL = `
+		SELECT oi.offer_id, i.id, i.uuid, i.variants, oi.is_cover
+		FROM kvartirum.OfferImages oi
+		LEFT JOIN kvartirum.Image i ON oi.image_id = i.id
+		WHERE oi.offer_id = ANY($1)
+		ORDER BY oi.offer_id, oi.is_cover DESC, oi.position, oi.id;
+	`
+
+	getOffersStatSQL = `
+		SELECT
+			o.id,
+			(SELECT COUNT(*) FROM kvartirum.Likes l WHERE l.offer_id = o.id),
+			(SELECT COUNT(*) FROM kvartirum.UserOfferFavourites f WHERE f.offer_id = o.id),
+			(SELECT COUNT(*) FROM kvartirum.Views v WHERE v.offer_id = o.id)
+		FROM unnest($1::bigint[]) AS o(id);
+	`
+
+	getOffersUserStatSQL = `
+		SELECT
+			o.id,
+			EXISTS (SELECT 1 FROM kvartirum.Likes l WHERE l.offer_id = o.id AND l.user_id = $2),
+			f.offer_id IS NOT NULL,
+			f.note
+		FROM unnest($1::bigint[]) AS o(id)
+		LEFT JOIN kvartirum.UserOfferFavourites f ON f.offer_id = o.id AND f.user_id = $2;
+	`
+
+	getFullPriceHistorySQL = `
+		SELECT price, recorded_at
+		FROM kvartirum.OfferPriceHistory
+		WHERE offer_id = $1
+		ORDER BY recorded_at, id;
+	`
+
+	// priceDroppedSQL Последнее изменение цены объявления было снижением
+	priceDroppedSQL = `EXISTS (
+		SELECT 1 FROM (
+			SELECT price,
+				LAG(price) OVER (ORDER BY recorded_at, id) AS prev_price,
+				ROW_NUMBER() OVER (ORDER BY recorded_at DESC, id DESC) AS rn
+			FROM kvartirum.OfferPriceHistory
+			WHERE offer_id = Offer.id
+		) h
+		WHERE h.rn = 1 AND h.price < h.prev_price
+	)`
+
+	// addFavoriteSQL Добавляет в избранное и в основную подборку, создавая ее при первом добавлении
 	addFavoriteSQL = `
-		INSERT INTO kvartirum.UserOfferFavourites (user_id, offer_id)
-		VALUES ($1, $2)
+		WITH favorite AS (
+			INSERT INTO kvartirum.UserOfferFavourites (user_id, offer_id)
+			VALUES ($1, $2)
+			ON CONFLICT DO NOTHING
+		),
+		collection AS (` + upsertDefaultCollectionSQL + `)
+		INSERT INTO kvartirum.FavoriteCollectionOffer (collection_id, offer_id)
+		SELECT id, $2 FROM collection
 		ON CONFLICT DO NOTHING;
 	`
 
+	// removeFavoriteSQL Убирает из избранного вместе со всеми подборками пользователя
 	removeFavoriteSQL = `
+		WITH collections AS (
+			DELETE FROM kvartirum.FavoriteCollectionOffer co
+			USING kvartirum.FavoriteCollection c
+			WHERE c.id = co.collection_id AND c.user_id = $1 AND co.offer_id = $2
+		)
 		DELETE FROM kvartirum.UserOfferFavourites
 		WHERE user_id = $1 AND offer_id = $2;
 	`
@@ -189,8 +323,71 @@ const (
 	getFavoriteStat = `
 		SELECT COUNT(*) FROM kvartirum.UserOfferFavourites WHERE offer_id = $1;
 	`
+
+	countFavoritesSQL = `
+	SELECT COUNT(*)
+	FROM kvartirum.UserOfferFavourites f
+	JOIN kvartirum.Offer o ON o.id = f.offer_id
+	WHERE f.user_id = $1
+	`
+
+	// collectionFilterSQL Подборка проверяется по владельцу, чтобы чужой id не раскрывал состав подборки
+	collectionFilterSQL = ` AND EXISTS (
+		SELECT 1 FROM kvartirum.FavoriteCollectionOffer co
+		JOIN kvartirum.FavoriteCollection c ON c.id = co.collection_id
+		WHERE c.id = $%d AND c.user_id = f.user_id AND co.offer_id = o.id
+	)`
 )
 
+// sortOrder Выражение сортировки ленты. Без выражения записи упорядочены только по id
+type sortOrder struct {
+	expr string
+	desc bool
+}
+
+var newestFirst = sortOrder{desc: true}
+
+var offerSortOrders = map[domain.OfferSort]sortOrder{
+	domain.SortNewest:     newestFirst,
+	domain.SortPriceAsc:   {expr: "price"},
+	domain.SortPriceDesc:  {expr: "price", desc: true},
+	domain.SortPricePerM2: {expr: "(price::float8 / GREATEST(area, 1))"},
+	domain.SortAreaDesc:   {expr: "area", desc: true},
+}
+
+// paginate Дописывает к запросу условие курсора, сортировку и лимит.
+// Запрос должен уже содержать WHERE. Выбирается на одну запись больше лимита,
+// чтобы usecase мог понять, есть ли следующая страница.
+// При сортировке по выражению id используется как второй ключ, чтобы порядок был однозначным
+func paginate(query string, args []any, idColumn string, page domain.Pagination, order sortOrder) (string, []any) {
+	query = strings.TrimRight(query, "\t\n ;")
+
+	direction, cmp := "ASC", ">"
+	if order.desc {
+		direction, cmp = "DESC", "<"
+	}
+
+	if page.Cursor != nil {
+		if order.expr != "" && page.Cursor.Value != nil {
+			args = append(args, *page.Cursor.Value, page.Cursor.ID)
+			query += fmt.Sprintf(" AND (%s, %s) %s ($%d::float8, $%d)", order.expr, idColumn, cmp, len(args)-1, len(args))
+		} else {
+			args = append(args, page.Cursor.ID)
+			query += fmt.Sprintf(" AND %s %s $%d", idColumn, cmp, len(args))
+		}
+	}
+
+	orderBy := idColumn + " " + direction
+	if order.expr != "" {
+		orderBy = order.expr + " " + direction + ", " + orderBy
+	}
+
+	args = append(args, page.Limit+1)
+	query += fmt.Sprintf(" ORDER BY %s LIMIT $%d;", orderBy, len(args))
+
+	return query, args
+}
+
 func (r *offerRepository) CreateOffer(ctx context.Context, o Offer) (int64, error) {
 	requestID := ctx.Value(utils.RequestIDKey)
 	var id int64
@@ -234,13 +431,22 @@ func (r *offerRepository) GetOfferByID(ctx context.Context, id int64) (Offer, er
 	return o, err
 }
 
-func (r *offerRepository) GetOffersBySellerID(ctx context.Context, sellerID int64) ([]Offer, error) {
+func (r *offerRepository) GetOffersBySellerID(ctx context.Context, sellerID int64, page domain.Pagination) ([]Offer, int, error) {
 	requestID := ctx.Value(utils.RequestIDKey)
 
-	rows, err := r.db.Query(ctx, getOffersBySellerSQL, sellerID)
+	var total int
+	err := r.db.QueryRow(ctx, countOffersBySellerSQL, sellerID).Scan(&total)
 	if err != nil {
-		r.logger.WithFields(logger.LoggerFields{"requestID": requestID, "query": getOffersBySellerSQL, "params": logger.LoggerFields{"seller_id": sellerID}, "success": false, "err": err.Error()}).Error("SQL query GetOffersBySellerID failed")
-		return nil, err
+		r.logger.WithFields(logger.LoggerFields{"requestID": requestID, "query": countOffersBySellerSQL, "params": logger.LoggerFields{"seller_id": sellerID}, "success": false, "err": err.Error()}).Error("SQL query CountOffersBySellerID failed")
+		return nil, 0, err
+	}
+
+	query, args := paginate(getOffersBySellerSQL, []any{sellerID}, "id", page, newestFirst)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		r.logger.WithFields(logger.LoggerFields{"requestID": requestID, "query": query, "params": logger.LoggerFields{"seller_id": sellerID}, "success": false, "err": err.Error()}).Error("SQL query GetOffersBySellerID failed")
+		return nil, 0, err
 	}
 	defer rows.Close()
 
@@ -255,24 +461,32 @@ func (r *offerRepository) GetOffersBySellerID(ctx context.Context, sellerID int6
 			&o.Longitude, &o.Latitude, &o.CreatedAt, &o.UpdatedAt, &o.PromotesUntil,
 		)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 		offers = append(offers, o)
 	}
 
-	r.logger.WithFields(logger.LoggerFields{"requestID": requestID, "query": getOffersBySellerSQL, "params": logger.LoggerFields{"seller_id": sellerID}, "success": true, "count": len(offers)}).Info("SQL query GetOffersBySellerID succeeded")
+	r.logger.WithFields(logger.LoggerFields{"requestID": requestID, "query": query, "params": logger.LoggerFields{"seller_id": sellerID}, "success": true, "count": len(offers)}).Info("SQL query GetOffersBySellerID succeeded")
 
-	return offers, nil
+	return offers, total, nil
 }
 
-func (r *offerRepository) GetAllOffers(ctx context.Context) ([]Offer, error) {
-	query := getNotDraftOffersSQL
-
+func (r *offerRepository) GetAllOffers(ctx context.Context, page domain.Pagination) ([]Offer, int, error) {
 	requestID := ctx.Value(utils.RequestIDKey)
-	rows, err := r.db.Query(ctx, query)
+
+	var total int
+	err := r.db.QueryRow(ctx, countActiveOffersSQL).Scan(&total)
+	if err != nil {
+		r.logger.WithFields(logger.LoggerFields{"requestID": requestID, "query": countActiveOffersSQL, "success": false, "err": err.Error()}).Error("SQL query CountAllOffers failed")
+		return nil, 0, err
+	}
+
+	query, args := paginate(getActiveOffersSQL, nil, "id", page, newestFirst)
+
+	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
 		r.logger.WithFields(logger.LoggerFields{"requestID": requestID, "query": query, "success": false, "err": err.Error()}).Error("SQL query GetAllOffers failed")
-		return nil, err
+		return nil, 0, err
 	}
 	defer rows.Close()
 
@@ -287,31 +501,58 @@ func (r *offerRepository) GetAllOffers(ctx context.Context) ([]Offer, error) {
 			&o.Longitude, &o.Latitude, &o.CreatedAt, &o.UpdatedAt, &o.PromotesUntil,
 		)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 		offers = append(offers, o)
 	}
 
 	r.logger.WithFields(logger.LoggerFields{"requestID": requestID, "query": query, "success": true, "count": len(offers)}).Info("SQL query GetAllOffers succeeded")
 
-	return offers, nil
+	return offers, total, nil
 }
 
-func (r *offerRepository) GetOffersByFilter(ctx context.Context, f domain.OfferFilter, userID *int) ([]Offer, error) {
-	requestID := ctx.Value(utils.RequestIDKey)
+// offerFilterQuery Условие WHERE по фильтру ленты, его параметры и вычисляемые по фильтру выражения.
+// Если точка near или запрос q не заданы, соответствующие выражения равны NULL
+type offerFilterQuery struct {
+	where                string
+	args                 []any
+	distance             string
+	rank                 string
+	descriptionHighlight string
+	addressHighlight     string
+}
 
+func buildOfferFilter(f domain.OfferFilter, userID *int) offerFilterQuery {
 	var (
 		whereParts []string
 		args       []any
 		idx        = 1
 	)
 
+	q := offerFilterQuery{
+		distance:             "NULL::float8",
+		rank:                 "NULL::float8",
+		descriptionHighlight: "NULL::text",
+		addressHighlight:     "NULL::text",
+	}
+
 	addFilter := func(condition string, value any) {
 		whereParts = append(whereParts, fmt.Sprintf(condition, idx))
 		args = append(args, value)
 		idx++
 	}
 
+	// addIn Условие на одно или несколько допустимых значений колонки
+	addIn := func(column string, values []int) {
+		switch len(values) {
+		case 0:
+		case 1:
+			addFilter(column+" = $%d", values[0])
+		default:
+			addFilter(column+" = ANY($%d)", values)
+		}
+	}
+
 	// Фильтры
 	if f.MinArea != nil {
 		addFilter("area >= $%d", *f.MinArea)
@@ -328,26 +569,41 @@ func (r *offerRepository) GetOffersByFilter(ctx context.Context, f domain.OfferF
 	if f.Floor != nil {
 		addFilter("floor = $%d", *f.Floor)
 	}
-	if f.Rooms != nil {
-		addFilter("rooms = $%d", *f.Rooms)
+	if f.FloorMin != nil {
+		addFilter("floor >= $%d", *f.FloorMin)
+	}
+	if f.FloorMax != nil {
+		addFilter("floor <= $%d", *f.FloorMax)
+	}
+	if f.NotFirstFloor {
+		whereParts = append(whereParts, "floor > 1")
 	}
+	if f.NotLastFloor {
+		whereParts = append(whereParts, "floor < total_floors")
+	}
+	addIn("rooms", f.Rooms)
 	if f.Address != nil {
 		addFilter("address ILIKE $%d", "%"+*f.Address+"%")
 	}
-	if f.RenovationID != nil {
-		addFilter("renovation_id = $%d", *f.RenovationID)
+	addIn("renovation_id", f.RenovationID)
+	addIn("property_type_id", f.PropertyTypeID)
+	addIn("purchase_type_id", f.PurchaseTypeID)
+	addIn("rent_type_id", f.RentTypeID)
+	if f.OfferTypeID != nil {
+		addFilter("offer_type_id = $%d", *f.OfferTypeID)
 	}
-	if f.PropertyTypeID != nil {
-		addFilter("property_type_id = $%d", *f.PropertyTypeID)
+	addIn("metro_station_id", f.MetroStationID)
+	if f.ComplexID != nil {
+		addFilter("complex_id = $%d", *f.ComplexID)
 	}
-	if f.PurchaseTypeID != nil {
-		addFilter("purchase_type_id = $%d", *f.PurchaseTypeID)
+	if f.MinCeilingHeight != nil {
+		addFilter("ceiling_height >= $%d", *f.MinCeilingHeight)
 	}
-	if f.RentTypeID != nil {
-		addFilter("rent_type_id = $%d", *f.RentTypeID)
+	if f.PublishedAfter != nil {
+		addFilter("published_at > $%d", *f.PublishedAfter)
 	}
-	if f.OfferTypeID != nil {
-		addFilter("offer_type_id = $%d", *f.OfferTypeID)
+	if f.PublishedBefore != nil {
+		addFilter("published_at <= $%d", *f.PublishedBefore)
 	}
 
 	if f.OnlyMe != nil && *f.OnlyMe && userID != nil {
@@ -365,19 +621,99 @@ func (r *offerRepository) GetOffersByFilter(ctx context.Context, f domain.OfferF
 			whereParts = append(whereParts, "complex_id IS NULL")
 		}
 	}
+	if f.PriceDropped != nil {
+		if *f.PriceDropped {
+			whereParts = append(whereParts, priceDroppedSQL)
+		} else {
+			whereParts = append(whereParts, "NOT "+priceDroppedSQL)
+		}
+	}
 
-	query := strings.TrimRight(getAllOffersSQL, "\t\n;")
+	// Гео
+	addRange := func(column string, from, to float64) {
+		whereParts = append(whereParts, fmt.Sprintf("%s BETWEEN $%d AND $%d", column, idx, idx+1))
+		args = append(args, from, to)
+		idx += 2
+	}
 
-	if len(whereParts) > 0 {
-		query += " WHERE " + strings.Join(whereParts, " AND ")
+	if f.BBox != nil {
+		addRange("geo_longitude", f.BBox.MinLongitude, f.BBox.MaxLongitude)
+		addRange("geo_latitude", f.BBox.MinLatitude, f.BBox.MaxLatitude)
 	}
+	if f.Near != nil {
+		radius := float64(domain.DefaultSearchRadius)
+		if f.Radius != nil {
+			radius = float64(*f.Radius)
+		}
 
-	query += ";"
+		q.distance = fmt.Sprintf(distanceSQL, idx, idx+1)
+		args = append(args, f.Near.Latitude, f.Near.Longitude)
+		idx += 2
+
+		// Сначала отсекаем квадратом вокруг точки, чтобы сработал индекс по координатам
+		deltaLat := radius / metersPerDegree
+		deltaLon := deltaLat / math.Max(math.Cos(f.Near.Latitude*math.Pi/180), 0.01)
+		addRange("geo_latitude", f.Near.Latitude-deltaLat, f.Near.Latitude+deltaLat)
+		addRange("geo_longitude", f.Near.Longitude-deltaLon, f.Near.Longitude+deltaLon)
+
+		addFilter(q.distance+" <= $%d", radius)
+	}
+
+	// Полнотекстовый поиск
+	if f.Query != nil {
+		tsQuery := fmt.Sprintf(searchQuerySQL, idx)
+		addFilter("search_vector @@ "+searchQuerySQL, *f.Query)
+
+		q.rank = fmt.Sprintf(rankSQL, tsQuery)
+		q.descriptionHighlight = fmt.Sprintf(headlineSQL, "description", tsQuery)
+		q.addressHighlight = fmt.Sprintf(headlineSQL, "address", tsQuery)
+	}
+
+	q.where = " WHERE " + strings.Join(whereParts, " AND ")
+	q.args = args
+
+	return q
+}
+
+// highlightHTML Экранирует фрагмент ts_headline и расставляет теги подсветки.
+// Описание и адрес хранятся уже экранированными, поэтому фрагмент сначала возвращается
+// к исходному тексту, иначе кавычки и & дойдут до клиента сущностями
+func highlightHTML(fragment *string) *string {
+	if fragment == nil {
+		return nil
+	}
+	escaped := html.EscapeString(html.UnescapeString(*fragment))
+	escaped = strings.NewReplacer("\x01", "<mark>", "\x02", "</mark>").Replace(escaped)
+	return &escaped
+}
+
+func (r *offerRepository) GetOffersByFilter(ctx context.Context, f domain.OfferFilter, page domain.Pagination, userID *int) ([]Offer, int, error) {
+	requestID := ctx.Value(utils.RequestIDKey)
+
+	fq := buildOfferFilter(f, userID)
+	args := fq.args
+
+	countQuery := strings.TrimRight(countOffersSQL, "\t\n;") + fq.where + ";"
+
+	var total int
+	err := r.db.QueryRow(ctx, countQuery, args...).Scan(&total)
+	if err != nil {
+		r.logger.WithFields(logger.LoggerFields{"requestID": requestID, "query": countQuery, "params": args, "success": false, "err": err.Error()}).Error("SQL query CountOffersByFilter failed")
+		return nil, 0, err
+	}
+
+	order := offerSortOrders[f.Sort.OrDefault()]
+	if f.Sort == domain.SortRelevance {
+		order = sortOrder{expr: fq.rank, desc: true}
+	}
+
+	selectQuery := fmt.Sprintf(filterOffersSQL, fq.distance, fq.rank, fq.descriptionHighlight, fq.addressHighlight)
+	query, args := paginate(selectQuery+fq.where, args, "id", page, order)
 
 	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
 		r.logger.WithFields(logger.LoggerFields{"requestID": requestID, "query": query, "params": args, "success": false, "err": err.Error()}).Error("SQL query GetOffersByFilter failed")
-		return nil, err
+		return nil, 0, err
 	}
 	defer rows.Close()
 
@@ -390,16 +726,48 @@ func (r *offerRepository) GetOffersByFilter(ctx context.Context, f domain.OfferF
 			&o.ComplexID, &o.Price, &o.Description, &o.Floor, &o.TotalFloors,
 			&o.Rooms, &o.Address, &o.Flat, &o.Area, &o.CeilingHeight,
 			&o.Longitude, &o.Latitude, &o.CreatedAt, &o.UpdatedAt, &o.PromotesUntil,
+			&o.Distance, &o.Rank, &o.DescriptionHighlight, &o.AddressHighlight,
 		)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
+		o.DescriptionHighlight = highlightHTML(o.DescriptionHighlight)
+		o.AddressHighlight = highlightHTML(o.AddressHighlight)
 		offers = append(offers, o)
 	}
 
 	r.logger.WithFields(logger.LoggerFields{"requestID": requestID, "query": query, "params": args, "success": true, "count": len(offers)}).Info("SQL query GetOffersByFilter succeeded")
 
-	return offers, nil
+	return offers, total, nil
+}
+
+func (r *offerRepository) GetOfferClusters(ctx context.Context, f domain.OfferFilter, cellSize float64, userID *int) ([]domain.OfferCluster, error) {
+	requestID := ctx.Value(utils.RequestIDKey)
+
+	fq := buildOfferFilter(f, userID)
+	args := append(fq.args, cellSize)
+	query := fmt.Sprintf(offerClustersSQL, fq.where, len(args))
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		r.logger.WithFields(logger.LoggerFields{"requestID": requestID, "query": query, "params": args, "success": false, "err": err.Error()}).Error("SQL query GetOfferClusters failed")
+		return nil, err
+	}
+	defer rows.Close()
+
+	clusters := make([]domain.OfferCluster, 0)
+	for rows.Next() {
+		var c domain.OfferCluster
+		if err := rows.Scan(&c.Count, &c.Longitude, &c.Latitude, &c.MinPrice, &c.MaxPrice, &c.OfferID); err != nil {
+			r.logger.WithFields(logger.LoggerFields{"requestID": requestID, "query": query, "success": false, "err": err.Error()}).Error("SQL query GetOfferClusters scan failed")
+			return nil, err
+		}
+		clusters = append(clusters, c)
+	}
+
+	r.logger.WithFields(logger.LoggerFields{"requestID": requestID, "query": query, "params": args, "success": true, "count": len(clusters)}).Info("SQL query GetOfferClusters succeeded")
+
+	return clusters, nil
 }
 
 func (r *offerRepository) UpdateOffer(ctx context.Context, o Offer) error {
@@ -439,133 +807,215 @@ func (r *offerRepository) DeleteOffer(ctx context.Context, id int64) error {
 	return err
 }
 
-func (r *offerRepository) CreateImageAndBindToOffer(ctx context.Context, offerID int, uuid string) (int64, error) {
+func (r *offerRepository) CreateImageAndBindToOffer(ctx context.Context, offerID int, uuid string, meta domain.ImageMeta) (int64, error) {
 	requestID := ctx.Value(utils.RequestIDKey)
 
+	variants := meta.Variants
+	if variants == nil {
+		variants = []int{}
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		r.logger.WithFields(logger.LoggerFields{"requestID": requestID, "step": "begin", "offer_id": offerID, "err": err.Error()}).Error("Ошибка при открытии транзакции")
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Параллельные загрузки в одно объявление выстраиваются в очередь на строке объявления,
+	// иначе обе займут одну позицию и обе станут обложкой
+	var lockedID int64
+	err = tx.QueryRow(ctx, `
+		SELECT id FROM kvartirum.Offer WHERE id = $1 FOR UPDATE;
+	`, offerID).Scan(&lockedID)
+	if err != nil {
+		r.logger.WithFields(logger.LoggerFields{"requestID": requestID, "step": "lock offer", "offer_id": offerID, "err": err.Error()}).Error("Ошибка при блокировке Offer")
+		return 0, err
+	}
+
 	var imageID int64
-	err := r.db.QueryRow(ctx, `
-		INSERT INTO kvartirum.Image (uuid)
-		VALUES ($1)
+	err = tx.QueryRow(ctx, `
+		INSERT INTO kvartirum.Image (uuid, phash, variants)
+		VALUES ($1, $2, $3)
 		RETURNING id;
-	`, uuid).Scan(&imageID)
+	`, uuid, meta.PHash, variants).Scan(&imageID)
 
 	if err != nil {
 		r.logger.WithFields(logger.LoggerFields{"requestID": requestID, "step": "insert image", "uuid": uuid, "err": err.Error()}).Error("Ошибка при вставке Image")
 		return 0, err
 	}
 
-	_, err = r.db.Exec(ctx, `
-		INSERT INTO kvartirum.OfferImages (offer_id, image_id)
-		VALUES ($1, $2);
+	// Новая фотография встает в конец галереи и становится обложкой, только если обложки еще нет
+	_, err = tx.Exec(ctx, `
+		INSERT INTO kvartirum.OfferImages (offer_id, image_id, position, is_cover)
+		SELECT $1, $2, COALESCE(MAX(position) + 1, 0), NOT COALESCE(BOOL_OR(is_cover), FALSE)
+		FROM kvartirum.OfferImages
+		WHERE offer_id = $1;
 	`, offerID, imageID)
 	if err != nil {
 		r.logger.WithFields(logger.LoggerFields{"requestID": requestID, "step": "bind to offer", "offer_id": offerID, "image_id": imageID, "err": err.Error()}).Error("Ошибка при вставке в OfferImages")
 		return 0, err
 	}
 
+	if err = tx.Commit(ctx); err != nil {
+		r.logger.WithFields(logger.LoggerFields{"requestID": requestID, "step": "commit", "offer_id": offerID, "image_id": imageID, "err": err.Error()}).Error("Ошибка при фиксации транзакции")
+		return 0, err
+	}
+
 	r.logger.WithFields(logger.LoggerFields{"requestID": requestID, "offer_id": offerID, "image_id": imageID, "success": true}).Info("Изображение добавлено и связано с оффером")
 
 	return imageID, nil
 }
 
-func (r *offerRepository) UpdateOfferStatus(ctx context.Context, offerID int, statusID int) error {
+// ChangeOfferStatus Переводит объявление из статуса from в статус to и пишет переход в историю.
+// Возвращает false, если статус объявления успели изменить
+func (r *offerRepository) ChangeOfferStatus(ctx context.Context, offerID int, from int, to int, userID int, reason string) (bool, error) {
 	requestID := ctx.Value(utils.RequestIDKey)
 
-	_, err := r.db.Exec(ctx, `
-		UPDATE kvartirum.Offer
-		SET offer_status_id = $1
-		WHERE id = $2;
-	`, statusID, offerID)
+	tag, err := r.db.Exec(ctx, changeOfferStatusSQL, offerID, from, to, userID, reason)
 
-	r.logger.WithFields(logger.LoggerFields{"requestID": requestID, "offer_id": offerID, "status_id": statusID, "success": err == nil}).Info("SQL query UpdateOfferStatus")
+	logFields := logger.LoggerFields{"requestID": requestID, "query": changeOfferStatusSQL, "params": logger.LoggerFields{"offer_id": offerID, "from": from, "to": to}, "success": err == nil}
+	if err != nil {
+		r.logger.WithFields(logFields).Error("SQL query ChangeOfferStatus failed")
+		return false, err
+	}
+	r.logger.WithFields(logFields).Info("SQL query ChangeOfferStatus succeeded")
 
-	return err
+	return tag.RowsAffected() > 0, nil
 }
 
-func (r *offerRepository) GetOfferData(ctx context.Context, offer domain.Offer, userID *int) (domain.OfferData, error) {
+// GetOffersData Фотографии и счетчики сразу для всех объявлений страницы, двумя запросами.
+// Отметки пользователя сюда не входят, чтобы результат можно было кешировать для всех
+func (r *offerRepository) GetOffersData(ctx context.Context, offerIDs []int) (map[int]domain.OfferData, error) {
 	requestID := ctx.Value(utils.RequestIDKey)
 
-	var offerData domain.OfferData
+	data := make(map[int]domain.OfferData, len(offerIDs))
 
-	rows, err := r.db.Query(ctx, `
-	SELECT
-		i.id,
-		i.uuid
-	FROM kvartirum.OfferImages oi
-	LEFT JOIN kvartirum.Image i ON oi.image_id = i.id
-	WHERE oi.offer_id = $1
-	ORDER BY oi.created_at;
-	`, offer.ID)
+	rows, err := r.db.Query(ctx, getOffersImagesSQL, offerIDs)
+
+	logFields := logger.LoggerFields{"requestID": requestID, "query": getOffersImagesSQL, "params": logger.LoggerFields{"offer_ids": offerIDs}, "success": err == nil}
+	if err != nil {
+		r.logger.WithFields(logFields).Error("SQL query GetOffersImages failed")
+		return nil, err
+	}
 
 	for rows.Next() {
+		var offerID int
 		var offerImage domain.OfferImage
-		err := rows.Scan(&offerImage.ID, &offerImage.Image)
-		if err != nil {
-			return domain.OfferData{}, err
+		var widths []int
+		if err := rows.Scan(&offerID, &offerImage.ID, &offerImage.Image, &widths, &offerImage.IsCover); err != nil {
+			rows.Close()
+			r.logger.WithFields(logFields).Error("SQL query GetOffersImages failed")
+			return nil, err
+		}
+		if len(widths) > 0 {
+			offerImage.Variants = make(map[string]string, len(widths))
+			for _, w := range widths {
+				offerImage.Variants[strconv.Itoa(w)] = domain.VariantObjectName(offerImage.Image, w)
+			}
 		}
+		offerData := data[offerID]
 		offerData.Images = append(offerData.Images, offerImage)
+		data[offerID] = offerData
 	}
+	rows.Close()
+	r.logger.WithFields(logFields).Info("SQL query GetOffersImages succeeded")
 
-	r.logger.WithFields(logger.LoggerFields{"requestID": requestID, "offerID": offer.ID, "success": err == nil}).Info("SQL GetOfferImages")
+	rows, err = r.db.Query(ctx, getOffersStatSQL, offerIDs)
 
-	err = r.db.QueryRow(ctx, `
-	SELECT
-		ms.id as station_id, ms.name as station_name, ml.color as color
-	FROM kvartirum.MetroStation ms
-	LEFT JOIN kvartirum.MetroLine ml ON ms.metro_line_id = ml.id
-	WHERE ms.id = $1;
-	`, offer.MetroStationID).Scan(&offerData.Metro.Station, &offerData.Metro.Id, &offerData.Metro.Color)
+	logFields = logger.LoggerFields{"requestID": requestID, "query": getOffersStatSQL, "params": logger.LoggerFields{"offer_ids": offerIDs}, "success": err == nil}
+	if err != nil {
+		r.logger.WithFields(logFields).Error("SQL query GetOffersStat failed")
+		return nil, err
+	}
+	defer rows.Close()
 
-	r.logger.WithFields(logger.LoggerFields{"requestID": requestID, "offerID": offer.ID, "success": err == nil}).Info("SQL GetOfferStation")
+	for rows.Next() {
+		var offerID, views int
+		var stat domain.OfferStat
+		if err := rows.Scan(&offerID, &stat.LikesStat.Amount, &stat.FavoriteStat.Amount, &views); err != nil {
+			r.logger.WithFields(logFields).Error("SQL query GetOffersStat failed")
+			return nil, err
+		}
+		stat.Views = &views
+		offerData := data[offerID]
+		offerData.OfferStat = stat
+		data[offerID] = offerData
+	}
+	r.logger.WithFields(logFields).Info("SQL query GetOffersStat succeeded")
 
-	if userID != nil {
-		err = r.db.QueryRow(ctx, isOfferLiked, userID, offer.ID).Scan(&offerData.OfferStat.LikesStat.IsLiked)
-		r.logger.WithFields(logger.LoggerFields{"requestID": requestID, "offerID": offer.ID, "success": err == nil}).Info("SQL Query IsLikedOffer")
+	return data, nil
+}
 
-		err = r.db.QueryRow(ctx, isFavoriteSQL, userID, offer.ID).Scan(&offerData.OfferStat.FavoriteStat.IsFavorited)
-		r.logger.WithFields(logger.LoggerFields{"requestID": requestID, "offerID": offer.ID, "success": err == nil}).Info("SQL Query IsFavoritedOffer")
-	}
+// GetOffersUserStat Лайки, избранное и заметки пользователя по объявлениям страницы
+func (r *offerRepository) GetOffersUserStat(ctx context.Context, offerIDs []int, userID int) (map[int]domain.OfferUserStat, error) {
+	requestID := ctx.Value(utils.RequestIDKey)
 
-	err = r.db.QueryRow(ctx, getLikeStat, offer.ID).Scan(&offerData.OfferStat.LikesStat.Amount)
-	r.logger.WithFields(logger.LoggerFields{"requestID": requestID, "offerID": offer.ID, "success": err == nil}).Info("SQL Query GetLikeStat")
+	rows, err := r.db.Query(ctx, getOffersUserStatSQL, offerIDs, userID)
 
-	err = r.db.QueryRow(ctx, getFavoriteStat, offer.ID).Scan(&offerData.OfferStat.FavoriteStat.Amount)
-	r.logger.WithFields(logger.LoggerFields{"requestID": requestID, "offerID": offer.ID, "success": err == nil}).Info("SQL Query GetFavoriteStat")
+	logFields := logger.LoggerFields{"requestID": requestID, "query": getOffersUserStatSQL, "params": logger.LoggerFields{"offer_ids": offerIDs, "user_id": userID}, "success": err == nil}
+	if err != nil {
+		r.logger.WithFields(logFields).Error("SQL query GetOffersUserStat failed")
+		return nil, err
+	}
+	defer rows.Close()
 
-	err = r.db.QueryRow(ctx, countView, offer.ID).Scan(&offerData.OfferStat.Views)
-	r.logger.WithFields(logger.LoggerFields{"requestID": requestID, "offerID": offer.ID, "success": err == nil}).Info("SQL Query CountViews")
+	stats := make(map[int]domain.OfferUserStat, len(offerIDs))
+	for rows.Next() {
+		var offerID int
+		var stat domain.OfferUserStat
+		if err := rows.Scan(&offerID, &stat.IsLiked, &stat.IsFavorited, &stat.Note); err != nil {
+			r.logger.WithFields(logFields).Error("SQL query GetOffersUserStat failed")
+			return nil, err
+		}
+		stats[offerID] = stat
+	}
+	r.logger.WithFields(logFields).Info("SQL query GetOffersUserStat succeeded")
 
-	return offerData, nil
+	return stats, nil
 }
 
-func (r *offerRepository) GetOfferImageWithUUID(ctx context.Context, imageID int64) (int64, string, error) {
+func (r *offerRepository) GetOfferImageWithUUID(ctx context.Context, imageID int64) (int64, string, []int, error) {
 	requestID := ctx.Value(utils.RequestIDKey)
 
 	var offerID int64
 	var uuid string
+	var variants []int
 
 	err := r.db.QueryRow(ctx, `
-		SELECT oi.offer_id, i.uuid
+		SELECT oi.offer_id, i.uuid, i.variants
 		FROM kvartirum.OfferImages oi
 		JOIN kvartirum.Image i ON oi.image_id = i.id
 		WHERE oi.image_id = $1;
-	`, imageID).Scan(&offerID, &uuid)
+	`, imageID).Scan(&offerID, &uuid, &variants)
 
 	if err != nil {
-		return 0, "", err
+		return 0, "", nil, err
 	}
 
 	r.logger.WithFields(logger.LoggerFields{"requestID": requestID, "image_id": imageID, "offer_id": offerID, "uuid": uuid}).Info("Получена связь offer-image")
 
-	return offerID, uuid, nil
+	return offerID, uuid, variants, nil
 }
 
 func (r *offerRepository) DeleteOfferImage(ctx context.Context, imageID int64) error {
 	requestID := ctx.Value(utils.RequestIDKey)
 
+	// Если удаляется обложка, ею становится первая из оставшихся фотографий
 	_, err := r.db.Exec(ctx, `
-		DELETE FROM kvartirum.OfferImages
-		WHERE image_id = $1;
+		WITH deleted AS (
+			DELETE FROM kvartirum.OfferImages
+			WHERE image_id = $1
+			RETURNING offer_id, is_cover
+		)
+		UPDATE kvartirum.OfferImages SET is_cover = TRUE, updated_at = CURRENT_TIMESTAMP
+		WHERE id = (
+			SELECT oi.id FROM kvartirum.OfferImages oi
+			JOIN deleted d ON d.offer_id = oi.offer_id AND d.is_cover
+			WHERE oi.image_id <> $1
+			ORDER BY oi.position, oi.id
+			LIMIT 1
+		);
 	`, imageID)
 
 	r.logger.WithFields(logger.LoggerFields{"requestID": requestID, "image_id": imageID, "success": err == nil}).Info("SQL Delete OfferImage")
@@ -693,17 +1143,42 @@ func (r *offerRepository) DeletePriceHistory(ctx context.Context, offerID int64)
 	return err
 }
 
-func (r *offerRepository) GetPriceHistory(ctx context.Context, offerID int64, limit int) ([]domain.OfferPriceHistory, error) {
+// GetPriceHistories Последние limit изменений цены по каждому объявлению, от новых к старым
+func (r *offerRepository) GetPriceHistories(ctx context.Context, offerIDs []int, limit int) (map[int][]domain.OfferPriceHistory, error) {
 	requestID := ctx.Value(utils.RequestIDKey)
 
-	rows, err := r.db.Query(ctx, `
-		SELECT price, recorded_at
-		FROM kvartirum.OfferPriceHistory
-		WHERE offer_id = $1
-		ORDER BY recorded_at DESC
-		LIMIT $2;
-	`, offerID, limit)
+	rows, err := r.db.Query(ctx, getPriceHistoriesSQL, offerIDs, limit)
+
+	logFields := logger.LoggerFields{"requestID": requestID, "query": getPriceHistoriesSQL, "params": logger.LoggerFields{"offer_ids": offerIDs, "limit": limit}, "success": err == nil}
+	if err != nil {
+		r.logger.WithFields(logFields).Error("SQL query GetPriceHistories failed")
+		return nil, err
+	}
+	defer rows.Close()
+
+	histories := make(map[int][]domain.OfferPriceHistory, len(offerIDs))
+	for rows.Next() {
+		var offerID int
+		var record domain.OfferPriceHistory
+		if err := rows.Scan(&offerID, &record.Price, &record.Date); err != nil {
+			r.logger.WithFields(logFields).Error("SQL query GetPriceHistories failed")
+			return nil, err
+		}
+		histories[offerID] = append(histories[offerID], record)
+	}
+	r.logger.WithFields(logFields).Info("SQL query GetPriceHistories succeeded")
+
+	return histories, nil
+}
+
+func (r *offerRepository) GetFullPriceHistory(ctx context.Context, offerID int64) ([]domain.OfferPriceHistory, error) {
+	requestID := ctx.Value(utils.RequestIDKey)
+
+	rows, err := r.db.Query(ctx, getFullPriceHistorySQL, offerID)
+
+	logFields := logger.LoggerFields{"requestID": requestID, "query": getFullPriceHistorySQL, "params": logger.LoggerFields{"offer_id": offerID}, "success": err == nil}
 	if err != nil {
+		r.logger.WithFields(logFields).Error("SQL query GetFullPriceHistory failed")
 		return nil, err
 	}
 	defer rows.Close()
@@ -712,12 +1187,16 @@ func (r *offerRepository) GetPriceHistory(ctx context.Context, offerID int64, li
 	for rows.Next() {
 		var record domain.OfferPriceHistory
 		if err := rows.Scan(&record.Price, &record.Date); err != nil {
+			r.logger.WithFields(logFields).Error("SQL query GetFullPriceHistory scan failed")
 			return nil, err
 		}
 		history = append(history, record)
 	}
-
-	r.logger.WithFields(logger.LoggerFields{"requestID": requestID, "success": err == nil}).Info("SQL Query  GetPriceHistory")
+	if err := rows.Err(); err != nil {
+		r.logger.WithFields(logFields).Error("SQL query GetFullPriceHistory failed")
+		return nil, err
+	}
+	r.logger.WithFields(logFields).Info("SQL query GetFullPriceHistory succeeded")
 
 	return history, nil
 }
@@ -746,18 +1225,46 @@ func (r *offerRepository) RemoveFavorite(ctx context.Context, userID, offerID in
 	return err
 }
 
-func (r *offerRepository) GetFavorites(ctx context.Context, userID int64, offerTypeID *int) ([]Offer, error) {
+// GetFavorites Избранное пользователя. statusID оставляет только объявления в этом статусе,
+// без него отдаются все, включая снятые с публикации
+func (r *offerRepository) GetFavorites(ctx context.Context, userID int64, offerTypeID *int, collectionID *int, statusID *int, page domain.Pagination) ([]Offer, int, error) {
+	requestID := ctx.Value(utils.RequestIDKey)
+
 	query := getFavoritesSQL
+	countQuery := countFavoritesSQL
 	args := []any{userID}
 
 	if offerTypeID != nil {
-		query += " AND o.offer_type_id = $2"
 		args = append(args, *offerTypeID)
+		cond := fmt.Sprintf(" AND o.offer_type_id = $%d", len(args))
+		query += cond
+		countQuery += cond
+	}
+	if collectionID != nil {
+		args = append(args, *collectionID)
+		cond := fmt.Sprintf(collectionFilterSQL, len(args))
+		query += cond
+		countQuery += cond
+	}
+	if statusID != nil {
+		args = append(args, *statusID)
+		cond := fmt.Sprintf(" AND o.offer_status_id = $%d", len(args))
+		query += cond
+		countQuery += cond
 	}
 
+	var total int
+	if err := r.db.QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
+		r.logger.WithFields(logger.LoggerFields{"requestID": requestID, "query": countQuery, "params": args, "success": false, "err": err.Error()}).Error("SQL query CountFavorites failed")
+		return nil, 0, err
+	}
+
+	query, args = paginate(query, args, "o.id", page, newestFirst)
+
 	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
-		return nil, err
+		r.logger.WithFields(logger.LoggerFields{"requestID": requestID, "query": query, "params": args, "success": false, "err": err.Error()}).Error("SQL query GetFavorites failed")
+		return nil, 0, err
 	}
 	defer rows.Close()
 
@@ -771,12 +1278,12 @@ func (r *offerRepository) GetFavorites(ctx context.Context, userID int64, offerT
 			&o.Rooms, &o.Address, &o.Flat, &o.Area, &o.CeilingHeight,
 			&o.Longitude, &o.Latitude, &o.CreatedAt, &o.UpdatedAt,
 		); err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 		offers = append(offers, o)
 	}
 
-	return offers, nil
+	return offers, total, nil
 }
 
 func (r *offerRepository) IsFavorite(ctx context.Context, userID, offerID int) (bool, error) {
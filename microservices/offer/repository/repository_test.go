@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"regexp"
 	"testing"
 	"time"
@@ -9,6 +10,7 @@ import (
 	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/domain"
 	"github.com/go-park-mail-ru/2025_1_404/pkg/logger"
 	"github.com/go-park-mail-ru/2025_1_404/pkg/utils"
+	"github.com/jackc/pgx/v5"
 	pgxmock "github.com/pashagolub/pgxmock/v4"
 	"github.com/stretchr/testify/require"
 )
@@ -109,7 +111,10 @@ func TestRepository_GetAllOffers(t *testing.T) {
 	defer mock.Close()
 
 	promotesUntil := time.Now()
-	mock.ExpectQuery(`(?i)SELECT .* FROM kvartirum.Offer`).
+	mock.ExpectQuery(`(?i)SELECT COUNT\(\*\) FROM kvartirum.Offer WHERE offer_status_id = 1`).
+		WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(`(?i)SELECT .* FROM kvartirum.Offer WHERE offer_status_id = 1 ORDER BY id DESC LIMIT \$1;`).
+		WithArgs(domain.DefaultPageLimit + 1).
 		WillReturnRows(pgxmock.NewRows([]string{
 			"id", "seller_id", "offer_type_id", "metro_station_id", "rent_type_id", "purchase_type_id",
 			"property_type_id", "offer_status_id", "renovation_id", "complex_id", "price", "description",
@@ -121,9 +126,10 @@ func TestRepository_GetAllOffers(t *testing.T) {
 			"37.6173", "55.7558", time.Now(), time.Now(), &promotesUntil,
 		))
 
-	list, err := repo.GetAllOffers(context.Background())
+	list, total, err := repo.GetAllOffers(context.Background(), domain.Pagination{Limit: domain.DefaultPageLimit})
 	require.NoError(t, err)
 	require.NotEmpty(t, list)
+	require.Equal(t, 1, total)
 	require.NoError(t, mock.ExpectationsWereMet())
 }
 
@@ -181,6 +187,13 @@ func TestRepository_DeleteOffer(t *testing.T) {
 	require.NoError(t, mock.ExpectationsWereMet())
 }
 
+var filterOfferColumns = []string{
+	"id", "seller_id", "offer_type_id", "metro_station_id", "rent_type_id", "purchase_type_id",
+	"property_type_id", "offer_status_id", "renovation_id", "complex_id", "price", "description",
+	"floor", "total_floors", "rooms", "address", "flat", "area", "ceiling_height", "longitude", "latitude",
+	"created_at", "updated_at", "promotes_until", "distance", "rank", "description_highlight", "address_highlight",
+}
+
 func TestRepository_GetOffersByFilter(t *testing.T) {
 	repo, mock := newTestRepo(t)
 	defer mock.Close()
@@ -191,20 +204,207 @@ func TestRepository_GetOffersByFilter(t *testing.T) {
 	}
 
 	timeNow := time.Now()
-	mock.ExpectQuery(`(?i)SELECT id, seller_id.*FROM kvartirum.Offer WHERE area >= \$1 AND price <= \$2 AND offer_status_id = \$3;`).
+	mock.ExpectQuery(`(?i)SELECT COUNT\(\*\) FROM kvartirum.Offer WHERE area >= \$1 AND price <= \$2 AND offer_status_id = \$3;`).
 		WithArgs(*filter.MinArea, *filter.MaxPrice, 1).
+		WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(7))
+	mock.ExpectQuery(`(?i)SELECT id, seller_id.*FROM kvartirum.Offer WHERE area >= \$1 AND price <= \$2 AND offer_status_id = \$3 AND id < \$4 ORDER BY id DESC LIMIT \$5;`).
+		WithArgs(*filter.MinArea, *filter.MaxPrice, 1, 50, 11).
+		WillReturnRows(pgxmock.NewRows(filterOfferColumns).AddRow(
+			1, 2, 1, nil, nil, nil, 1, 1, 1, nil, 1800000, nil, 2, 5, 2, nil, 10, 50, 3, "37.6173", "55.7558", timeNow, timeNow, &timeNow, nil, nil, nil, nil,
+		))
+
+	page := domain.Pagination{Limit: 10, Cursor: &domain.Cursor{ID: 50}}
+	offers, total, err := repo.GetOffersByFilter(context.Background(), filter, page, nil)
+	require.NoError(t, err)
+	require.Len(t, offers, 1)
+	require.Equal(t, 7, total)
+	require.Equal(t, int64(1), offers[0].ID)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_GetOffersByFilterLists(t *testing.T) {
+	repo, mock := newTestRepo(t)
+	defer mock.Close()
+
+	filter := domain.OfferFilter{
+		FloorMin:         ptr(3),
+		FloorMax:         ptr(10),
+		NotFirstFloor:    true,
+		NotLastFloor:     true,
+		Rooms:            []int{2, 3},
+		MetroStationID:   []int{1, 5, 9},
+		ComplexID:        ptr(4),
+		MinCeilingHeight: ptr(3),
+	}
+
+	mock.ExpectQuery(`(?i)SELECT COUNT\(\*\) FROM kvartirum.Offer WHERE floor >= \$1 AND floor <= \$2 AND floor > 1 AND floor < total_floors AND rooms = ANY\(\$3\) AND metro_station_id = ANY\(\$4\) AND complex_id = \$5 AND ceiling_height >= \$6 AND offer_status_id = \$7;`).
+		WithArgs(3, 10, []int{2, 3}, []int{1, 5, 9}, 4, 3, 1).
+		WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery(`(?i)SELECT id, seller_id.*WHERE floor >= \$1 .* offer_status_id = \$7 ORDER BY id DESC LIMIT \$8;`).
+		WithArgs(3, 10, []int{2, 3}, []int{1, 5, 9}, 4, 3, 1, 21).
+		WillReturnRows(pgxmock.NewRows(filterOfferColumns))
+
+	offers, total, err := repo.GetOffersByFilter(context.Background(), filter, domain.Pagination{Limit: 20}, nil)
+	require.NoError(t, err)
+	require.Empty(t, offers)
+	require.Equal(t, 0, total)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_GetOffersByFilterSorted(t *testing.T) {
+	repo, mock := newTestRepo(t)
+	defer mock.Close()
+
+	value := 1500000.0
+	filter := domain.OfferFilter{Sort: domain.SortPriceAsc}
+	page := domain.Pagination{Limit: 10, Cursor: &domain.Cursor{ID: 12, Sort: domain.SortPriceAsc, Value: &value}}
+
+	mock.ExpectQuery(`(?i)SELECT COUNT\(\*\) FROM kvartirum.Offer WHERE offer_status_id = \$1;`).
+		WithArgs(1).
+		WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery(`(?i)SELECT id, seller_id.*WHERE offer_status_id = \$1 AND \(price, id\) > \(\$2::float8, \$3\) ORDER BY price ASC, id ASC LIMIT \$4;`).
+		WithArgs(1, value, 12, 11).
+		WillReturnRows(pgxmock.NewRows(filterOfferColumns))
+
+	offers, total, err := repo.GetOffersByFilter(context.Background(), filter, page, nil)
+	require.NoError(t, err)
+	require.Empty(t, offers)
+	require.Equal(t, 0, total)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_GetOffersByFilterNear(t *testing.T) {
+	repo, mock := newTestRepo(t)
+	defer mock.Close()
+
+	filter := domain.OfferFilter{
+		BBox:   &domain.BBox{MinLongitude: 37, MinLatitude: 55, MaxLongitude: 38, MaxLatitude: 56},
+		Near:   &domain.GeoPoint{Longitude: 37.6173, Latitude: 55.7558},
+		Radius: ptr(2000),
+	}
+	timeNow := time.Now()
+	distance := 1234.4
+
+	mock.ExpectQuery(`(?i)SELECT COUNT\(\*\) FROM kvartirum.Offer WHERE offer_status_id = \$1 AND geo_longitude BETWEEN \$2 AND \$3 AND geo_latitude BETWEEN \$4 AND \$5 AND geo_latitude BETWEEN \$8 AND \$9 AND geo_longitude BETWEEN \$10 AND \$11 AND .*ASIN.* <= \$12;`).
+		WithArgs(1, 37.0, 38.0, 55.0, 56.0, 55.7558, 37.6173, pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), 2000.0).
+		WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(`(?i)SELECT id, seller_id.*promotes_until,\s+\(2 \* 6371000 .*\) AS distance, NULL::float8 AS rank.*FROM kvartirum.Offer WHERE .* ORDER BY id DESC LIMIT \$13;`).
+		WithArgs(1, 37.0, 38.0, 55.0, 56.0, 55.7558, 37.6173, pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), 2000.0, 21).
+		WillReturnRows(pgxmock.NewRows(filterOfferColumns).AddRow(
+			1, 2, 1, nil, nil, nil, 1, 1, 1, nil, 1800000, nil, 2, 5, 2, nil, 10, 50, 3, "37.63", "55.76", timeNow, timeNow, nil, &distance, nil, nil, nil,
+		))
+
+	offers, total, err := repo.GetOffersByFilter(context.Background(), filter, domain.Pagination{Limit: 20}, nil)
+	require.NoError(t, err)
+	require.Equal(t, 1, total)
+	require.Len(t, offers, 1)
+	require.Equal(t, distance, *offers[0].Distance)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_GetOffersByFilterSearch(t *testing.T) {
+	repo, mock := newTestRepo(t)
+	defer mock.Close()
+
+	query := "однокомнатная у метро"
+	filter := domain.OfferFilter{Query: &query, Sort: domain.SortRelevance}
+	timeNow := time.Now()
+	rank := 0.6
+	description := "Светлая \x01однокомнатная\x02 <b>квартира</b>"
+
+	mock.ExpectQuery(`(?i)SELECT COUNT\(\*\) FROM kvartirum.Offer WHERE offer_status_id = \$1 AND search_vector @@ websearch_to_tsquery\('russian', \$2\);`).
+		WithArgs(1, query).
+		WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(`(?i)SELECT id, seller_id.*ts_rank\(search_vector, websearch_to_tsquery\('russian', \$2\)\)::float8 AS rank, ts_headline\('russian', description.*ORDER BY ts_rank.* DESC, id DESC LIMIT \$3;`).
+		WithArgs(1, query, 21).
+		WillReturnRows(pgxmock.NewRows(filterOfferColumns).AddRow(
+			1, 2, 1, nil, nil, nil, 1, 1, 1, nil, 1800000, nil, 2, 5, 2, nil, 10, 50, 3, "37.63", "55.76", timeNow, timeNow, nil, nil, &rank, &description, nil,
+		))
+
+	offers, total, err := repo.GetOffersByFilter(context.Background(), filter, domain.Pagination{Limit: 20}, nil)
+	require.NoError(t, err)
+	require.Equal(t, 1, total)
+	require.Len(t, offers, 1)
+	require.Equal(t, rank, *offers[0].Rank)
+	require.Equal(t, "Светлая <mark>однокомнатная</mark> &lt;b&gt;квартира&lt;/b&gt;", *offers[0].DescriptionHighlight)
+	require.Nil(t, offers[0].AddressHighlight)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestHighlightHTML(t *testing.T) {
+	// Так ts_headline возвращает фрагмент описания, сохраненного через html.EscapeString
+	fragment := "ЖК &#34;Солнечный&#34; &amp; \x01парк\x02 у O&#39;Key &lt;b&gt;"
+
+	require.Equal(t, "ЖК &#34;Солнечный&#34; &amp; <mark>парк</mark> у O&#39;Key &lt;b&gt;", *highlightHTML(&fragment))
+	require.Nil(t, highlightHTML(nil))
+}
+
+func TestRepository_GetOfferClusters(t *testing.T) {
+	repo, mock := newTestRepo(t)
+	defer mock.Close()
+
+	filter := domain.OfferFilter{
+		Rooms: []int{2},
+		BBox:  &domain.BBox{MinLongitude: 37, MinLatitude: 55, MaxLongitude: 38, MaxLatitude: 56},
+	}
+
+	mock.ExpectQuery(`(?i)SELECT COUNT\(\*\), AVG\(geo_longitude\).*FROM kvartirum.Offer WHERE rooms = \$1 AND offer_status_id = \$2 AND geo_longitude BETWEEN \$3 AND \$4 AND geo_latitude BETWEEN \$5 AND \$6 AND geo_longitude IS NOT NULL AND geo_latitude IS NOT NULL\s+GROUP BY FLOOR\(geo_longitude / \$7\), FLOOR\(geo_latitude / \$7\);`).
+		WithArgs(2, 1, 37.0, 38.0, 55.0, 56.0, 0.5).
+		WillReturnRows(pgxmock.NewRows([]string{"count", "lon", "lat", "min", "max", "id"}).
+			AddRow(3, 37.61, 55.75, 1000000, 3000000, 7).
+			AddRow(1, 37.9, 55.9, 500000, 500000, 9))
+
+	clusters, err := repo.GetOfferClusters(context.Background(), filter, 0.5, nil)
+	require.NoError(t, err)
+	require.Len(t, clusters, 2)
+	require.Equal(t, domain.OfferCluster{Count: 3, Longitude: 37.61, Latitude: 55.75, MinPrice: 1000000, MaxPrice: 3000000, OfferID: 7}, clusters[0])
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_GetFavorites(t *testing.T) {
+	repo, mock := newTestRepo(t)
+	defer mock.Close()
+
+	offerTypeID := 2
+	timeNow := time.Now()
+
+	mock.ExpectQuery(`(?i)SELECT COUNT\(\*\) FROM kvartirum.UserOfferFavourites f JOIN kvartirum.Offer o ON o.id = f.offer_id WHERE f.user_id = \$1 AND o.offer_type_id = \$2`).
+		WithArgs(int64(1), offerTypeID).
+		WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(`(?i)SELECT o.id.*WHERE f.user_id = \$1 AND o.offer_type_id = \$2 ORDER BY o.id DESC LIMIT \$3;`).
+		WithArgs(int64(1), offerTypeID, 21).
 		WillReturnRows(pgxmock.NewRows([]string{
 			"id", "seller_id", "offer_type_id", "metro_station_id", "rent_type_id", "purchase_type_id",
 			"property_type_id", "offer_status_id", "renovation_id", "complex_id", "price", "description",
-			"floor", "total_floors", "rooms", "address", "flat", "area", "ceiling_height", "longitude", "latitude", "created_at", "updated_at", "promotes_until",
+			"floor", "total_floors", "rooms", "address", "flat", "area", "ceiling_height", "longitude", "latitude", "created_at", "updated_at",
 		}).AddRow(
-			1, 2, 1, nil, nil, nil, 1, 1, 1, nil, 1800000, nil, 2, 5, 2, nil, 10, 50, 3, "37.6173", "55.7558", timeNow, timeNow, &timeNow,
+			3, 2, offerTypeID, nil, nil, nil, 1, 1, 1, nil, 1800000, nil, 2, 5, 2, nil, 10, 50, 3, "37.6173", "55.7558", timeNow, timeNow,
 		))
 
-	offers, err := repo.GetOffersByFilter(context.Background(), filter, nil)
+	offers, total, err := repo.GetFavorites(context.Background(), 1, &offerTypeID, nil, nil, domain.Pagination{Limit: 20})
 	require.NoError(t, err)
 	require.Len(t, offers, 1)
-	require.Equal(t, int64(1), offers[0].ID)
+	require.Equal(t, 1, total)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_GetFavorites_Collection(t *testing.T) {
+	repo, mock := newTestRepo(t)
+	defer mock.Close()
+
+	collectionID := 5
+
+	mock.ExpectQuery(`(?i)SELECT COUNT\(\*\) FROM kvartirum.UserOfferFavourites f .*WHERE f.user_id = \$1 AND EXISTS \(.*WHERE c.id = \$2 AND c.user_id = f.user_id AND co.offer_id = o.id\s*\) AND o.offer_status_id = \$3`).
+		WithArgs(int64(1), collectionID, 1).
+		WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery(`(?i)SELECT o.id.*WHERE c.id = \$2 .*AND o.offer_status_id = \$3 ORDER BY o.id DESC LIMIT \$4;`).
+		WithArgs(int64(1), collectionID, 1, 21).
+		WillReturnRows(pgxmock.NewRows([]string{"id"}))
+
+	offers, total, err := repo.GetFavorites(context.Background(), 1, nil, &collectionID, ptr(domain.OfferStatusActive), domain.Pagination{Limit: 20})
+	require.NoError(t, err)
+	require.Empty(t, offers)
+	require.Equal(t, 0, total)
 	require.NoError(t, mock.ExpectationsWereMet())
 }
 
@@ -219,30 +419,53 @@ func TestRepository_CreateImageAndBindToOffer(t *testing.T) {
 	ctx := context.Background()
 	offerID := 1
 	uuid := "image-uuid"
+	phash := int64(0x0f0f0f0f)
+
+	// Ожидаем блокировку объявления в транзакции
+	mock.ExpectBegin()
+	mock.ExpectQuery(`(?i)SELECT id FROM kvartirum.Offer WHERE id = \$1 FOR UPDATE`).
+		WithArgs(offerID).
+		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(int64(offerID)))
 
 	// Ожидаем вставку картинки
-	mock.ExpectQuery(`(?i)INSERT INTO kvartirum.Image \(uuid\)`).
-		WithArgs(uuid).
+	mock.ExpectQuery(`(?i)INSERT INTO kvartirum.Image \(uuid, phash, variants\)`).
+		WithArgs(uuid, &phash, []int{800, 320}).
 		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(int64(10)))
 
 	// Ожидаем связь с оффером
-	mock.ExpectExec(`(?i)INSERT INTO kvartirum.OfferImages`).
+	mock.ExpectExec(`(?i)INSERT INTO kvartirum.OfferImages \(offer_id, image_id, position, is_cover\)\s+SELECT \$1, \$2, COALESCE\(MAX\(position\) \+ 1, 0\)`).
 		WithArgs(offerID, int64(10)).
 		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	mock.ExpectCommit()
 
-	imageID, err := repo.CreateImageAndBindToOffer(ctx, offerID, uuid)
+	imageID, err := repo.CreateImageAndBindToOffer(ctx, offerID, uuid, domain.ImageMeta{PHash: &phash, Variants: []int{800, 320}})
 	require.NoError(t, err)
 	require.Equal(t, int64(10), imageID)
 	require.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestRepository_CreateImageAndBindToOfferNoOffer(t *testing.T) {
+	repo, mock := newTestRepo(t)
+	defer mock.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`(?i)SELECT id FROM kvartirum.Offer WHERE id = \$1 FOR UPDATE`).
+		WithArgs(1).
+		WillReturnError(pgx.ErrNoRows)
+	mock.ExpectRollback()
+
+	_, err := repo.CreateImageAndBindToOffer(context.Background(), 1, "image-uuid", domain.ImageMeta{})
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestRepository_DeleteOfferImage(t *testing.T) {
 	repo, mock := newTestRepo(t)
 	defer mock.Close()
 
 	imageID := int64(5)
 
-	mock.ExpectExec(`(?i)DELETE FROM kvartirum.OfferImages`).
+	mock.ExpectExec(`(?i)DELETE FROM kvartirum.OfferImages.*UPDATE kvartirum.OfferImages SET is_cover = TRUE`).
 		WithArgs(imageID).
 		WillReturnResult(pgxmock.NewResult("DELETE", 1))
 
@@ -259,27 +482,38 @@ func TestRepository_GetOfferImageWithUUID(t *testing.T) {
 	expectedOfferID := int64(3)
 	expectedUUID := "abc-uuid"
 
-	mock.ExpectQuery(`(?i)SELECT oi.offer_id, i.uuid FROM kvartirum.OfferImages oi`).
+	mock.ExpectQuery(`(?i)SELECT oi.offer_id, i.uuid, i.variants FROM kvartirum.OfferImages oi`).
 		WithArgs(imageID).
-		WillReturnRows(pgxmock.NewRows([]string{"offer_id", "uuid"}).AddRow(expectedOfferID, expectedUUID))
+		WillReturnRows(pgxmock.NewRows([]string{"offer_id", "uuid", "variants"}).AddRow(expectedOfferID, expectedUUID, []int{320}))
 
-	offerID, uuid, err := repo.GetOfferImageWithUUID(context.Background(), imageID)
+	offerID, uuid, variants, err := repo.GetOfferImageWithUUID(context.Background(), imageID)
 	require.NoError(t, err)
 	require.Equal(t, expectedOfferID, offerID)
 	require.Equal(t, expectedUUID, uuid)
+	require.Equal(t, []int{320}, variants)
 	require.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestRepository_UpdateOfferStatus(t *testing.T) {
+func TestRepository_ChangeOfferStatus(t *testing.T) {
 	repo, mock := newTestRepo(t)
 	defer mock.Close()
 
-	mock.ExpectExec(`(?i)UPDATE kvartirum.Offer SET offer_status_id = \$1 WHERE id = \$2;`).
-		WithArgs(2, 1).
-		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	mock.ExpectExec(`(?i)UPDATE kvartirum.Offer SET offer_status_id = \$3\s+WHERE id = \$1 AND offer_status_id = \$2.*INSERT INTO kvartirum.OfferStatusHistory`).
+		WithArgs(1, 1, 3, 5, "Продано").
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
 
-	err := repo.UpdateOfferStatus(context.Background(), 1, 2)
+	changed, err := repo.ChangeOfferStatus(context.Background(), 1, 1, 3, 5, "Продано")
 	require.NoError(t, err)
+	require.True(t, changed)
+
+	// Статус уже изменен другим запросом
+	mock.ExpectExec(`(?i)UPDATE kvartirum.Offer SET offer_status_id`).
+		WithArgs(1, 1, 2, 5, "").
+		WillReturnResult(pgxmock.NewResult("INSERT", 0))
+
+	changed, err = repo.ChangeOfferStatus(context.Background(), 1, 1, 2, 5, "")
+	require.NoError(t, err)
+	require.False(t, changed)
 	require.NoError(t, mock.ExpectationsWereMet())
 }
 
@@ -390,20 +624,144 @@ func TestRepository_DeletePriceHistory(t *testing.T) {
 	require.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestRepository_GetPriceHistory(t *testing.T) {
+func TestRepository_GetPriceHistories(t *testing.T) {
+	repo, mock := newTestRepo(t)
+	defer mock.Close()
+
+	newer := time.Now()
+	older := newer.Add(-24 * time.Hour)
+	mock.ExpectQuery(`(?i)ROW_NUMBER\(\) OVER \(PARTITION BY offer_id ORDER BY recorded_at DESC, id DESC\).*WHERE offer_id = ANY\(\$1\)`).
+		WithArgs([]int{1, 2, 3}, 5).
+		WillReturnRows(pgxmock.NewRows([]string{"offer_id", "price", "recorded_at"}).
+			AddRow(1, 100, newer).
+			AddRow(1, 120, older).
+			AddRow(2, 300, newer))
+
+	histories, err := repo.GetPriceHistories(context.Background(), []int{1, 2, 3}, 5)
+	require.NoError(t, err)
+	require.Equal(t, []domain.OfferPriceHistory{{Price: 100, Date: newer}, {Price: 120, Date: older}}, histories[1])
+	require.Len(t, histories[2], 1)
+	require.Nil(t, histories[3])
+
+	mock.ExpectQuery(`(?i)FROM kvartirum.OfferPriceHistory`).
+		WithArgs([]int{4}, 5).
+		WillReturnError(errors.New("db error"))
+
+	_, err = repo.GetPriceHistories(context.Background(), []int{4}, 5)
+	require.Error(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_GetOffersData(t *testing.T) {
+	repo, mock := newTestRepo(t)
+	defer mock.Close()
+
+	mock.ExpectQuery(`(?i)SELECT oi.offer_id, i.id, i.uuid, i.variants, oi.is_cover FROM kvartirum.OfferImages oi .*WHERE oi.offer_id = ANY\(\$1\)`).
+		WithArgs([]int{1, 2}).
+		WillReturnRows(pgxmock.NewRows([]string{"offer_id", "id", "uuid", "variants", "is_cover"}).
+			AddRow(1, 10, "cover.jpg", []int{320}, true).
+			AddRow(1, 11, "room.jpg", []int(nil), false))
+	mock.ExpectQuery(`(?i)FROM unnest\(\$1::bigint\[\]\) AS o\(id\)`).
+		WithArgs([]int{1, 2}).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "likes", "favorites", "views"}).
+			AddRow(1, 3, 2, 40).
+			AddRow(2, 0, 0, 0))
+
+	data, err := repo.GetOffersData(context.Background(), []int{1, 2})
+	require.NoError(t, err)
+
+	require.Len(t, data[1].Images, 2)
+	require.Equal(t, map[string]string{"320": domain.VariantObjectName("cover.jpg", 320)}, data[1].Images[0].Variants)
+	require.Nil(t, data[1].Images[1].Variants)
+	require.Equal(t, domain.LikesStat{Amount: 3}, data[1].OfferStat.LikesStat)
+	require.Equal(t, domain.FavoriteStat{Amount: 2}, data[1].OfferStat.FavoriteStat)
+	require.Equal(t, 40, *data[1].OfferStat.Views)
+
+	require.Nil(t, data[2].Images)
+	require.Equal(t, 0, *data[2].OfferStat.Views)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_GetOffersDataError(t *testing.T) {
+	repo, mock := newTestRepo(t)
+	defer mock.Close()
+
+	mock.ExpectQuery(`(?i)FROM kvartirum.OfferImages oi`).
+		WithArgs([]int{1}).
+		WillReturnRows(pgxmock.NewRows([]string{"offer_id", "id", "uuid", "variants", "is_cover"}))
+	mock.ExpectQuery(`(?i)FROM unnest`).
+		WithArgs([]int{1}).
+		WillReturnError(errors.New("db error"))
+
+	_, err := repo.GetOffersData(context.Background(), []int{1})
+	require.Error(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_GetOffersUserStat(t *testing.T) {
+	repo, mock := newTestRepo(t)
+	defer mock.Close()
+
+	note := "позвонить вечером"
+	mock.ExpectQuery(`(?i)FROM unnest\(\$1::bigint\[\]\) AS o\(id\) LEFT JOIN kvartirum.UserOfferFavourites f ON f.offer_id = o.id AND f.user_id = \$2`).
+		WithArgs([]int{1, 2}, 7).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "is_liked", "is_favorited", "note"}).
+			AddRow(1, true, true, &note).
+			AddRow(2, false, false, nil))
+
+	stats, err := repo.GetOffersUserStat(context.Background(), []int{1, 2}, 7)
+	require.NoError(t, err)
+	require.Equal(t, domain.OfferUserStat{IsLiked: true, IsFavorited: true, Note: &note}, stats[1])
+	require.Equal(t, domain.OfferUserStat{}, stats[2])
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_GetFullPriceHistory(t *testing.T) {
+	repo, mock := newTestRepo(t)
+	defer mock.Close()
+
+	first := time.Now().Add(-48 * time.Hour)
+	second := time.Now()
+	mock.ExpectQuery(`(?i)SELECT price, recorded_at FROM kvartirum.OfferPriceHistory WHERE offer_id = \$1 ORDER BY recorded_at, id`).
+		WithArgs(int64(1)).
+		WillReturnRows(pgxmock.NewRows([]string{"price", "recorded_at"}).AddRow(200, first).AddRow(150, second))
+
+	history, err := repo.GetFullPriceHistory(context.Background(), 1)
+	require.NoError(t, err)
+	require.Equal(t, []domain.OfferPriceHistory{{Price: 200, Date: first}, {Price: 150, Date: second}}, history)
+
+	mock.ExpectQuery(`(?i)FROM kvartirum.OfferPriceHistory`).
+		WithArgs(int64(2)).
+		WillReturnError(errors.New("db error"))
+
+	_, err = repo.GetFullPriceHistory(context.Background(), 2)
+	require.Error(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_GetOffersByFilterPriceDropped(t *testing.T) {
 	repo, mock := newTestRepo(t)
 	defer mock.Close()
 
-	timestamp := time.Now()
-	mock.ExpectQuery(`(?i)SELECT price, recorded_at FROM kvartirum.OfferPriceHistory`).
-		WithArgs(int64(1), 5).
-		WillReturnRows(pgxmock.NewRows([]string{"price", "recorded_at"}).AddRow(123456, timestamp))
+	filter := domain.OfferFilter{PriceDropped: ptr(true)}
+
+	mock.ExpectQuery(`(?i)SELECT COUNT\(\*\) FROM kvartirum.Offer WHERE offer_status_id = \$1 AND EXISTS \(.*LAG\(price\).*WHERE offer_id = Offer.id.*h.price < h.prev_price\s*\);`).
+		WithArgs(1).
+		WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery(`(?i)SELECT id, seller_id.*WHERE offer_status_id = \$1 AND EXISTS .* ORDER BY id DESC LIMIT \$2;`).
+		WithArgs(1, 11).
+		WillReturnRows(pgxmock.NewRows(filterOfferColumns))
 
-	history, err := repo.GetPriceHistory(context.Background(), 1, 5)
+	_, _, err := repo.GetOffersByFilter(context.Background(), filter, domain.Pagination{Limit: 10}, nil)
 	require.NoError(t, err)
-	require.Len(t, history, 1)
-	require.Equal(t, 123456, history[0].Price)
-	require.Equal(t, timestamp, history[0].Date)
+
+	filter.PriceDropped = ptr(false)
+	mock.ExpectQuery(`(?i)SELECT COUNT\(\*\) FROM kvartirum.Offer WHERE offer_status_id = \$1 AND NOT EXISTS`).
+		WithArgs(1).
+		WillReturnError(errors.New("db error"))
+
+	_, _, err = repo.GetOffersByFilter(context.Background(), filter, domain.Pagination{Limit: 10}, nil)
+	require.Error(t, err)
 	require.NoError(t, mock.ExpectationsWereMet())
 }
 
@@ -473,7 +831,7 @@ func TestRepository_RemoveFavorite_Success(t *testing.T) {
 	requestID := "test-request-id"
 	ctx := context.WithValue(context.Background(), utils.RequestIDKey, requestID)
 
-	mock.ExpectExec(`DELETE FROM kvartirum\.UserOfferFavourites WHERE user_id = \$1 AND offer_id = \$2`).
+	mock.ExpectExec(`(?s)DELETE FROM kvartirum\.FavoriteCollectionOffer co.*DELETE FROM kvartirum\.UserOfferFavourites WHERE user_id = \$1 AND offer_id = \$2`).
 		WithArgs(userID, offerID).
 		WillReturnResult(pgxmock.NewResult("DELETE", 1))
 
@@ -492,12 +850,7 @@ func TestRepository_AddFavorite_Success(t *testing.T) {
 	requestID := "test-request"
 	ctx := context.WithValue(context.Background(), utils.RequestIDKey, requestID)
 
-	// Expect exact SQL with ON CONFLICT clause
-	mock.ExpectExec(regexp.QuoteMeta(`
-        INSERT INTO kvartirum.UserOfferFavourites (user_id, offer_id)
-        VALUES ($1, $2)
-        ON CONFLICT DO NOTHING
-    `)).WithArgs(userID, offerID).
+	mock.ExpectExec(regexp.QuoteMeta(addFavoriteSQL)).WithArgs(userID, offerID).
 		WillReturnResult(pgxmock.NewResult("INSERT", 1))
 
 	err := repo.AddFavorite(ctx, userID, offerID)
@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/domain"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/logger"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/utils"
+)
+
+const (
+	createSavedSearchSQL = `
+		INSERT INTO kvartirum.SavedSearch (user_id, name, filter, frequency)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, last_checked_at, created_at;
+	`
+
+	getSavedSearchesSQL = `
+		SELECT id, user_id, name, filter, frequency, last_checked_at, created_at
+		FROM kvartirum.SavedSearch
+		WHERE user_id = $1
+		ORDER BY id DESC;
+	`
+
+	deleteSavedSearchSQL = `
+		DELETE FROM kvartirum.SavedSearch
+		WHERE id = $1 AND user_id = $2
+		RETURNING id;
+	`
+
+	// getDueSavedSearchesSQL Поиски, которые пора проверить. Дольше всех ждавшие идут первыми
+	getDueSavedSearchesSQL = `
+		SELECT id, user_id, name, filter, frequency, last_checked_at, created_at
+		FROM kvartirum.SavedSearch
+		WHERE frequency = 'instant'
+			OR (frequency = 'daily' AND last_checked_at <= $1)
+			OR (frequency = 'weekly' AND last_checked_at <= $2)
+		ORDER BY last_checked_at
+		LIMIT $3;
+	`
+
+	// saveSearchAlertsSQL Уведомления и отметка о проверке пишутся одним запросом,
+	// чтобы при ошибке одни и те же совпадения не отправились повторно
+	saveSearchAlertsSQL = `
+		WITH checked AS (
+			UPDATE kvartirum.SavedSearch SET last_checked_at = $2 WHERE id = $1
+		)
+		INSERT INTO kvartirum.UserNotification (user_id, message, redirect_uri)
+		SELECT $3, n.message, NULLIF(n.redirect_uri, '')
+		FROM unnest($4::text[], $5::text[]) AS n(message, redirect_uri);
+	`
+)
+
+func (r *offerRepository) CreateSavedSearch(ctx context.Context, search domain.SavedSearch) (domain.SavedSearch, error) {
+	requestID := ctx.Value(utils.RequestIDKey)
+
+	filter, err := search.Filter.MarshalJSON()
+	if err != nil {
+		return domain.SavedSearch{}, err
+	}
+
+	err = r.db.QueryRow(ctx, createSavedSearchSQL, search.UserID, search.Name, string(filter), search.Frequency).
+		Scan(&search.ID, &search.LastCheckedAt, &search.CreatedAt)
+
+	logFields := logger.LoggerFields{"requestID": requestID, "query": createSavedSearchSQL, "params": logger.LoggerFields{"user_id": search.UserID, "name": search.Name}, "success": err == nil}
+	if err != nil {
+		r.logger.WithFields(logFields).Error("SQL query CreateSavedSearch failed")
+		return domain.SavedSearch{}, err
+	}
+	r.logger.WithFields(logFields).Info("SQL query CreateSavedSearch succeeded")
+
+	return search, nil
+}
+
+func (r *offerRepository) GetSavedSearches(ctx context.Context, userID int) ([]domain.SavedSearch, error) {
+	return r.querySavedSearches(ctx, "GetSavedSearches", getSavedSearchesSQL, userID)
+}
+
+func (r *offerRepository) GetDueSavedSearches(ctx context.Context, now time.Time, limit int) ([]domain.SavedSearch, error) {
+	return r.querySavedSearches(ctx, "GetDueSavedSearches", getDueSavedSearchesSQL,
+		now.Add(-domain.FrequencyDaily.Period()), now.Add(-domain.FrequencyWeekly.Period()), limit)
+}
+
+func (r *offerRepository) querySavedSearches(ctx context.Context, name string, query string, args ...any) ([]domain.SavedSearch, error) {
+	requestID := ctx.Value(utils.RequestIDKey)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		r.logger.WithFields(logger.LoggerFields{"requestID": requestID, "query": query, "params": args, "success": false, "err": err.Error()}).Error("SQL query " + name + " failed")
+		return nil, err
+	}
+	defer rows.Close()
+
+	searches := make([]domain.SavedSearch, 0)
+	for rows.Next() {
+		var (
+			s      domain.SavedSearch
+			filter []byte
+		)
+		if err := rows.Scan(&s.ID, &s.UserID, &s.Name, &filter, &s.Frequency, &s.LastCheckedAt, &s.CreatedAt); err != nil {
+			r.logger.WithFields(logger.LoggerFields{"requestID": requestID, "query": query, "success": false, "err": err.Error()}).Error("SQL query " + name + " scan failed")
+			return nil, err
+		}
+		if err := s.Filter.UnmarshalJSON(filter); err != nil {
+			r.logger.WithFields(logger.LoggerFields{"requestID": requestID, "search_id": s.ID, "err": err.Error()}).Error("SQL query " + name + " filter decode failed")
+			return nil, err
+		}
+		searches = append(searches, s)
+	}
+
+	r.logger.WithFields(logger.LoggerFields{"requestID": requestID, "query": query, "params": args, "success": true, "count": len(searches)}).Info("SQL query " + name + " succeeded")
+
+	return searches, nil
+}
+
+func (r *offerRepository) DeleteSavedSearch(ctx context.Context, id int, userID int) error {
+	requestID := ctx.Value(utils.RequestIDKey)
+
+	var deletedID int
+	err := r.db.QueryRow(ctx, deleteSavedSearchSQL, id, userID).Scan(&deletedID)
+
+	logFields := logger.LoggerFields{"requestID": requestID, "query": deleteSavedSearchSQL, "params": logger.LoggerFields{"id": id, "user_id": userID}, "success": err == nil}
+	if err != nil {
+		r.logger.WithFields(logFields).Error("SQL query DeleteSavedSearch failed")
+		return err
+	}
+	r.logger.WithFields(logFields).Info("SQL query DeleteSavedSearch succeeded")
+
+	return nil
+}
+
+func (r *offerRepository) SaveSearchAlerts(ctx context.Context, search domain.SavedSearch, checkedAt time.Time, notifications []domain.Notification) error {
+	requestID := ctx.Value(utils.RequestIDKey)
+
+	messages := make([]string, 0, len(notifications))
+	redirects := make([]string, 0, len(notifications))
+	for _, n := range notifications {
+		messages = append(messages, n.Message)
+		redirects = append(redirects, n.RedirectURI)
+	}
+
+	_, err := r.db.Exec(ctx, saveSearchAlertsSQL, search.ID, checkedAt, search.UserID, messages, redirects)
+
+	logFields := logger.LoggerFields{"requestID": requestID, "query": saveSearchAlertsSQL, "params": logger.LoggerFields{"search_id": search.ID, "count": len(notifications)}, "success": err == nil}
+	if err != nil {
+		r.logger.WithFields(logFields).Error("SQL query SaveSearchAlerts failed")
+		return err
+	}
+	r.logger.WithFields(logFields).Info("SQL query SaveSearchAlerts succeeded")
+
+	return nil
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/domain"
+	pgxmock "github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/require"
+)
+
+var savedSearchColumns = []string{"id", "user_id", "name", "filter", "frequency", "last_checked_at", "created_at"}
+
+func TestRepository_CreateSavedSearch(t *testing.T) {
+	repo, mock := newTestRepo(t)
+	defer mock.Close()
+
+	minPrice := 100000
+	search := domain.SavedSearch{
+		UserID:    1,
+		Name:      "Двушка у метро",
+		Frequency: domain.FrequencyDaily,
+		Filter:    domain.OfferFilter{MinPrice: &minPrice, Rooms: []int{2}},
+	}
+	filter, err := search.Filter.MarshalJSON()
+	require.NoError(t, err)
+	now := time.Now()
+
+	mock.ExpectQuery(`(?i)INSERT INTO kvartirum.SavedSearch`).
+		WithArgs(1, "Двушка у метро", string(filter), domain.FrequencyDaily).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "last_checked_at", "created_at"}).AddRow(7, now, now))
+
+	created, err := repo.CreateSavedSearch(context.Background(), search)
+	require.NoError(t, err)
+	require.Equal(t, 7, created.ID)
+	require.Equal(t, now, created.LastCheckedAt)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_GetSavedSearches(t *testing.T) {
+	repo, mock := newTestRepo(t)
+	defer mock.Close()
+
+	now := time.Now()
+	mock.ExpectQuery(`(?i)FROM kvartirum.SavedSearch\s+WHERE user_id = \$1`).
+		WithArgs(1).
+		WillReturnRows(pgxmock.NewRows(savedSearchColumns).
+			AddRow(2, 1, "Студии", []byte(`{"rooms":[0]}`), domain.FrequencyInstant, now, now).
+			AddRow(1, 1, "Все", []byte(`{}`), domain.FrequencyWeekly, now, now))
+
+	searches, err := repo.GetSavedSearches(context.Background(), 1)
+	require.NoError(t, err)
+	require.Len(t, searches, 2)
+	require.Equal(t, []int{0}, searches[0].Filter.Rooms)
+	require.Equal(t, domain.FrequencyWeekly, searches[1].Frequency)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_GetDueSavedSearches(t *testing.T) {
+	repo, mock := newTestRepo(t)
+	defer mock.Close()
+
+	now := time.Now()
+	mock.ExpectQuery(`(?i)FROM kvartirum.SavedSearch\s+WHERE frequency = 'instant'`).
+		WithArgs(now.Add(-24*time.Hour), now.Add(-7*24*time.Hour), 100).
+		WillReturnRows(pgxmock.NewRows(savedSearchColumns).
+			AddRow(3, 5, "Новостройки", []byte(`{"complex_id":4}`), domain.FrequencyDaily, now.Add(-25*time.Hour), now))
+
+	searches, err := repo.GetDueSavedSearches(context.Background(), now, 100)
+	require.NoError(t, err)
+	require.Len(t, searches, 1)
+	require.Equal(t, 5, searches[0].UserID)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_DeleteSavedSearch(t *testing.T) {
+	repo, mock := newTestRepo(t)
+	defer mock.Close()
+
+	mock.ExpectQuery(`(?i)DELETE FROM kvartirum.SavedSearch`).
+		WithArgs(3, 1).
+		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(3))
+	require.NoError(t, repo.DeleteSavedSearch(context.Background(), 3, 1))
+
+	mock.ExpectQuery(`(?i)DELETE FROM kvartirum.SavedSearch`).
+		WithArgs(3, 2).
+		WillReturnError(errors.New("no rows in result set"))
+	require.Error(t, repo.DeleteSavedSearch(context.Background(), 3, 2))
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_SaveSearchAlerts(t *testing.T) {
+	repo, mock := newTestRepo(t)
+	defer mock.Close()
+
+	now := time.Now()
+	search := domain.SavedSearch{ID: 3, UserID: 5}
+	notifications := []domain.Notification{
+		{UserID: 5, Message: "Новое объявление по поиску «Все»", RedirectURI: "/offer/10"},
+		{UserID: 5, Message: "Новое объявление по поиску «Все»", RedirectURI: "/offer/11"},
+	}
+
+	mock.ExpectExec(`(?i)UPDATE kvartirum.SavedSearch SET last_checked_at`).
+		WithArgs(3, now, 5,
+			[]string{notifications[0].Message, notifications[1].Message},
+			[]string{"/offer/10", "/offer/11"}).
+		WillReturnResult(pgxmock.NewResult("INSERT", 2))
+
+	require.NoError(t, repo.SaveSearchAlerts(context.Background(), search, now, notifications))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,81 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/domain"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/logger"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/utils"
+)
+
+// GetOfferStats Просмотры, лайки и избранное по объявлению за период. Доступно только владельцу
+func (u *offerUsecase) GetOfferStats(ctx context.Context, offerID int, userID int, period domain.StatsPeriod) (domain.OfferStats, error) {
+	requestID := ctx.Value(utils.RequestIDKey)
+
+	if err := u.CheckAccessToOffer(ctx, offerID, userID); err != nil {
+		u.logger.WithFields(logger.LoggerFields{"requestID": requestID, "offerID": offerID, "userID": userID, "err": err.Error()}).Warn("Offer usecase: offer stats access denied")
+		return domain.OfferStats{}, err
+	}
+
+	points, err := u.repo.GetOfferStats(ctx, offerID, period)
+	if err != nil {
+		u.logger.WithFields(logger.LoggerFields{"requestID": requestID, "offerID": offerID, "err": err.Error()}).Error("Offer usecase: get offer stats failed")
+		return domain.OfferStats{}, err
+	}
+	if points == nil {
+		points = []domain.StatsPoint{}
+	}
+
+	var totals domain.StatsTotals
+	for _, p := range points {
+		totals.Views += p.Views
+		totals.Likes += p.Likes
+		totals.Favorites += p.Favorites
+	}
+
+	return domain.OfferStats{
+		OfferID:    offerID,
+		From:       period.From,
+		To:         statsLastDay(period),
+		Bucket:     period.Bucket,
+		Points:     points,
+		Totals:     totals,
+		Conversion: totals.Conversion(),
+	}, nil
+}
+
+// GetSellerStats Сводка по всем объявлениям продавца в сравнении с похожими объявлениями
+func (u *offerUsecase) GetSellerStats(ctx context.Context, sellerID int, period domain.StatsPeriod) (domain.SellerStats, error) {
+	requestID := ctx.Value(utils.RequestIDKey)
+
+	offers, err := u.repo.GetSellerStats(ctx, sellerID, period)
+	if err != nil {
+		u.logger.WithFields(logger.LoggerFields{"requestID": requestID, "sellerID": sellerID, "err": err.Error()}).Error("Offer usecase: get seller stats failed")
+		return domain.SellerStats{}, err
+	}
+
+	stats := domain.SellerStats{
+		From:   period.From,
+		To:     statsLastDay(period),
+		Offers: make([]domain.SellerOfferStats, 0, len(offers)),
+	}
+	for _, offer := range offers {
+		offer.Conversion = offer.Totals.Conversion()
+		if offer.SimilarViews > 0 {
+			ratio := float64(offer.Totals.Views) / offer.SimilarViews
+			offer.ViewsVsSimilar = &ratio
+		}
+		stats.Totals.Views += offer.Totals.Views
+		stats.Totals.Likes += offer.Totals.Likes
+		stats.Totals.Favorites += offer.Totals.Favorites
+		stats.Offers = append(stats.Offers, offer)
+	}
+
+	return stats, nil
+}
+
+// statsLastDay Клиенту возвращается последний день периода включительно, как он его и передал
+func statsLastDay(period domain.StatsPeriod) time.Time {
+	return period.To.AddDate(0, 0, -1)
+}
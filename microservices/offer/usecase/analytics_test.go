@@ -0,0 +1,115 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-park-mail-ru/2025_1_404/config"
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/domain"
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/mocks"
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/repository"
+	yaMock "github.com/go-park-mail-ru/2025_1_404/pkg/api/yandex/mocks"
+	redisMock "github.com/go-park-mail-ru/2025_1_404/pkg/database/redis/mocks"
+	s3Mock "github.com/go-park-mail-ru/2025_1_404/pkg/database/s3/mocks"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/logger"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/utils"
+	authService "github.com/go-park-mail-ru/2025_1_404/proto/auth/mocks"
+	paymentService "github.com/go-park-mail-ru/2025_1_404/proto/payment/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetOfferStats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOfferRepository(ctrl)
+	offerUsecase := NewOfferUsecase(mockRepo, logger.NewStub(), s3Mock.NewMockS3Repo(ctrl), &config.Config{},
+		authService.NewMockAuthServiceClient(ctrl), paymentService.NewMockPaymentServiceClient(ctrl),
+		redisMock.NewMockRedisRepo(ctrl), yaMock.NewMockYandexRepo(ctrl))
+	ctx := context.WithValue(context.Background(), utils.RequestIDKey, "test-request-id")
+
+	from := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+	period := domain.StatsPeriod{From: from, To: from.AddDate(0, 0, 2), Bucket: domain.StatsBucketDay}
+
+	t.Run("totals and conversion", func(t *testing.T) {
+		mockRepo.EXPECT().GetOfferByID(ctx, int64(1)).Return(repository.Offer{ID: 1, SellerID: 5}, nil)
+		mockRepo.EXPECT().GetOfferStats(ctx, 1, period).Return([]domain.StatsPoint{
+			{Date: from, Views: 30, Likes: 3, Favorites: 1},
+			{Date: from.AddDate(0, 0, 1), Views: 10, Likes: 1, Favorites: 1},
+		}, nil)
+
+		stats, err := offerUsecase.GetOfferStats(ctx, 1, 5, period)
+		require.NoError(t, err)
+		assert.Equal(t, domain.StatsTotals{Views: 40, Likes: 4, Favorites: 2}, stats.Totals)
+		assert.InDelta(t, 0.1, stats.Conversion.LikeRate, 1e-9)
+		assert.InDelta(t, 0.05, stats.Conversion.FavoriteRate, 1e-9)
+		assert.Equal(t, from.AddDate(0, 0, 1), stats.To)
+	})
+
+	t.Run("no views", func(t *testing.T) {
+		mockRepo.EXPECT().GetOfferByID(ctx, int64(1)).Return(repository.Offer{ID: 1, SellerID: 5}, nil)
+		mockRepo.EXPECT().GetOfferStats(ctx, 1, period).Return(nil, nil)
+
+		stats, err := offerUsecase.GetOfferStats(ctx, 1, 5, period)
+		require.NoError(t, err)
+		assert.Empty(t, stats.Points)
+		assert.NotNil(t, stats.Points)
+		assert.Equal(t, domain.StatsConversion{}, stats.Conversion)
+	})
+
+	t.Run("not owner", func(t *testing.T) {
+		mockRepo.EXPECT().GetOfferByID(ctx, int64(1)).Return(repository.Offer{ID: 1, SellerID: 6}, nil)
+
+		_, err := offerUsecase.GetOfferStats(ctx, 1, 5, period)
+		assert.EqualError(t, err, "нет доступа к этому объявлению")
+	})
+
+	t.Run("repo error", func(t *testing.T) {
+		mockRepo.EXPECT().GetOfferByID(ctx, int64(1)).Return(repository.Offer{ID: 1, SellerID: 5}, nil)
+		mockRepo.EXPECT().GetOfferStats(ctx, 1, period).Return(nil, errors.New("db error"))
+
+		_, err := offerUsecase.GetOfferStats(ctx, 1, 5, period)
+		assert.Error(t, err)
+	})
+}
+
+func TestGetSellerStats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOfferRepository(ctrl)
+	offerUsecase := NewOfferUsecase(mockRepo, logger.NewStub(), s3Mock.NewMockS3Repo(ctrl), &config.Config{},
+		authService.NewMockAuthServiceClient(ctrl), paymentService.NewMockPaymentServiceClient(ctrl),
+		redisMock.NewMockRedisRepo(ctrl), yaMock.NewMockYandexRepo(ctrl))
+	ctx := context.WithValue(context.Background(), utils.RequestIDKey, "test-request-id")
+
+	from := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+	period := domain.StatsPeriod{From: from, To: from.AddDate(0, 0, 30), Bucket: domain.StatsBucketDay}
+
+	t.Run("compare with similar", func(t *testing.T) {
+		mockRepo.EXPECT().GetSellerStats(ctx, 5, period).Return([]domain.SellerOfferStats{
+			{OfferID: 1, Totals: domain.StatsTotals{Views: 40, Likes: 4, Favorites: 2}, SimilarCount: 3, SimilarViews: 20},
+			{OfferID: 2, Totals: domain.StatsTotals{Views: 5}},
+		}, nil)
+
+		stats, err := offerUsecase.GetSellerStats(ctx, 5, period)
+		require.NoError(t, err)
+		require.Len(t, stats.Offers, 2)
+		assert.Equal(t, domain.StatsTotals{Views: 45, Likes: 4, Favorites: 2}, stats.Totals)
+		require.NotNil(t, stats.Offers[0].ViewsVsSimilar)
+		assert.InDelta(t, 2.0, *stats.Offers[0].ViewsVsSimilar, 1e-9)
+		assert.InDelta(t, 0.1, stats.Offers[0].Conversion.LikeRate, 1e-9)
+		assert.Nil(t, stats.Offers[1].ViewsVsSimilar)
+	})
+
+	t.Run("repo error", func(t *testing.T) {
+		mockRepo.EXPECT().GetSellerStats(ctx, 5, period).Return(nil, errors.New("db error"))
+
+		_, err := offerUsecase.GetSellerStats(ctx, 5, period)
+		assert.Error(t, err)
+	})
+}
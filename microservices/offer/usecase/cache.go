@@ -0,0 +1,35 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/go-park-mail-ru/2025_1_404/pkg/cache"
+)
+
+// invalidateOffer Сбрасывает кешированную карточку объявления и сведения о ЖК, в которые
+// оно входило до изменения и входит после
+func (u *offerUsecase) invalidateOffer(ctx context.Context, offerID int, complexIDs ...*int) {
+	if u.cfg.App.Cache.OfferCards > 0 {
+		u.cache.Delete(ctx, cache.OfferCardKey(offerID))
+	}
+
+	if u.cfg.App.Cache.ZhkInfo > 0 {
+		keys := make([]string, 0, len(complexIDs))
+		for _, complexID := range complexIDs {
+			if complexID != nil {
+				keys = append(keys, cache.ZhkInfoKey(int64(*complexID)))
+			}
+		}
+		if len(keys) > 0 {
+			u.cache.Delete(ctx, keys...)
+		}
+	}
+}
+
+// invalidateAllZhkInfo Сбрасывает сведения обо всех ЖК, когда неизвестно, каких из них
+// коснулось изменение
+func (u *offerUsecase) invalidateAllZhkInfo(ctx context.Context) {
+	if u.cfg.App.Cache.ZhkInfo > 0 {
+		u.cache.DeletePattern(ctx, cache.ZhkInfoPattern)
+	}
+}
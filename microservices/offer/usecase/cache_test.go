@@ -0,0 +1,207 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-park-mail-ru/2025_1_404/config"
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/domain"
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/mocks"
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/repository"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/api/yandex"
+	yaMock "github.com/go-park-mail-ru/2025_1_404/pkg/api/yandex/mocks"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/cache"
+	redisMock "github.com/go-park-mail-ru/2025_1_404/pkg/database/redis/mocks"
+	s3Mock "github.com/go-park-mail-ru/2025_1_404/pkg/database/s3/mocks"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/logger"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/utils"
+	authpb "github.com/go-park-mail-ru/2025_1_404/proto/auth"
+	authService "github.com/go-park-mail-ru/2025_1_404/proto/auth/mocks"
+	paymentService "github.com/go-park-mail-ru/2025_1_404/proto/payment/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestOfferCardsCache(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOfferRepository(ctrl)
+	mockAuthService := authService.NewMockAuthServiceClient(ctrl)
+	mockRedis := redisMock.NewMockRedisRepo(ctrl)
+	cfg := &config.Config{
+		Minio: config.MinioConfig{Path: Path, OffersBucket: Bucket},
+		App:   config.AppConfig{Cache: config.CacheConfig{OfferCards: time.Minute}},
+	}
+	offerUsecase := NewOfferUsecase(mockRepo, logger.NewStub(), s3Mock.NewMockS3Repo(ctrl), cfg,
+		mockAuthService, paymentService.NewMockPaymentServiceClient(ctrl), mockRedis, yaMock.NewMockYandexRepo(ctrl))
+	ctx := context.WithValue(context.Background(), utils.RequestIDKey, "test-request-id")
+
+	userID := 7
+	views := 12
+	cachedCard := domain.OfferData{
+		Images:    []domain.OfferImage{{ID: 5, Image: Path + Bucket + "a.jpg"}},
+		OfferStat: domain.OfferStat{LikesStat: domain.LikesStat{Amount: 3}, Views: &views},
+	}
+	cachedJSON, err := cachedCard.MarshalJSON()
+	require.NoError(t, err)
+	offers := []domain.Offer{{ID: 1, SellerID: 3}, {ID: 2, SellerID: 3}}
+	keys := []interface{}{cache.OfferCardKey(1), cache.OfferCardKey(2)}
+
+	t.Run("only missing cards are loaded and cached", func(t *testing.T) {
+		mockRedis.EXPECT().MGet(ctx, keys...).Return(map[string]string{cache.OfferCardKey(1): string(cachedJSON)}, nil)
+		mockRepo.EXPECT().GetOffersData(ctx, []int{2}).Return(map[int]domain.OfferData{
+			2: {Images: []domain.OfferImage{{ID: 6, Image: "b.jpg"}}},
+		}, nil)
+		mockAuthService.EXPECT().GetUsersByIds(ctx, &authpb.GetUsersByIdsRequest{Ids: []int32{3}}).
+			Return(&authpb.GetUsersByIdsResponse{Users: []*authpb.User{{Id: 3, FirstName: "Ivan", CreatedAt: timestamppb.Now()}}}, nil)
+		mockRepo.EXPECT().GetPriceHistories(ctx, []int{2}, 5).Return(map[int][]domain.OfferPriceHistory{}, nil)
+		mockRedis.EXPECT().Set(ctx, cache.OfferCardKey(2), gomock.Any(), time.Minute).Return(nil)
+		mockRepo.EXPECT().GetOffersUserStat(ctx, []int{1, 2}, userID).
+			Return(map[int]domain.OfferUserStat{1: {IsLiked: true}}, nil)
+
+		result, err := offerUsecase.PrepareOffersInfo(ctx, offers, &userID)
+
+		require.NoError(t, err)
+		require.Len(t, result, 2)
+		assert.Equal(t, Path+Bucket+"a.jpg", result[0].OfferData.Images[0].Image)
+		assert.Equal(t, domain.LikesStat{IsLiked: true, Amount: 3}, result[0].OfferData.OfferStat.LikesStat)
+		assert.Equal(t, 12, *result[0].OfferData.OfferStat.Views)
+		assert.Equal(t, Path+Bucket+"b.jpg", result[1].OfferData.Images[0].Image)
+		assert.Equal(t, "Ivan", result[1].OfferData.Seller.FirstName)
+	})
+
+	t.Run("card without seller is not cached", func(t *testing.T) {
+		mockRedis.EXPECT().MGet(ctx, keys...).Return(map[string]string{}, nil)
+		mockRepo.EXPECT().GetOffersData(ctx, []int{1, 2}).Return(map[int]domain.OfferData{}, nil)
+		mockAuthService.EXPECT().GetUsersByIds(ctx, gomock.Any()).Return(nil, errors.New("unavailable"))
+		mockRepo.EXPECT().GetPriceHistories(ctx, []int{1, 2}, 5).Return(map[int][]domain.OfferPriceHistory{}, nil)
+
+		result, err := offerUsecase.PrepareOffersInfo(ctx, offers, nil)
+
+		require.NoError(t, err)
+		assert.Len(t, result, 2)
+	})
+}
+
+func TestStationsCache(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOfferRepository(ctrl)
+	mockRedis := redisMock.NewMockRedisRepo(ctrl)
+	cfg := &config.Config{App: config.AppConfig{Cache: config.CacheConfig{Dictionaries: time.Hour}}}
+	offerUsecase := NewOfferUsecase(mockRepo, logger.NewStub(), s3Mock.NewMockS3Repo(ctrl), cfg,
+		authService.NewMockAuthServiceClient(ctrl), paymentService.NewMockPaymentServiceClient(ctrl),
+		mockRedis, yaMock.NewMockYandexRepo(ctrl))
+	ctx := context.WithValue(context.Background(), utils.RequestIDKey, "test-request-id")
+
+	stations := []domain.Metro{{Id: 1, Station: "Арбатская", Color: "#0078BE"}}
+	notFound := errors.New("redis: nil")
+
+	t.Run("miss reads repository and fills cache", func(t *testing.T) {
+		mockRedis.EXPECT().Get(ctx, cache.StationsKey).Return("", notFound)
+		mockRedis.EXPECT().IsNotFound(notFound).Return(true)
+		mockRepo.EXPECT().GetStations(ctx).Return(stations, nil)
+		mockRedis.EXPECT().Set(ctx, cache.StationsKey, `[{"station_id":1,"color":"#0078BE","station":"Арбатская"}]`, time.Hour).Return(nil)
+
+		result, err := offerUsecase.GetStations(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, stations, result)
+	})
+
+	t.Run("hit skips repository", func(t *testing.T) {
+		mockRedis.EXPECT().Get(ctx, cache.StationsKey).Return(`[{"station_id":1,"color":"#0078BE","station":"Арбатская"}]`, nil)
+
+		result, err := offerUsecase.GetStations(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, stations, result)
+	})
+}
+
+func TestCacheInvalidation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOfferRepository(ctrl)
+	mockRedis := redisMock.NewMockRedisRepo(ctrl)
+	cfg := &config.Config{App: config.AppConfig{
+		Cache:      config.CacheConfig{OfferCards: time.Minute, ZhkInfo: time.Minute},
+		Expiration: config.ExpirationConfig{BatchSize: 50, Lifetimes: map[int]time.Duration{2: 720 * time.Hour}},
+	}}
+	offerUsecase := NewOfferUsecase(mockRepo, logger.NewStub(), s3Mock.NewMockS3Repo(ctrl), cfg,
+		authService.NewMockAuthServiceClient(ctrl), paymentService.NewMockPaymentServiceClient(ctrl),
+		mockRedis, yaMock.NewMockYandexRepo(ctrl))
+	ctx := context.WithValue(context.Background(), utils.RequestIDKey, "test-request-id")
+
+	t.Run("status change drops card and complex", func(t *testing.T) {
+		complexID := 4
+		mockRepo.EXPECT().GetOfferByID(ctx, int64(1)).Return(repository.Offer{ID: 1, SellerID: 5, StatusID: domain.OfferStatusActive, ComplexID: &complexID}, nil)
+		mockRepo.EXPECT().ChangeOfferStatus(ctx, 1, domain.OfferStatusActive, domain.OfferStatusCompleted, 5, "").Return(true, nil)
+		mockRedis.EXPECT().Del(ctx, cache.OfferCardKey(1)).Return(nil)
+		mockRedis.EXPECT().Del(ctx, cache.ZhkInfoKey(4)).Return(nil)
+
+		assert.NoError(t, offerUsecase.ChangeOfferStatus(ctx, 1, 5, domain.TransitionComplete, ""))
+	})
+
+	t.Run("like toggle drops card", func(t *testing.T) {
+		like := domain.LikeRequest{OfferId: 2, UserId: 7}
+		mockRepo.EXPECT().IsOfferLiked(ctx, like).Return(false, nil)
+		mockRepo.EXPECT().CreateLike(ctx, like).Return(nil)
+		mockRedis.EXPECT().Del(ctx, cache.OfferCardKey(2)).Return(nil)
+		mockRepo.EXPECT().GetLikeStat(ctx, like).Return(1, nil)
+
+		_, err := offerUsecase.LikeOffer(ctx, like)
+		assert.NoError(t, err)
+	})
+
+	t.Run("failed toggle keeps card", func(t *testing.T) {
+		req := domain.FavoriteRequest{OfferId: 2, UserId: 7}
+		mockRepo.EXPECT().IsFavorite(ctx, 7, 2).Return(false, nil)
+		mockRepo.EXPECT().AddFavorite(ctx, 7, 2).Return(errors.New("db error"))
+
+		_, err := offerUsecase.FavoriteOffer(ctx, req)
+		assert.Error(t, err)
+	})
+
+	t.Run("expiration drops all complexes", func(t *testing.T) {
+		mockRepo.EXPECT().ExpireOffers(ctx, 2, gomock.Any(), 50, expiredOfferReason, expiredOfferMessage).Return(3, nil)
+		mockRepo.EXPECT().NotifyExpiringOffers(ctx, 2, gomock.Any(), 50, expiringOfferMessage).Return(0, nil)
+		mockRedis.EXPECT().DelByPattern(ctx, cache.ZhkInfoPattern).Return(2, nil)
+
+		assert.NoError(t, offerUsecase.ExpireOffers(ctx))
+	})
+}
+
+// TestCacheWithoutRedis CLI импорта создает usecase без Redis при включенных TTL
+func TestCacheWithoutRedis(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOfferRepository(ctrl)
+	mockYa := yaMock.NewMockYandexRepo(ctrl)
+	cfg := &config.Config{
+		App: config.AppConfig{Cache: config.CacheConfig{Dictionaries: time.Hour, OfferCards: time.Minute, ZhkInfo: time.Minute}},
+	}
+	offerUsecase := NewOfferUsecase(mockRepo, logger.NewStub(), s3Mock.NewMockS3Repo(ctrl), cfg, nil, nil, nil, mockYa)
+	ctx := context.WithValue(context.Background(), utils.RequestIDKey, "test-request-id")
+
+	address := "Москва, Тверская, 1"
+	purchaseType := 2
+	newOffer := domain.Offer{OfferTypeID: 1, PurchaseTypeID: &purchaseType, PropertyTypeID: 3, RenovationID: 1, SellerID: 7, Address: &address}
+
+	mockRepo.EXPECT().GetDictionaries(ctx).Return(testDictionaries, nil)
+	mockYa.EXPECT().GetCoordinatesOfAddress(address).Return(&yandex.Coordinates{Latitude: 55.7, Longitude: 37.6}, nil)
+	mockRepo.EXPECT().FindDuplicateOffers(ctx, gomock.Any()).Return(nil, nil)
+	mockRepo.EXPECT().CreateOffer(ctx, gomock.AssignableToTypeOf(repository.Offer{})).Return(int64(5), nil)
+
+	id, err := offerUsecase.CreateOffer(ctx, newOffer)
+	require.NoError(t, err)
+	assert.Equal(t, 5, id)
+
+	assert.NotPanics(t, func() { offerUsecase.invalidateOffer(ctx, 5, nil) })
+}
@@ -0,0 +1,166 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/domain"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/logger"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/utils"
+	"github.com/google/uuid"
+)
+
+const (
+	errCollectionNotFound      = "подборка не найдена"
+	errCollectionOfferNotFound = "объявление не найдено в подборке"
+)
+
+// GetCollections Список подборок пользователя. Основная подборка создается при первом обращении,
+// поэтому в списке она есть всегда и идет первой
+func (u *offerUsecase) GetCollections(ctx context.Context, userID int) (domain.FavoriteCollections, error) {
+	requestID := ctx.Value(utils.RequestIDKey)
+
+	if _, err := u.repo.GetDefaultCollectionID(ctx, userID); err != nil {
+		u.logger.WithFields(logger.LoggerFields{"requestID": requestID, "user_id": userID, "err": err.Error()}).Error("Offer usecase: get default collection failed")
+		return nil, err
+	}
+
+	collections, err := u.repo.GetCollections(ctx, userID)
+	if err != nil {
+		u.logger.WithFields(logger.LoggerFields{"requestID": requestID, "user_id": userID, "err": err.Error()}).Error("Offer usecase: get collections failed")
+		return nil, err
+	}
+
+	return collections, nil
+}
+
+func (u *offerUsecase) CreateCollection(ctx context.Context, userID int, name string) (domain.FavoriteCollection, error) {
+	requestID := ctx.Value(utils.RequestIDKey)
+
+	created, err := u.repo.CreateCollection(ctx, domain.FavoriteCollection{UserID: userID, Name: name})
+	if err != nil {
+		u.logger.WithFields(logger.LoggerFields{"requestID": requestID, "user_id": userID, "err": err.Error()}).Error("Offer usecase: create collection failed")
+		return domain.FavoriteCollection{}, err
+	}
+
+	return created, nil
+}
+
+func (u *offerUsecase) RenameCollection(ctx context.Context, id int, userID int, name string) (domain.FavoriteCollection, error) {
+	collection, err := u.repo.RenameCollection(ctx, id, userID, name)
+	if err != nil {
+		return domain.FavoriteCollection{}, fmt.Errorf(errCollectionNotFound)
+	}
+	return collection, nil
+}
+
+// DeleteCollection Удаляет подборку, объявления из нее остаются в избранном
+func (u *offerUsecase) DeleteCollection(ctx context.Context, id int, userID int) error {
+	requestID := ctx.Value(utils.RequestIDKey)
+
+	defaultID, err := u.repo.GetDefaultCollectionID(ctx, userID)
+	if err != nil {
+		u.logger.WithFields(logger.LoggerFields{"requestID": requestID, "user_id": userID, "err": err.Error()}).Error("Offer usecase: get default collection failed")
+		return fmt.Errorf("не удалось удалить подборку")
+	}
+	if id == defaultID {
+		return fmt.Errorf("основную подборку нельзя удалить")
+	}
+
+	if err := u.repo.DeleteCollection(ctx, id, userID, defaultID); err != nil {
+		return fmt.Errorf(errCollectionNotFound)
+	}
+	return nil
+}
+
+// AddToCollection Добавляет объявление в подборку, не убирая из других. Так же копируют
+// объявление из одной подборки в другую
+func (u *offerUsecase) AddToCollection(ctx context.Context, userID int, collectionID int, offerID int) error {
+	if err := u.repo.AddToCollection(ctx, userID, collectionID, offerID); err != nil {
+		return fmt.Errorf("подборка или объявление не найдены")
+	}
+	u.invalidateOffer(ctx, offerID)
+	return nil
+}
+
+func (u *offerUsecase) MoveToCollection(ctx context.Context, userID int, fromID int, toID int, offerID int) error {
+	if fromID == toID {
+		return fmt.Errorf("объявление уже в этой подборке")
+	}
+	if err := u.repo.MoveToCollection(ctx, userID, fromID, toID, offerID); err != nil {
+		return fmt.Errorf(errCollectionOfferNotFound)
+	}
+	return nil
+}
+
+// RemoveFromCollection Убирает объявление из подборки. Если других подборок с ним нет,
+// объявление пропадает из избранного
+func (u *offerUsecase) RemoveFromCollection(ctx context.Context, userID int, collectionID int, offerID int) error {
+	if err := u.repo.RemoveFromCollection(ctx, userID, collectionID, offerID); err != nil {
+		return fmt.Errorf(errCollectionOfferNotFound)
+	}
+	u.invalidateOffer(ctx, offerID)
+	return nil
+}
+
+// UpdateFavoriteNote Сохраняет личную заметку к избранному объявлению, пустая строка удаляет ее
+func (u *offerUsecase) UpdateFavoriteNote(ctx context.Context, userID int, offerID int, note string) error {
+	if utf8.RuneCountInString(note) > domain.MaxFavoriteNoteLength {
+		return fmt.Errorf("заметка не должна быть длиннее %d символов", domain.MaxFavoriteNoteLength)
+	}
+
+	var value *string
+	if note != "" {
+		value = &note
+	}
+	if err := u.repo.UpdateFavoriteNote(ctx, userID, offerID, value); err != nil {
+		return fmt.Errorf("объявление не в избранном")
+	}
+	return nil
+}
+
+// ShareCollection Открывает подборку по ссылке только для чтения. Повторный вызов выпускает
+// новую ссылку, старая перестает работать
+func (u *offerUsecase) ShareCollection(ctx context.Context, id int, userID int) (domain.FavoriteCollection, error) {
+	token := uuid.NewString()
+	collection, err := u.repo.SetCollectionShareToken(ctx, id, userID, &token)
+	if err != nil {
+		return domain.FavoriteCollection{}, fmt.Errorf(errCollectionNotFound)
+	}
+	return collection, nil
+}
+
+func (u *offerUsecase) UnshareCollection(ctx context.Context, id int, userID int) error {
+	if _, err := u.repo.SetCollectionShareToken(ctx, id, userID, nil); err != nil {
+		return fmt.Errorf(errCollectionNotFound)
+	}
+	return nil
+}
+
+// GetSharedCollection Подборка по ссылке. Объявления готовятся без пользователя,
+// поэтому заметки и отметки владельца не попадают в ответ. Ссылка открыта всем,
+// поэтому в нее попадают только активные объявления: черновики и снятые с публикации скрыты
+func (u *offerUsecase) GetSharedCollection(ctx context.Context, token string, page domain.Pagination) (domain.SharedCollection, error) {
+	requestID := ctx.Value(utils.RequestIDKey)
+
+	collection, err := u.repo.GetCollectionByShareToken(ctx, token)
+	if err != nil {
+		return domain.SharedCollection{}, fmt.Errorf(errCollectionNotFound)
+	}
+
+	status := domain.OfferStatusActive
+	rawOffers, total, err := u.repo.GetFavorites(ctx, int64(collection.UserID), nil, &collection.ID, &status, page)
+	if err != nil {
+		u.logger.WithFields(logger.LoggerFields{"requestID": requestID, "collection_id": collection.ID, "err": err.Error()}).Error("Offer usecase: get shared collection offers failed")
+		return domain.SharedCollection{}, err
+	}
+
+	offersPage, err := u.preparePage(ctx, rawOffers, total, page, domain.SortNewest, nil)
+	if err != nil {
+		u.logger.WithFields(logger.LoggerFields{"requestID": requestID, "err": err.Error()}).Error("Offer usecase: prepare shared collection failed")
+		return domain.SharedCollection{}, err
+	}
+
+	return domain.SharedCollection{Name: collection.Name, Offers: offersPage}, nil
+}
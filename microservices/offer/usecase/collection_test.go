@@ -0,0 +1,176 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-park-mail-ru/2025_1_404/config"
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/domain"
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/mocks"
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/repository"
+	yaMock "github.com/go-park-mail-ru/2025_1_404/pkg/api/yandex/mocks"
+	redisMock "github.com/go-park-mail-ru/2025_1_404/pkg/database/redis/mocks"
+	s3Mock "github.com/go-park-mail-ru/2025_1_404/pkg/database/s3/mocks"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/logger"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/utils"
+	authpb "github.com/go-park-mail-ru/2025_1_404/proto/auth"
+	authService "github.com/go-park-mail-ru/2025_1_404/proto/auth/mocks"
+	paymentService "github.com/go-park-mail-ru/2025_1_404/proto/payment/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestCollections(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOfferRepository(ctrl)
+	offerUsecase := NewOfferUsecase(mockRepo, logger.NewStub(), s3Mock.NewMockS3Repo(ctrl), &config.Config{},
+		authService.NewMockAuthServiceClient(ctrl), paymentService.NewMockPaymentServiceClient(ctrl),
+		redisMock.NewMockRedisRepo(ctrl), yaMock.NewMockYandexRepo(ctrl))
+	ctx := context.WithValue(context.Background(), utils.RequestIDKey, "test-request-id")
+
+	t.Run("default collection is created before listing", func(t *testing.T) {
+		collections := []domain.FavoriteCollection{{ID: 1, UserID: 7, Name: "Избранное", IsDefault: true}}
+		gomock.InOrder(
+			mockRepo.EXPECT().GetDefaultCollectionID(ctx, 7).Return(1, nil),
+			mockRepo.EXPECT().GetCollections(ctx, 7).Return(collections, nil),
+		)
+
+		result, err := offerUsecase.GetCollections(ctx, 7)
+		require.NoError(t, err)
+		assert.Equal(t, domain.FavoriteCollections(collections), result)
+	})
+
+	t.Run("create", func(t *testing.T) {
+		mockRepo.EXPECT().CreateCollection(ctx, domain.FavoriteCollection{UserID: 7, Name: "Для родителей"}).
+			Return(domain.FavoriteCollection{ID: 2, UserID: 7, Name: "Для родителей"}, nil)
+
+		created, err := offerUsecase.CreateCollection(ctx, 7, "Для родителей")
+		require.NoError(t, err)
+		assert.Equal(t, 2, created.ID)
+	})
+
+	t.Run("rename foreign collection", func(t *testing.T) {
+		mockRepo.EXPECT().RenameCollection(ctx, 2, 8, "Моя").Return(domain.FavoriteCollection{}, errors.New("no rows"))
+
+		_, err := offerUsecase.RenameCollection(ctx, 2, 8, "Моя")
+		assert.EqualError(t, err, "подборка не найдена")
+	})
+
+	t.Run("default collection can not be deleted", func(t *testing.T) {
+		mockRepo.EXPECT().GetDefaultCollectionID(ctx, 7).Return(1, nil)
+
+		err := offerUsecase.DeleteCollection(ctx, 1, 7)
+		assert.EqualError(t, err, "основную подборку нельзя удалить")
+	})
+
+	t.Run("delete moves offers to default", func(t *testing.T) {
+		mockRepo.EXPECT().GetDefaultCollectionID(ctx, 7).Return(1, nil)
+		mockRepo.EXPECT().DeleteCollection(ctx, 2, 7, 1).Return(nil)
+
+		assert.NoError(t, offerUsecase.DeleteCollection(ctx, 2, 7))
+	})
+
+	t.Run("move to the same collection", func(t *testing.T) {
+		err := offerUsecase.MoveToCollection(ctx, 7, 2, 2, 10)
+		assert.EqualError(t, err, "объявление уже в этой подборке")
+	})
+
+	t.Run("move and copy", func(t *testing.T) {
+		mockRepo.EXPECT().MoveToCollection(ctx, 7, 1, 2, 10).Return(nil)
+		mockRepo.EXPECT().AddToCollection(ctx, 7, 3, 10).Return(nil)
+		mockRepo.EXPECT().RemoveFromCollection(ctx, 7, 3, 11).Return(errors.New("no rows"))
+
+		assert.NoError(t, offerUsecase.MoveToCollection(ctx, 7, 1, 2, 10))
+		assert.NoError(t, offerUsecase.AddToCollection(ctx, 7, 3, 10))
+		assert.EqualError(t, offerUsecase.RemoveFromCollection(ctx, 7, 3, 11), "объявление не найдено в подборке")
+	})
+}
+
+func TestUpdateFavoriteNote(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOfferRepository(ctrl)
+	offerUsecase := NewOfferUsecase(mockRepo, logger.NewStub(), s3Mock.NewMockS3Repo(ctrl), &config.Config{},
+		authService.NewMockAuthServiceClient(ctrl), paymentService.NewMockPaymentServiceClient(ctrl),
+		redisMock.NewMockRedisRepo(ctrl), yaMock.NewMockYandexRepo(ctrl))
+	ctx := context.WithValue(context.Background(), utils.RequestIDKey, "test-request-id")
+
+	note := "спросить про парковку"
+	mockRepo.EXPECT().UpdateFavoriteNote(ctx, 7, 10, &note).Return(nil)
+	assert.NoError(t, offerUsecase.UpdateFavoriteNote(ctx, 7, 10, note))
+
+	mockRepo.EXPECT().UpdateFavoriteNote(ctx, 7, 10, nil).Return(nil)
+	assert.NoError(t, offerUsecase.UpdateFavoriteNote(ctx, 7, 10, ""))
+
+	mockRepo.EXPECT().UpdateFavoriteNote(ctx, 7, 11, nil).Return(errors.New("no rows"))
+	assert.EqualError(t, offerUsecase.UpdateFavoriteNote(ctx, 7, 11, ""), "объявление не в избранном")
+
+	err := offerUsecase.UpdateFavoriteNote(ctx, 7, 10, strings.Repeat("я", domain.MaxFavoriteNoteLength+1))
+	assert.Error(t, err)
+}
+
+func TestSharedCollection(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOfferRepository(ctrl)
+	mockAuthService := authService.NewMockAuthServiceClient(ctrl)
+	offerUsecase := NewOfferUsecase(mockRepo, logger.NewStub(), s3Mock.NewMockS3Repo(ctrl), &config.Config{},
+		mockAuthService, paymentService.NewMockPaymentServiceClient(ctrl),
+		redisMock.NewMockRedisRepo(ctrl), yaMock.NewMockYandexRepo(ctrl))
+	ctx := context.WithValue(context.Background(), utils.RequestIDKey, "test-request-id")
+
+	t.Run("share issues new token", func(t *testing.T) {
+		var token *string
+		mockRepo.EXPECT().SetCollectionShareToken(ctx, 2, 7, gomock.Not(gomock.Nil())).
+			DoAndReturn(func(_ context.Context, id int, userID int, t *string) (domain.FavoriteCollection, error) {
+				token = t
+				return domain.FavoriteCollection{ID: id, UserID: userID, ShareToken: t}, nil
+			})
+
+		collection, err := offerUsecase.ShareCollection(ctx, 2, 7)
+		require.NoError(t, err)
+		require.NotNil(t, token)
+		assert.Len(t, *token, 36)
+		assert.Equal(t, token, collection.ShareToken)
+	})
+
+	t.Run("unshare", func(t *testing.T) {
+		mockRepo.EXPECT().SetCollectionShareToken(ctx, 2, 7, nil).Return(domain.FavoriteCollection{}, nil)
+		assert.NoError(t, offerUsecase.UnshareCollection(ctx, 2, 7))
+	})
+
+	t.Run("unknown token", func(t *testing.T) {
+		mockRepo.EXPECT().GetCollectionByShareToken(ctx, "revoked").Return(domain.FavoriteCollection{}, errors.New("no rows"))
+
+		_, err := offerUsecase.GetSharedCollection(ctx, "revoked", domain.Pagination{Limit: 20})
+		assert.EqualError(t, err, "подборка не найдена")
+	})
+
+	t.Run("offers are prepared without owner", func(t *testing.T) {
+		collectionID := 2
+		activeOnly := domain.OfferStatusActive
+		page := domain.Pagination{Limit: 20}
+		user := &authpb.User{Id: 7, FirstName: "Ivan", CreatedAt: timestamppb.New(time.Now())}
+
+		mockRepo.EXPECT().GetCollectionByShareToken(ctx, "token").Return(domain.FavoriteCollection{ID: collectionID, UserID: 7, Name: "Для родителей"}, nil)
+		mockRepo.EXPECT().GetFavorites(ctx, int64(7), nil, &collectionID, &activeOnly, page).Return([]repository.Offer{{ID: 4}}, 1, nil)
+		mockRepo.EXPECT().GetOffersData(ctx, []int{4}).Return(map[int]domain.OfferData{}, nil)
+		mockAuthService.EXPECT().GetUsersByIds(ctx, gomock.Any()).Return(&authpb.GetUsersByIdsResponse{Users: []*authpb.User{user}}, nil)
+		mockRepo.EXPECT().GetPriceHistories(ctx, []int{4}, 5).Return(nil, nil)
+
+		shared, err := offerUsecase.GetSharedCollection(ctx, "token", page)
+		require.NoError(t, err)
+		assert.Equal(t, "Для родителей", shared.Name)
+		assert.Len(t, shared.Offers.Offers, 1)
+		assert.Equal(t, 1, shared.Offers.Total)
+	})
+}
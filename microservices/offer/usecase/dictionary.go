@@ -0,0 +1,67 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/domain"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/cache"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/logger"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/utils"
+)
+
+// GetDictionaries Справочники меняются только миграциями, поэтому кешируются вместе с версией.
+// Версия - хеш содержимого, после миграции она меняется сама
+func (u *offerUsecase) GetDictionaries(ctx context.Context) (domain.Dictionaries, error) {
+	requestID := ctx.Value(utils.RequestIDKey)
+	ttl := u.cfg.App.Cache.Dictionaries
+
+	var dicts domain.Dictionaries
+	if ttl > 0 && u.cache.Get(ctx, cache.Dictionaries, cache.DictionariesKey, &dicts) {
+		return dicts, nil
+	}
+
+	dicts, err := u.repo.GetDictionaries(ctx)
+	if err != nil {
+		u.logger.WithFields(logger.LoggerFields{"requestID": requestID, "err": err.Error()}).Error("Offer usecase: get dictionaries failed")
+		return domain.Dictionaries{}, err
+	}
+
+	data, err := dicts.MarshalJSON()
+	if err != nil {
+		return domain.Dictionaries{}, err
+	}
+	sum := sha256.Sum256(data)
+	dicts.Version = hex.EncodeToString(sum[:16])
+
+	if ttl > 0 {
+		u.cache.Set(ctx, cache.DictionariesKey, dicts, ttl)
+	}
+	return dicts, nil
+}
+
+// checkOfferReferences Несуществующий id иначе дошел бы до базы и упал на внешнем ключе
+func (u *offerUsecase) checkOfferReferences(ctx context.Context, offer domain.Offer) error {
+	dicts, err := u.GetDictionaries(ctx)
+	if err != nil {
+		return fmt.Errorf("не удалось проверить справочные значения")
+	}
+	if err := dicts.CheckOfferReferences(offer); err != nil {
+		return err
+	}
+
+	if offer.ComplexID == nil {
+		return nil
+	}
+	exists, err := u.repo.HousingComplexExists(ctx, *offer.ComplexID)
+	if err != nil {
+		u.logger.WithFields(logger.LoggerFields{"requestID": ctx.Value(utils.RequestIDKey), "err": err.Error()}).Error("Offer usecase: check housing complex failed")
+		return fmt.Errorf("не удалось проверить справочные значения")
+	}
+	if !exists {
+		return &domain.InvalidReferenceError{Field: "complex_id", ID: *offer.ComplexID}
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-park-mail-ru/2025_1_404/config"
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/domain"
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/mocks"
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/repository"
+	yaMock "github.com/go-park-mail-ru/2025_1_404/pkg/api/yandex/mocks"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/cache"
+	redisMock "github.com/go-park-mail-ru/2025_1_404/pkg/database/redis/mocks"
+	s3Mock "github.com/go-park-mail-ru/2025_1_404/pkg/database/s3/mocks"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/logger"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/utils"
+	authService "github.com/go-park-mail-ru/2025_1_404/proto/auth/mocks"
+	paymentService "github.com/go-park-mail-ru/2025_1_404/proto/payment/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testDictionaries Справочники из миграций, на которые ссылаются объявления в тестах
+var testDictionaries = domain.Dictionaries{
+	OfferTypes:    []domain.DictionaryItem{{ID: 1, Name: "Продажа"}, {ID: 2, Name: "Аренда"}},
+	RentTypes:     []domain.DictionaryItem{{ID: 1, Name: "Посуточно"}, {ID: 2, Name: "Долгосрок"}},
+	PurchaseTypes: []domain.DictionaryItem{{ID: 1, Name: "Новостройка"}, {ID: 2, Name: "Вторичка"}},
+	PropertyTypes: []domain.DictionaryItem{{ID: 1, Name: "Апартаменты"}, {ID: 2, Name: "Дом"}, {ID: 3, Name: "Квартира"}},
+	Renovations:   []domain.DictionaryItem{{ID: 1, Name: "Современный ремонт"}, {ID: 2, Name: "Косметический ремонт"}},
+	MetroLines: []domain.MetroLine{
+		{ID: 4, Name: "Замоскворецкая", Color: "007D3C", Stations: []domain.DictionaryItem{{ID: 15, Name: "Тверская"}}},
+	},
+}
+
+func TestGetDictionaries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOfferRepository(ctrl)
+	mockRedis := redisMock.NewMockRedisRepo(ctrl)
+	cfg := &config.Config{App: config.AppConfig{Cache: config.CacheConfig{Dictionaries: time.Hour}}}
+	offerUsecase := NewOfferUsecase(mockRepo, logger.NewStub(), s3Mock.NewMockS3Repo(ctrl), cfg,
+		authService.NewMockAuthServiceClient(ctrl), paymentService.NewMockPaymentServiceClient(ctrl),
+		mockRedis, yaMock.NewMockYandexRepo(ctrl))
+	ctx := context.WithValue(context.Background(), utils.RequestIDKey, "test-request-id")
+	notFound := errors.New("redis: nil")
+
+	var version string
+	t.Run("version is a content hash", func(t *testing.T) {
+		mockRedis.EXPECT().Get(ctx, cache.DictionariesKey).Return("", notFound)
+		mockRedis.EXPECT().IsNotFound(notFound).Return(true)
+		mockRepo.EXPECT().GetDictionaries(ctx).Return(testDictionaries, nil)
+		mockRedis.EXPECT().Set(ctx, cache.DictionariesKey, gomock.Any(), time.Hour).Return(nil)
+
+		dicts, err := offerUsecase.GetDictionaries(ctx)
+		require.NoError(t, err)
+		assert.Len(t, dicts.Version, 32)
+		assert.Equal(t, testDictionaries.MetroLines, dicts.MetroLines)
+		version = dicts.Version
+	})
+
+	t.Run("same content keeps version", func(t *testing.T) {
+		mockRedis.EXPECT().Get(ctx, cache.DictionariesKey).Return("", notFound)
+		mockRedis.EXPECT().IsNotFound(notFound).Return(true)
+		mockRepo.EXPECT().GetDictionaries(ctx).Return(testDictionaries, nil)
+		mockRedis.EXPECT().Set(ctx, cache.DictionariesKey, gomock.Any(), time.Hour).Return(nil)
+
+		dicts, err := offerUsecase.GetDictionaries(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, version, dicts.Version)
+	})
+
+	t.Run("cached", func(t *testing.T) {
+		mockRedis.EXPECT().Get(ctx, cache.DictionariesKey).Return(`{"version":"v1","offer_types":[{"id":1,"name":"Продажа"}]}`, nil)
+
+		dicts, err := offerUsecase.GetDictionaries(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "v1", dicts.Version)
+		assert.Equal(t, []domain.DictionaryItem{{ID: 1, Name: "Продажа"}}, dicts.OfferTypes)
+	})
+}
+
+func TestOfferReferencesChecked(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOfferRepository(ctrl)
+	offerUsecase := NewOfferUsecase(mockRepo, logger.NewStub(), s3Mock.NewMockS3Repo(ctrl), &config.Config{},
+		authService.NewMockAuthServiceClient(ctrl), paymentService.NewMockPaymentServiceClient(ctrl),
+		redisMock.NewMockRedisRepo(ctrl), yaMock.NewMockYandexRepo(ctrl))
+	ctx := context.WithValue(context.Background(), utils.RequestIDKey, "test-request-id")
+
+	address := "Москва"
+	station := 99
+	purchaseType := 2
+	rentType := 2
+	renovation := 7
+
+	t.Run("unknown station", func(t *testing.T) {
+		mockRepo.EXPECT().GetDictionaries(ctx).Return(testDictionaries, nil)
+
+		_, err := offerUsecase.CreateOffer(ctx, domain.Offer{OfferTypeID: 1, PurchaseTypeID: &purchaseType, PropertyTypeID: 3, RenovationID: 1, MetroStationID: &station, Address: &address})
+
+		var refErr *domain.InvalidReferenceError
+		require.ErrorAs(t, err, &refErr)
+		assert.Equal(t, domain.InvalidReferenceError{Field: "metro_station_id", ID: 99}, *refErr)
+	})
+
+	t.Run("unknown renovation", func(t *testing.T) {
+		mockRepo.EXPECT().GetDictionaries(ctx).Return(testDictionaries, nil)
+
+		_, err := offerUsecase.CreateOffer(ctx, domain.Offer{OfferTypeID: 2, RentTypeID: &rentType, PropertyTypeID: 3, RenovationID: renovation, Address: &address})
+		assert.EqualError(t, err, "некорректное значение renovation_id: 7")
+	})
+
+	t.Run("unknown housing complex", func(t *testing.T) {
+		complexID := 42
+		mockRepo.EXPECT().GetDictionaries(ctx).Return(testDictionaries, nil)
+		mockRepo.EXPECT().HousingComplexExists(ctx, complexID).Return(false, nil)
+
+		_, err := offerUsecase.CreateOffer(ctx, domain.Offer{OfferTypeID: 1, PurchaseTypeID: &purchaseType, PropertyTypeID: 3, RenovationID: 1, ComplexID: &complexID, Address: &address})
+
+		var refErr *domain.InvalidReferenceError
+		require.ErrorAs(t, err, &refErr)
+		assert.Equal(t, domain.InvalidReferenceError{Field: "complex_id", ID: 42}, *refErr)
+	})
+
+	t.Run("dictionaries unavailable", func(t *testing.T) {
+		mockRepo.EXPECT().GetOfferByID(ctx, int64(1)).Return(repository.Offer{ID: 1, Address: &address}, nil)
+		mockRepo.EXPECT().GetDictionaries(ctx).Return(domain.Dictionaries{}, errors.New("db error"))
+
+		err := offerUsecase.UpdateOffer(ctx, domain.Offer{ID: 1, OfferTypeID: 1, PurchaseTypeID: &purchaseType, PropertyTypeID: 3, RenovationID: 1, Address: &address})
+
+		var refErr *domain.InvalidReferenceError
+		assert.False(t, errors.As(err, &refErr))
+		assert.EqualError(t, err, "не удалось проверить справочные значения")
+	})
+}
@@ -0,0 +1,97 @@
+package usecase
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/domain"
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/repository"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/logger"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/utils"
+)
+
+// maxDuplicateReviewItems Сколько последних дубликатов отдается модератору
+const maxDuplicateReviewItems = 100
+
+// checkDuplicates Ищет активные объявления, совпадающие с кандидатом. Совпадение с объявлением
+// того же продавца запрещает операцию, совпадения с чужими возвращаются для передачи модераторам
+func (u *offerUsecase) checkDuplicates(ctx context.Context, c domain.DuplicateCandidate, sellerID int) ([]domain.OfferDuplicate, error) {
+	requestID := ctx.Value(utils.RequestIDKey)
+
+	matches, err := u.repo.FindDuplicateOffers(ctx, c)
+	if err != nil {
+		// Сбой проверки не должен мешать продавцу разместить объявление
+		u.logger.WithFields(logger.LoggerFields{"requestID": requestID, "offer_id": c.OfferID, "err": err.Error()}).Warn("Offer usecase: duplicate check failed")
+		return nil, nil
+	}
+
+	var own []int
+	seen := make(map[int]bool)
+	others := make([]domain.OfferDuplicate, 0, len(matches))
+	for _, m := range matches {
+		if m.SellerID != sellerID {
+			others = append(others, m)
+			continue
+		}
+		if !seen[m.DuplicateOf] {
+			seen[m.DuplicateOf] = true
+			own = append(own, m.DuplicateOf)
+		}
+	}
+	if len(own) > 0 {
+		return nil, &domain.DuplicateOfferError{OfferIDs: own}
+	}
+
+	return others, nil
+}
+
+// flagDuplicates Сохраняет вероятные дубликаты для проверки модератором
+func (u *offerUsecase) flagDuplicates(ctx context.Context, offerID int, matches []domain.OfferDuplicate) {
+	if len(matches) == 0 {
+		return
+	}
+
+	requestID := ctx.Value(utils.RequestIDKey)
+	if err := u.repo.SaveOfferDuplicates(ctx, offerID, matches); err != nil {
+		u.logger.WithFields(logger.LoggerFields{"requestID": requestID, "offer_id": offerID, "err": err.Error()}).Warn("Offer usecase: save duplicates failed")
+		return
+	}
+	u.logger.WithFields(logger.LoggerFields{"requestID": requestID, "offer_id": offerID, "count": len(matches)}).Info("Offer usecase: probable duplicates flagged")
+}
+
+// duplicateCandidate Параметры сохраненного объявления для поиска дубликатов
+func duplicateCandidate(o repository.Offer) domain.DuplicateCandidate {
+	c := domain.DuplicateCandidate{
+		OfferID: int(o.ID),
+		Rooms:   o.Rooms,
+		Floor:   o.Floor,
+		Flat:    o.Flat,
+		Area:    o.Area,
+	}
+	lat, latErr := strconv.ParseFloat(o.Latitude, 64)
+	lon, lonErr := strconv.ParseFloat(o.Longitude, 64)
+	if latErr == nil && lonErr == nil {
+		c.Latitude, c.Longitude = &lat, &lon
+	}
+	return c
+}
+
+func (u *offerUsecase) IsModerator(ctx context.Context, userID int) (bool, error) {
+	role, err := u.repo.GetUserRole(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return role == domain.RoleModerator, nil
+}
+
+func (u *offerUsecase) GetOfferDuplicates(ctx context.Context, offerID *int) (domain.OfferDuplicates, error) {
+	requestID := ctx.Value(utils.RequestIDKey)
+
+	duplicates, err := u.repo.GetOfferDuplicates(ctx, offerID, maxDuplicateReviewItems)
+	if err != nil {
+		u.logger.WithFields(logger.LoggerFields{"requestID": requestID, "err": err.Error()}).Error("Offer usecase: get duplicates failed")
+		return nil, err
+	}
+
+	return duplicates, nil
+}
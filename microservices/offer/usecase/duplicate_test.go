@@ -0,0 +1,100 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-park-mail-ru/2025_1_404/config"
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/domain"
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/mocks"
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/repository"
+	yaMock "github.com/go-park-mail-ru/2025_1_404/pkg/api/yandex/mocks"
+	redisMock "github.com/go-park-mail-ru/2025_1_404/pkg/database/redis/mocks"
+	s3Mock "github.com/go-park-mail-ru/2025_1_404/pkg/database/s3/mocks"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/logger"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/utils"
+	authService "github.com/go-park-mail-ru/2025_1_404/proto/auth/mocks"
+	paymentService "github.com/go-park-mail-ru/2025_1_404/proto/payment/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPublishOfferDuplicates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOfferRepository(ctrl)
+	offerUsecase := NewOfferUsecase(mockRepo, logger.NewStub(), s3Mock.NewMockS3Repo(ctrl), &config.Config{},
+		authService.NewMockAuthServiceClient(ctrl), paymentService.NewMockPaymentServiceClient(ctrl),
+		redisMock.NewMockRedisRepo(ctrl), yaMock.NewMockYandexRepo(ctrl))
+	ctx := context.WithValue(context.Background(), utils.RequestIDKey, "test-request-id")
+
+	address := "Москва, Тверская, 1"
+	purchaseType := 2
+	draft := repository.Offer{
+		ID: 7, SellerID: 5, StatusID: domain.OfferStatusDraft, Price: 100, Area: 40, Floor: 3,
+		TotalFloors: 9, Rooms: 1, Flat: 12, PropertyTypeID: 1, RenovationID: 1, OfferTypeID: 1,
+		PurchaseTypeID: &purchaseType, Address: &address, Latitude: "55.75", Longitude: "37.61",
+	}
+
+	t.Run("own duplicate rejected", func(t *testing.T) {
+		mockRepo.EXPECT().GetOfferByID(ctx, int64(7)).Return(draft, nil)
+		mockRepo.EXPECT().FindDuplicateOffers(ctx, gomock.Any()).
+			DoAndReturn(func(_ context.Context, c domain.DuplicateCandidate) ([]domain.OfferDuplicate, error) {
+				assert.Equal(t, 7, c.OfferID)
+				assert.Equal(t, 12, c.Flat)
+				assert.Equal(t, 55.75, *c.Latitude)
+				return []domain.OfferDuplicate{
+					{DuplicateOf: 3, SellerID: 5, Reason: domain.DuplicateByAttributes},
+					{DuplicateOf: 3, SellerID: 5, Reason: domain.DuplicateByImages},
+					{DuplicateOf: 4, SellerID: 8, Reason: domain.DuplicateByImages},
+				}, nil
+			})
+
+		err := offerUsecase.PublishOffer(ctx, 7, 5)
+
+		var dupErr *domain.DuplicateOfferError
+		assert.True(t, errors.As(err, &dupErr))
+		assert.Equal(t, []int{3}, dupErr.OfferIDs)
+	})
+
+	t.Run("foreign duplicate flagged", func(t *testing.T) {
+		foreign := []domain.OfferDuplicate{{DuplicateOf: 4, SellerID: 8, Reason: domain.DuplicateByImages}}
+		mockRepo.EXPECT().GetOfferByID(ctx, int64(7)).Return(draft, nil)
+		mockRepo.EXPECT().FindDuplicateOffers(ctx, gomock.Any()).Return(foreign, nil)
+		mockRepo.EXPECT().SaveOfferDuplicates(ctx, 7, foreign).Return(nil)
+		mockRepo.EXPECT().ChangeOfferStatus(ctx, 7, domain.OfferStatusDraft, domain.OfferStatusActive, 5, "").Return(true, nil)
+
+		assert.NoError(t, offerUsecase.PublishOffer(ctx, 7, 5))
+	})
+
+	t.Run("check failure does not block", func(t *testing.T) {
+		mockRepo.EXPECT().GetOfferByID(ctx, int64(7)).Return(draft, nil)
+		mockRepo.EXPECT().FindDuplicateOffers(ctx, gomock.Any()).Return(nil, errors.New("db error"))
+		mockRepo.EXPECT().ChangeOfferStatus(ctx, 7, domain.OfferStatusDraft, domain.OfferStatusActive, 5, "").Return(true, nil)
+
+		assert.NoError(t, offerUsecase.PublishOffer(ctx, 7, 5))
+	})
+}
+
+func TestIsModerator(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOfferRepository(ctrl)
+	offerUsecase := NewOfferUsecase(mockRepo, logger.NewStub(), s3Mock.NewMockS3Repo(ctrl), &config.Config{},
+		authService.NewMockAuthServiceClient(ctrl), paymentService.NewMockPaymentServiceClient(ctrl),
+		redisMock.NewMockRedisRepo(ctrl), yaMock.NewMockYandexRepo(ctrl))
+	ctx := context.Background()
+
+	mockRepo.EXPECT().GetUserRole(ctx, 1).Return(domain.RoleModerator, nil)
+	ok, err := offerUsecase.IsModerator(ctx, 1)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	mockRepo.EXPECT().GetUserRole(ctx, 2).Return("user", nil)
+	ok, err = offerUsecase.IsModerator(ctx, 2)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
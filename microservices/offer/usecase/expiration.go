@@ -0,0 +1,87 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/domain"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/logger"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/utils"
+)
+
+const (
+	expiringOfferMessage = "Срок публикации объявления скоро истечет"
+	expiredOfferMessage  = "Объявление снято: срок публикации истек"
+	expiredOfferReason   = "Срок публикации истек"
+)
+
+// ExpireOffers Снимает объявления с истекшим сроком публикации и предупреждает
+// продавцов тех, чей срок истекает в ближайшие NotifyBefore
+func (u *offerUsecase) ExpireOffers(ctx context.Context) error {
+	requestID := ctx.Value(utils.RequestIDKey)
+	expCfg := u.cfg.App.Expiration
+	now := time.Now()
+
+	var firstErr error
+	totalExpired := 0
+	for offerTypeID, lifetime := range expCfg.Lifetimes {
+		if lifetime <= 0 {
+			continue
+		}
+
+		expired, err := u.repo.ExpireOffers(ctx, offerTypeID, now.Add(-lifetime), expCfg.BatchSize, expiredOfferReason, expiredOfferMessage)
+		if err != nil {
+			u.logger.WithFields(logger.LoggerFields{"requestID": requestID, "offer_type_id": offerTypeID, "err": err.Error()}).Error("Offer usecase: expire offers failed")
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		totalExpired += expired
+
+		notified, err := u.repo.NotifyExpiringOffers(ctx, offerTypeID, now.Add(expCfg.NotifyBefore-lifetime), expCfg.BatchSize, expiringOfferMessage)
+		if err != nil {
+			u.logger.WithFields(logger.LoggerFields{"requestID": requestID, "offer_type_id": offerTypeID, "err": err.Error()}).Error("Offer usecase: notify expiring offers failed")
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+
+		u.logger.WithFields(logger.LoggerFields{"requestID": requestID, "offer_type_id": offerTypeID, "expired": expired, "notified": notified}).Info("Offer usecase: expiration processed")
+	}
+
+	// Снятые объявления пропадают из сведений о ЖК
+	if totalExpired > 0 {
+		u.invalidateAllZhkInfo(ctx)
+	}
+
+	return firstErr
+}
+
+func (u *offerUsecase) ExtendOffer(ctx context.Context, offerID int, userID int) error {
+	requestID := ctx.Value(utils.RequestIDKey)
+
+	offer, err := u.repo.GetOfferByID(ctx, int64(offerID))
+	if err != nil {
+		return fmt.Errorf("объявление не найдено")
+	}
+	if int(offer.SellerID) != userID {
+		return fmt.Errorf("нет доступа к продлению этого объявления")
+	}
+	if offer.StatusID != domain.OfferStatusActive {
+		return fmt.Errorf("продлить можно только активное объявление")
+	}
+
+	extended, err := u.repo.ExtendOffer(ctx, offerID, time.Now())
+	if err != nil {
+		u.logger.WithFields(logger.LoggerFields{"requestID": requestID, "offer_id": offerID, "err": err.Error()}).Error("Offer usecase: extend offer failed")
+		return fmt.Errorf("ошибка при продлении объявления")
+	}
+	if !extended {
+		return fmt.Errorf("продлить можно только активное объявление")
+	}
+
+	return nil
+}
@@ -0,0 +1,108 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-park-mail-ru/2025_1_404/config"
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/domain"
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/mocks"
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/repository"
+	yaMock "github.com/go-park-mail-ru/2025_1_404/pkg/api/yandex/mocks"
+	redisMock "github.com/go-park-mail-ru/2025_1_404/pkg/database/redis/mocks"
+	s3Mock "github.com/go-park-mail-ru/2025_1_404/pkg/database/s3/mocks"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/logger"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/utils"
+	authService "github.com/go-park-mail-ru/2025_1_404/proto/auth/mocks"
+	paymentService "github.com/go-park-mail-ru/2025_1_404/proto/payment/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpireOffers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOfferRepository(ctrl)
+	cfg := &config.Config{
+		App: config.AppConfig{Expiration: config.ExpirationConfig{
+			NotifyBefore: 72 * time.Hour,
+			BatchSize:    50,
+			Lifetimes:    map[int]time.Duration{2: 720 * time.Hour, 1: 0},
+		}},
+	}
+	offerUsecase := NewOfferUsecase(mockRepo, logger.NewStub(), s3Mock.NewMockS3Repo(ctrl), cfg,
+		authService.NewMockAuthServiceClient(ctrl), paymentService.NewMockPaymentServiceClient(ctrl),
+		redisMock.NewMockRedisRepo(ctrl), yaMock.NewMockYandexRepo(ctrl))
+	ctx := context.WithValue(context.Background(), utils.RequestIDKey, "test-request-id")
+
+	t.Run("ExpireOffers ok", func(t *testing.T) {
+		var expireBefore time.Time
+		mockRepo.EXPECT().ExpireOffers(ctx, 2, gomock.Any(), 50, expiredOfferReason, expiredOfferMessage).
+			DoAndReturn(func(_ context.Context, _ int, before time.Time, _ int, _, _ string) (int, error) {
+				expireBefore = before
+				return 1, nil
+			})
+		mockRepo.EXPECT().NotifyExpiringOffers(ctx, 2, gomock.Any(), 50, expiringOfferMessage).
+			DoAndReturn(func(_ context.Context, _ int, before time.Time, _ int, _ string) (int, error) {
+				assert.Equal(t, 72*time.Hour, before.Sub(expireBefore))
+				return 4, nil
+			})
+
+		err := offerUsecase.ExpireOffers(ctx)
+		assert.NoError(t, err)
+	})
+
+	t.Run("ExpireOffers repo error", func(t *testing.T) {
+		mockRepo.EXPECT().ExpireOffers(ctx, 2, gomock.Any(), 50, gomock.Any(), gomock.Any()).Return(0, errors.New("db error"))
+
+		err := offerUsecase.ExpireOffers(ctx)
+		assert.Error(t, err)
+	})
+
+	t.Run("messages fit notification", func(t *testing.T) {
+		assert.LessOrEqual(t, len([]rune(expiringOfferMessage)), maxNotificationMessageLength)
+		assert.LessOrEqual(t, len([]rune(expiredOfferMessage)), maxNotificationMessageLength)
+	})
+}
+
+func TestExtendOffer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOfferRepository(ctrl)
+	offerUsecase := NewOfferUsecase(mockRepo, logger.NewStub(), s3Mock.NewMockS3Repo(ctrl), &config.Config{},
+		authService.NewMockAuthServiceClient(ctrl), paymentService.NewMockPaymentServiceClient(ctrl),
+		redisMock.NewMockRedisRepo(ctrl), yaMock.NewMockYandexRepo(ctrl))
+	ctx := context.WithValue(context.Background(), utils.RequestIDKey, "test-request-id")
+
+	activeOffer := repository.Offer{ID: 1, SellerID: 5, StatusID: domain.OfferStatusActive}
+
+	t.Run("ExtendOffer ok", func(t *testing.T) {
+		mockRepo.EXPECT().GetOfferByID(ctx, int64(1)).Return(activeOffer, nil)
+		mockRepo.EXPECT().ExtendOffer(ctx, 1, gomock.Any()).Return(true, nil)
+
+		assert.NoError(t, offerUsecase.ExtendOffer(ctx, 1, 5))
+	})
+
+	t.Run("not owner", func(t *testing.T) {
+		mockRepo.EXPECT().GetOfferByID(ctx, int64(1)).Return(activeOffer, nil)
+
+		assert.EqualError(t, offerUsecase.ExtendOffer(ctx, 1, 6), "нет доступа к продлению этого объявления")
+	})
+
+	t.Run("not active", func(t *testing.T) {
+		mockRepo.EXPECT().GetOfferByID(ctx, int64(1)).Return(repository.Offer{ID: 1, SellerID: 5, StatusID: domain.OfferStatusCompleted}, nil)
+
+		assert.EqualError(t, offerUsecase.ExtendOffer(ctx, 1, 5), "продлить можно только активное объявление")
+	})
+
+	t.Run("status changed concurrently", func(t *testing.T) {
+		mockRepo.EXPECT().GetOfferByID(ctx, int64(1)).Return(activeOffer, nil)
+		mockRepo.EXPECT().ExtendOffer(ctx, 1, gomock.Any()).Return(false, nil)
+
+		assert.Error(t, offerUsecase.ExtendOffer(ctx, 1, 5))
+	})
+}
@@ -0,0 +1,207 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"html"
+	"sort"
+	"time"
+
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/domain"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/logger"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/utils"
+)
+
+// feedWatermarkOverlap Транзакция может закоммитить объявление с updated_at меньше уже учтенного,
+// поэтому досборка перечитывает изменения за последнюю минуту до отметки
+const feedWatermarkOverlap = time.Minute
+
+// GetOfferFeed Возвращает фид активных объявлений. Снимок хранится в Redis и дособирается
+// по updated_at: из базы читаются только объявления, измененные после прошлой сборки.
+// Фотографии, данные продавца и названия станций updated_at не меняют, поэтому снимок
+// старше SnapshotTTL собирается заново целиком
+func (u *offerUsecase) GetOfferFeed(ctx context.Context, filter domain.FeedFilter) (domain.OfferFeed, error) {
+	requestID := ctx.Value(utils.RequestIDKey)
+	feedCfg := u.cfg.App.Feed
+	key := filter.CacheKey()
+
+	snapshot, cached := u.loadFeedSnapshot(ctx, key)
+	if cached && time.Since(snapshot.BuiltAt) >= feedCfg.SnapshotTTL {
+		cached = false
+	}
+	if cached && time.Since(snapshot.CheckedAt) < feedCfg.RefreshInterval {
+		return feedFromSnapshot(snapshot), nil
+	}
+
+	var err error
+	if cached {
+		snapshot, err = u.refreshFeed(ctx, filter, snapshot)
+	} else {
+		snapshot, err = u.buildFeed(ctx, filter)
+	}
+	if err != nil {
+		u.logger.WithFields(logger.LoggerFields{"requestID": requestID, "key": key, "err": err.Error()}).Error("Offer usecase: build offer feed failed")
+		return domain.OfferFeed{}, err
+	}
+
+	// Досборка не продлевает жизнь снимка: ключ истекает к моменту обязательной полной сборки
+	data, err := snapshot.MarshalJSON()
+	if err == nil {
+		err = u.redisRepo.Set(ctx, key, string(data), feedCfg.SnapshotTTL-time.Since(snapshot.BuiltAt))
+	}
+	if err != nil {
+		// Фид отдается и без кеша, следующий запрос соберет его заново
+		u.logger.WithFields(logger.LoggerFields{"requestID": requestID, "key": key, "err": err.Error()}).Warn("Offer usecase: save offer feed failed")
+	}
+
+	return feedFromSnapshot(snapshot), nil
+}
+
+func (u *offerUsecase) loadFeedSnapshot(ctx context.Context, key string) (domain.FeedSnapshot, bool) {
+	requestID := ctx.Value(utils.RequestIDKey)
+
+	var snapshot domain.FeedSnapshot
+	data, err := u.redisRepo.Get(ctx, key)
+	if err != nil {
+		if !u.redisRepo.IsNotFound(err) {
+			u.logger.WithFields(logger.LoggerFields{"requestID": requestID, "key": key, "err": err.Error()}).Warn("Offer usecase: load offer feed failed")
+		}
+		return snapshot, false
+	}
+	if err := snapshot.UnmarshalJSON([]byte(data)); err != nil {
+		u.logger.WithFields(logger.LoggerFields{"requestID": requestID, "key": key, "err": err.Error()}).Warn("Offer usecase: broken offer feed snapshot")
+		return domain.FeedSnapshot{}, false
+	}
+
+	return snapshot, true
+}
+
+// buildFeed Собирает фид с нуля по всем активным объявлениям
+func (u *offerUsecase) buildFeed(ctx context.Context, filter domain.FeedFilter) (domain.FeedSnapshot, error) {
+	offers, err := u.repo.GetFeedOffers(ctx, filter.SellerIDs, time.Time{}, true)
+	if err != nil {
+		return domain.FeedSnapshot{}, err
+	}
+
+	byID := make(map[int]domain.FeedOffer, len(offers))
+	var watermark time.Time
+	for _, offer := range offers {
+		byID[offer.ID] = u.prepareFeedOffer(offer)
+		if offer.UpdatedAt.After(watermark) {
+			watermark = offer.UpdatedAt
+		}
+	}
+
+	snapshot, err := finishFeedSnapshot(domain.FeedSnapshot{}, byID, watermark)
+	if err != nil {
+		return snapshot, err
+	}
+	snapshot.BuiltAt = snapshot.CheckedAt
+	return snapshot, nil
+}
+
+// refreshFeed Применяет к снимку изменения после его отметки. Снятые с публикации объявления
+// приходят с новым updated_at, удаленные отсеиваются по списку активных id
+func (u *offerUsecase) refreshFeed(ctx context.Context, filter domain.FeedFilter, snapshot domain.FeedSnapshot) (domain.FeedSnapshot, error) {
+	changed, err := u.repo.GetFeedOffers(ctx, filter.SellerIDs, snapshot.Watermark.Add(-feedWatermarkOverlap), false)
+	if err != nil {
+		return snapshot, err
+	}
+	activeIDs, err := u.repo.GetActiveOfferIDs(ctx, filter.SellerIDs)
+	if err != nil {
+		return snapshot, err
+	}
+
+	byID := make(map[int]domain.FeedOffer, len(snapshot.Offers)+len(changed))
+	for _, offer := range snapshot.Offers {
+		byID[offer.ID] = offer
+	}
+
+	watermark := snapshot.Watermark
+	for _, offer := range changed {
+		if offer.StatusID == domain.OfferStatusActive {
+			byID[offer.ID] = u.prepareFeedOffer(offer)
+		} else {
+			delete(byID, offer.ID)
+		}
+		if offer.UpdatedAt.After(watermark) {
+			watermark = offer.UpdatedAt
+		}
+	}
+
+	active := make(map[int]struct{}, len(activeIDs))
+	for _, id := range activeIDs {
+		active[id] = struct{}{}
+	}
+	for id := range byID {
+		if _, ok := active[id]; !ok {
+			delete(byID, id)
+		}
+	}
+
+	return finishFeedSnapshot(snapshot, byID, watermark)
+}
+
+// finishFeedSnapshot Упорядочивает объявления и пересчитывает ETag. Если содержимое не изменилось,
+// время генерации остается прежним
+func finishFeedSnapshot(prev domain.FeedSnapshot, byID map[int]domain.FeedOffer, watermark time.Time) (domain.FeedSnapshot, error) {
+	offers := make([]domain.FeedOffer, 0, len(byID))
+	for _, offer := range byID {
+		offers = append(offers, offer)
+	}
+	sort.Slice(offers, func(i, j int) bool { return offers[i].ID < offers[j].ID })
+
+	data, err := domain.OfferFeed{Offers: offers}.MarshalJSON()
+	if err != nil {
+		return prev, err
+	}
+	sum := sha256.Sum256(data)
+	etag := hex.EncodeToString(sum[:16])
+
+	now := time.Now()
+	generatedAt := prev.GeneratedAt
+	if etag != prev.ETag || generatedAt.IsZero() {
+		generatedAt = now
+	}
+
+	return domain.FeedSnapshot{
+		Watermark:   watermark,
+		BuiltAt:     prev.BuiltAt,
+		CheckedAt:   now,
+		GeneratedAt: generatedAt,
+		ETag:        etag,
+		Offers:      offers,
+	}, nil
+}
+
+// prepareFeedOffer Превращает имена объектов и id в абсолютные ссылки для площадок. Текст
+// хранится экранированным для нашего фронтенда, площадкам он уходит как есть
+func (u *offerUsecase) prepareFeedOffer(offer domain.FeedOffer) domain.FeedOffer {
+	if offer.Description != nil {
+		description := html.UnescapeString(*offer.Description)
+		offer.Description = &description
+	}
+	if offer.Address != nil {
+		address := html.UnescapeString(*offer.Address)
+		offer.Address = &address
+	}
+	offer.URL = u.cfg.App.BaseFrontendDir + fmt.Sprintf(offerRedirectURI, offer.ID)
+
+	images := make([]string, 0, len(offer.Images))
+	for _, name := range offer.Images {
+		images = append(images, u.cfg.Minio.Path+u.cfg.Minio.OffersBucket+name)
+	}
+	offer.Images = images
+
+	return offer
+}
+
+func feedFromSnapshot(snapshot domain.FeedSnapshot) domain.OfferFeed {
+	return domain.OfferFeed{
+		GeneratedAt: snapshot.GeneratedAt,
+		ETag:        snapshot.ETag,
+		Offers:      snapshot.Offers,
+	}
+}
@@ -0,0 +1,189 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-park-mail-ru/2025_1_404/config"
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/domain"
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/mocks"
+	yaMock "github.com/go-park-mail-ru/2025_1_404/pkg/api/yandex/mocks"
+	redisMock "github.com/go-park-mail-ru/2025_1_404/pkg/database/redis/mocks"
+	s3Mock "github.com/go-park-mail-ru/2025_1_404/pkg/database/s3/mocks"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/logger"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/utils"
+	authService "github.com/go-park-mail-ru/2025_1_404/proto/auth/mocks"
+	paymentService "github.com/go-park-mail-ru/2025_1_404/proto/payment/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetOfferFeed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOfferRepository(ctrl)
+	mockRedis := redisMock.NewMockRedisRepo(ctrl)
+	cfg := &config.Config{
+		App: config.AppConfig{
+			BaseFrontendDir: "https://kvartirum.ru",
+			Feed:            config.FeedConfig{RefreshInterval: 5 * time.Minute, SnapshotTTL: 24 * time.Hour},
+		},
+		Minio: config.MinioConfig{Path: "https://s3.kvartirum.ru", OffersBucket: "/offers/"},
+	}
+	offerUsecase := NewOfferUsecase(mockRepo, logger.NewStub(), s3Mock.NewMockS3Repo(ctrl), cfg,
+		authService.NewMockAuthServiceClient(ctrl), paymentService.NewMockPaymentServiceClient(ctrl),
+		mockRedis, yaMock.NewMockYandexRepo(ctrl))
+	ctx := context.WithValue(context.Background(), utils.RequestIDKey, "test-request-id")
+
+	filter := domain.FeedFilter{SellerIDs: []int{7}}
+	key := "offer_feed:7"
+	updated := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	description := "Рядом &quot;Ашан&quot;"
+
+	var stored string
+	saveSnapshot := func(_ context.Context, _ string, value interface{}, ttl time.Duration) error {
+		assert.True(t, ttl > 23*time.Hour && ttl <= 24*time.Hour, ttl)
+		stored = value.(string)
+		return nil
+	}
+
+	t.Run("full build", func(t *testing.T) {
+		notFound := errors.New("redis: nil")
+		mockRedis.EXPECT().Get(ctx, key).Return("", notFound)
+		mockRedis.EXPECT().IsNotFound(notFound).Return(true)
+		mockRepo.EXPECT().GetFeedOffers(ctx, []int{7}, time.Time{}, true).Return([]domain.FeedOffer{
+			{ID: 2, StatusID: domain.OfferStatusActive, Price: 100, Images: []string{"b.jpg"}, UpdatedAt: updated},
+			{ID: 1, StatusID: domain.OfferStatusActive, Price: 200, Description: &description, UpdatedAt: updated.Add(-time.Hour)},
+		}, nil)
+		mockRedis.EXPECT().Set(ctx, key, gomock.Any(), gomock.Any()).DoAndReturn(saveSnapshot)
+
+		feed, err := offerUsecase.GetOfferFeed(ctx, filter)
+		require.NoError(t, err)
+		require.Len(t, feed.Offers, 2)
+		assert.Equal(t, 1, feed.Offers[0].ID)
+		assert.Equal(t, "Рядом \"Ашан\"", *feed.Offers[0].Description)
+		assert.Equal(t, "https://kvartirum.ru/offer/2", feed.Offers[1].URL)
+		assert.Equal(t, []string{"https://s3.kvartirum.ru/offers/b.jpg"}, feed.Offers[1].Images)
+		assert.NotEmpty(t, feed.ETag)
+	})
+
+	var snapshot domain.FeedSnapshot
+	require.NoError(t, snapshot.UnmarshalJSON([]byte(stored)))
+	assert.True(t, snapshot.Watermark.Equal(updated))
+
+	t.Run("fresh snapshot", func(t *testing.T) {
+		mockRedis.EXPECT().Get(ctx, key).Return(stored, nil)
+
+		feed, err := offerUsecase.GetOfferFeed(ctx, filter)
+		require.NoError(t, err)
+		assert.Equal(t, snapshot.ETag, feed.ETag)
+	})
+
+	t.Run("incremental refresh", func(t *testing.T) {
+		stale := snapshot
+		stale.CheckedAt = time.Now().Add(-time.Hour)
+		data, err := stale.MarshalJSON()
+		require.NoError(t, err)
+
+		mockRedis.EXPECT().Get(ctx, key).Return(string(data), nil)
+		mockRepo.EXPECT().GetFeedOffers(ctx, []int{7}, updated.Add(-feedWatermarkOverlap), false).Return([]domain.FeedOffer{
+			// Снято с публикации
+			{ID: 2, StatusID: domain.OfferStatusDraft, UpdatedAt: updated.Add(time.Minute)},
+			// Новое объявление
+			{ID: 5, StatusID: domain.OfferStatusActive, Price: 300, UpdatedAt: updated.Add(2 * time.Minute)},
+		}, nil)
+		// Объявление 1 удалено, его нет среди активных
+		mockRepo.EXPECT().GetActiveOfferIDs(ctx, []int{7}).Return([]int{5}, nil)
+		mockRedis.EXPECT().Set(ctx, key, gomock.Any(), gomock.Any()).DoAndReturn(saveSnapshot)
+
+		feed, err := offerUsecase.GetOfferFeed(ctx, filter)
+		require.NoError(t, err)
+		require.Len(t, feed.Offers, 1)
+		assert.Equal(t, 5, feed.Offers[0].ID)
+		assert.NotEqual(t, snapshot.ETag, feed.ETag)
+
+		var refreshed domain.FeedSnapshot
+		require.NoError(t, refreshed.UnmarshalJSON([]byte(stored)))
+		assert.True(t, refreshed.Watermark.Equal(updated.Add(2*time.Minute)))
+	})
+
+	t.Run("unchanged content keeps etag", func(t *testing.T) {
+		var current domain.FeedSnapshot
+		require.NoError(t, current.UnmarshalJSON([]byte(stored)))
+		current.CheckedAt = time.Now().Add(-time.Hour)
+		data, err := current.MarshalJSON()
+		require.NoError(t, err)
+
+		mockRedis.EXPECT().Get(ctx, key).Return(string(data), nil)
+		mockRepo.EXPECT().GetFeedOffers(ctx, []int{7}, gomock.Any(), false).Return(nil, nil)
+		mockRepo.EXPECT().GetActiveOfferIDs(ctx, []int{7}).Return([]int{5}, nil)
+		mockRedis.EXPECT().Set(ctx, key, gomock.Any(), gomock.Any()).DoAndReturn(saveSnapshot)
+
+		feed, err := offerUsecase.GetOfferFeed(ctx, filter)
+		require.NoError(t, err)
+		assert.Equal(t, current.ETag, feed.ETag)
+		assert.True(t, current.GeneratedAt.Equal(feed.GeneratedAt))
+	})
+
+	t.Run("old snapshot is rebuilt", func(t *testing.T) {
+		var current domain.FeedSnapshot
+		require.NoError(t, current.UnmarshalJSON([]byte(stored)))
+		current.BuiltAt = time.Now().Add(-25 * time.Hour)
+		current.CheckedAt = time.Now().Add(-time.Hour)
+		data, err := current.MarshalJSON()
+		require.NoError(t, err)
+
+		mockRedis.EXPECT().Get(ctx, key).Return(string(data), nil)
+		mockRepo.EXPECT().GetFeedOffers(ctx, []int{7}, time.Time{}, true).Return([]domain.FeedOffer{
+			{ID: 5, StatusID: domain.OfferStatusActive, Price: 300, Images: []string{"new.jpg"}, UpdatedAt: updated.Add(2 * time.Minute)},
+		}, nil)
+		mockRedis.EXPECT().Set(ctx, key, gomock.Any(), gomock.Any()).DoAndReturn(saveSnapshot)
+
+		feed, err := offerUsecase.GetOfferFeed(ctx, filter)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"https://s3.kvartirum.ru/offers/new.jpg"}, feed.Offers[0].Images)
+
+		var rebuilt domain.FeedSnapshot
+		require.NoError(t, rebuilt.UnmarshalJSON([]byte(stored)))
+		assert.WithinDuration(t, time.Now(), rebuilt.BuiltAt, time.Minute)
+	})
+
+	t.Run("refresh keeps build time", func(t *testing.T) {
+		var current domain.FeedSnapshot
+		require.NoError(t, current.UnmarshalJSON([]byte(stored)))
+		current.BuiltAt = time.Now().Add(-20 * time.Hour)
+		current.CheckedAt = time.Now().Add(-time.Hour)
+		data, err := current.MarshalJSON()
+		require.NoError(t, err)
+
+		mockRedis.EXPECT().Get(ctx, key).Return(string(data), nil)
+		mockRepo.EXPECT().GetFeedOffers(ctx, []int{7}, gomock.Any(), false).Return(nil, nil)
+		mockRepo.EXPECT().GetActiveOfferIDs(ctx, []int{7}).Return([]int{5}, nil)
+		mockRedis.EXPECT().Set(ctx, key, gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, _ string, _ interface{}, ttl time.Duration) error {
+			assert.True(t, ttl > 3*time.Hour && ttl <= 4*time.Hour, ttl)
+			return nil
+		})
+
+		_, err = offerUsecase.GetOfferFeed(ctx, filter)
+		require.NoError(t, err)
+	})
+
+	t.Run("db error", func(t *testing.T) {
+		notFound := errors.New("redis: nil")
+		mockRedis.EXPECT().Get(ctx, key).Return("", notFound)
+		mockRedis.EXPECT().IsNotFound(notFound).Return(true)
+		mockRepo.EXPECT().GetFeedOffers(ctx, []int{7}, time.Time{}, true).Return(nil, errors.New("db error"))
+
+		_, err := offerUsecase.GetOfferFeed(ctx, filter)
+		assert.Error(t, err)
+	})
+}
+
+func TestFeedFilterCacheKey(t *testing.T) {
+	assert.Equal(t, "offer_feed:all", domain.FeedFilter{}.CacheKey())
+	assert.Equal(t, "offer_feed:3,7", domain.FeedFilter{SellerIDs: []int{7, 3, 7}}.CacheKey())
+}
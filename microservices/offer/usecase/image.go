@@ -0,0 +1,205 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/domain"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/content"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/database/s3"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/logger"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/utils"
+	"github.com/google/uuid"
+)
+
+// imageVariant Закодированная уменьшенная копия изображения
+type imageVariant struct {
+	width int
+	data  []byte
+}
+
+// makeImageVariants Строит копии для настроенных ширин, меньших ширины оригинала.
+// Копии считаются от большей к меньшей, каждая из предыдущей, чтобы не обходить оригинал несколько раз
+func (u *offerUsecase) makeImageVariants(ctx context.Context, img image.Image) []imageVariant {
+	requestID := ctx.Value(utils.RequestIDKey)
+	imagesCfg := u.cfg.App.Images
+
+	widths := append([]int(nil), imagesCfg.VariantWidths...)
+	sort.Sort(sort.Reverse(sort.IntSlice(widths)))
+
+	variants := make([]imageVariant, 0, len(widths))
+	source := img
+	for _, width := range widths {
+		if width <= 0 || width >= source.Bounds().Dx() {
+			continue
+		}
+
+		source = content.ResizeToWidth(source, width)
+		data, err := content.EncodeJPEG(source, imagesCfg.Quality)
+		if err != nil {
+			u.logger.WithFields(logger.LoggerFields{"requestID": requestID, "width": width, "err": err.Error()}).Warn("Offer usecase: image variant encode failed")
+			continue
+		}
+		variants = append(variants, imageVariant{width: width, data: data})
+	}
+
+	return variants
+}
+
+// putImageVariants Загружает копии рядом с оригиналом и возвращает ширины сохраненных
+func (u *offerUsecase) putImageVariants(ctx context.Context, bucket string, objectName string, variants []imageVariant) []int {
+	requestID := ctx.Value(utils.RequestIDKey)
+
+	widths := make([]int, 0, len(variants))
+	for _, v := range variants {
+		_, err := u.s3Repo.Put(ctx, s3.Upload{
+			Bucket:      bucket,
+			File:        bytes.NewReader(v.data),
+			Size:        int64(len(v.data)),
+			ContentType: "image/jpeg",
+			ObjectName:  domain.VariantObjectName(objectName, v.width),
+		})
+		if err != nil {
+			u.logger.WithFields(logger.LoggerFields{"requestID": requestID, "width": v.width, "err": err.Error()}).Warn("Offer usecase: image variant upload failed")
+			continue
+		}
+		widths = append(widths, v.width)
+	}
+
+	return widths
+}
+
+// ReorderOfferImages Меняет порядок фотографий в галерее. Нужно перечислить все фотографии объявления
+func (u *offerUsecase) ReorderOfferImages(ctx context.Context, offerID int, userID int, imageIDs []int64) error {
+	requestID := ctx.Value(utils.RequestIDKey)
+
+	if len(imageIDs) == 0 {
+		return fmt.Errorf("список изображений пуст")
+	}
+	seen := make(map[int64]struct{}, len(imageIDs))
+	for _, id := range imageIDs {
+		if _, ok := seen[id]; ok {
+			return fmt.Errorf("изображение %d указано несколько раз", id)
+		}
+		seen[id] = struct{}{}
+	}
+
+	if err := u.CheckAccessToOffer(ctx, offerID, userID); err != nil {
+		return err
+	}
+
+	reordered, err := u.repo.ReorderOfferImages(ctx, offerID, imageIDs)
+	if err != nil {
+		u.logger.WithFields(logger.LoggerFields{"requestID": requestID, "offer_id": offerID, "err": err.Error()}).Error("Offer usecase: reorder images failed")
+		return fmt.Errorf("ошибка при изменении порядка изображений")
+	}
+	if !reordered {
+		return fmt.Errorf("список изображений не совпадает с изображениями объявления")
+	}
+	u.invalidateOffer(ctx, offerID)
+
+	return nil
+}
+
+// SetOfferCover Делает фотографию обложкой, она показывается первой в ленте и в галерее
+func (u *offerUsecase) SetOfferCover(ctx context.Context, offerID int, userID int, imageID int64) error {
+	requestID := ctx.Value(utils.RequestIDKey)
+
+	if err := u.CheckAccessToOffer(ctx, offerID, userID); err != nil {
+		return err
+	}
+
+	updated, err := u.repo.SetOfferCover(ctx, offerID, imageID)
+	if err != nil {
+		u.logger.WithFields(logger.LoggerFields{"requestID": requestID, "offer_id": offerID, "image_id": imageID, "err": err.Error()}).Error("Offer usecase: set cover failed")
+		return fmt.Errorf("ошибка при выборе обложки")
+	}
+	if !updated {
+		return fmt.Errorf("изображение не найдено")
+	}
+	u.invalidateOffer(ctx, offerID)
+
+	return nil
+}
+
+// CreateImageUploadURL Выдает подписанную форму для загрузки фотографии напрямую в хранилище.
+// Политика формы фиксирует имя объекта и не пропускает файлы больше content.MAX_SIZE и не изображения.
+// После загрузки клиент подтверждает ее через ConfirmImageUpload
+func (u *offerUsecase) CreateImageUploadURL(ctx context.Context, offerID int, userID int) (domain.ImageUploadURL, error) {
+	requestID := ctx.Value(utils.RequestIDKey)
+
+	if err := u.CheckAccessToOffer(ctx, offerID, userID); err != nil {
+		return domain.ImageUploadURL{}, err
+	}
+
+	expiry := u.cfg.App.Images.UploadURLExpiry
+	objectName := domain.PendingImageObject(offerID, uuid.New().String())
+	url, fields, err := u.s3Repo.PresignedPost(ctx, s3.PostPolicy{
+		Bucket:            "offers",
+		ObjectName:        objectName,
+		MaxSize:           content.MAX_SIZE,
+		ContentTypePrefix: "image/",
+		Expires:           expiry,
+	})
+	if err != nil {
+		u.logger.WithFields(logger.LoggerFields{"requestID": requestID, "offer_id": offerID, "err": err.Error()}).Error("Offer usecase: presign upload failed")
+		return domain.ImageUploadURL{}, fmt.Errorf("не удалось создать ссылку для загрузки")
+	}
+
+	return domain.ImageUploadURL{URL: url, Fields: fields, ObjectName: objectName, ExpiresAt: time.Now().Add(expiry)}, nil
+}
+
+// ConfirmImageUpload Проверяет загруженный по ссылке объект и сохраняет его как фотографию объявления.
+// Файл перекодируется так же, как при обычной загрузке, исходный объект удаляется
+func (u *offerUsecase) ConfirmImageUpload(ctx context.Context, offerID int, userID int, objectName string) (int64, error) {
+	requestID := ctx.Value(utils.RequestIDKey)
+
+	if !domain.IsPendingImageObject(offerID, objectName) {
+		return 0, fmt.Errorf("некорректное имя объекта")
+	}
+	if err := u.CheckAccessToOffer(ctx, offerID, userID); err != nil {
+		return 0, err
+	}
+
+	object, err := u.s3Repo.Get(ctx, "offers", objectName)
+	if err != nil {
+		return 0, fmt.Errorf("загруженный файл не найден")
+	}
+	data, err := io.ReadAll(io.LimitReader(object, content.MAX_SIZE+1))
+	object.Close()
+	if err != nil {
+		return 0, fmt.Errorf("загруженный файл не найден")
+	}
+
+	sanitized, err := content.PrepareImage(data)
+	if err != nil {
+		u.removePendingImage(ctx, objectName)
+		return 0, err
+	}
+
+	imageID, err := u.SaveOfferImage(ctx, offerID, s3.Upload{
+		Bucket:      "offers",
+		Filename:    content.SanitizedFilename("image"),
+		Size:        int64(len(sanitized)),
+		File:        bytes.NewReader(sanitized),
+		ContentType: content.SanitizedContentType,
+	})
+	if err != nil {
+		u.logger.WithFields(logger.LoggerFields{"requestID": requestID, "offer_id": offerID, "object": objectName, "err": err.Error()}).Error("Offer usecase: confirm upload failed")
+		return 0, fmt.Errorf("ошибка при сохранении изображения")
+	}
+	u.removePendingImage(ctx, objectName)
+
+	return imageID, nil
+}
+
+func (u *offerUsecase) removePendingImage(ctx context.Context, objectName string) {
+	if err := u.s3Repo.Remove(ctx, "offers", objectName); err != nil {
+		u.logger.WithFields(logger.LoggerFields{"requestID": ctx.Value(utils.RequestIDKey), "object": objectName, "err": err.Error()}).Warn("Offer usecase: pending image remove failed")
+	}
+}
@@ -0,0 +1,259 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-park-mail-ru/2025_1_404/config"
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/domain"
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/mocks"
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/repository"
+	yaMock "github.com/go-park-mail-ru/2025_1_404/pkg/api/yandex/mocks"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/content"
+	redisMock "github.com/go-park-mail-ru/2025_1_404/pkg/database/redis/mocks"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/database/s3"
+	s3Mock "github.com/go-park-mail-ru/2025_1_404/pkg/database/s3/mocks"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/logger"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/utils"
+	authService "github.com/go-park-mail-ru/2025_1_404/proto/auth/mocks"
+	paymentService "github.com/go-park-mail-ru/2025_1_404/proto/payment/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaveOfferImageVariants(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOfferRepository(ctrl)
+	mockS3 := s3Mock.NewMockS3Repo(ctrl)
+	cfg := &config.Config{
+		App: config.AppConfig{Images: config.ImagesConfig{VariantWidths: []int{320, 1600, 800}, Quality: 80}},
+	}
+	offerUsecase := NewOfferUsecase(mockRepo, logger.NewStub(), mockS3, cfg,
+		authService.NewMockAuthServiceClient(ctrl), paymentService.NewMockPaymentServiceClient(ctrl),
+		redisMock.NewMockRedisRepo(ctrl), yaMock.NewMockYandexRepo(ctrl))
+	ctx := context.WithValue(context.Background(), utils.RequestIDKey, "test-request-id")
+
+	src := image.NewRGBA(image.Rect(0, 0, 1000, 500))
+	for x := 0; x < 1000; x++ {
+		src.Set(x, 0, color.RGBA{G: uint8(x), A: 255})
+	}
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, src))
+	original := buf.Bytes()
+
+	upload := s3.Upload{Bucket: "offers", Filename: "flat.png", File: bytes.NewReader(original), ContentType: "image/png"}
+
+	mockS3.EXPECT().Put(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, u s3.Upload) (string, error) {
+		data, err := io.ReadAll(u.File)
+		require.NoError(t, err)
+		assert.Equal(t, original, data)
+		assert.Empty(t, u.ObjectName)
+		return "uuid-flat.png", nil
+	})
+	// 1600 шире оригинала и пропускается, копии загружаются от большей к меньшей
+	for _, width := range []int{800, 320} {
+		width := width
+		mockS3.EXPECT().Put(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, u s3.Upload) (string, error) {
+			assert.Equal(t, domain.VariantObjectName("uuid-flat.png", width), u.ObjectName)
+			assert.Equal(t, "image/jpeg", u.ContentType)
+			data, err := io.ReadAll(u.File)
+			require.NoError(t, err)
+			imgCfg, err := jpeg.DecodeConfig(bytes.NewReader(data))
+			require.NoError(t, err)
+			assert.Equal(t, width, imgCfg.Width)
+			return u.ObjectName, nil
+		})
+	}
+	mockRepo.EXPECT().CreateImageAndBindToOffer(ctx, 1, "uuid-flat.png", gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ int, _ string, meta domain.ImageMeta) (int64, error) {
+			assert.NotNil(t, meta.PHash)
+			assert.Equal(t, []int{800, 320}, meta.Variants)
+			return 5, nil
+		})
+
+	id, err := offerUsecase.SaveOfferImage(ctx, 1, upload)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), id)
+}
+
+func TestReorderOfferImages(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOfferRepository(ctrl)
+	offerUsecase := NewOfferUsecase(mockRepo, logger.NewStub(), s3Mock.NewMockS3Repo(ctrl), &config.Config{},
+		authService.NewMockAuthServiceClient(ctrl), paymentService.NewMockPaymentServiceClient(ctrl),
+		redisMock.NewMockRedisRepo(ctrl), yaMock.NewMockYandexRepo(ctrl))
+	ctx := context.WithValue(context.Background(), utils.RequestIDKey, "test-request-id")
+
+	offer := repository.Offer{ID: 1, SellerID: 5}
+	ids := []int64{3, 1, 2}
+
+	t.Run("ReorderOfferImages ok", func(t *testing.T) {
+		mockRepo.EXPECT().GetOfferByID(ctx, int64(1)).Return(offer, nil)
+		mockRepo.EXPECT().ReorderOfferImages(ctx, 1, ids).Return(true, nil)
+
+		assert.NoError(t, offerUsecase.ReorderOfferImages(ctx, 1, 5, ids))
+	})
+
+	t.Run("empty list", func(t *testing.T) {
+		assert.Error(t, offerUsecase.ReorderOfferImages(ctx, 1, 5, nil))
+	})
+
+	t.Run("duplicate ids", func(t *testing.T) {
+		assert.Error(t, offerUsecase.ReorderOfferImages(ctx, 1, 5, []int64{1, 2, 1}))
+	})
+
+	t.Run("not owner", func(t *testing.T) {
+		mockRepo.EXPECT().GetOfferByID(ctx, int64(1)).Return(offer, nil)
+
+		assert.Error(t, offerUsecase.ReorderOfferImages(ctx, 1, 6, ids))
+	})
+
+	t.Run("list does not match gallery", func(t *testing.T) {
+		mockRepo.EXPECT().GetOfferByID(ctx, int64(1)).Return(offer, nil)
+		mockRepo.EXPECT().ReorderOfferImages(ctx, 1, ids).Return(false, nil)
+
+		assert.Error(t, offerUsecase.ReorderOfferImages(ctx, 1, 5, ids))
+	})
+
+	t.Run("repo error", func(t *testing.T) {
+		mockRepo.EXPECT().GetOfferByID(ctx, int64(1)).Return(offer, nil)
+		mockRepo.EXPECT().ReorderOfferImages(ctx, 1, ids).Return(false, errors.New("db error"))
+
+		assert.Error(t, offerUsecase.ReorderOfferImages(ctx, 1, 5, ids))
+	})
+}
+
+func TestSetOfferCover(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOfferRepository(ctrl)
+	offerUsecase := NewOfferUsecase(mockRepo, logger.NewStub(), s3Mock.NewMockS3Repo(ctrl), &config.Config{},
+		authService.NewMockAuthServiceClient(ctrl), paymentService.NewMockPaymentServiceClient(ctrl),
+		redisMock.NewMockRedisRepo(ctrl), yaMock.NewMockYandexRepo(ctrl))
+	ctx := context.WithValue(context.Background(), utils.RequestIDKey, "test-request-id")
+
+	offer := repository.Offer{ID: 1, SellerID: 5}
+
+	t.Run("SetOfferCover ok", func(t *testing.T) {
+		mockRepo.EXPECT().GetOfferByID(ctx, int64(1)).Return(offer, nil)
+		mockRepo.EXPECT().SetOfferCover(ctx, 1, int64(2)).Return(true, nil)
+
+		assert.NoError(t, offerUsecase.SetOfferCover(ctx, 1, 5, 2))
+	})
+
+	t.Run("image of another offer", func(t *testing.T) {
+		mockRepo.EXPECT().GetOfferByID(ctx, int64(1)).Return(offer, nil)
+		mockRepo.EXPECT().SetOfferCover(ctx, 1, int64(9)).Return(false, nil)
+
+		assert.Error(t, offerUsecase.SetOfferCover(ctx, 1, 5, 9))
+	})
+
+	t.Run("offer not found", func(t *testing.T) {
+		mockRepo.EXPECT().GetOfferByID(ctx, int64(1)).Return(repository.Offer{}, errors.New("no rows"))
+
+		assert.Error(t, offerUsecase.SetOfferCover(ctx, 1, 5, 2))
+	})
+}
+
+func TestImageUploadURL(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOfferRepository(ctrl)
+	mockS3 := s3Mock.NewMockS3Repo(ctrl)
+	cfg := &config.Config{
+		App: config.AppConfig{Images: config.ImagesConfig{UploadURLExpiry: 15 * time.Minute}},
+	}
+	offerUsecase := NewOfferUsecase(mockRepo, logger.NewStub(), mockS3, cfg,
+		authService.NewMockAuthServiceClient(ctrl), paymentService.NewMockPaymentServiceClient(ctrl),
+		redisMock.NewMockRedisRepo(ctrl), yaMock.NewMockYandexRepo(ctrl))
+	ctx := context.WithValue(context.Background(), utils.RequestIDKey, "test-request-id")
+
+	offer := repository.Offer{ID: 1, SellerID: 5}
+
+	t.Run("CreateImageUploadURL ok", func(t *testing.T) {
+		mockRepo.EXPECT().GetOfferByID(ctx, int64(1)).Return(offer, nil)
+		mockS3.EXPECT().PresignedPost(ctx, gomock.Any()).
+			DoAndReturn(func(_ context.Context, policy s3.PostPolicy) (string, map[string]string, error) {
+				assert.Equal(t, "offers", policy.Bucket)
+				assert.True(t, domain.IsPendingImageObject(1, policy.ObjectName))
+				assert.Equal(t, int64(content.MAX_SIZE), policy.MaxSize)
+				assert.Equal(t, "image/", policy.ContentTypePrefix)
+				assert.Equal(t, 15*time.Minute, policy.Expires)
+				return "http://minio/offers", map[string]string{"key": policy.ObjectName, "policy": "p"}, nil
+			})
+
+		uploadURL, err := offerUsecase.CreateImageUploadURL(ctx, 1, 5)
+		require.NoError(t, err)
+		assert.Equal(t, "http://minio/offers", uploadURL.URL)
+		assert.Equal(t, uploadURL.ObjectName, uploadURL.Fields["key"])
+		assert.WithinDuration(t, time.Now().Add(15*time.Minute), uploadURL.ExpiresAt, time.Minute)
+	})
+
+	t.Run("CreateImageUploadURL not owner", func(t *testing.T) {
+		mockRepo.EXPECT().GetOfferByID(ctx, int64(1)).Return(offer, nil)
+
+		_, err := offerUsecase.CreateImageUploadURL(ctx, 1, 6)
+		assert.Error(t, err)
+	})
+
+	src := image.NewRGBA(image.Rect(0, 0, 200, 200))
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, src))
+	validImage := buf.Bytes()
+
+	t.Run("ConfirmImageUpload ok", func(t *testing.T) {
+		mockRepo.EXPECT().GetOfferByID(ctx, int64(1)).Return(offer, nil)
+		mockS3.EXPECT().Get(ctx, "offers", "pending/1/abc").Return(io.NopCloser(bytes.NewReader(validImage)), nil)
+		mockS3.EXPECT().Put(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, u s3.Upload) (string, error) {
+			assert.Equal(t, "image/jpeg", u.ContentType)
+			return "uuid-image.jpg", nil
+		})
+		mockRepo.EXPECT().CreateImageAndBindToOffer(ctx, 1, "uuid-image.jpg", gomock.Any()).Return(int64(9), nil)
+		mockS3.EXPECT().Remove(ctx, "offers", "pending/1/abc").Return(nil)
+
+		imageID, err := offerUsecase.ConfirmImageUpload(ctx, 1, 5, "pending/1/abc")
+		require.NoError(t, err)
+		assert.Equal(t, int64(9), imageID)
+	})
+
+	t.Run("ConfirmImageUpload foreign object", func(t *testing.T) {
+		_, err := offerUsecase.ConfirmImageUpload(ctx, 1, 5, "pending/2/abc")
+		assert.Error(t, err)
+
+		_, err = offerUsecase.ConfirmImageUpload(ctx, 1, 5, "pending/1/../2/abc")
+		assert.Error(t, err)
+	})
+
+	t.Run("ConfirmImageUpload invalid file is removed", func(t *testing.T) {
+		mockRepo.EXPECT().GetOfferByID(ctx, int64(1)).Return(offer, nil)
+		mockS3.EXPECT().Get(ctx, "offers", "pending/1/abc").Return(io.NopCloser(strings.NewReader("not an image")), nil)
+		mockS3.EXPECT().Remove(ctx, "offers", "pending/1/abc").Return(nil)
+
+		_, err := offerUsecase.ConfirmImageUpload(ctx, 1, 5, "pending/1/abc")
+		assert.Error(t, err)
+	})
+
+	t.Run("ConfirmImageUpload missing object", func(t *testing.T) {
+		mockRepo.EXPECT().GetOfferByID(ctx, int64(1)).Return(offer, nil)
+		mockS3.EXPECT().Get(ctx, "offers", "pending/1/abc").Return(nil, errors.New("not found"))
+
+		_, err := offerUsecase.ConfirmImageUpload(ctx, 1, 5, "pending/1/abc")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,105 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/domain"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/database/s3"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/logger"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/utils"
+)
+
+// CollectImageGarbage Сверяет объекты настроенных бакетов с kvartirum.Image. Сиротами считаются
+// объекты без записи, записи без ссылок и записи, объект которых пропал. Все, что моложе
+// GracePeriod, пропускается. В режиме dryRun только возвращает отчет
+func (u *offerUsecase) CollectImageGarbage(ctx context.Context, dryRun bool) (domain.ImageGCReport, error) {
+	requestID := ctx.Value(utils.RequestIDKey)
+	gcCfg := u.cfg.App.ImageGC
+	deadline := time.Now().Add(-gcCfg.GracePeriod)
+
+	report := domain.ImageGCReport{DryRun: dryRun}
+
+	images, err := u.repo.GetStoredImages(ctx)
+	if err != nil {
+		u.logger.WithFields(logger.LoggerFields{"requestID": requestID, "err": err.Error()}).Error("Offer usecase: get stored images failed")
+		return report, err
+	}
+
+	// Без полного списка объектов нельзя отличить сироту от живого файла, поэтому ошибка прерывает сверку
+	objects := make(map[string][]s3.ObjectInfo, len(gcCfg.Buckets))
+	listed := make(map[string]map[string]struct{}, len(gcCfg.Buckets))
+	for _, bucket := range gcCfg.Buckets {
+		bucketObjects, err := u.s3Repo.List(ctx, bucket, "")
+		if err != nil {
+			u.logger.WithFields(logger.LoggerFields{"requestID": requestID, "bucket": bucket, "err": err.Error()}).Error("Offer usecase: list bucket failed")
+			return report, err
+		}
+		objects[bucket] = bucketObjects
+		listed[bucket] = make(map[string]struct{}, len(bucketObjects))
+		for _, object := range bucketObjects {
+			listed[bucket][object.Name] = struct{}{}
+		}
+	}
+
+	keep := make(map[string]struct{}, len(images))
+	for _, image := range images {
+		if image.CreatedAt.Before(deadline) {
+			if image.Bucket == "" {
+				report.UnreferencedImages = append(report.UnreferencedImages, image.ID)
+				continue
+			}
+			if names, scanned := listed[image.Bucket]; scanned {
+				if _, ok := names[image.UUID]; !ok {
+					report.MissingObjectImages = append(report.MissingObjectImages, image.ID)
+					continue
+				}
+			}
+		}
+
+		keep[image.UUID] = struct{}{}
+		for _, width := range image.Variants {
+			keep[domain.VariantObjectName(image.UUID, width)] = struct{}{}
+		}
+	}
+
+	for _, bucket := range gcCfg.Buckets {
+		for _, object := range objects[bucket] {
+			if _, ok := keep[object.Name]; ok || !object.LastModified.Before(deadline) {
+				continue
+			}
+			report.OrphanObjects = append(report.OrphanObjects, domain.ImageGCObject{Bucket: bucket, Name: object.Name})
+		}
+	}
+
+	logFields := logger.LoggerFields{"requestID": requestID, "dry_run": dryRun, "orphan_objects": len(report.OrphanObjects),
+		"unreferenced_images": len(report.UnreferencedImages), "missing_object_images": len(report.MissingObjectImages)}
+	if dryRun {
+		u.logger.WithFields(logFields).Info("Offer usecase: image gc report")
+		return report, nil
+	}
+
+	for _, object := range report.OrphanObjects {
+		if err := u.s3Repo.Remove(ctx, object.Bucket, object.Name); err != nil {
+			u.logger.WithFields(logger.LoggerFields{"requestID": requestID, "bucket": object.Bucket, "object": object.Name, "err": err.Error()}).Warn("Offer usecase: orphan object remove failed")
+			continue
+		}
+		report.DeletedObjects++
+	}
+
+	ids := append(append([]int64{}, report.UnreferencedImages...), report.MissingObjectImages...)
+	if len(ids) > 0 {
+		deleted, err := u.repo.DeleteImages(ctx, ids)
+		if err != nil {
+			u.logger.WithFields(logger.LoggerFields{"requestID": requestID, "err": err.Error()}).Error("Offer usecase: delete orphan images failed")
+			return report, err
+		}
+		report.DeletedImages = deleted
+	}
+
+	logFields["deleted_objects"] = report.DeletedObjects
+	logFields["deleted_images"] = report.DeletedImages
+	u.logger.WithFields(logFields).Info("Offer usecase: image gc done")
+
+	return report, nil
+}
@@ -0,0 +1,115 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-park-mail-ru/2025_1_404/config"
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/domain"
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/mocks"
+	yaMock "github.com/go-park-mail-ru/2025_1_404/pkg/api/yandex/mocks"
+	redisMock "github.com/go-park-mail-ru/2025_1_404/pkg/database/redis/mocks"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/database/s3"
+	s3Mock "github.com/go-park-mail-ru/2025_1_404/pkg/database/s3/mocks"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/logger"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/utils"
+	authService "github.com/go-park-mail-ru/2025_1_404/proto/auth/mocks"
+	paymentService "github.com/go-park-mail-ru/2025_1_404/proto/payment/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectImageGarbage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOfferRepository(ctrl)
+	mockS3 := s3Mock.NewMockS3Repo(ctrl)
+	cfg := &config.Config{
+		App: config.AppConfig{ImageGC: config.ImageGCConfig{GracePeriod: 24 * time.Hour, Buckets: []string{"offers", "avatars"}}},
+	}
+	offerUsecase := NewOfferUsecase(mockRepo, logger.NewStub(), mockS3, cfg,
+		authService.NewMockAuthServiceClient(ctrl), paymentService.NewMockPaymentServiceClient(ctrl),
+		redisMock.NewMockRedisRepo(ctrl), yaMock.NewMockYandexRepo(ctrl))
+	ctx := context.WithValue(context.Background(), utils.RequestIDKey, "test-request-id")
+
+	old := time.Now().Add(-72 * time.Hour)
+	fresh := time.Now().Add(-time.Hour)
+
+	images := []domain.StoredImage{
+		// Живое изображение объявления с копией
+		{ID: 1, UUID: "flat.jpg", Variants: []int{320}, Bucket: "offers", CreatedAt: old},
+		// Аватар, файл которого пропал
+		{ID: 2, UUID: "lost.jpg", Bucket: "avatars", CreatedAt: old},
+		// Изображение удаленного объявления
+		{ID: 3, UUID: "deleted.jpg", Variants: []int{320}, CreatedAt: old},
+		// Только что загружено, привязка еще не создана
+		{ID: 4, UUID: "uploading.jpg", CreatedAt: fresh},
+		// ЖК лежат в бакете, который не сверяется
+		{ID: 5, UUID: "zhk.jpg", Bucket: "images", CreatedAt: old},
+	}
+	offersObjects := []s3.ObjectInfo{
+		{Name: "flat.jpg", LastModified: old},
+		{Name: "flat.jpg_320w.jpg", LastModified: old},
+		{Name: "deleted.jpg", LastModified: old},
+		{Name: "deleted.jpg_320w.jpg", LastModified: old},
+		{Name: "uploading.jpg", LastModified: fresh},
+		{Name: "pending/1/abc", LastModified: old},
+		{Name: "pending/1/new", LastModified: fresh},
+	}
+	avatarsObjects := []s3.ObjectInfo{
+		{Name: "failed-upload.png", LastModified: old},
+	}
+
+	expectedOrphans := []domain.ImageGCObject{
+		{Bucket: "offers", Name: "deleted.jpg"},
+		{Bucket: "offers", Name: "deleted.jpg_320w.jpg"},
+		{Bucket: "offers", Name: "pending/1/abc"},
+		{Bucket: "avatars", Name: "failed-upload.png"},
+	}
+
+	t.Run("dry run only reports", func(t *testing.T) {
+		mockRepo.EXPECT().GetStoredImages(ctx).Return(images, nil)
+		mockS3.EXPECT().List(ctx, "offers", "").Return(offersObjects, nil)
+		mockS3.EXPECT().List(ctx, "avatars", "").Return(avatarsObjects, nil)
+
+		report, err := offerUsecase.CollectImageGarbage(ctx, true)
+		require.NoError(t, err)
+		assert.True(t, report.DryRun)
+		assert.Equal(t, expectedOrphans, report.OrphanObjects)
+		assert.Equal(t, []int64{3}, report.UnreferencedImages)
+		assert.Equal(t, []int64{2}, report.MissingObjectImages)
+		assert.Zero(t, report.DeletedObjects)
+		assert.Zero(t, report.DeletedImages)
+	})
+
+	t.Run("deletes orphans", func(t *testing.T) {
+		mockRepo.EXPECT().GetStoredImages(ctx).Return(images, nil)
+		mockS3.EXPECT().List(ctx, "offers", "").Return(offersObjects, nil)
+		mockS3.EXPECT().List(ctx, "avatars", "").Return(avatarsObjects, nil)
+		for _, object := range expectedOrphans {
+			var err error
+			if object.Name == "pending/1/abc" {
+				err = errors.New("s3 error")
+			}
+			mockS3.EXPECT().Remove(ctx, object.Bucket, object.Name).Return(err)
+		}
+		mockRepo.EXPECT().DeleteImages(ctx, []int64{3, 2}).Return(2, nil)
+
+		report, err := offerUsecase.CollectImageGarbage(ctx, false)
+		require.NoError(t, err)
+		assert.Equal(t, 3, report.DeletedObjects)
+		assert.Equal(t, 2, report.DeletedImages)
+	})
+
+	t.Run("list failure aborts", func(t *testing.T) {
+		mockRepo.EXPECT().GetStoredImages(ctx).Return(images, nil)
+		mockS3.EXPECT().List(ctx, "offers", "").Return(nil, errors.New("s3 error"))
+
+		_, err := offerUsecase.CollectImageGarbage(ctx, false)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,236 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"path"
+	"unicode/utf8"
+
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/domain"
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/importer"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/content"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/database/s3"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/logger"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/utils"
+)
+
+const maxExternalIDLength = 64
+
+// SetImageDownloader Подменяет загрузчик фотографий импорта, по умолчанию они скачиваются по HTTP
+func (u *offerUsecase) SetImageDownloader(downloader importer.ImageDownloader) {
+	u.downloader = downloader
+}
+
+func (u *offerUsecase) imageDownloader() importer.ImageDownloader {
+	if u.downloader == nil {
+		u.downloader = importer.NewHTTPDownloader(u.cfg.App.Import.DownloadTimeout)
+	}
+	return u.downloader
+}
+
+// ImportOffers Создает или обновляет объявления продавца из файла импорта. Повторный импорт
+// объявления с тем же external_id обновляет его, а не создает новое. Фотографии скачиваются
+// только для новых объявлений, чтобы повторный импорт не дублировал галерею
+func (u *offerUsecase) ImportOffers(ctx context.Context, sellerID int, records []domain.ImportRecord) (domain.ImportReport, error) {
+	requestID := ctx.Value(utils.RequestIDKey)
+
+	report := domain.ImportReport{Rows: make([]domain.ImportRowResult, 0, len(records))}
+
+	dicts, err := u.repo.GetImportDictionaries(ctx)
+	if err != nil {
+		u.logger.WithFields(logger.LoggerFields{"requestID": requestID, "err": err.Error()}).Error("Offer usecase: get import dictionaries failed")
+		return report, err
+	}
+
+	for _, record := range records {
+		result := u.importRecord(ctx, sellerID, dicts, record)
+		switch {
+		case result.Error != "":
+			report.Failed++
+		case result.Action == domain.ImportActionCreated:
+			report.Created++
+		case result.Action == domain.ImportActionUpdated:
+			report.Updated++
+		}
+		report.Rows = append(report.Rows, result)
+	}
+
+	u.logger.WithFields(logger.LoggerFields{"requestID": requestID, "seller_id": sellerID, "created": report.Created, "updated": report.Updated, "failed": report.Failed}).Info("Offer usecase: import finished")
+
+	return report, nil
+}
+
+func (u *offerUsecase) importRecord(ctx context.Context, sellerID int, dicts domain.ImportDictionaries, record domain.ImportRecord) domain.ImportRowResult {
+	requestID := ctx.Value(utils.RequestIDKey)
+	result := domain.ImportRowResult{Row: record.Row, ExternalID: record.ExternalID}
+
+	if record.Error != "" {
+		result.Error = record.Error
+		return result
+	}
+
+	offer, err := mapImportRecord(record, dicts)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	offer.SellerID = sellerID
+
+	existingID, err := u.repo.GetOfferIDByExternalID(ctx, sellerID, record.ExternalID)
+	if err != nil {
+		result.Error = "не удалось проверить, импортировалось ли объявление ранее"
+		return result
+	}
+
+	if existingID != 0 {
+		offer.ID = existingID
+		if err := u.UpdateOffer(ctx, offer); err != nil {
+			result.Error = err.Error()
+			return result
+		}
+		result.OfferID = existingID
+		result.Action = domain.ImportActionUpdated
+		return result
+	}
+
+	offerID, err := u.CreateOffer(ctx, offer)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	if err := u.repo.SetOfferExternalID(ctx, offerID, record.ExternalID); err != nil {
+		// Без external_id следующий импорт создал бы дубликат, поэтому объявление откатывается
+		u.logger.WithFields(logger.LoggerFields{"requestID": requestID, "offer_id": offerID, "external_id": record.ExternalID, "err": err.Error()}).Error("Offer usecase: set external id failed")
+		if err := u.DeleteOffer(ctx, offerID); err != nil {
+			u.logger.WithFields(logger.LoggerFields{"requestID": requestID, "offer_id": offerID, "err": err.Error()}).Error("Offer usecase: rollback imported offer failed")
+		}
+		result.Error = "не удалось сохранить external_id объявления"
+		return result
+	}
+
+	result.OfferID = offerID
+	result.Action = domain.ImportActionCreated
+	result.ImageErrors = u.importImages(ctx, offerID, record.ImageURLs)
+
+	return result
+}
+
+// importImages Скачивает и сохраняет фотографии по порядку, первая становится обложкой.
+// Возвращает описания ошибок по ссылкам, которые сохранить не удалось
+func (u *offerUsecase) importImages(ctx context.Context, offerID int, urls []string) []string {
+	requestID := ctx.Value(utils.RequestIDKey)
+
+	var imageErrors []string
+	for i, rawURL := range urls {
+		if limit := u.cfg.App.Import.MaxImages; limit > 0 && i >= limit {
+			imageErrors = append(imageErrors, fmt.Sprintf("пропущено фотографий сверх лимита %d: %d", limit, len(urls)-limit))
+			break
+		}
+
+		data, err := u.imageDownloader().Download(ctx, rawURL)
+		if err == nil {
+			data, err = content.PrepareImage(data)
+		}
+		if err == nil {
+			_, err = u.SaveOfferImage(ctx, offerID, s3.Upload{
+				Bucket:      "offers",
+				Filename:    content.SanitizedFilename(imageFilename(rawURL)),
+				Size:        int64(len(data)),
+				File:        bytes.NewReader(data),
+				ContentType: content.SanitizedContentType,
+			})
+		}
+		if err != nil {
+			u.logger.WithFields(logger.LoggerFields{"requestID": requestID, "offer_id": offerID, "url": rawURL, "err": err.Error()}).Warn("Offer usecase: import image failed")
+			imageErrors = append(imageErrors, fmt.Sprintf("%s: %s", rawURL, err.Error()))
+		}
+	}
+
+	return imageErrors
+}
+
+// mapImportRecord Сопоставляет названия со справочниками. Диапазоны и длины полей проверяет
+// domain.ValidateOffer при создании и обновлении объявления
+func mapImportRecord(record domain.ImportRecord, dicts domain.ImportDictionaries) (domain.Offer, error) {
+	var offer domain.Offer
+
+	if record.ExternalID == "" {
+		return offer, fmt.Errorf("не указан external_id")
+	}
+	if utf8.RuneCountInString(record.ExternalID) > maxExternalIDLength {
+		return offer, fmt.Errorf("external_id длиннее %d символов", maxExternalIDLength)
+	}
+	if record.Address == "" {
+		return offer, fmt.Errorf("не указан адрес")
+	}
+
+	var err error
+	if offer.OfferTypeID, err = resolveDictionary(dicts.OfferTypes, record.OfferType, "тип объявления"); err != nil {
+		return offer, err
+	}
+	if offer.PropertyTypeID, err = resolveDictionary(dicts.PropertyTypes, record.PropertyType, "тип недвижимости"); err != nil {
+		return offer, err
+	}
+	if offer.RenovationID, err = resolveDictionary(dicts.Renovations, record.Renovation, "ремонт"); err != nil {
+		return offer, err
+	}
+	if offer.RentTypeID, err = resolveOptionalDictionary(dicts.RentTypes, record.RentType, "тип аренды"); err != nil {
+		return offer, err
+	}
+	if offer.PurchaseTypeID, err = resolveOptionalDictionary(dicts.PurchaseTypes, record.PurchaseType, "тип покупки"); err != nil {
+		return offer, err
+	}
+	if offer.MetroStationID, err = resolveOptionalDictionary(dicts.MetroStations, record.MetroStation, "станция метро"); err != nil {
+		return offer, err
+	}
+
+	address := record.Address
+	offer.Address = &address
+	if record.Description != "" {
+		description := record.Description
+		offer.Description = &description
+	}
+	offer.Price = record.Price
+	offer.Floor = record.Floor
+	offer.TotalFloors = record.TotalFloors
+	offer.Rooms = record.Rooms
+	offer.Flat = record.Flat
+	offer.Area = record.Area
+	offer.CeilingHeight = record.CeilingHeight
+
+	return offer, nil
+}
+
+func resolveDictionary(dict map[string]int, name string, field string) (int, error) {
+	if name == "" {
+		return 0, fmt.Errorf("не указано поле «%s»", field)
+	}
+	id, ok := dict[domain.NormalizeDictionaryName(name)]
+	if !ok {
+		return 0, fmt.Errorf("неизвестное значение поля «%s»: %s", field, name)
+	}
+	return id, nil
+}
+
+func resolveOptionalDictionary(dict map[string]int, name string, field string) (*int, error) {
+	if name == "" {
+		return nil, nil
+	}
+	id, err := resolveDictionary(dict, name, field)
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
+
+// imageFilename Имя файла из пути ссылки, для ссылок вида /photo?id=1 подставляется общее имя
+func imageFilename(rawURL string) string {
+	if u, err := url.Parse(rawURL); err == nil {
+		if name := path.Base(u.Path); name != "." && name != "/" {
+			return name
+		}
+	}
+	return "import"
+}
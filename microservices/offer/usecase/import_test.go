@@ -0,0 +1,175 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/png"
+	"testing"
+
+	"github.com/go-park-mail-ru/2025_1_404/config"
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/domain"
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/mocks"
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/repository"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/api/yandex"
+	yaMock "github.com/go-park-mail-ru/2025_1_404/pkg/api/yandex/mocks"
+	redisMock "github.com/go-park-mail-ru/2025_1_404/pkg/database/redis/mocks"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/database/s3"
+	s3Mock "github.com/go-park-mail-ru/2025_1_404/pkg/database/s3/mocks"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/logger"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/utils"
+	authService "github.com/go-park-mail-ru/2025_1_404/proto/auth/mocks"
+	paymentService "github.com/go-park-mail-ru/2025_1_404/proto/payment/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubDownloader Отдает заранее подготовленные файлы вместо скачивания по сети
+type stubDownloader map[string][]byte
+
+func (d stubDownloader) Download(_ context.Context, rawURL string) ([]byte, error) {
+	data, ok := d[rawURL]
+	if !ok {
+		return nil, errors.New("не удалось скачать файл")
+	}
+	return data, nil
+}
+
+func TestImportOffers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOfferRepository(ctrl)
+	mockS3 := s3Mock.NewMockS3Repo(ctrl)
+	mockYa := yaMock.NewMockYandexRepo(ctrl)
+	cfg := &config.Config{App: config.AppConfig{Import: config.ImportConfig{MaxImages: 2}}}
+	offerUsecase := NewOfferUsecase(mockRepo, logger.NewStub(), mockS3, cfg,
+		authService.NewMockAuthServiceClient(ctrl), paymentService.NewMockPaymentServiceClient(ctrl),
+		redisMock.NewMockRedisRepo(ctrl), mockYa)
+	ctx := context.WithValue(context.Background(), utils.RequestIDKey, "test-request-id")
+
+	var photo bytes.Buffer
+	require.NoError(t, png.Encode(&photo, image.NewRGBA(image.Rect(0, 0, 120, 100))))
+	offerUsecase.SetImageDownloader(stubDownloader{"https://example.com/1.png": photo.Bytes()})
+
+	dicts := domain.ImportDictionaries{
+		OfferTypes:    map[string]int{"продажа": 1, "аренда": 2},
+		RentTypes:     map[string]int{"долгосрок": 2},
+		PurchaseTypes: map[string]int{"вторичка": 2},
+		PropertyTypes: map[string]int{"квартира": 3},
+		Renovations:   map[string]int{"современный ремонт": 1},
+		MetroStations: map[string]int{"тверская": 15},
+	}
+	mockRepo.EXPECT().GetImportDictionaries(ctx).Return(dicts, nil)
+	mockRepo.EXPECT().GetDictionaries(ctx).Return(testDictionaries, nil).AnyTimes()
+
+	records := []domain.ImportRecord{
+		{
+			Row: 2, ExternalID: "A-1", OfferType: "Продажа", PurchaseType: "Вторичка", PropertyType: "квартира",
+			Renovation: "Современный ремонт", MetroStation: "Тверская", Address: "Москва, Тверская, 7",
+			Price: 5000000, Area: 45, Rooms: 2, Floor: 3, TotalFloors: 9,
+			ImageURLs: []string{"https://example.com/1.png", "https://example.com/broken.png", "https://example.com/3.png"},
+		},
+		{
+			Row: 3, ExternalID: "A-2", OfferType: "аренда", RentType: "Долгосрок", PropertyType: "Квартира",
+			Renovation: "современный ремонт", Address: "Москва, Арбат, 1", Price: 90000,
+		},
+		{Row: 4, ExternalID: "A-3", OfferType: "Продажа", PropertyType: "Квартира", Renovation: "Евроремонт", Address: "Москва"},
+		{Row: 5, ExternalID: "A-4", Error: "некорректное значение в колонке price"},
+		{Row: 6, OfferType: "Продажа", PropertyType: "Квартира", Renovation: "Современный ремонт", Address: "Москва"},
+	}
+
+	// A-1 импортируется впервые
+	mockRepo.EXPECT().GetOfferIDByExternalID(ctx, 42, "A-1").Return(0, nil)
+	mockYa.EXPECT().GetCoordinatesOfAddress("Москва, Тверская, 7").Return(&yandex.Coordinates{Latitude: 55.76, Longitude: 37.6}, nil)
+	mockRepo.EXPECT().FindDuplicateOffers(ctx, gomock.Any()).Return(nil, nil)
+	mockRepo.EXPECT().CreateOffer(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, o repository.Offer) (int64, error) {
+		assert.Equal(t, int64(42), o.SellerID)
+		assert.Equal(t, 1, o.OfferTypeID)
+		assert.Equal(t, 2, *o.PurchaseTypeID)
+		assert.Equal(t, 15, *o.MetroStationID)
+		assert.Nil(t, o.RentTypeID)
+		assert.Equal(t, domain.OfferStatusDraft, o.StatusID)
+		return 10, nil
+	})
+	mockRepo.EXPECT().AddOrUpdatePriceHistory(ctx, int64(10), 5000000).Return(nil)
+	mockRepo.EXPECT().SetOfferExternalID(ctx, 10, "A-1").Return(nil)
+	mockS3.EXPECT().Put(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, upload s3.Upload) (string, error) {
+		assert.Equal(t, "offers", upload.Bucket)
+		assert.Equal(t, "1.jpg", upload.Filename)
+		return "uuid.jpg", nil
+	})
+	mockRepo.EXPECT().CreateImageAndBindToOffer(ctx, 10, "uuid.jpg", gomock.Any()).Return(int64(100), nil)
+
+	// A-2 уже импортировалось, адрес и цена не изменились
+	address := "Москва, Арбат, 1"
+	mockRepo.EXPECT().GetOfferIDByExternalID(ctx, 42, "A-2").Return(7, nil)
+	mockRepo.EXPECT().GetOfferByID(ctx, int64(7)).Return(repository.Offer{ID: 7, SellerID: 42, OfferTypeID: 2, Price: 90000, Address: &address}, nil)
+	mockRepo.EXPECT().UpdateOffer(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, o repository.Offer) error {
+		assert.Equal(t, int64(7), o.ID)
+		assert.Equal(t, 2, *o.RentTypeID)
+		return nil
+	})
+
+	report, err := offerUsecase.ImportOffers(ctx, 42, records)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, report.Created)
+	assert.Equal(t, 1, report.Updated)
+	assert.Equal(t, 3, report.Failed)
+	require.Len(t, report.Rows, 5)
+
+	assert.Equal(t, domain.ImportRowResult{
+		Row: 2, ExternalID: "A-1", OfferID: 10, Action: domain.ImportActionCreated,
+		ImageErrors: []string{
+			"https://example.com/broken.png: не удалось скачать файл",
+			"пропущено фотографий сверх лимита 2: 1",
+		},
+	}, report.Rows[0])
+	assert.Equal(t, domain.ImportRowResult{Row: 3, ExternalID: "A-2", OfferID: 7, Action: domain.ImportActionUpdated}, report.Rows[1])
+	assert.Equal(t, "неизвестное значение поля «ремонт»: Евроремонт", report.Rows[2].Error)
+	assert.Equal(t, "некорректное значение в колонке price", report.Rows[3].Error)
+	assert.Equal(t, "не указан external_id", report.Rows[4].Error)
+}
+
+func TestImportOffersRollback(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOfferRepository(ctrl)
+	mockYa := yaMock.NewMockYandexRepo(ctrl)
+	offerUsecase := NewOfferUsecase(mockRepo, logger.NewStub(), s3Mock.NewMockS3Repo(ctrl), &config.Config{},
+		authService.NewMockAuthServiceClient(ctrl), paymentService.NewMockPaymentServiceClient(ctrl),
+		redisMock.NewMockRedisRepo(ctrl), mockYa)
+	ctx := context.WithValue(context.Background(), utils.RequestIDKey, "test-request-id")
+
+	dicts := domain.ImportDictionaries{
+		OfferTypes:    map[string]int{"продажа": 1},
+		PurchaseTypes: map[string]int{"вторичка": 2},
+		PropertyTypes: map[string]int{"квартира": 3},
+		Renovations:   map[string]int{"современный ремонт": 1},
+	}
+	record := domain.ImportRecord{Row: 2, ExternalID: "A-1", OfferType: "Продажа", PurchaseType: "Вторичка", PropertyType: "Квартира", Renovation: "Современный ремонт", Address: "Москва"}
+
+	mockRepo.EXPECT().GetImportDictionaries(ctx).Return(dicts, nil)
+	mockRepo.EXPECT().GetDictionaries(ctx).Return(testDictionaries, nil).AnyTimes()
+	mockRepo.EXPECT().GetOfferIDByExternalID(ctx, 42, "A-1").Return(0, nil)
+	mockYa.EXPECT().GetCoordinatesOfAddress("Москва").Return(&yandex.Coordinates{}, nil)
+	mockRepo.EXPECT().FindDuplicateOffers(ctx, gomock.Any()).Return(nil, nil)
+	mockRepo.EXPECT().CreateOffer(ctx, gomock.Any()).Return(int64(10), nil)
+	// Параллельный импорт успел занять external_id
+	mockRepo.EXPECT().SetOfferExternalID(ctx, 10, "A-1").Return(errors.New("unique violation"))
+	mockRepo.EXPECT().DeletePriceHistory(ctx, int64(10)).Return(nil)
+	mockRepo.EXPECT().DeleteOffer(ctx, int64(10)).Return(nil)
+
+	report, err := offerUsecase.ImportOffers(ctx, 42, []domain.ImportRecord{record})
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Failed)
+	assert.Equal(t, "не удалось сохранить external_id объявления", report.Rows[0].Error)
+
+	mockRepo.EXPECT().GetImportDictionaries(ctx).Return(domain.ImportDictionaries{}, errors.New("db error"))
+	_, err = offerUsecase.ImportOffers(ctx, 42, []domain.ImportRecord{record})
+	assert.Error(t, err)
+}
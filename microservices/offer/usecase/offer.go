@@ -1,24 +1,32 @@
 package usecase
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	paymentpb "github.com/go-park-mail-ru/2025_1_404/proto/payment"
 	"html"
-	"sort"
+	"io"
+	"math"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/go-park-mail-ru/2025_1_404/config"
 	"github.com/go-park-mail-ru/2025_1_404/microservices/offer"
 	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/domain"
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/importer"
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/notify"
 	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/repository"
 	"github.com/go-park-mail-ru/2025_1_404/pkg/api/yandex"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/cache"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/content"
 	"github.com/go-park-mail-ru/2025_1_404/pkg/database/redis"
 	"github.com/go-park-mail-ru/2025_1_404/pkg/database/s3"
 	"github.com/go-park-mail-ru/2025_1_404/pkg/logger"
 	"github.com/go-park-mail-ru/2025_1_404/pkg/utils"
 	authpb "github.com/go-park-mail-ru/2025_1_404/proto/auth"
+	"github.com/go-playground/validator/v10"
 )
 
 type offerUsecase struct {
@@ -30,51 +38,63 @@ type offerUsecase struct {
 	authService    authpb.AuthServiceClient
 	paymentService paymentpb.PaymentServiceClient
 	redisRepo      redis.RedisRepo
+	downloader     importer.ImageDownloader
+	notifier       notify.Channel
+	notifierOnce   sync.Once
+	cache          *cache.Cache
+	validate       *validator.Validate
 }
 
 func NewOfferUsecase(repo offer.OfferRepository, logger logger.Logger, s3Repo s3.S3Repo, cfg *config.Config, authService authpb.AuthServiceClient, paymentService paymentpb.PaymentServiceClient, redisRepo redis.RedisRepo, yandexRepo yandex.YandexRepo) *offerUsecase {
-	return &offerUsecase{repo: repo, logger: logger, s3Repo: s3Repo, cfg: cfg, authService: authService, paymentService: paymentService, redisRepo: redisRepo, yandexRepo: yandexRepo}
+	return &offerUsecase{repo: repo, logger: logger, s3Repo: s3Repo, cfg: cfg, authService: authService, paymentService: paymentService, redisRepo: redisRepo, yandexRepo: yandexRepo, cache: cache.New(redisRepo, logger), validate: domain.NewOfferValidator()}
 }
 
-func (u *offerUsecase) GetOffers(ctx context.Context, userID *int) ([]domain.OfferInfo, error) {
+func (u *offerUsecase) GetOffers(ctx context.Context, page domain.Pagination, userID *int) (domain.OffersPage, error) {
 	requestID := ctx.Value(utils.RequestIDKey)
 
-	offers, err := u.repo.GetAllOffers(ctx)
+	offers, total, err := u.repo.GetAllOffers(ctx, page)
 	if err != nil {
 		u.logger.WithFields(logger.LoggerFields{"requestID": requestID, "err": err.Error()}).Error("Offer usecase: get all offers failed")
-		return nil, err
+		return domain.OffersPage{}, err
 	}
 
-	offersDTO := mapOffers(offers)
-
-	offersInfo, err := u.PrepareOffersInfo(ctx, offersDTO, userID)
-
+	offersPage, err := u.preparePage(ctx, offers, total, page, domain.SortNewest, userID)
 	if err != nil {
 		u.logger.WithFields(logger.LoggerFields{"requestID": requestID, "err": err.Error()}).Error("Offer usecase: get offers data failed")
-		return []domain.OfferInfo{}, err
+		return domain.OffersPage{}, err
 	}
 
-	return offersInfo, nil
+	return offersPage, nil
 }
 
-func (u *offerUsecase) GetOffersByFilter(ctx context.Context, filter domain.OfferFilter, userID *int) ([]domain.OfferInfo, error) {
+func (u *offerUsecase) GetOffersByFilter(ctx context.Context, filter domain.OfferFilter, page domain.Pagination, userID *int) (domain.OffersPage, error) {
 	requestID := ctx.Value(utils.RequestIDKey)
 
-	rawOffers, err := u.repo.GetOffersByFilter(ctx, filter, userID)
+	rawOffers, total, err := u.repo.GetOffersByFilter(ctx, filter, page, userID)
 	if err != nil {
 		u.logger.WithFields(logger.LoggerFields{"requestID": requestID, "err": err.Error()}).Error("Offer usecase: filter offers failed")
-		return nil, err
+		return domain.OffersPage{}, err
 	}
 
-	offersDTO := mapOffers(rawOffers)
-
-	offersInfo, err := u.PrepareOffersInfo(ctx, offersDTO, userID)
+	offersPage, err := u.preparePage(ctx, rawOffers, total, page, filter.Sort, userID)
 	if err != nil {
 		u.logger.WithFields(logger.LoggerFields{"requestID": requestID, "err": err.Error()}).Error("Offer usecase: get offers data failed")
-		return []domain.OfferInfo{}, err
+		return domain.OffersPage{}, err
 	}
 
-	return offersInfo, nil
+	return offersPage, nil
+}
+
+func (u *offerUsecase) GetOfferClusters(ctx context.Context, filter domain.OfferFilter, zoom int, userID *int) (domain.OfferClusters, error) {
+	requestID := ctx.Value(utils.RequestIDKey)
+
+	clusters, err := u.repo.GetOfferClusters(ctx, filter, domain.ClusterCellSize(zoom), userID)
+	if err != nil {
+		u.logger.WithFields(logger.LoggerFields{"requestID": requestID, "zoom": zoom, "err": err.Error()}).Error("Offer usecase: get offer clusters failed")
+		return nil, err
+	}
+
+	return clusters, nil
 }
 
 func (u *offerUsecase) GetOfferByID(ctx context.Context, id int, ip string, userID *int) (domain.OfferInfo, error) {
@@ -138,39 +158,38 @@ func (u *offerUsecase) addView(ctx context.Context, offerId int, ip string) erro
 	return nil
 }
 
-func (u *offerUsecase) GetOffersBySellerID(ctx context.Context, sellerID int, userID *int) ([]domain.OfferInfo, error) {
+func (u *offerUsecase) GetOffersBySellerID(ctx context.Context, sellerID int, page domain.Pagination, userID *int) (domain.OffersPage, error) {
 	requestID := ctx.Value(utils.RequestIDKey)
 
-	offers, err := u.repo.GetOffersBySellerID(ctx, int64(sellerID))
+	offers, total, err := u.repo.GetOffersBySellerID(ctx, int64(sellerID), page)
 	if err != nil {
 		u.logger.WithFields(logger.LoggerFields{"requestID": requestID, "seller_id": sellerID, "err": err.Error()}).Error("Offer usecase: get offers by seller failed")
-		return nil, err
+		return domain.OffersPage{}, err
 	}
 
-	offersDTO := mapOffers(offers)
-
-	offersInfo, err := u.PrepareOffersInfo(ctx, offersDTO, userID)
-
+	offersPage, err := u.preparePage(ctx, offers, total, page, domain.SortNewest, userID)
 	if err != nil {
 		u.logger.WithFields(logger.LoggerFields{"requestID": requestID, "err": err.Error()}).Error("Offer usecase: get offers data failed")
-		return []domain.OfferInfo{}, err
+		return domain.OffersPage{}, err
 	}
 
-	return offersInfo, nil
+	return offersPage, nil
 }
 
 func (u *offerUsecase) CreateOffer(ctx context.Context, offer domain.Offer) (int, error) {
 	requestID := ctx.Value(utils.RequestIDKey)
 
-	if offer.Description != nil {
-		escaped := html.EscapeString(*offer.Description)
-		offer.Description = &escaped
+	if err := domain.ValidateOffer(u.validate, offer); err != nil {
+		return 0, err
 	}
 
 	if offer.Address == nil {
 		return 0, fmt.Errorf("не указан адрес")
 	}
-	*offer.Address = html.EscapeString(*offer.Address)
+	if err := u.checkOfferReferences(ctx, offer); err != nil {
+		return 0, err
+	}
+	escapeOfferText(&offer)
 	coords, err := u.yandexRepo.GetCoordinatesOfAddress(*offer.Address)
 	if err != nil {
 		u.logger.WithFields(logger.LoggerFields{"requestID": requestID, "err": err.Error()}).Error("Offer usecase: get coordinates failed")
@@ -181,12 +200,25 @@ func (u *offerUsecase) CreateOffer(ctx context.Context, offer domain.Offer) (int
 
 	offer.StatusID = domain.OfferStatusDraft
 
+	duplicates, err := u.checkDuplicates(ctx, domain.DuplicateCandidate{
+		Rooms:     offer.Rooms,
+		Floor:     offer.Floor,
+		Flat:      offer.Flat,
+		Area:      offer.Area,
+		Latitude:  &coords.Latitude,
+		Longitude: &coords.Longitude,
+	}, offer.SellerID)
+	if err != nil {
+		return 0, err
+	}
+
 	repoOffer := unmapOffer(offer)
 	id, err := u.repo.CreateOffer(ctx, repoOffer)
 	if err != nil {
 		u.logger.WithFields(logger.LoggerFields{"requestID": requestID, "err": err.Error()}).Error("Offer usecase: create offer failed")
 		return 0, err
 	}
+	u.flagDuplicates(ctx, int(id), duplicates)
 
 	if offer.Price > 0 {
 		err = u.repo.AddOrUpdatePriceHistory(ctx, id, offer.Price)
@@ -212,16 +244,18 @@ func (u *offerUsecase) UpdateOffer(ctx context.Context, offer domain.Offer) erro
 	offer.Latitude = existing.Latitude
 	offer.Longitude = existing.Longitude
 
-	if offer.Description != nil {
-		escaped := html.EscapeString(*offer.Description)
-		offer.Description = &escaped
+	if err := domain.ValidateOffer(u.validate, offer); err != nil {
+		return err
 	}
 
 	if offer.Address == nil {
 		return fmt.Errorf("не указан адрес")
 	}
+	if err := u.checkOfferReferences(ctx, offer); err != nil {
+		return err
+	}
 
-	*offer.Address = html.EscapeString(*offer.Address)
+	escapeOfferText(&offer)
 	if existing.Address != nil && *existing.Address != *offer.Address {
 		coords, err := u.yandexRepo.GetCoordinatesOfAddress(*offer.Address)
 		if err != nil {
@@ -266,6 +300,17 @@ func (u *offerUsecase) UpdateOffer(ctx context.Context, offer domain.Offer) erro
 		u.logger.WithFields(logger.LoggerFields{"requestID": requestID, "offer_id": offer.ID, "err": err.Error()}).Error("Offer usecase: update offer failed")
 		return err
 	}
+	u.invalidateOffer(ctx, offer.ID, existing.ComplexID, offer.ComplexID)
+
+	// Снижение цены опубликованного объявления того же типа сделки
+	if offer.Price < existing.Price && offer.OfferTypeID == existing.OfferTypeID && existing.StatusID == domain.OfferStatusActive {
+		u.notifyPriceDrop(ctx, domain.PriceDropAlert{
+			OfferID:  offer.ID,
+			SellerID: int(existing.SellerID),
+			OldPrice: existing.Price,
+			NewPrice: offer.Price,
+		})
+	}
 
 	return nil
 }
@@ -279,16 +324,72 @@ func (u *offerUsecase) DeleteOffer(ctx context.Context, id int) error {
 		u.logger.WithFields(logger.LoggerFields{"requestID": requestID, "offer_id": id, "err": err.Error()}).Error("Offer usecase: delete offer failed")
 		return err
 	}
+	u.invalidateOffer(ctx, id)
+	u.invalidateAllZhkInfo(ctx)
 	return nil
 }
 
 func (u *offerUsecase) SaveOfferImage(ctx context.Context, offerID int, upload s3.Upload) (int64, error) {
+	requestID := ctx.Value(utils.RequestIDKey)
+
+	// Хеш и уменьшенные копии необязательны, без них изображение все равно сохраняется
+	var meta domain.ImageMeta
+	var variants []imageVariant
+	if upload.File != nil {
+		data, err := io.ReadAll(upload.File)
+		if err != nil {
+			return 0, err
+		}
+		upload.File = bytes.NewReader(data)
+
+		img, err := content.DecodeImage(data)
+		if err != nil {
+			u.logger.WithFields(logger.LoggerFields{"requestID": requestID, "offer_id": offerID, "err": err.Error()}).Warn("Offer usecase: image decode failed")
+		} else {
+			hash := int64(content.ImageHash(img))
+			meta.PHash = &hash
+			variants = u.makeImageVariants(ctx, img)
+		}
+	}
+
 	fileName, err := u.s3Repo.Put(ctx, upload)
 	if err != nil {
 		return 0, err
 	}
+	meta.Variants = u.putImageVariants(ctx, upload.Bucket, fileName, variants)
 
-	return u.repo.CreateImageAndBindToOffer(ctx, offerID, fileName)
+	imageID, err := u.repo.CreateImageAndBindToOffer(ctx, offerID, fileName, meta)
+	if err != nil {
+		return 0, err
+	}
+	u.invalidateOffer(ctx, offerID)
+	return imageID, nil
+}
+
+// escapeOfferText Текст хранится экранированным. Проверка идет по введенному тексту,
+// поэтому экранирование делается после нее
+func escapeOfferText(offer *domain.Offer) {
+	if offer.Description != nil {
+		escaped := html.EscapeString(*offer.Description)
+		offer.Description = &escaped
+	}
+	if offer.Address != nil {
+		escaped := html.EscapeString(*offer.Address)
+		offer.Address = &escaped
+	}
+}
+
+// unescapeOfferText Возвращает сохраненный текст к введенному, чтобы проверить его так же, как при сохранении
+func unescapeOfferText(offer domain.Offer) domain.Offer {
+	if offer.Description != nil {
+		text := html.UnescapeString(*offer.Description)
+		offer.Description = &text
+	}
+	if offer.Address != nil {
+		text := html.UnescapeString(*offer.Address)
+		offer.Address = &text
+	}
+	return offer
 }
 
 func (u *offerUsecase) PublishOffer(ctx context.Context, offerID int, userID int) error {
@@ -303,22 +404,79 @@ func (u *offerUsecase) PublishOffer(ctx context.Context, offerID int, userID int
 		return fmt.Errorf("объявление уже активно или завершено")
 	}
 
-	// Проверка обязательных полей
-	if offer.Price <= 0 || offer.Area <= 0 || offer.Floor <= 0 ||
-		offer.TotalFloors <= 0 || offer.Rooms <= 0 || offer.PropertyTypeID == 0 ||
-		offer.RenovationID == 0 || offer.OfferTypeID == 0 || offer.StatusID == 0 {
-		return fmt.Errorf("не все обязательные поля заполнены")
+	if err := u.checkActivation(ctx, offer, userID); err != nil {
+		return err
 	}
 
-	if offer.Address == nil || *offer.Address == "" {
-		return fmt.Errorf("не указан адрес")
+	if err := u.applyTransition(ctx, offerID, offer.StatusID, domain.TransitionPublish, userID, ""); err != nil {
+		return err
+	}
+	u.invalidateOffer(ctx, offerID, offer.ComplexID)
+	return nil
+}
+
+func (u *offerUsecase) ChangeOfferStatus(ctx context.Context, offerID int, userID int, transition domain.OfferTransition, reason string) error {
+	offer, err := u.repo.GetOfferByID(ctx, int64(offerID))
+	if err != nil {
+		return fmt.Errorf("объявление не найдено")
+	}
+	if int(offer.SellerID) != userID {
+		return fmt.Errorf("нет доступа к изменению статуса этого объявления")
+	}
+
+	// Завершенное объявление могли отредактировать, а за это время опубликовать такое же,
+	// поэтому возврат в активные проверяется как публикация
+	if to, ok := transition.Target(offer.StatusID); ok && to == domain.OfferStatusActive {
+		if err := u.checkActivation(ctx, offer, userID); err != nil {
+			return err
+		}
+	}
+
+	if err := u.applyTransition(ctx, offerID, offer.StatusID, transition, userID, reason); err != nil {
+		return err
+	}
+	u.invalidateOffer(ctx, offerID, offer.ComplexID)
+	return nil
+}
+
+// checkActivation Проверки перед переводом объявления в активные: поля, обязательные
+// для публикации, и дубликаты. Чужие дубликаты помечаются для модератора
+func (u *offerUsecase) checkActivation(ctx context.Context, offer repository.Offer, userID int) error {
+	if err := domain.ValidateOfferForPublish(u.validate, unescapeOfferText(mapOffer(offer))); err != nil {
+		return err
+	}
+
+	duplicates, err := u.checkDuplicates(ctx, duplicateCandidate(offer), userID)
+	if err != nil {
+		return err
+	}
+	u.flagDuplicates(ctx, int(offer.ID), duplicates)
+	return nil
+}
+
+// applyTransition Проверяет допустимость перехода и меняет статус объявления
+func (u *offerUsecase) applyTransition(ctx context.Context, offerID int, from int, transition domain.OfferTransition, userID int, reason string) error {
+	requestID := ctx.Value(utils.RequestIDKey)
+
+	to, ok := transition.Target(from)
+	if !ok {
+		return fmt.Errorf("недопустимое изменение статуса объявления")
+	}
+
+	changed, err := u.repo.ChangeOfferStatus(ctx, offerID, from, to, userID, reason)
+	if err != nil {
+		u.logger.WithFields(logger.LoggerFields{"requestID": requestID, "offer_id": offerID, "transition": transition, "err": err.Error()}).Error("Offer usecase: change offer status failed")
+		return fmt.Errorf("ошибка при изменении статуса объявления")
+	}
+	if !changed {
+		return fmt.Errorf("статус объявления уже изменен")
 	}
 
-	return u.repo.UpdateOfferStatus(ctx, offerID, 1)
+	return nil
 }
 
 func (u *offerUsecase) DeleteOfferImage(ctx context.Context, imageID int, userID int) error {
-	offerID, uuid, err := u.repo.GetOfferImageWithUUID(ctx, int64(imageID))
+	offerID, uuid, variants, err := u.repo.GetOfferImageWithUUID(ctx, int64(imageID))
 	if err != nil {
 		return fmt.Errorf("изображение не найдено")
 	}
@@ -335,10 +493,17 @@ func (u *offerUsecase) DeleteOfferImage(ctx context.Context, imageID int, userID
 	if err != nil {
 		return fmt.Errorf("ошибка при удалении связи с изображением")
 	}
+	u.invalidateOffer(ctx, int(offerID))
 
-	// удаляем физически файл
-	if err := u.s3Repo.Remove(ctx, "offers", uuid); err != nil {
-		u.logger.WithFields(logger.LoggerFields{"image_id": imageID, "uuid": uuid, "err": err.Error()}).Warn("Ошибка при удалении файла")
+	// удаляем физически файл и его уменьшенные копии
+	objects := []string{uuid}
+	for _, width := range variants {
+		objects = append(objects, domain.VariantObjectName(uuid, width))
+	}
+	for _, object := range objects {
+		if err := u.s3Repo.Remove(ctx, "offers", object); err != nil {
+			u.logger.WithFields(logger.LoggerFields{"image_id": imageID, "uuid": object, "err": err.Error()}).Warn("Ошибка при удалении файла")
+		}
 	}
 
 	return nil
@@ -356,8 +521,15 @@ func (u *offerUsecase) GetOffersByZhkId(ctx context.Context, zhkId int) ([]domai
 	return offers, nil
 }
 
+// GetStations Справочник станций метро. Он меняется только миграциями, поэтому читается через кеш
 func (u *offerUsecase) GetStations(ctx context.Context) ([]domain.Metro, error) {
 	requestID := ctx.Value(utils.RequestIDKey)
+	ttl := u.cfg.App.Cache.Dictionaries
+
+	var stations domain.Stations
+	if ttl > 0 && u.cache.Get(ctx, cache.Dictionaries, cache.StationsKey, &stations) {
+		return stations, nil
+	}
 
 	stations, err := u.repo.GetStations(ctx)
 	if err != nil {
@@ -365,6 +537,9 @@ func (u *offerUsecase) GetStations(ctx context.Context) ([]domain.Metro, error)
 		return []domain.Metro{}, err
 	}
 
+	if ttl > 0 {
+		u.cache.Set(ctx, cache.StationsKey, stations, ttl)
+	}
 	return stations, nil
 }
 
@@ -390,6 +565,7 @@ func (u *offerUsecase) LikeOffer(ctx context.Context, like domain.LikeRequest) (
 		u.logger.WithFields(logger.LoggerFields{"requestID": requestID, "err": err.Error()}).Error("Offer usecase: toggle like failed")
 		return likeStat, err
 	}
+	u.invalidateOffer(ctx, like.OfferId)
 
 	total, err := u.repo.GetLikeStat(ctx, like)
 	if err != nil {
@@ -402,24 +578,22 @@ func (u *offerUsecase) LikeOffer(ctx context.Context, like domain.LikeRequest) (
 	return likeStat, nil
 }
 
-func (u *offerUsecase) GetFavorites(ctx context.Context, userID int, offerTypeID *int) ([]domain.OfferInfo, error) {
+func (u *offerUsecase) GetFavorites(ctx context.Context, userID int, offerTypeID *int, collectionID *int, page domain.Pagination) (domain.OffersPage, error) {
 	requestID := ctx.Value(utils.RequestIDKey)
 
-	rawOffers, err := u.repo.GetFavorites(ctx, int64(userID), offerTypeID)
+	rawOffers, total, err := u.repo.GetFavorites(ctx, int64(userID), offerTypeID, collectionID, nil, page)
 	if err != nil {
 		u.logger.WithFields(logger.LoggerFields{"requestID": requestID, "userID": userID, "err": err.Error()}).Error("Offer usecase: get favorites failed")
-		return nil, err
+		return domain.OffersPage{}, err
 	}
 
-	offersDTO := mapOffers(rawOffers)
-
-	offersInfo, err := u.PrepareOffersInfo(ctx, offersDTO, &userID)
+	offersPage, err := u.preparePage(ctx, rawOffers, total, page, domain.SortNewest, &userID)
 	if err != nil {
 		u.logger.WithFields(logger.LoggerFields{"requestID": requestID, "err": err.Error()}).Error("Offer usecase: prepare favorites failed")
-		return nil, err
+		return domain.OffersPage{}, err
 	}
 
-	return offersInfo, nil
+	return offersPage, nil
 }
 
 func (u *offerUsecase) FavoriteOffer(ctx context.Context, req domain.FavoriteRequest) (domain.FavoriteStat, error) {
@@ -448,6 +622,7 @@ func (u *offerUsecase) FavoriteOffer(ctx context.Context, req domain.FavoriteReq
 		}).Error("Offer usecase: toggle favorite failed")
 		return stat, err
 	}
+	u.invalidateOffer(ctx, req.OfferId)
 
 	total, err := u.repo.GetFavoriteStat(ctx, req)
 	if err != nil {
@@ -527,75 +702,273 @@ func (u *offerUsecase) CheckPayment(ctx context.Context, paymentId int) (*domain
 }
 
 func (u *offerUsecase) PrepareOfferInfo(ctx context.Context, offer domain.Offer, userID *int) (domain.OfferInfo, error) {
+	offersInfo, err := u.PrepareOffersInfo(ctx, []domain.Offer{offer}, userID)
+	if err != nil {
+		return domain.OfferInfo{}, err
+	}
+	return offersInfo[0], nil
+}
+
+// PrepareOffersInfo Дополняет объявления фотографиями, статистикой, продавцом и историей цен.
+// Общая для всех пользователей часть карточек берется из кеша, недостающие карточки грузятся
+// пачкой на всю страницу. Отметки пользователя читаются одним запросом на каждый вызов
+func (u *offerUsecase) PrepareOffersInfo(ctx context.Context, offers []domain.Offer, userID *int) ([]domain.OfferInfo, error) {
 	requestID := ctx.Value(utils.RequestIDKey)
 
-	offerData, err := u.repo.GetOfferData(ctx, offer, userID)
+	if len(offers) == 0 {
+		return []domain.OfferInfo{}, nil
+	}
+
+	offerIDs := make([]int, 0, len(offers))
+	for _, offer := range offers {
+		offerIDs = append(offerIDs, offer.ID)
+	}
+
+	cards, err := u.getOfferCards(ctx, offers)
 	if err != nil {
-		u.logger.WithFields(logger.LoggerFields{"requestID": requestID, "err": err.Error(), "offer_id": offer.ID}).Error("Offer usecase: get offer data failed")
-		return domain.OfferInfo{}, fmt.Errorf("offer data get failed")
+		u.logger.WithFields(logger.LoggerFields{"requestID": requestID, "err": err.Error(), "offer_ids": offerIDs}).Error("Offer usecase: get offers data failed")
+		return []domain.OfferInfo{}, fmt.Errorf("offer data get failed")
+	}
+
+	var userStats map[int]domain.OfferUserStat
+	if userID != nil {
+		userStats, err = u.repo.GetOffersUserStat(ctx, offerIDs, *userID)
+		if err != nil {
+			u.logger.WithFields(logger.LoggerFields{"requestID": requestID, "err": err.Error(), "offer_ids": offerIDs}).Error("Offer usecase: get offers user stat failed")
+			return []domain.OfferInfo{}, fmt.Errorf("offer data get failed")
+		}
+	}
+
+	offersInfo := make([]domain.OfferInfo, 0, len(offers))
+	for _, offer := range offers {
+		offerData := cards[offer.ID]
+
+		// Станция отдается в том же виде, что и раньше: в station попадает ее id, остальные поля пустые
+		if offer.MetroStationID != nil {
+			offerData.Metro.Station = strconv.Itoa(*offer.MetroStationID)
+		}
+
+		if stat, ok := userStats[offer.ID]; ok {
+			offerData.OfferStat.LikesStat.IsLiked = stat.IsLiked
+			offerData.OfferStat.FavoriteStat.IsFavorited = stat.IsFavorited
+			offerData.OfferStat.FavoriteStat.Note = stat.Note
+		}
+
+		offerData.Promotion = nil
+		if userID != nil && *userID == offer.SellerID {
+			offerData.Promotion = &domain.OfferPromotion{
+				IsPromoted:    offer.PromotesUntil != nil && offer.PromotesUntil.After(time.Now()),
+				PromotedUntil: offer.PromotesUntil,
+			}
+		}
+
+		offersInfo = append(offersInfo, domain.OfferInfo{
+			Offer:     offer,
+			OfferData: offerData,
+		})
 	}
+	return offersInfo, nil
+}
 
-	offerData.Promotion = nil
-	if userID != nil && *userID == offer.SellerID {
-		offerData.Promotion = &domain.OfferPromotion{
-			IsPromoted:    offer.PromotesUntil != nil && offer.PromotesUntil.After(time.Now()),
-			PromotedUntil: offer.PromotesUntil,
+// getOfferCards Карточки объявлений без отметок пользователя: фотографии, продавец, счетчики
+// и история цен. Найденные в кеше карточки не перечитываются
+func (u *offerUsecase) getOfferCards(ctx context.Context, offers []domain.Offer) (map[int]domain.OfferData, error) {
+	ttl := u.cfg.App.Cache.OfferCards
+	cards := make(map[int]domain.OfferData, len(offers))
+
+	missing := offers
+	if ttl > 0 {
+		keys := make([]string, 0, len(offers))
+		for _, offer := range offers {
+			keys = append(keys, cache.OfferCardKey(offer.ID))
+		}
+		cached := u.cache.MGet(ctx, cache.OfferCards, keys)
+
+		missing = make([]domain.Offer, 0, len(offers)-len(cached))
+		for _, offer := range offers {
+			var card domain.OfferData
+			if data, ok := cached[cache.OfferCardKey(offer.ID)]; ok && card.UnmarshalJSON([]byte(data)) == nil {
+				cards[offer.ID] = card
+				continue
+			}
+			missing = append(missing, offer)
 		}
 	}
-	offerData.PromotionScore = float32(offerData.OfferStat.LikesStat.Amount) * u.cfg.App.Promotion.LikeScore
-	if offer.PromotesUntil != nil && offer.PromotesUntil.After(time.Now()) {
-		offerData.PromotionScore += u.cfg.App.Promotion.PromotionScore
+	if len(missing) == 0 {
+		return cards, nil
 	}
 
-	seller, err := u.authService.GetUserById(ctx, &authpb.GetUserRequest{Id: int32(offer.SellerID)})
+	loaded, complete, err := u.loadOfferCards(ctx, missing)
 	if err != nil {
-		u.logger.WithFields(logger.LoggerFields{"requestID": requestID, "err": err.Error()}).Warn("Offer usecase: get seller failed")
+		return nil, err
 	}
+	for id, card := range loaded {
+		cards[id] = card
+		// Карточку без продавца или истории цен не кешируем, чтобы не закрепить сбой
+		if ttl > 0 && complete {
+			u.cache.Set(ctx, cache.OfferCardKey(id), card, ttl)
+		}
+	}
+	return cards, nil
+}
 
-	offerData.Seller = domain.OfferSeller{
-		FirstName: seller.User.FirstName,
-		LastName:  seller.User.LastName,
-		Avatar:    seller.User.Image,
-		CreatedAt: seller.User.CreatedAt.AsTime(),
+// loadOfferCards Грузит карточки пачкой: два запроса за фотографиями и счетчиками, один
+// за историей цен и один вызов сервиса авторизации за продавцами. complete равен false,
+// если продавцов или историю цен получить не удалось и карточки собраны без них
+func (u *offerUsecase) loadOfferCards(ctx context.Context, offers []domain.Offer) (map[int]domain.OfferData, bool, error) {
+	requestID := ctx.Value(utils.RequestIDKey)
+
+	offerIDs := make([]int, 0, len(offers))
+	sellerIDs := make([]int32, 0, len(offers))
+	seenSellers := make(map[int]bool, len(offers))
+	for _, offer := range offers {
+		offerIDs = append(offerIDs, offer.ID)
+		if !seenSellers[offer.SellerID] {
+			seenSellers[offer.SellerID] = true
+			sellerIDs = append(sellerIDs, int32(offer.SellerID))
+		}
 	}
 
-	for i, img := range offerData.Images {
-		offerData.Images[i].Image = u.cfg.Minio.Path + u.cfg.Minio.OffersBucket + img.Image
+	offersData, err := u.repo.GetOffersData(ctx, offerIDs)
+	if err != nil {
+		return nil, false, err
 	}
 
-	priceHistory, err := u.repo.GetPriceHistory(ctx, int64(offer.ID), 5)
+	complete := true
+
+	sellers := make(map[int]*authpb.User, len(sellerIDs))
+	sellersResp, err := u.authService.GetUsersByIds(ctx, &authpb.GetUsersByIdsRequest{Ids: sellerIDs})
 	if err != nil {
-		u.logger.WithFields(logger.LoggerFields{
-			"requestID": requestID, "offerID": offer.ID, "err": err.Error(),
-		}).Warn("не удалось получить историю цен")
+		u.logger.WithFields(logger.LoggerFields{"requestID": requestID, "err": err.Error()}).Warn("Offer usecase: get sellers failed")
+		complete = false
 	} else {
-		offerData.Prices = priceHistory
+		for _, seller := range sellersResp.Users {
+			sellers[int(seller.Id)] = seller
+		}
 	}
 
-	offerInfo := domain.OfferInfo{
-		Offer:     offer,
-		OfferData: offerData,
+	priceHistories, historyErr := u.repo.GetPriceHistories(ctx, offerIDs, 5)
+	if historyErr != nil {
+		u.logger.WithFields(logger.LoggerFields{
+			"requestID": requestID, "offer_ids": offerIDs, "err": historyErr.Error(),
+		}).Warn("не удалось получить историю цен")
+		complete = false
 	}
 
-	return offerInfo, nil
+	cards := make(map[int]domain.OfferData, len(offers))
+	for _, offer := range offers {
+		// Повтор объявления на странице не должен второй раз дописывать путь к фотографиям
+		if _, ok := cards[offer.ID]; ok {
+			continue
+		}
+		offerData := offersData[offer.ID]
+
+		seller := sellers[offer.SellerID]
+		offerData.Seller = domain.OfferSeller{
+			FirstName: seller.GetFirstName(),
+			LastName:  seller.GetLastName(),
+			Avatar:    seller.GetImage(),
+			CreatedAt: seller.GetCreatedAt().AsTime(),
+		}
+
+		for i, img := range offerData.Images {
+			offerData.Images[i].Image = u.cfg.Minio.Path + u.cfg.Minio.OffersBucket + img.Image
+			for width, name := range img.Variants {
+				img.Variants[width] = u.cfg.Minio.Path + u.cfg.Minio.OffersBucket + name
+			}
+		}
+
+		if historyErr == nil {
+			priceHistory := priceHistories[offer.ID]
+			offerData.Prices = priceHistory
+			offerData.PriceReducedSince = domain.PriceReducedSince(priceHistory)
+			offerData.PriceDrop = offerData.PriceReducedSince != nil
+		}
+
+		cards[offer.ID] = offerData
+	}
+	return cards, complete, nil
 }
 
-func (u *offerUsecase) PrepareOffersInfo(ctx context.Context, offers []domain.Offer, userID *int) ([]domain.OfferInfo, error) {
-	requestID := ctx.Value(utils.RequestIDKey)
+// preparePage Собирает страницу ленты из выборки репозитория.
+// Репозиторий отдаёт на одну запись больше лимита: если она пришла, значит есть следующая страница.
+// Курсор берётся по последней записи в порядке выборки из БД, до закрепления
+// продвигаемых объявлений, поэтому порядок внутри страницы на него не влияет
+func (u *offerUsecase) preparePage(ctx context.Context, raw []repository.Offer, total int, page domain.Pagination, sortBy domain.OfferSort, userID *int) (domain.OffersPage, error) {
+	var nextCursor *string
+	if len(raw) > page.Limit {
+		raw = raw[:page.Limit]
 
-	offersInfo := make([]domain.OfferInfo, 0, len(offers))
-	for _, offer := range offers {
-		offerInfo, err := u.PrepareOfferInfo(ctx, offer, userID)
+		cursor, err := domain.EncodeCursor(pageCursor(raw[len(raw)-1], sortBy))
 		if err != nil {
-			u.logger.WithFields(logger.LoggerFields{"requestID": requestID, "err": err.Error(), "offerID": offer.ID}).Error("Offer usecase: prepareOffersInfo failed")
-			return []domain.OfferInfo{}, err
+			return domain.OffersPage{}, err
 		}
-		offersInfo = append(offersInfo, offerInfo)
+		nextCursor = &cursor
 	}
-	sort.Slice(offersInfo, func(i, j int) bool {
-		return offersInfo[i].OfferData.PromotionScore > offersInfo[j].OfferData.PromotionScore
-	})
-	return offersInfo, nil
+
+	offersInfo, err := u.PrepareOffersInfo(ctx, mapOffers(raw), userID)
+	if err != nil {
+		return domain.OffersPage{}, err
+	}
+	for i, o := range raw {
+		if o.DescriptionHighlight != nil || o.AddressHighlight != nil {
+			offersInfo[i].Highlight = &domain.OfferHighlight{Description: o.DescriptionHighlight, Address: o.AddressHighlight}
+		}
+	}
+
+	return domain.OffersPage{
+		Offers:     u.pinPromoted(offersInfo),
+		NextCursor: nextCursor,
+		Total:      total,
+	}, nil
+}
+
+// pageCursor Курсор на запись, после которой продолжится выдача при заданной сортировке.
+// Значение ключа считается так же, как в SQL выражении сортировки репозитория
+func pageCursor(o repository.Offer, sortBy domain.OfferSort) domain.Cursor {
+	cursor := domain.Cursor{ID: int(o.ID)}
+
+	var value float64
+	switch sortBy.OrDefault() {
+	case domain.SortPriceAsc, domain.SortPriceDesc:
+		value = float64(o.Price)
+	case domain.SortPricePerM2:
+		value = float64(o.Price) / float64(max(o.Area, 1))
+	case domain.SortAreaDesc:
+		value = float64(o.Area)
+	case domain.SortRelevance:
+		if o.Rank != nil {
+			value = *o.Rank
+		}
+	default:
+		return cursor
+	}
+
+	cursor.Sort = sortBy
+	cursor.Value = &value
+	return cursor
+}
+
+// pinPromoted Поднимает продвигаемые объявления в начало страницы, не больше PinPerPage штук.
+// Остальные объявления сохраняют порядок выбранной сортировки
+func (u *offerUsecase) pinPromoted(offers []domain.OfferInfo) []domain.OfferInfo {
+	limit := u.cfg.App.Promotion.PinPerPage
+	if limit <= 0 {
+		return offers
+	}
+
+	now := time.Now()
+	pinned := make([]domain.OfferInfo, 0, len(offers))
+	rest := make([]domain.OfferInfo, 0, len(offers))
+	for _, o := range offers {
+		if len(pinned) < limit && o.Offer.PromotesUntil != nil && o.Offer.PromotesUntil.After(now) {
+			pinned = append(pinned, o)
+			continue
+		}
+		rest = append(rest, o)
+	}
+
+	return append(pinned, rest...)
 }
 
 func (u *offerUsecase) CheckAccessToOffer(ctx context.Context, offerID int, userID int) error {
@@ -635,7 +1008,16 @@ func mapOffer(o repository.Offer) domain.Offer {
 		CreatedAt:      o.CreatedAt,
 		UpdatedAt:      o.UpdatedAt,
 		PromotesUntil:  o.PromotesUntil,
+		Distance:       roundDistance(o.Distance),
+	}
+}
+
+func roundDistance(distance *float64) *int {
+	if distance == nil {
+		return nil
 	}
+	meters := int(math.Round(*distance))
+	return &meters
 }
 
 func mapOffers(raw []repository.Offer) []domain.Offer {
@@ -4,10 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/go-park-mail-ru/2025_1_404/config"
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer"
 	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/domain"
 	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/mocks"
 	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/repository"
@@ -18,12 +21,15 @@ import (
 	s3Mock "github.com/go-park-mail-ru/2025_1_404/pkg/database/s3/mocks"
 	"github.com/go-park-mail-ru/2025_1_404/pkg/logger"
 	"github.com/go-park-mail-ru/2025_1_404/pkg/utils"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/validation"
 	authpb "github.com/go-park-mail-ru/2025_1_404/proto/auth"
 	authService "github.com/go-park-mail-ru/2025_1_404/proto/auth/mocks"
 	paymentpb "github.com/go-park-mail-ru/2025_1_404/proto/payment"
 	paymentService "github.com/go-park-mail-ru/2025_1_404/proto/payment/mocks"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -52,6 +58,7 @@ func TestGetOffers(t *testing.T) {
 
 	var userID = 1
 	UserID := &userID
+	page := domain.Pagination{Limit: domain.DefaultPageLimit}
 
 	User1 := &authpb.GetUserResponse{User: &authpb.User{Id: 3, FirstName: "Ivan", LastName: "Ivanov", Image: "image1.png", CreatedAt: timestamppb.New(time.Now())}}
 	User2 := &authpb.GetUserResponse{User: &authpb.User{Id: 1, FirstName: "Maksim", LastName: "Maksimov", Image: "image2.png", CreatedAt: timestamppb.New(time.Now())}}
@@ -69,35 +76,33 @@ func TestGetOffers(t *testing.T) {
 			{ID: 2, SellerID: 1},
 		}
 
-		mockRepo.EXPECT().GetAllOffers(ctx).Return(repoOffers, nil)
-		mockRepo.EXPECT().GetOfferData(ctx, domainOffers[0], UserID).Return(domain.OfferData{
-			Images: []domain.OfferImage{{ID: 1, Image: "image1.jpg"}},
+		mockRepo.EXPECT().GetAllOffers(ctx, page).Return(repoOffers, len(repoOffers), nil)
+		mockRepo.EXPECT().GetOffersUserStat(ctx, []int{domainOffers[0].ID, domainOffers[1].ID}, *UserID).Return(map[int]domain.OfferUserStat{}, nil)
+		mockRepo.EXPECT().GetOffersData(ctx, []int{domainOffers[0].ID, domainOffers[1].ID}).Return(map[int]domain.OfferData{
+			1: {Images: []domain.OfferImage{{ID: 1, Image: "image1.jpg"}}},
+			2: {Images: []domain.OfferImage{{ID: 2, Image: "image2.jpg"}}},
 		}, nil)
 
-		mockRepo.EXPECT().GetOfferData(ctx, domainOffers[1], UserID).Return(domain.OfferData{
-			Images: []domain.OfferImage{{ID: 2, Image: "image2.jpg"}},
-		}, nil)
-
-		mockAuthService.EXPECT().GetUserById(ctx, &authpb.GetUserRequest{Id: int32(domainOffers[0].SellerID)}).Return(User1, nil)
-		mockAuthService.EXPECT().GetUserById(ctx, &authpb.GetUserRequest{Id: int32(domainOffers[1].SellerID)}).Return(User2, nil)
+		mockAuthService.EXPECT().GetUsersByIds(ctx, &authpb.GetUsersByIdsRequest{Ids: []int32{int32(domainOffers[0].SellerID), int32(domainOffers[1].SellerID)}}).
+			Return(&authpb.GetUsersByIdsResponse{Users: []*authpb.User{User1.User, User2.User}}, nil)
 
-		mockRepo.EXPECT().GetPriceHistory(ctx, int64(domainOffers[0].ID), 5).Return(History1, nil)
-		mockRepo.EXPECT().GetPriceHistory(ctx, int64(domainOffers[1].ID), 5).Return(History2, nil)
-		result, err := offerUsecase.GetOffers(ctx, UserID)
+		mockRepo.EXPECT().GetPriceHistories(ctx, []int{1, 2}, 5).
+			Return(map[int][]domain.OfferPriceHistory{1: History1, 2: History2}, nil)
+		result, err := offerUsecase.GetOffers(ctx, page, UserID)
 
 		assert.NoError(t, err)
-		assert.Len(t, result, 2)
-		assert.Equal(t, Path+Bucket+"image1.jpg", result[0].OfferData.Images[0].Image)
+		assert.Len(t, result.Offers, 2)
+		assert.Equal(t, Path+Bucket+"image1.jpg", result.Offers[0].OfferData.Images[0].Image)
 	})
 
 	t.Run("get offers from repository failed", func(t *testing.T) {
 		expectedErr := fmt.Errorf("database error")
-		mockRepo.EXPECT().GetAllOffers(ctx).Return([]repository.Offer{}, expectedErr)
+		mockRepo.EXPECT().GetAllOffers(ctx, page).Return([]repository.Offer{}, 0, expectedErr)
 
-		result, err := offerUsecase.GetOffers(ctx, UserID)
+		result, err := offerUsecase.GetOffers(ctx, page, UserID)
 
 		assert.Error(t, err)
-		assert.Nil(t, result)
+		assert.Equal(t, domain.OffersPage{}, result)
 		assert.Equal(t, expectedErr, err)
 	})
 
@@ -110,17 +115,152 @@ func TestGetOffers(t *testing.T) {
 		}
 		expectedErr := fmt.Errorf("offer data get failed")
 
-		mockRepo.EXPECT().GetAllOffers(ctx).Return(repoOffers, nil)
-		mockRepo.EXPECT().GetOfferData(ctx, domainOffers[0], UserID).Return(domain.OfferData{}, expectedErr)
+		mockRepo.EXPECT().GetAllOffers(ctx, page).Return(repoOffers, len(repoOffers), nil)
+		mockRepo.EXPECT().GetOffersData(ctx, []int{domainOffers[0].ID}).Return(nil, expectedErr)
 
-		result, err := offerUsecase.GetOffers(ctx, UserID)
+		result, err := offerUsecase.GetOffers(ctx, page, UserID)
 
 		assert.Error(t, err)
-		assert.Equal(t, []domain.OfferInfo{}, result)
+		assert.Equal(t, domain.OffersPage{}, result)
 		assert.Equal(t, expectedErr, err)
 	})
 }
 
+func TestGetOffersPagination(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOfferRepository(ctrl)
+	mockLogger := logger.NewStub()
+	mockS3 := s3Mock.NewMockS3Repo(ctrl)
+	cfg := &config.Config{
+		Minio: config.MinioConfig{Path: Path, OffersBucket: Bucket},
+		App:   config.AppConfig{Promotion: config.PromotionConfig{PinPerPage: 1}},
+	}
+	mockYa := yaMock.NewMockYandexRepo(ctrl)
+	mockAuthService := authService.NewMockAuthServiceClient(ctrl)
+	mockPaymentService := paymentService.NewMockPaymentServiceClient(ctrl)
+	mockRedis := redisMock.NewMockRedisRepo(ctrl)
+
+	offerUsecase := NewOfferUsecase(mockRepo, mockLogger, mockS3, cfg, mockAuthService, mockPaymentService, mockRedis, mockYa)
+	ctx := context.WithValue(context.Background(), utils.RequestIDKey, "test-request-id")
+
+	User := &authpb.GetUserResponse{User: &authpb.User{Id: 1, FirstName: "Ivan", LastName: "Ivanov", CreatedAt: timestamppb.New(time.Now())}}
+
+	t.Run("next cursor is taken from the last row before promotion pin", func(t *testing.T) {
+		page := domain.Pagination{Limit: 3}
+		promotesUntil := time.Now().Add(time.Hour)
+		// Репозиторий отдаёт limit+1 записей в порядке id DESC
+		repoOffers := []repository.Offer{{ID: 40}, {ID: 30, PromotesUntil: &promotesUntil}, {ID: 20, PromotesUntil: &promotesUntil}, {ID: 10}}
+
+		mockRepo.EXPECT().GetAllOffers(ctx, page).Return(repoOffers, 5, nil)
+		mockRepo.EXPECT().GetOffersData(ctx, []int{40, 30, 20}).Return(map[int]domain.OfferData{}, nil)
+		mockAuthService.EXPECT().GetUsersByIds(ctx, &authpb.GetUsersByIdsRequest{Ids: []int32{0}}).
+			Return(&authpb.GetUsersByIdsResponse{Users: []*authpb.User{User.User}}, nil)
+		mockRepo.EXPECT().GetPriceHistories(ctx, []int{40, 30, 20}, 5).Return(nil, nil)
+
+		result, err := offerUsecase.GetOffers(ctx, page, nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 5, result.Total)
+		assert.Len(t, result.Offers, 3)
+		// Закрепляется только одно продвигаемое объявление, остальные идут в порядке выборки
+		assert.Equal(t, 30, result.Offers[0].Offer.ID)
+		assert.Equal(t, 40, result.Offers[1].Offer.ID)
+		assert.Equal(t, 20, result.Offers[2].Offer.ID)
+		if assert.NotNil(t, result.NextCursor) {
+			cursor, err := domain.DecodeCursor(*result.NextCursor)
+			assert.NoError(t, err)
+			assert.Equal(t, 20, cursor.ID)
+		}
+	})
+
+	t.Run("last page has no next cursor", func(t *testing.T) {
+		page := domain.Pagination{Limit: 2, Cursor: &domain.Cursor{ID: 20}}
+
+		mockRepo.EXPECT().GetAllOffers(ctx, page).Return([]repository.Offer{{ID: 10}}, 3, nil)
+		mockRepo.EXPECT().GetOffersData(ctx, []int{10}).Return(map[int]domain.OfferData{}, nil)
+		mockAuthService.EXPECT().GetUsersByIds(ctx, gomock.Any()).Return(&authpb.GetUsersByIdsResponse{Users: []*authpb.User{User.User}}, nil)
+		mockRepo.EXPECT().GetPriceHistories(ctx, []int{10}, 5).Return(nil, nil)
+
+		result, err := offerUsecase.GetOffers(ctx, page, nil)
+
+		assert.NoError(t, err)
+		assert.Len(t, result.Offers, 1)
+		assert.Nil(t, result.NextCursor)
+	})
+
+	t.Run("cursor keeps sort key", func(t *testing.T) {
+		filter := domain.OfferFilter{Sort: domain.SortPricePerM2}
+		page := domain.Pagination{Limit: 1}
+
+		mockRepo.EXPECT().GetOffersByFilter(ctx, filter, page, nil).Return([]repository.Offer{
+			{ID: 8, Price: 900000, Area: 30},
+			{ID: 9, Price: 1000000, Area: 30},
+		}, 2, nil)
+		mockRepo.EXPECT().GetOffersData(ctx, []int{8}).Return(map[int]domain.OfferData{}, nil)
+		mockAuthService.EXPECT().GetUsersByIds(ctx, gomock.Any()).Return(&authpb.GetUsersByIdsResponse{Users: []*authpb.User{User.User}}, nil)
+		mockRepo.EXPECT().GetPriceHistories(ctx, []int{8}, 5).Return(nil, nil)
+
+		result, err := offerUsecase.GetOffersByFilter(ctx, filter, page, nil)
+
+		assert.NoError(t, err)
+		if assert.NotNil(t, result.NextCursor) {
+			cursor, err := domain.DecodeCursor(*result.NextCursor)
+			assert.NoError(t, err)
+			assert.Equal(t, 8, cursor.ID)
+			assert.Equal(t, domain.SortPricePerM2, cursor.Sort)
+			if assert.NotNil(t, cursor.Value) {
+				assert.InDelta(t, 30000.0, *cursor.Value, 0.001)
+			}
+		}
+	})
+
+	t.Run("search results carry highlight and rank cursor", func(t *testing.T) {
+		query := "студия"
+		filter := domain.OfferFilter{Query: &query, Sort: domain.SortRelevance}
+		page := domain.Pagination{Limit: 1}
+		rank := 0.25
+		highlight := "<mark>Студия</mark> у парка"
+
+		mockRepo.EXPECT().GetOffersByFilter(ctx, filter, page, nil).Return([]repository.Offer{
+			{ID: 3, Rank: &rank, DescriptionHighlight: &highlight},
+			{ID: 1},
+		}, 2, nil)
+		mockRepo.EXPECT().GetOffersData(ctx, []int{3}).Return(map[int]domain.OfferData{}, nil)
+		mockAuthService.EXPECT().GetUsersByIds(ctx, gomock.Any()).Return(&authpb.GetUsersByIdsResponse{Users: []*authpb.User{User.User}}, nil)
+		mockRepo.EXPECT().GetPriceHistories(ctx, []int{3}, 5).Return(nil, nil)
+
+		result, err := offerUsecase.GetOffersByFilter(ctx, filter, page, nil)
+
+		assert.NoError(t, err)
+		if assert.NotNil(t, result.Offers[0].Highlight) {
+			assert.Equal(t, &highlight, result.Offers[0].Highlight.Description)
+		}
+		cursor, err := domain.DecodeCursor(*result.NextCursor)
+		assert.NoError(t, err)
+		assert.Equal(t, domain.SortRelevance, cursor.Sort)
+		assert.Equal(t, rank, *cursor.Value)
+	})
+
+	t.Run("favorites are paginated", func(t *testing.T) {
+		userID := 7
+		page := domain.Pagination{Limit: 1}
+
+		mockRepo.EXPECT().GetFavorites(ctx, int64(userID), nil, nil, nil, page).Return([]repository.Offer{{ID: 4}, {ID: 2}}, 2, nil)
+		mockRepo.EXPECT().GetOffersUserStat(ctx, []int{4}, userID).Return(map[int]domain.OfferUserStat{}, nil)
+		mockRepo.EXPECT().GetOffersData(ctx, []int{4}).Return(map[int]domain.OfferData{}, nil)
+		mockAuthService.EXPECT().GetUsersByIds(ctx, gomock.Any()).Return(&authpb.GetUsersByIdsResponse{Users: []*authpb.User{User.User}}, nil)
+		mockRepo.EXPECT().GetPriceHistories(ctx, []int{4}, 5).Return(nil, nil)
+
+		result, err := offerUsecase.GetFavorites(ctx, userID, nil, nil, page)
+
+		assert.NoError(t, err)
+		assert.Len(t, result.Offers, 1)
+		assert.NotNil(t, result.NextCursor)
+	})
+}
+
 func TestGetOffersByFilter(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -141,6 +281,7 @@ func TestGetOffersByFilter(t *testing.T) {
 
 	var userID = 1
 	UserID := &userID
+	page := domain.Pagination{Limit: domain.DefaultPageLimit}
 
 	User1 := &authpb.GetUserResponse{User: &authpb.User{Id: 3, FirstName: "Ivan", LastName: "Ivanov", Image: "image1.png", CreatedAt: timestamppb.New(time.Now())}}
 	User2 := &authpb.GetUserResponse{User: &authpb.User{Id: 1, FirstName: "Maksim", LastName: "Maksimov", Image: "image2.png", CreatedAt: timestamppb.New(time.Now())}}
@@ -164,37 +305,35 @@ func TestGetOffersByFilter(t *testing.T) {
 			{ID: 2, Price: 10000000},
 		}
 
-		mockRepo.EXPECT().GetOffersByFilter(ctx, filter, UserID).Return(repoOffers, nil)
-		mockRepo.EXPECT().GetOfferData(ctx, domainOffers[0], UserID).Return(domain.OfferData{
-			Images: []domain.OfferImage{{ID: 1, Image: "image1.jpg"}},
+		mockRepo.EXPECT().GetOffersByFilter(ctx, filter, page, UserID).Return(repoOffers, len(repoOffers), nil)
+		mockRepo.EXPECT().GetOffersUserStat(ctx, []int{domainOffers[0].ID, domainOffers[1].ID}, *UserID).Return(map[int]domain.OfferUserStat{}, nil)
+		mockRepo.EXPECT().GetOffersData(ctx, []int{domainOffers[0].ID, domainOffers[1].ID}).Return(map[int]domain.OfferData{
+			1: {Images: []domain.OfferImage{{ID: 1, Image: "image1.jpg"}}},
+			2: {Images: []domain.OfferImage{{ID: 2, Image: "image2.jpg"}}},
 		}, nil)
 
-		mockRepo.EXPECT().GetOfferData(ctx, domainOffers[1], UserID).Return(domain.OfferData{
-			Images: []domain.OfferImage{{ID: 2, Image: "image2.jpg"}},
-		}, nil)
-
-		mockAuthService.EXPECT().GetUserById(ctx, &authpb.GetUserRequest{Id: int32(domainOffers[0].SellerID)}).Return(User1, nil)
-		mockAuthService.EXPECT().GetUserById(ctx, &authpb.GetUserRequest{Id: int32(domainOffers[1].SellerID)}).Return(User2, nil)
+		mockAuthService.EXPECT().GetUsersByIds(ctx, &authpb.GetUsersByIdsRequest{Ids: []int32{int32(domainOffers[0].SellerID)}}).
+			Return(&authpb.GetUsersByIdsResponse{Users: []*authpb.User{User1.User, User2.User}}, nil)
 
-		mockRepo.EXPECT().GetPriceHistory(ctx, int64(domainOffers[0].ID), 5).Return(History1, nil)
-		mockRepo.EXPECT().GetPriceHistory(ctx, int64(domainOffers[1].ID), 5).Return(History2, nil)
+		mockRepo.EXPECT().GetPriceHistories(ctx, []int{1, 2}, 5).
+			Return(map[int][]domain.OfferPriceHistory{1: History1, 2: History2}, nil)
 
-		result, err := offerUsecase.GetOffersByFilter(ctx, filter, UserID)
+		result, err := offerUsecase.GetOffersByFilter(ctx, filter, page, UserID)
 
 		assert.NoError(t, err)
-		assert.Len(t, result, 2)
-		assert.Equal(t, Path+Bucket+"image1.jpg", result[0].OfferData.Images[0].Image)
+		assert.Len(t, result.Offers, 2)
+		assert.Equal(t, Path+Bucket+"image1.jpg", result.Offers[0].OfferData.Images[0].Image)
 	})
 
 	t.Run("filter error from repository", func(t *testing.T) {
 		expectedErr := fmt.Errorf("database error")
 
-		mockRepo.EXPECT().GetOffersByFilter(ctx, filter, UserID).Return(nil, expectedErr)
+		mockRepo.EXPECT().GetOffersByFilter(ctx, filter, page, UserID).Return(nil, 0, expectedErr)
 
-		result, err := offerUsecase.GetOffersByFilter(ctx, filter, UserID)
+		result, err := offerUsecase.GetOffersByFilter(ctx, filter, page, UserID)
 
 		assert.Error(t, err)
-		assert.Nil(t, result)
+		assert.Equal(t, domain.OffersPage{}, result)
 		assert.Equal(t, expectedErr, err)
 	})
 
@@ -207,13 +346,13 @@ func TestGetOffersByFilter(t *testing.T) {
 		}
 		expectedErr := fmt.Errorf("offer data get failed")
 
-		mockRepo.EXPECT().GetOffersByFilter(ctx, filter, UserID).Return(repoOffers, nil)
-		mockRepo.EXPECT().GetOfferData(ctx, domainOffers[0], UserID).Return(domain.OfferData{}, expectedErr)
+		mockRepo.EXPECT().GetOffersByFilter(ctx, filter, page, UserID).Return(repoOffers, len(repoOffers), nil)
+		mockRepo.EXPECT().GetOffersData(ctx, []int{domainOffers[0].ID}).Return(nil, expectedErr)
 
-		result, err := offerUsecase.GetOffersByFilter(ctx, filter, UserID)
+		result, err := offerUsecase.GetOffersByFilter(ctx, filter, page, UserID)
 
 		assert.Error(t, err)
-		assert.Equal(t, []domain.OfferInfo{}, result)
+		assert.Equal(t, domain.OffersPage{}, result)
 		assert.Equal(t, expectedErr, err)
 	})
 }
@@ -264,9 +403,11 @@ func TestGetOfferByID(t *testing.T) {
 		}
 
 		mockRepo.EXPECT().GetOfferByID(ctx, int64(testID)).Return(repoOffer, nil)
-		mockRepo.EXPECT().GetOfferData(ctx, domainOffer, UserID).Return(expectedOfferData, nil)
-		mockAuthService.EXPECT().GetUserById(ctx, &authpb.GetUserRequest{Id: int32(domainOffer.SellerID)}).Return(User1, nil)
-		mockRepo.EXPECT().GetPriceHistory(ctx, int64(domainOffer.ID), 5).Return(History1, nil)
+		mockRepo.EXPECT().GetOffersUserStat(ctx, []int{domainOffer.ID}, *UserID).Return(map[int]domain.OfferUserStat{}, nil)
+		mockRepo.EXPECT().GetOffersData(ctx, []int{domainOffer.ID}).Return(map[int]domain.OfferData{domainOffer.ID: expectedOfferData}, nil)
+		mockAuthService.EXPECT().GetUsersByIds(ctx, &authpb.GetUsersByIdsRequest{Ids: []int32{int32(domainOffer.SellerID)}}).
+			Return(&authpb.GetUsersByIdsResponse{Users: []*authpb.User{User1.User}}, nil)
+		mockRepo.EXPECT().GetPriceHistories(ctx, []int{domainOffer.ID}, 5).Return(map[int][]domain.OfferPriceHistory{domainOffer.ID: History1}, nil)
 		mockRedis.EXPECT().Get(ctx, key).Return("", fmt.Errorf("some error"))
 		mockRedis.EXPECT().IsNotFound(fmt.Errorf("some error")).Return(true)
 		mockRedis.EXPECT().Set(ctx, key, "1", 10*time.Minute).Return(nil)
@@ -276,6 +417,8 @@ func TestGetOfferByID(t *testing.T) {
 
 		assert.NoError(t, err)
 		assert.Equal(t, Path+Bucket+"image1.jpg", result.OfferData.Images[0].Image)
+		assert.True(t, result.OfferData.PriceDrop)
+		assert.Equal(t, History1[0].Date, *result.OfferData.PriceReducedSince)
 	})
 
 	t.Run("offer not found", func(t *testing.T) {
@@ -297,7 +440,7 @@ func TestGetOfferByID(t *testing.T) {
 		expectedErr := fmt.Errorf("offer data get failed")
 
 		mockRepo.EXPECT().GetOfferByID(ctx, int64(testID)).Return(repoOffer, nil)
-		mockRepo.EXPECT().GetOfferData(ctx, gomock.Any(), UserID).Return(domain.OfferData{}, expectedErr)
+		mockRepo.EXPECT().GetOffersData(ctx, []int{testID}).Return(nil, expectedErr)
 		mockRedis.EXPECT().Get(ctx, key).Return("", nil)
 
 		result, err := offerUsecase.GetOfferByID(ctx, testID, IP, UserID)
@@ -328,6 +471,7 @@ func TestGetOffersBySellerID(t *testing.T) {
 
 	var userID = 1
 	UserID := &userID
+	page := domain.Pagination{Limit: domain.DefaultPageLimit}
 
 	User1 := &authpb.GetUserResponse{User: &authpb.User{Id: 3, FirstName: "Ivan", LastName: "Ivanov", Image: "image1.png", CreatedAt: timestamppb.New(time.Now())}}
 	User2 := &authpb.GetUserResponse{User: &authpb.User{Id: 1, FirstName: "Maksim", LastName: "Maksimov", Image: "image2.png", CreatedAt: timestamppb.New(time.Now())}}
@@ -347,48 +491,46 @@ func TestGetOffersBySellerID(t *testing.T) {
 			{ID: 2, SellerID: 1},
 		}
 
-		mockRepo.EXPECT().GetOffersBySellerID(ctx, int64(sellerID)).Return(repoOffers, nil)
-		mockRepo.EXPECT().GetOfferData(ctx, domainOffers[0], UserID).Return(domain.OfferData{
-			Images: []domain.OfferImage{{ID: 1, Image: "image1.jpg"}},
-		}, nil)
-
-		mockRepo.EXPECT().GetOfferData(ctx, domainOffers[1], UserID).Return(domain.OfferData{
-			Images: []domain.OfferImage{{ID: 2, Image: "image2.jpg"}},
+		mockRepo.EXPECT().GetOffersBySellerID(ctx, int64(sellerID), page).Return(repoOffers, len(repoOffers), nil)
+		mockRepo.EXPECT().GetOffersUserStat(ctx, []int{domainOffers[0].ID, domainOffers[1].ID}, *UserID).Return(map[int]domain.OfferUserStat{}, nil)
+		mockRepo.EXPECT().GetOffersData(ctx, []int{domainOffers[0].ID, domainOffers[1].ID}).Return(map[int]domain.OfferData{
+			1: {Images: []domain.OfferImage{{ID: 1, Image: "image1.jpg"}}},
+			2: {Images: []domain.OfferImage{{ID: 2, Image: "image2.jpg"}}},
 		}, nil)
 
-		mockAuthService.EXPECT().GetUserById(ctx, &authpb.GetUserRequest{Id: int32(domainOffers[0].SellerID)}).Return(User1, nil)
-		mockAuthService.EXPECT().GetUserById(ctx, &authpb.GetUserRequest{Id: int32(domainOffers[1].SellerID)}).Return(User2, nil)
+		mockAuthService.EXPECT().GetUsersByIds(ctx, &authpb.GetUsersByIdsRequest{Ids: []int32{int32(domainOffers[0].SellerID), int32(domainOffers[1].SellerID)}}).
+			Return(&authpb.GetUsersByIdsResponse{Users: []*authpb.User{User1.User, User2.User}}, nil)
 
-		mockRepo.EXPECT().GetPriceHistory(ctx, int64(domainOffers[0].ID), 5).Return(History1, nil)
-		mockRepo.EXPECT().GetPriceHistory(ctx, int64(domainOffers[1].ID), 5).Return(History2, nil)
+		mockRepo.EXPECT().GetPriceHistories(ctx, []int{1, 2}, 5).
+			Return(map[int][]domain.OfferPriceHistory{1: History1, 2: History2}, nil)
 
-		result, err := offerUsecase.GetOffersBySellerID(ctx, sellerID, UserID)
+		result, err := offerUsecase.GetOffersBySellerID(ctx, sellerID, page, UserID)
 		assert.NoError(t, err)
-		assert.Equal(t, Path+Bucket+"image1.jpg", result[0].OfferData.Images[0].Image)
-		assert.Len(t, result, 2)
+		assert.Equal(t, Path+Bucket+"image1.jpg", result.Offers[0].OfferData.Images[0].Image)
+		assert.Len(t, result.Offers, 2)
 
 	})
 
 	t.Run("no offers found for seller", func(t *testing.T) {
 		// Репозиторий возвращает пустой список
-		mockRepo.EXPECT().GetOffersBySellerID(ctx, int64(sellerID)).Return([]repository.Offer{}, nil)
+		mockRepo.EXPECT().GetOffersBySellerID(ctx, int64(sellerID), page).Return([]repository.Offer{}, 0, nil)
 
-		result, err := offerUsecase.GetOffersBySellerID(ctx, sellerID, UserID)
+		result, err := offerUsecase.GetOffersBySellerID(ctx, sellerID, page, UserID)
 
 		// Проверяем результаты
 		assert.NoError(t, err)
-		assert.Empty(t, result)
+		assert.Empty(t, result.Offers)
 	})
 
 	t.Run("repository error", func(t *testing.T) {
 		expectedErr := fmt.Errorf("database error")
 
-		mockRepo.EXPECT().GetOffersBySellerID(ctx, int64(sellerID)).Return(nil, expectedErr)
+		mockRepo.EXPECT().GetOffersBySellerID(ctx, int64(sellerID), page).Return(nil, 0, expectedErr)
 
-		result, err := offerUsecase.GetOffersBySellerID(ctx, sellerID, UserID)
+		result, err := offerUsecase.GetOffersBySellerID(ctx, sellerID, page, UserID)
 
 		assert.Error(t, err)
-		assert.Nil(t, result)
+		assert.Equal(t, domain.OffersPage{}, result)
 		assert.Equal(t, expectedErr, err)
 	})
 
@@ -398,14 +540,14 @@ func TestGetOffersBySellerID(t *testing.T) {
 		}
 		expectedErr := fmt.Errorf("offer data get failed")
 
-		mockRepo.EXPECT().GetOffersBySellerID(ctx, int64(sellerID)).Return(repoOffers, nil)
-		mockRepo.EXPECT().GetOfferData(ctx, gomock.Any(), UserID).Return(domain.OfferData{}, expectedErr)
+		mockRepo.EXPECT().GetOffersBySellerID(ctx, int64(sellerID), page).Return(repoOffers, len(repoOffers), nil)
+		mockRepo.EXPECT().GetOffersData(ctx, []int{1}).Return(nil, expectedErr)
 
-		result, err := offerUsecase.GetOffersBySellerID(ctx, sellerID, UserID)
+		result, err := offerUsecase.GetOffersBySellerID(ctx, sellerID, page, UserID)
 
 		// Проверяем результаты
 		assert.Error(t, err)
-		assert.Equal(t, []domain.OfferInfo{}, result)
+		assert.Equal(t, domain.OffersPage{}, result)
 		assert.Equal(t, expectedErr, err)
 	})
 }
@@ -430,27 +572,38 @@ func TestCreateOffer(t *testing.T) {
 
 	description := "Описание"
 	address := "Москва, Улица Пушкина, Дом Кукушкина, квартира 1"
+	purchaseType := 2
 	testOffer := domain.Offer{
-		ID:          1,
-		Price:       100000,
-		SellerID:    123,
-		Description: &description,
-		Address:     &address,
+		ID:             1,
+		OfferTypeID:    1,
+		PurchaseTypeID: &purchaseType,
+		PropertyTypeID: 3,
+		RenovationID:   1,
+		Price:          100000,
+		SellerID:       123,
+		Description:    &description,
+		Address:        &address,
 	}
 
 	expectedRepoOffer := repository.Offer{
-		ID:          1,
-		Price:       100000,
-		SellerID:    123,
-		StatusID:    2,
-		Description: &description,
-		Address:     &address,
-		Latitude:    "123.2",
-		Longitude:   "123.1",
+		ID:             1,
+		OfferTypeID:    1,
+		PurchaseTypeID: &purchaseType,
+		PropertyTypeID: 3,
+		RenovationID:   1,
+		Price:          100000,
+		SellerID:       123,
+		StatusID:       2,
+		Description:    &description,
+		Address:        &address,
+		Latitude:       "123.2",
+		Longitude:      "123.1",
 	}
+	mockRepo.EXPECT().GetDictionaries(ctx).Return(testDictionaries, nil).AnyTimes()
 
 	t.Run("CreateOffer ok", func(t *testing.T) {
 		mockYa.EXPECT().GetCoordinatesOfAddress(*testOffer.Address).Return(&yandex.Coordinates{Latitude: 123.2, Longitude: 123.1}, nil)
+		mockRepo.EXPECT().FindDuplicateOffers(ctx, gomock.Any()).Return(nil, nil)
 		mockRepo.EXPECT().CreateOffer(ctx, expectedRepoOffer).Return(int64(1), nil)
 		mockRepo.EXPECT().AddOrUpdatePriceHistory(ctx, int64(testOffer.ID), testOffer.Price).Return(nil)
 
@@ -488,6 +641,7 @@ func TestCreateOffer(t *testing.T) {
 		expectedErr := fmt.Errorf("не удалось создать оффер")
 
 		mockYa.EXPECT().GetCoordinatesOfAddress(*testOffer.Address).Return(&yandex.Coordinates{Latitude: 123.2, Longitude: 123.1}, nil)
+		mockRepo.EXPECT().FindDuplicateOffers(ctx, gomock.Any()).Return(nil, nil)
 		mockRepo.EXPECT().CreateOffer(ctx, gomock.AssignableToTypeOf(repository.Offer{})).Return(int64(0), expectedErr)
 
 		id, err := offerUsecase.CreateOffer(ctx, testOffer)
@@ -496,6 +650,35 @@ func TestCreateOffer(t *testing.T) {
 		assert.Equal(t, 0, id)
 		assert.Equal(t, expectedErr, err)
 	})
+
+	t.Run("invalid fields", func(t *testing.T) {
+		rentType := 1
+		invalid := testOffer
+		invalid.RentTypeID = &rentType
+		invalid.Floor = 10
+		invalid.TotalFloors = 5
+		invalid.Area = 1001
+
+		_, err := offerUsecase.CreateOffer(ctx, invalid)
+
+		assert.Equal(t, validation.FieldErrors{
+			{Field: "area", Rule: "max", Message: "должно быть не больше 1000"},
+			{Field: "floor", Rule: "ltefield", Message: "должно быть не больше total_floors"},
+			{Field: "rent_type_id", Rule: "excluded_if", Message: "не указывается, если указано offer_type_id=1"},
+		}, err)
+	})
+
+	t.Run("escaped address too long", func(t *testing.T) {
+		address := strings.Repeat("&", 500)
+		invalid := testOffer
+		invalid.Address = &address
+
+		_, err := offerUsecase.CreateOffer(ctx, invalid)
+
+		assert.Equal(t, validation.FieldErrors{
+			{Field: "address", Rule: "escaped_max", Message: "должно содержать не более 512 символов, символы & < > \" ' считаются за несколько"},
+		}, err)
+	})
 }
 
 func TestDeleteOffer(t *testing.T) {
@@ -565,7 +748,7 @@ func TestSaveOfferImage(t *testing.T) {
 
 	t.Run("SaveOfferImage success", func(t *testing.T) {
 		mockS3.EXPECT().Put(ctx, file).Return(file.Filename, nil)
-		mockRepo.EXPECT().CreateImageAndBindToOffer(ctx, offerId, file.Filename).Return(int64(1), nil)
+		mockRepo.EXPECT().CreateImageAndBindToOffer(ctx, offerId, file.Filename, domain.ImageMeta{Variants: []int{}}).Return(int64(1), nil)
 
 		id, err := offerUsecase.SaveOfferImage(ctx, offerId, file)
 
@@ -605,24 +788,27 @@ func TestPublishOffer(t *testing.T) {
 	offerID := 1
 	userID := 1
 	address := "Москва, Улица Пушкина, Дом Кукушкина, квартира 1"
+	purchaseType := 2
 	repoOffer := repository.Offer{
 		ID:             int64(offerID),
 		Price:          100000,
 		SellerID:       1,
 		StatusID:       2,
 		Area:           99,
-		Floor:          123,
+		Floor:          2,
 		TotalFloors:    3,
 		Rooms:          2,
 		PropertyTypeID: 1,
 		RenovationID:   1,
 		OfferTypeID:    1,
+		PurchaseTypeID: &purchaseType,
 		Address:        &address,
 	}
 
 	t.Run("PublishOffer success", func(t *testing.T) {
 		mockRepo.EXPECT().GetOfferByID(ctx, int64(offerID)).Return(repoOffer, nil)
-		mockRepo.EXPECT().UpdateOfferStatus(ctx, offerID, 1).Return(nil)
+		mockRepo.EXPECT().FindDuplicateOffers(ctx, gomock.Any()).Return(nil, nil)
+		mockRepo.EXPECT().ChangeOfferStatus(ctx, offerID, domain.OfferStatusDraft, domain.OfferStatusActive, userID, "").Return(true, nil)
 
 		err := offerUsecase.PublishOffer(ctx, offerID, userID)
 
@@ -659,23 +845,34 @@ func TestPublishOffer(t *testing.T) {
 	})
 
 	t.Run("Incorrect field", func(t *testing.T) {
-		expectedErr := fmt.Errorf("не все обязательные поля заполнены")
+		repoOffer.StatusID = 2
+		repoOffer.SellerID = 1
 		repoOffer.OfferTypeID = 0
+		repoOffer.Floor = 5
+		repoOffer.Rooms = 0
 		mockRepo.EXPECT().GetOfferByID(ctx, int64(offerID)).Return(repoOffer, nil)
 
 		err := offerUsecase.PublishOffer(ctx, offerID, userID)
 
-		assert.Error(t, err, expectedErr)
+		var fieldErrors validation.FieldErrors
+		require.ErrorAs(t, err, &fieldErrors)
+		assert.Equal(t, validation.FieldErrors{
+			{Field: "offer_type_id", Rule: "required", Message: "обязательное поле"},
+			{Field: "floor", Rule: "ltefield", Message: "должно быть не больше total_floors"},
+			{Field: "rooms", Rule: "required", Message: "обязательное поле"},
+		}, fieldErrors)
 	})
 
 	t.Run("Empty Address", func(t *testing.T) {
-		expectedErr := fmt.Errorf("не указан адрес")
+		repoOffer.OfferTypeID = 1
+		repoOffer.Floor = 2
+		repoOffer.Rooms = 2
 		repoOffer.Address = nil
 		mockRepo.EXPECT().GetOfferByID(ctx, int64(offerID)).Return(repoOffer, nil)
 
 		err := offerUsecase.PublishOffer(ctx, offerID, userID)
 
-		assert.Error(t, err, expectedErr)
+		assert.Equal(t, validation.FieldErrors{{Field: "address", Rule: "required", Message: "обязательное поле"}}, err)
 	})
 }
 
@@ -703,10 +900,11 @@ func TestDeleteOfferImage(t *testing.T) {
 	testUUID := "test-uuid-123"
 
 	t.Run("DeleteOfferImage ok", func(t *testing.T) {
-		mockRepo.EXPECT().GetOfferImageWithUUID(ctx, int64(imageID)).Return(int64(offerID), testUUID, nil)
+		mockRepo.EXPECT().GetOfferImageWithUUID(ctx, int64(imageID)).Return(int64(offerID), testUUID, []int{320}, nil)
 		mockRepo.EXPECT().GetOfferByID(ctx, int64(offerID)).Return(repository.Offer{SellerID: int64(userID)}, nil)
 		mockRepo.EXPECT().DeleteOfferImage(ctx, int64(imageID)).Return(nil)
 		mockS3.EXPECT().Remove(ctx, "offers", testUUID).Return(nil)
+		mockS3.EXPECT().Remove(ctx, "offers", testUUID+"_320w.jpg").Return(nil)
 
 		err := offerUsecase.DeleteOfferImage(ctx, imageID, userID)
 
@@ -715,7 +913,7 @@ func TestDeleteOfferImage(t *testing.T) {
 
 	t.Run("image not found", func(t *testing.T) {
 		expectedErr := fmt.Errorf("изображение не найдено")
-		mockRepo.EXPECT().GetOfferImageWithUUID(ctx, int64(imageID)).Return(int64(offerID), testUUID, expectedErr)
+		mockRepo.EXPECT().GetOfferImageWithUUID(ctx, int64(imageID)).Return(int64(offerID), testUUID, nil, expectedErr)
 
 		err := offerUsecase.DeleteOfferImage(ctx, imageID, userID)
 
@@ -725,7 +923,7 @@ func TestDeleteOfferImage(t *testing.T) {
 
 	t.Run("offer not found", func(t *testing.T) {
 		expectedErr := fmt.Errorf("объявление не найдено")
-		mockRepo.EXPECT().GetOfferImageWithUUID(ctx, int64(imageID)).Return(int64(offerID), testUUID, nil)
+		mockRepo.EXPECT().GetOfferImageWithUUID(ctx, int64(imageID)).Return(int64(offerID), testUUID, []int{320}, nil)
 		mockRepo.EXPECT().GetOfferByID(ctx, int64(offerID)).Return(repository.Offer{SellerID: int64(userID)}, expectedErr)
 
 		err := offerUsecase.DeleteOfferImage(ctx, imageID, userID)
@@ -736,7 +934,7 @@ func TestDeleteOfferImage(t *testing.T) {
 
 	t.Run("user is not owner", func(t *testing.T) {
 		expectedErr := fmt.Errorf("нет доступа к удалению этого изображения")
-		mockRepo.EXPECT().GetOfferImageWithUUID(ctx, int64(imageID)).Return(int64(offerID), testUUID, nil)
+		mockRepo.EXPECT().GetOfferImageWithUUID(ctx, int64(imageID)).Return(int64(offerID), testUUID, []int{320}, nil)
 		mockRepo.EXPECT().GetOfferByID(ctx, int64(offerID)).Return(repository.Offer{SellerID: int64(333)}, nil)
 
 		err := offerUsecase.DeleteOfferImage(ctx, imageID, userID)
@@ -747,7 +945,7 @@ func TestDeleteOfferImage(t *testing.T) {
 
 	t.Run("failed to delete image relation", func(t *testing.T) {
 		expectedErr := fmt.Errorf("ошибка при удалении связи с изображением")
-		mockRepo.EXPECT().GetOfferImageWithUUID(ctx, int64(imageID)).Return(int64(offerID), testUUID, nil)
+		mockRepo.EXPECT().GetOfferImageWithUUID(ctx, int64(imageID)).Return(int64(offerID), testUUID, []int{320}, nil)
 		mockRepo.EXPECT().GetOfferByID(ctx, int64(offerID)).Return(repository.Offer{SellerID: int64(userID)}, nil)
 		mockRepo.EXPECT().DeleteOfferImage(ctx, int64(imageID)).Return(expectedErr)
 
@@ -759,7 +957,7 @@ func TestDeleteOfferImage(t *testing.T) {
 
 	t.Run("failed to delete physical file but still success", func(t *testing.T) {
 		expectedErr := fmt.Errorf("не удалось удалить из s3")
-		mockRepo.EXPECT().GetOfferImageWithUUID(ctx, int64(imageID)).Return(int64(offerID), testUUID, nil)
+		mockRepo.EXPECT().GetOfferImageWithUUID(ctx, int64(imageID)).Return(int64(offerID), testUUID, nil, nil)
 		mockRepo.EXPECT().GetOfferByID(ctx, int64(offerID)).Return(repository.Offer{SellerID: int64(userID)}, nil)
 		mockRepo.EXPECT().DeleteOfferImage(ctx, int64(imageID)).Return(nil)
 		mockS3.EXPECT().Remove(ctx, "offers", testUUID).Return(expectedErr)
@@ -1102,6 +1300,45 @@ func TestIsFavorite(t *testing.T) {
 	})
 }
 
+func TestGetOfferClusters(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOfferRepository(ctrl)
+	mockLogger := logger.NewStub()
+	mockS3 := s3Mock.NewMockS3Repo(ctrl)
+	cfg := &config.Config{}
+	mockYa := yaMock.NewMockYandexRepo(ctrl)
+	mockPaymentService := paymentService.NewMockPaymentServiceClient(ctrl)
+	mockAuthService := authService.NewMockAuthServiceClient(ctrl)
+	mockRedis := redisMock.NewMockRedisRepo(ctrl)
+
+	offerUsecase := NewOfferUsecase(mockRepo, mockLogger, mockS3, cfg, mockAuthService, mockPaymentService, mockRedis, mockYa)
+	ctx := context.WithValue(context.Background(), utils.RequestIDKey, "test-request-id")
+
+	filter := domain.OfferFilter{BBox: &domain.BBox{MinLongitude: 37, MinLatitude: 55, MaxLongitude: 38, MaxLatitude: 56}}
+
+	t.Run("GetOfferClusters ok", func(t *testing.T) {
+		clusters := []domain.OfferCluster{{Count: 3, Longitude: 37.6, Latitude: 55.7, MinPrice: 100, MaxPrice: 300, OfferID: 5}}
+		// На масштабе 10 ячейка занимает четверть тайла
+		mockRepo.EXPECT().GetOfferClusters(ctx, filter, 360.0/1024/4, nil).Return(clusters, nil)
+
+		result, err := offerUsecase.GetOfferClusters(ctx, filter, 10, nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.OfferClusters(clusters), result)
+	})
+
+	t.Run("GetOfferClusters error", func(t *testing.T) {
+		mockRepo.EXPECT().GetOfferClusters(ctx, filter, gomock.Any(), nil).Return(nil, errors.New("db error"))
+
+		result, err := offerUsecase.GetOfferClusters(ctx, filter, 10, nil)
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+	})
+}
+
 func TestFavoriteOffer(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -1178,3 +1415,154 @@ func TestFavoriteOffer(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+
+func TestPrepareOffersInfoBatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOfferRepository(ctrl)
+	mockAuthService := authService.NewMockAuthServiceClient(ctrl)
+	cfg := &config.Config{
+		Minio: config.MinioConfig{Path: Path, OffersBucket: Bucket},
+	}
+	offerUsecase := NewOfferUsecase(mockRepo, logger.NewStub(), s3Mock.NewMockS3Repo(ctrl), cfg,
+		mockAuthService, paymentService.NewMockPaymentServiceClient(ctrl),
+		redisMock.NewMockRedisRepo(ctrl), yaMock.NewMockYandexRepo(ctrl))
+	ctx := context.WithValue(context.Background(), utils.RequestIDKey, "test-request-id")
+
+	stationID := 15
+	createdAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	offers := []domain.Offer{
+		{ID: 1, SellerID: 3, MetroStationID: &stationID},
+		{ID: 2, SellerID: 3},
+		{ID: 3, SellerID: 9},
+	}
+
+	t.Run("one call per source, sellers are deduplicated", func(t *testing.T) {
+		mockRepo.EXPECT().GetOffersData(ctx, []int{1, 2, 3}).Return(map[int]domain.OfferData{
+			1: {Images: []domain.OfferImage{{ID: 5, Image: "a.jpg", Variants: map[string]string{"320": "a_320.jpg"}}}},
+		}, nil)
+		mockAuthService.EXPECT().GetUsersByIds(ctx, &authpb.GetUsersByIdsRequest{Ids: []int32{3, 9}}).
+			Return(&authpb.GetUsersByIdsResponse{Users: []*authpb.User{
+				{Id: 3, FirstName: "Ivan", LastName: "Ivanov", Image: "ivan.png", CreatedAt: timestamppb.New(createdAt)},
+			}}, nil)
+		mockRepo.EXPECT().GetPriceHistories(ctx, []int{1, 2, 3}, 5).Return(map[int][]domain.OfferPriceHistory{
+			2: {{Price: 100, Date: createdAt}, {Price: 120, Date: createdAt.Add(-time.Hour)}},
+		}, nil)
+
+		result, err := offerUsecase.PrepareOffersInfo(ctx, offers, nil)
+
+		assert.NoError(t, err)
+		assert.Len(t, result, 3)
+		assert.Equal(t, "15", result[0].OfferData.Metro.Station)
+		assert.Equal(t, Path+Bucket+"a.jpg", result[0].OfferData.Images[0].Image)
+		assert.Equal(t, Path+Bucket+"a_320.jpg", result[0].OfferData.Images[0].Variants["320"])
+		assert.Equal(t, domain.OfferSeller{FirstName: "Ivan", LastName: "Ivanov", Avatar: "ivan.png", CreatedAt: createdAt}, result[1].OfferData.Seller)
+		assert.True(t, result[1].OfferData.PriceDrop)
+		assert.Nil(t, result[0].OfferData.Prices)
+		// Продавца нет в ответе сервиса авторизации: объявление отдается без него
+		assert.Equal(t, "", result[2].OfferData.Seller.FirstName)
+	})
+
+	t.Run("sellers and price history are optional", func(t *testing.T) {
+		mockRepo.EXPECT().GetOffersData(ctx, []int{1, 2, 3}).Return(map[int]domain.OfferData{}, nil)
+		mockAuthService.EXPECT().GetUsersByIds(ctx, gomock.Any()).Return(nil, errors.New("unavailable"))
+		mockRepo.EXPECT().GetPriceHistories(ctx, []int{1, 2, 3}, 5).Return(nil, errors.New("db error"))
+
+		result, err := offerUsecase.PrepareOffersInfo(ctx, offers, nil)
+
+		assert.NoError(t, err)
+		assert.Len(t, result, 3)
+	})
+
+	t.Run("empty page makes no calls", func(t *testing.T) {
+		result, err := offerUsecase.PrepareOffersInfo(ctx, nil, nil)
+
+		assert.NoError(t, err)
+		assert.Empty(t, result)
+	})
+}
+
+// benchRoundTrip Задержка одного обращения к БД или сервису авторизации в бенчмарке
+const benchRoundTrip = 100 * time.Microsecond
+
+type benchOfferRepo struct {
+	offer.OfferRepository
+	calls *atomic.Int64
+}
+
+func (r benchOfferRepo) GetOffersData(_ context.Context, offerIDs []int) (map[int]domain.OfferData, error) {
+	r.calls.Add(2)
+	time.Sleep(2 * benchRoundTrip)
+	data := make(map[int]domain.OfferData, len(offerIDs))
+	for _, id := range offerIDs {
+		views := 10
+		data[id] = domain.OfferData{
+			Images:    []domain.OfferImage{{ID: id, Image: "image.jpg"}},
+			OfferStat: domain.OfferStat{Views: &views},
+		}
+	}
+	return data, nil
+}
+
+func (r benchOfferRepo) GetPriceHistories(_ context.Context, offerIDs []int, _ int) (map[int][]domain.OfferPriceHistory, error) {
+	r.calls.Add(1)
+	time.Sleep(benchRoundTrip)
+	histories := make(map[int][]domain.OfferPriceHistory, len(offerIDs))
+	for _, id := range offerIDs {
+		histories[id] = []domain.OfferPriceHistory{{Price: 100, Date: time.Now()}, {Price: 120, Date: time.Now()}}
+	}
+	return histories, nil
+}
+
+type benchAuthClient struct {
+	authpb.AuthServiceClient
+	calls *atomic.Int64
+}
+
+func (c benchAuthClient) GetUsersByIds(_ context.Context, in *authpb.GetUsersByIdsRequest, _ ...grpc.CallOption) (*authpb.GetUsersByIdsResponse, error) {
+	c.calls.Add(1)
+	time.Sleep(benchRoundTrip)
+	users := make([]*authpb.User, 0, len(in.Ids))
+	for _, id := range in.Ids {
+		users = append(users, &authpb.User{Id: id, FirstName: "Ivan", CreatedAt: timestamppb.Now()})
+	}
+	return &authpb.GetUsersByIdsResponse{Users: users}, nil
+}
+
+// BenchmarkPrepareOffersInfo Сравнивает подготовку страницы из 100 объявлений по одному
+// объявлению за раз и одной пачкой. roundtrips/op показывает число обращений к БД и сервису авторизации
+func BenchmarkPrepareOffersInfo(b *testing.B) {
+	offers := make([]domain.Offer, 100)
+	for i := range offers {
+		offers[i] = domain.Offer{ID: i + 1, SellerID: i%20 + 1}
+	}
+
+	calls := &atomic.Int64{}
+	cfg := &config.Config{Minio: config.MinioConfig{Path: Path, OffersBucket: Bucket}}
+	offerUsecase := NewOfferUsecase(benchOfferRepo{calls: calls}, logger.NewStub(), nil, cfg,
+		benchAuthClient{calls: calls}, nil, nil, nil)
+	ctx := context.WithValue(context.Background(), utils.RequestIDKey, "bench")
+
+	b.Run("per_offer", func(b *testing.B) {
+		calls.Store(0)
+		for i := 0; i < b.N; i++ {
+			for _, o := range offers {
+				if _, err := offerUsecase.PrepareOfferInfo(ctx, o, nil); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+		b.ReportMetric(float64(calls.Load())/float64(b.N), "roundtrips/op")
+	})
+
+	b.Run("batch", func(b *testing.B) {
+		calls.Store(0)
+		for i := 0; i < b.N; i++ {
+			if _, err := offerUsecase.PrepareOffersInfo(ctx, offers, nil); err != nil {
+				b.Fatal(err)
+			}
+		}
+		b.ReportMetric(float64(calls.Load())/float64(b.N), "roundtrips/op")
+	})
+}
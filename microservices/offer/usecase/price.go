@@ -0,0 +1,40 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/domain"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/logger"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/utils"
+)
+
+// GetOfferPrices Полная история цены объявления от первой записи к последней
+func (u *offerUsecase) GetOfferPrices(ctx context.Context, offerID int) (domain.OfferPrices, error) {
+	requestID := ctx.Value(utils.RequestIDKey)
+
+	if _, err := u.repo.GetOfferByID(ctx, int64(offerID)); err != nil {
+		u.logger.WithFields(logger.LoggerFields{"requestID": requestID, "offerID": offerID, "err": err.Error()}).Warn("Offer usecase: get offer for prices failed")
+		return domain.OfferPrices{}, fmt.Errorf("объявление не найдено")
+	}
+
+	history, err := u.repo.GetFullPriceHistory(ctx, int64(offerID))
+	if err != nil {
+		u.logger.WithFields(logger.LoggerFields{"requestID": requestID, "offerID": offerID, "err": err.Error()}).Error("Offer usecase: get price history failed")
+		return domain.OfferPrices{}, err
+	}
+
+	// PriceReducedSince ждет историю от новых записей к старым
+	reversed := make([]domain.OfferPriceHistory, len(history))
+	for i, record := range history {
+		reversed[len(history)-1-i] = record
+	}
+	since := domain.PriceReducedSince(reversed)
+
+	return domain.OfferPrices{
+		OfferID:           offerID,
+		Prices:            domain.NewPricePoints(history),
+		PriceDrop:         since != nil,
+		PriceReducedSince: since,
+	}, nil
+}
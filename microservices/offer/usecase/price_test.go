@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-park-mail-ru/2025_1_404/config"
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/domain"
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/mocks"
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/repository"
+	yaMock "github.com/go-park-mail-ru/2025_1_404/pkg/api/yandex/mocks"
+	redisMock "github.com/go-park-mail-ru/2025_1_404/pkg/database/redis/mocks"
+	s3Mock "github.com/go-park-mail-ru/2025_1_404/pkg/database/s3/mocks"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/logger"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/utils"
+	authService "github.com/go-park-mail-ru/2025_1_404/proto/auth/mocks"
+	paymentService "github.com/go-park-mail-ru/2025_1_404/proto/payment/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetOfferPrices(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOfferRepository(ctrl)
+	offerUsecase := NewOfferUsecase(mockRepo, logger.NewStub(), s3Mock.NewMockS3Repo(ctrl), &config.Config{},
+		authService.NewMockAuthServiceClient(ctrl), paymentService.NewMockPaymentServiceClient(ctrl),
+		redisMock.NewMockRedisRepo(ctrl), yaMock.NewMockYandexRepo(ctrl))
+	ctx := context.WithValue(context.Background(), utils.RequestIDKey, "test-request-id")
+
+	day := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("changes and drop", func(t *testing.T) {
+		mockRepo.EXPECT().GetOfferByID(ctx, int64(1)).Return(repository.Offer{ID: 1}, nil)
+		mockRepo.EXPECT().GetFullPriceHistory(ctx, int64(1)).Return([]domain.OfferPriceHistory{
+			{Price: 1000000, Date: day},
+			{Price: 1200000, Date: day.AddDate(0, 0, 1)},
+			{Price: 900000, Date: day.AddDate(0, 0, 2)},
+			{Price: 810000, Date: day.AddDate(0, 0, 3)},
+		}, nil)
+
+		prices, err := offerUsecase.GetOfferPrices(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, prices.Prices, 4)
+		assert.Equal(t, domain.PricePoint{Price: 1000000, Date: day}, prices.Prices[0])
+		assert.Equal(t, 200000, prices.Prices[1].Change)
+		assert.Equal(t, 20.0, prices.Prices[1].ChangePercent)
+		assert.Equal(t, -25.0, prices.Prices[2].ChangePercent)
+		assert.Equal(t, -10.0, prices.Prices[3].ChangePercent)
+		assert.True(t, prices.PriceDrop)
+		assert.Equal(t, day.AddDate(0, 0, 2), *prices.PriceReducedSince)
+	})
+
+	t.Run("price raised", func(t *testing.T) {
+		mockRepo.EXPECT().GetOfferByID(ctx, int64(1)).Return(repository.Offer{ID: 1}, nil)
+		mockRepo.EXPECT().GetFullPriceHistory(ctx, int64(1)).Return([]domain.OfferPriceHistory{
+			{Price: 1000000, Date: day},
+			{Price: 1100000, Date: day.AddDate(0, 0, 1)},
+		}, nil)
+
+		prices, err := offerUsecase.GetOfferPrices(ctx, 1)
+		require.NoError(t, err)
+		assert.False(t, prices.PriceDrop)
+		assert.Nil(t, prices.PriceReducedSince)
+	})
+
+	t.Run("offer not found", func(t *testing.T) {
+		mockRepo.EXPECT().GetOfferByID(ctx, int64(2)).Return(repository.Offer{}, errors.New("no rows"))
+
+		_, err := offerUsecase.GetOfferPrices(ctx, 2)
+		assert.EqualError(t, err, "объявление не найдено")
+	})
+
+	t.Run("repo error", func(t *testing.T) {
+		mockRepo.EXPECT().GetOfferByID(ctx, int64(1)).Return(repository.Offer{ID: 1}, nil)
+		mockRepo.EXPECT().GetFullPriceHistory(ctx, int64(1)).Return(nil, errors.New("db error"))
+
+		_, err := offerUsecase.GetOfferPrices(ctx, 1)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,123 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/domain"
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/notify"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/logger"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/utils"
+)
+
+const (
+	priceDropMessage = "Цена снижена: %s → %s ₽"
+	priceDropSubject = "Цена на избранное объявление снижена"
+)
+
+// SetNotifyChannel Подменяет канал доставки уведомлений, по умолчанию он выбирается по конфигу
+func (u *offerUsecase) SetNotifyChannel(channel notify.Channel) {
+	u.notifier = channel
+}
+
+func (u *offerUsecase) notifyChannel() notify.Channel {
+	u.notifierOnce.Do(func() {
+		if u.notifier != nil {
+			return
+		}
+		channel, err := notify.NewChannel(u.cfg.App.PriceAlerts)
+		if err != nil {
+			u.logger.WithFields(logger.LoggerFields{"err": err.Error()}).Error("Offer usecase: notify channel init failed, falling back to in-app")
+			channel = notify.NewInAppChannel()
+		}
+		u.notifier = channel
+	})
+	return u.notifier
+}
+
+// notifyPriceDrop Записывает уведомления о снижении цены и отправляет их через канал доставки.
+// Ошибки не прерывают обновление объявления. Доставка идет в фоне, чтобы продавец не ждал
+// рассылку по всем, у кого объявление в избранном
+func (u *offerUsecase) notifyPriceDrop(ctx context.Context, alert domain.PriceDropAlert) {
+	requestID := ctx.Value(utils.RequestIDKey)
+
+	notification := domain.Notification{
+		Message:     fmt.Sprintf(priceDropMessage, formatPrice(alert.OldPrice), formatPrice(alert.NewPrice)),
+		RedirectURI: fmt.Sprintf(offerRedirectURI, alert.OfferID),
+	}
+	recipients, err := u.repo.SavePriceDropNotifications(ctx, alert, u.cfg.App.PriceAlerts.MinDropPercent, notification)
+	if err != nil {
+		u.logger.WithFields(logger.LoggerFields{"requestID": requestID, "offer_id": alert.OfferID, "err": err.Error()}).Warn("Offer usecase: save price drop notifications failed")
+		return
+	}
+	if len(recipients) == 0 {
+		return
+	}
+
+	channel := u.notifyChannel()
+	url := u.cfg.App.BaseFrontendDir + notification.RedirectURI
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		for _, recipient := range recipients {
+			err := channel.Send(ctx, notify.Message{
+				UserID:  recipient.UserID,
+				Email:   recipient.Email,
+				Subject: priceDropSubject,
+				Text:    notification.Message,
+				URL:     url,
+			})
+			if err != nil {
+				u.logger.WithFields(logger.LoggerFields{"requestID": requestID, "offer_id": alert.OfferID, "user_id": recipient.UserID, "err": err.Error()}).Warn("Offer usecase: price drop delivery failed")
+			}
+		}
+	}()
+}
+
+func (u *offerUsecase) GetPriceAlertSettings(ctx context.Context, userID int) (domain.PriceAlertSettings, error) {
+	requestID := ctx.Value(utils.RequestIDKey)
+
+	settings, err := u.repo.GetPriceAlertSettings(ctx, userID)
+	if err != nil {
+		u.logger.WithFields(logger.LoggerFields{"requestID": requestID, "user_id": userID, "err": err.Error()}).Error("Offer usecase: get price alert settings failed")
+		return domain.PriceAlertSettings{}, err
+	}
+	settings.DefaultMinDropPercent = u.cfg.App.PriceAlerts.MinDropPercent
+
+	return settings, nil
+}
+
+func (u *offerUsecase) UpdatePriceAlertSettings(ctx context.Context, userID int, settings domain.PriceAlertSettings) (domain.PriceAlertSettings, error) {
+	requestID := ctx.Value(utils.RequestIDKey)
+
+	if settings.MinDropPercent != nil && (*settings.MinDropPercent < 0 || *settings.MinDropPercent > 100) {
+		return domain.PriceAlertSettings{}, fmt.Errorf("порог снижения цены должен быть от 0 до 100 процентов")
+	}
+
+	if err := u.repo.SavePriceAlertSettings(ctx, userID, settings); err != nil {
+		u.logger.WithFields(logger.LoggerFields{"requestID": requestID, "user_id": userID, "err": err.Error()}).Error("Offer usecase: save price alert settings failed")
+		return domain.PriceAlertSettings{}, fmt.Errorf("не удалось сохранить настройки")
+	}
+	settings.DefaultMinDropPercent = u.cfg.App.PriceAlerts.MinDropPercent
+
+	return settings, nil
+}
+
+// formatPrice Разбивает цену на разряды: 4500000 -> 4 500 000
+func formatPrice(price int) string {
+	digits := strconv.Itoa(price)
+	sign := ""
+	if strings.HasPrefix(digits, "-") {
+		sign, digits = "-", digits[1:]
+	}
+
+	var b strings.Builder
+	for i, r := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteRune(r)
+	}
+	return sign + b.String()
+}
@@ -0,0 +1,181 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-park-mail-ru/2025_1_404/config"
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/domain"
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/mocks"
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/notify"
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/repository"
+	yaMock "github.com/go-park-mail-ru/2025_1_404/pkg/api/yandex/mocks"
+	redisMock "github.com/go-park-mail-ru/2025_1_404/pkg/database/redis/mocks"
+	s3Mock "github.com/go-park-mail-ru/2025_1_404/pkg/database/s3/mocks"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/logger"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/utils"
+	authService "github.com/go-park-mail-ru/2025_1_404/proto/auth/mocks"
+	paymentService "github.com/go-park-mail-ru/2025_1_404/proto/payment/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingChannel Запоминает отправленные уведомления
+type recordingChannel struct {
+	mu       sync.Mutex
+	wg       sync.WaitGroup
+	messages []notify.Message
+}
+
+func (c *recordingChannel) Send(_ context.Context, msg notify.Message) error {
+	defer c.wg.Done()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.messages = append(c.messages, msg)
+	return nil
+}
+
+func (c *recordingChannel) wait(t *testing.T) {
+	done := make(chan struct{})
+	go func() {
+		c.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("уведомления не доставлены")
+	}
+}
+
+func TestUpdateOfferPriceDrop(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOfferRepository(ctrl)
+	cfg := &config.Config{App: config.AppConfig{
+		BaseFrontendDir: "https://kvartirum.ru",
+		PriceAlerts:     config.PriceAlertsConfig{MinDropPercent: 3},
+	}}
+	offerUsecase := NewOfferUsecase(mockRepo, logger.NewStub(), s3Mock.NewMockS3Repo(ctrl), cfg,
+		authService.NewMockAuthServiceClient(ctrl), paymentService.NewMockPaymentServiceClient(ctrl),
+		redisMock.NewMockRedisRepo(ctrl), yaMock.NewMockYandexRepo(ctrl))
+	channel := &recordingChannel{}
+	offerUsecase.SetNotifyChannel(channel)
+	ctx := context.WithValue(context.Background(), utils.RequestIDKey, "test-request-id")
+
+	address := "Москва"
+	existing := repository.Offer{ID: 1, SellerID: 3, OfferTypeID: 1, StatusID: domain.OfferStatusActive, Price: 5000000, Address: &address}
+	newOffer := func(price int) domain.Offer {
+		addr := address
+		purchaseType := 2
+		return domain.Offer{ID: 1, OfferTypeID: 1, PurchaseTypeID: &purchaseType, PropertyTypeID: 3, RenovationID: 1, Price: price, Address: &addr}
+	}
+	mockRepo.EXPECT().GetDictionaries(ctx).Return(testDictionaries, nil).AnyTimes()
+
+	t.Run("favorites notified", func(t *testing.T) {
+		alert := domain.PriceDropAlert{OfferID: 1, SellerID: 3, OldPrice: 5000000, NewPrice: 4500000}
+		notification := domain.Notification{Message: "Цена снижена: 5 000 000 → 4 500 000 ₽", RedirectURI: "/offer/1"}
+
+		mockRepo.EXPECT().GetOfferByID(ctx, int64(1)).Return(existing, nil)
+		mockRepo.EXPECT().AddOrUpdatePriceHistory(ctx, int64(1), 4500000).Return(nil)
+		mockRepo.EXPECT().UpdateOffer(ctx, gomock.Any()).Return(nil)
+		mockRepo.EXPECT().SavePriceDropNotifications(ctx, alert, 3, notification).
+			Return([]domain.PriceDropRecipient{{UserID: 7, Email: "a@mail.ru"}, {UserID: 8, Email: "b@mail.ru"}}, nil)
+		channel.wg.Add(2)
+
+		require.NoError(t, offerUsecase.UpdateOffer(ctx, newOffer(4500000)))
+		channel.wait(t)
+
+		require.Len(t, channel.messages, 2)
+		assert.Equal(t, notify.Message{
+			UserID:  7,
+			Email:   "a@mail.ru",
+			Subject: priceDropSubject,
+			Text:    notification.Message,
+			URL:     "https://kvartirum.ru/offer/1",
+		}, channel.messages[0])
+	})
+
+	t.Run("price raised", func(t *testing.T) {
+		mockRepo.EXPECT().GetOfferByID(ctx, int64(1)).Return(existing, nil)
+		mockRepo.EXPECT().AddOrUpdatePriceHistory(ctx, int64(1), 5500000).Return(nil)
+		mockRepo.EXPECT().UpdateOffer(ctx, gomock.Any()).Return(nil)
+
+		require.NoError(t, offerUsecase.UpdateOffer(ctx, newOffer(5500000)))
+	})
+
+	t.Run("draft not notified", func(t *testing.T) {
+		draft := existing
+		draft.StatusID = domain.OfferStatusDraft
+		mockRepo.EXPECT().GetOfferByID(ctx, int64(1)).Return(draft, nil)
+		mockRepo.EXPECT().AddOrUpdatePriceHistory(ctx, int64(1), 4000000).Return(nil)
+		mockRepo.EXPECT().UpdateOffer(ctx, gomock.Any()).Return(nil)
+
+		require.NoError(t, offerUsecase.UpdateOffer(ctx, newOffer(4000000)))
+	})
+
+	t.Run("notification failure does not fail update", func(t *testing.T) {
+		mockRepo.EXPECT().GetOfferByID(ctx, int64(1)).Return(existing, nil)
+		mockRepo.EXPECT().AddOrUpdatePriceHistory(ctx, int64(1), 4000000).Return(nil)
+		mockRepo.EXPECT().UpdateOffer(ctx, gomock.Any()).Return(nil)
+		mockRepo.EXPECT().SavePriceDropNotifications(ctx, gomock.Any(), 3, gomock.Any()).Return(nil, errors.New("db error"))
+
+		require.NoError(t, offerUsecase.UpdateOffer(ctx, newOffer(4000000)))
+	})
+}
+
+func TestPriceAlertSettings(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOfferRepository(ctrl)
+	cfg := &config.Config{App: config.AppConfig{PriceAlerts: config.PriceAlertsConfig{MinDropPercent: 3}}}
+	offerUsecase := NewOfferUsecase(mockRepo, logger.NewStub(), s3Mock.NewMockS3Repo(ctrl), cfg,
+		authService.NewMockAuthServiceClient(ctrl), paymentService.NewMockPaymentServiceClient(ctrl),
+		redisMock.NewMockRedisRepo(ctrl), yaMock.NewMockYandexRepo(ctrl))
+	ctx := context.WithValue(context.Background(), utils.RequestIDKey, "test-request-id")
+
+	t.Run("get with default threshold", func(t *testing.T) {
+		mockRepo.EXPECT().GetPriceAlertSettings(ctx, 7).Return(domain.PriceAlertSettings{Enabled: true}, nil)
+
+		settings, err := offerUsecase.GetPriceAlertSettings(ctx, 7)
+		require.NoError(t, err)
+		assert.Equal(t, domain.PriceAlertSettings{Enabled: true, DefaultMinDropPercent: 3}, settings)
+	})
+
+	t.Run("opt out", func(t *testing.T) {
+		mockRepo.EXPECT().SavePriceAlertSettings(ctx, 7, domain.PriceAlertSettings{Enabled: false}).Return(nil)
+
+		settings, err := offerUsecase.UpdatePriceAlertSettings(ctx, 7, domain.PriceAlertSettings{Enabled: false})
+		require.NoError(t, err)
+		assert.False(t, settings.Enabled)
+	})
+
+	t.Run("threshold out of range", func(t *testing.T) {
+		threshold := 101
+		_, err := offerUsecase.UpdatePriceAlertSettings(ctx, 7, domain.PriceAlertSettings{Enabled: true, MinDropPercent: &threshold})
+		assert.Error(t, err)
+	})
+
+	t.Run("repo error", func(t *testing.T) {
+		mockRepo.EXPECT().SavePriceAlertSettings(ctx, 7, gomock.Any()).Return(errors.New("db error"))
+
+		_, err := offerUsecase.UpdatePriceAlertSettings(ctx, 7, domain.PriceAlertSettings{Enabled: true})
+		assert.EqualError(t, err, "не удалось сохранить настройки")
+	})
+}
+
+func TestFormatPrice(t *testing.T) {
+	assert.Equal(t, "0", formatPrice(0))
+	assert.Equal(t, "999", formatPrice(999))
+	assert.Equal(t, "1 000", formatPrice(1000))
+	assert.Equal(t, "4 500 000", formatPrice(4500000))
+	assert.Equal(t, "-12 345", formatPrice(-12345))
+	assert.LessOrEqual(t, len([]rune(fmt.Sprintf(priceDropMessage, formatPrice(2147483647), formatPrice(2147483647)))), maxNotificationMessageLength)
+}
@@ -0,0 +1,125 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/domain"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/logger"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/utils"
+)
+
+// Ограничения kvartirum.UserNotification
+const (
+	maxNotificationMessageLength = 64
+	searchAlertMessage           = "Новое объявление по поиску «%s»"
+	searchAlertSummaryMessage    = "Новых объявлений по поиску «%s»: ещё %d"
+	offerRedirectURI             = "/offer/%d"
+)
+
+func (u *offerUsecase) CreateSavedSearch(ctx context.Context, search domain.SavedSearch) (domain.SavedSearch, error) {
+	requestID := ctx.Value(utils.RequestIDKey)
+
+	created, err := u.repo.CreateSavedSearch(ctx, search)
+	if err != nil {
+		u.logger.WithFields(logger.LoggerFields{"requestID": requestID, "user_id": search.UserID, "err": err.Error()}).Error("Offer usecase: create saved search failed")
+		return domain.SavedSearch{}, err
+	}
+
+	return created, nil
+}
+
+func (u *offerUsecase) GetSavedSearches(ctx context.Context, userID int) (domain.SavedSearches, error) {
+	requestID := ctx.Value(utils.RequestIDKey)
+
+	searches, err := u.repo.GetSavedSearches(ctx, userID)
+	if err != nil {
+		u.logger.WithFields(logger.LoggerFields{"requestID": requestID, "user_id": userID, "err": err.Error()}).Error("Offer usecase: get saved searches failed")
+		return nil, err
+	}
+
+	return searches, nil
+}
+
+func (u *offerUsecase) DeleteSavedSearch(ctx context.Context, id int, userID int) error {
+	if err := u.repo.DeleteSavedSearch(ctx, id, userID); err != nil {
+		return fmt.Errorf("сохраненный поиск не найден")
+	}
+	return nil
+}
+
+// ProcessSavedSearches Проверяет сохраненные поиски, которым пора сработать, на объявлениях,
+// опубликованных с прошлой проверки, и пишет уведомления о совпадениях
+func (u *offerUsecase) ProcessSavedSearches(ctx context.Context) error {
+	requestID := ctx.Value(utils.RequestIDKey)
+	alertsCfg := u.cfg.App.SearchAlerts
+	now := time.Now()
+
+	searches, err := u.repo.GetDueSavedSearches(ctx, now, alertsCfg.BatchSize)
+	if err != nil {
+		u.logger.WithFields(logger.LoggerFields{"requestID": requestID, "err": err.Error()}).Error("Offer usecase: get due saved searches failed")
+		return err
+	}
+
+	for _, search := range searches {
+		filter := search.Filter
+		filter.PublishedAfter = &search.LastCheckedAt
+		filter.PublishedBefore = &now
+
+		page := domain.Pagination{Limit: alertsCfg.MaxPerSearch}
+		offers, total, err := u.repo.GetOffersByFilter(ctx, filter, page, &search.UserID)
+		if err != nil {
+			u.logger.WithFields(logger.LoggerFields{"requestID": requestID, "search_id": search.ID, "err": err.Error()}).Warn("Offer usecase: saved search evaluation failed")
+			continue
+		}
+		if len(offers) > page.Limit {
+			offers = offers[:page.Limit]
+		}
+
+		notifications := make([]domain.Notification, 0, len(offers))
+		for _, o := range offers {
+			notifications = append(notifications, domain.Notification{
+				UserID:      search.UserID,
+				Message:     searchAlertText(search.Name),
+				RedirectURI: fmt.Sprintf(offerRedirectURI, o.ID),
+			})
+		}
+		// Совпадения сверх лимита не перечисляются, но отметка о проверке сдвигается за них,
+		// поэтому о них сообщает одно общее уведомление
+		if rest := total - len(offers); rest > 0 {
+			notifications = append(notifications, domain.Notification{
+				UserID:  search.UserID,
+				Message: searchAlertSummaryText(search.Name, rest),
+			})
+		}
+
+		if err := u.repo.SaveSearchAlerts(ctx, search, now, notifications); err != nil {
+			u.logger.WithFields(logger.LoggerFields{"requestID": requestID, "search_id": search.ID, "err": err.Error()}).Warn("Offer usecase: save search alerts failed")
+		}
+	}
+
+	return nil
+}
+
+// searchAlertText Текст уведомления, укороченный за счет названия поиска
+func searchAlertText(name string) string {
+	budget := maxNotificationMessageLength - utf8.RuneCountInString(fmt.Sprintf(searchAlertMessage, ""))
+	return fmt.Sprintf(searchAlertMessage, truncateRunes(name, budget))
+}
+
+// searchAlertSummaryText Текст уведомления о совпадениях сверх лимита
+func searchAlertSummaryText(name string, rest int) string {
+	budget := maxNotificationMessageLength - utf8.RuneCountInString(fmt.Sprintf(searchAlertSummaryMessage, "", rest))
+	return fmt.Sprintf(searchAlertSummaryMessage, truncateRunes(name, budget), rest)
+}
+
+// truncateRunes Обрезает строку до limit символов, помечая обрезку многоточием
+func truncateRunes(s string, limit int) string {
+	runes := []rune(s)
+	if len(runes) <= limit {
+		return s
+	}
+	return string(runes[:limit-1]) + "…"
+}
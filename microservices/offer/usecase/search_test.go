@@ -0,0 +1,86 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/go-park-mail-ru/2025_1_404/config"
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/domain"
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/mocks"
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/repository"
+	yaMock "github.com/go-park-mail-ru/2025_1_404/pkg/api/yandex/mocks"
+	redisMock "github.com/go-park-mail-ru/2025_1_404/pkg/database/redis/mocks"
+	s3Mock "github.com/go-park-mail-ru/2025_1_404/pkg/database/s3/mocks"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/logger"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/utils"
+	authService "github.com/go-park-mail-ru/2025_1_404/proto/auth/mocks"
+	paymentService "github.com/go-park-mail-ru/2025_1_404/proto/payment/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProcessSavedSearches(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOfferRepository(ctrl)
+	cfg := &config.Config{
+		App: config.AppConfig{SearchAlerts: config.SearchAlertsConfig{BatchSize: 10, MaxPerSearch: 2}},
+	}
+	offerUsecase := NewOfferUsecase(mockRepo, logger.NewStub(), s3Mock.NewMockS3Repo(ctrl), cfg,
+		authService.NewMockAuthServiceClient(ctrl), paymentService.NewMockPaymentServiceClient(ctrl),
+		redisMock.NewMockRedisRepo(ctrl), yaMock.NewMockYandexRepo(ctrl))
+	ctx := context.WithValue(context.Background(), utils.RequestIDKey, "test-request-id")
+
+	lastChecked := time.Now().Add(-time.Hour)
+	minPrice := 100000
+	searches := []domain.SavedSearch{
+		{ID: 1, UserID: 5, Name: "Дешево", Frequency: domain.FrequencyInstant, Filter: domain.OfferFilter{MinPrice: &minPrice}, LastCheckedAt: lastChecked},
+		{ID: 2, UserID: 6, Name: "Сломанный", Frequency: domain.FrequencyDaily, LastCheckedAt: lastChecked},
+	}
+
+	t.Run("ProcessSavedSearches ok", func(t *testing.T) {
+		mockRepo.EXPECT().GetDueSavedSearches(ctx, gomock.Any(), 10).Return(searches, nil)
+		mockRepo.EXPECT().GetOffersByFilter(ctx, gomock.Any(), domain.Pagination{Limit: 2}, gomock.Any()).
+			DoAndReturn(func(_ context.Context, f domain.OfferFilter, _ domain.Pagination, userID *int) ([]repository.Offer, int, error) {
+				assert.Equal(t, &minPrice, f.MinPrice)
+				assert.Equal(t, lastChecked, *f.PublishedAfter)
+				assert.NotNil(t, f.PublishedBefore)
+				assert.Equal(t, 5, *userID)
+				return []repository.Offer{{ID: 10}, {ID: 11}, {ID: 12}}, 5, nil
+			})
+		mockRepo.EXPECT().SaveSearchAlerts(ctx, searches[0], gomock.Any(), []domain.Notification{
+			{UserID: 5, Message: "Новое объявление по поиску «Дешево»", RedirectURI: "/offer/10"},
+			{UserID: 5, Message: "Новое объявление по поиску «Дешево»", RedirectURI: "/offer/11"},
+			{UserID: 5, Message: "Новых объявлений по поиску «Дешево»: ещё 3"},
+		}).Return(nil)
+		// Ошибка одного поиска не мешает остальным
+		mockRepo.EXPECT().GetOffersByFilter(ctx, gomock.Any(), domain.Pagination{Limit: 2}, gomock.Any()).
+			Return(nil, 0, errors.New("db error"))
+
+		err := offerUsecase.ProcessSavedSearches(ctx)
+		assert.NoError(t, err)
+	})
+
+	t.Run("ProcessSavedSearches repo error", func(t *testing.T) {
+		mockRepo.EXPECT().GetDueSavedSearches(ctx, gomock.Any(), 10).Return(nil, errors.New("db error"))
+
+		err := offerUsecase.ProcessSavedSearches(ctx)
+		assert.Error(t, err)
+	})
+}
+
+func TestSearchAlertText(t *testing.T) {
+	assert.Equal(t, "Новое объявление по поиску «Дача»", searchAlertText("Дача"))
+
+	long := searchAlertText("Очень длинное название сохраненного поиска для проверки обрезки")
+	assert.Equal(t, maxNotificationMessageLength, utf8.RuneCountInString(long))
+	assert.Contains(t, long, "…»")
+
+	summary := searchAlertSummaryText("Очень длинное название сохраненного поиска для проверки обрезки", 120)
+	assert.Equal(t, maxNotificationMessageLength, utf8.RuneCountInString(summary))
+	assert.Contains(t, summary, "…»: ещё 120")
+}
@@ -0,0 +1,123 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-park-mail-ru/2025_1_404/config"
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/domain"
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/mocks"
+	"github.com/go-park-mail-ru/2025_1_404/microservices/offer/repository"
+	yaMock "github.com/go-park-mail-ru/2025_1_404/pkg/api/yandex/mocks"
+	redisMock "github.com/go-park-mail-ru/2025_1_404/pkg/database/redis/mocks"
+	s3Mock "github.com/go-park-mail-ru/2025_1_404/pkg/database/s3/mocks"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/logger"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/utils"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/validation"
+	authService "github.com/go-park-mail-ru/2025_1_404/proto/auth/mocks"
+	paymentService "github.com/go-park-mail-ru/2025_1_404/proto/payment/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChangeOfferStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOfferRepository(ctrl)
+	offerUsecase := NewOfferUsecase(mockRepo, logger.NewStub(), s3Mock.NewMockS3Repo(ctrl), &config.Config{},
+		authService.NewMockAuthServiceClient(ctrl), paymentService.NewMockPaymentServiceClient(ctrl),
+		redisMock.NewMockRedisRepo(ctrl), yaMock.NewMockYandexRepo(ctrl))
+	ctx := context.WithValue(context.Background(), utils.RequestIDKey, "test-request-id")
+
+	address := "Москва, Тверская, 1"
+	purchaseType := 2
+	activeOffer := repository.Offer{ID: 1, SellerID: 5, StatusID: domain.OfferStatusActive}
+	completedOffer := repository.Offer{
+		ID: 1, SellerID: 5, StatusID: domain.OfferStatusCompleted,
+		OfferTypeID: 1, PurchaseTypeID: &purchaseType, PropertyTypeID: 3, RenovationID: 1,
+		Price: 100000, Area: 40, Floor: 2, TotalFloors: 9, Rooms: 1, Address: &address,
+	}
+
+	t.Run("complete ok", func(t *testing.T) {
+		mockRepo.EXPECT().GetOfferByID(ctx, int64(1)).Return(activeOffer, nil)
+		mockRepo.EXPECT().ChangeOfferStatus(ctx, 1, domain.OfferStatusActive, domain.OfferStatusCompleted, 5, "Продано").Return(true, nil)
+
+		err := offerUsecase.ChangeOfferStatus(ctx, 1, 5, domain.TransitionComplete, "Продано")
+		assert.NoError(t, err)
+	})
+
+	t.Run("unpublish ok", func(t *testing.T) {
+		mockRepo.EXPECT().GetOfferByID(ctx, int64(1)).Return(activeOffer, nil)
+		mockRepo.EXPECT().ChangeOfferStatus(ctx, 1, domain.OfferStatusActive, domain.OfferStatusDraft, 5, "").Return(true, nil)
+
+		err := offerUsecase.ChangeOfferStatus(ctx, 1, 5, domain.TransitionUnpublish, "")
+		assert.NoError(t, err)
+	})
+
+	t.Run("republish ok", func(t *testing.T) {
+		mockRepo.EXPECT().GetOfferByID(ctx, int64(1)).Return(completedOffer, nil)
+		mockRepo.EXPECT().FindDuplicateOffers(ctx, gomock.Any()).Return(nil, nil)
+		mockRepo.EXPECT().ChangeOfferStatus(ctx, 1, domain.OfferStatusCompleted, domain.OfferStatusActive, 5, "").Return(true, nil)
+
+		err := offerUsecase.ChangeOfferStatus(ctx, 1, 5, domain.TransitionRepublish, "")
+		assert.NoError(t, err)
+	})
+
+	t.Run("republish own duplicate", func(t *testing.T) {
+		mockRepo.EXPECT().GetOfferByID(ctx, int64(1)).Return(completedOffer, nil)
+		mockRepo.EXPECT().FindDuplicateOffers(ctx, gomock.Any()).Return([]domain.OfferDuplicate{{DuplicateOf: 9, SellerID: 5}}, nil)
+
+		err := offerUsecase.ChangeOfferStatus(ctx, 1, 5, domain.TransitionRepublish, "")
+
+		var dupErr *domain.DuplicateOfferError
+		assert.ErrorAs(t, err, &dupErr)
+	})
+
+	t.Run("republish unpublishable", func(t *testing.T) {
+		edited := completedOffer
+		edited.Price = 0
+		mockRepo.EXPECT().GetOfferByID(ctx, int64(1)).Return(edited, nil)
+
+		err := offerUsecase.ChangeOfferStatus(ctx, 1, 5, domain.TransitionRepublish, "")
+		assert.Equal(t, validation.FieldErrors{{Field: "price", Rule: "required", Message: "обязательное поле"}}, err)
+	})
+
+	t.Run("invalid transition", func(t *testing.T) {
+		mockRepo.EXPECT().GetOfferByID(ctx, int64(1)).Return(completedOffer, nil)
+
+		err := offerUsecase.ChangeOfferStatus(ctx, 1, 5, domain.TransitionUnpublish, "")
+		assert.EqualError(t, err, "недопустимое изменение статуса объявления")
+	})
+
+	t.Run("not owner", func(t *testing.T) {
+		mockRepo.EXPECT().GetOfferByID(ctx, int64(1)).Return(activeOffer, nil)
+
+		err := offerUsecase.ChangeOfferStatus(ctx, 1, 6, domain.TransitionComplete, "")
+		assert.EqualError(t, err, "нет доступа к изменению статуса этого объявления")
+	})
+
+	t.Run("offer not found", func(t *testing.T) {
+		mockRepo.EXPECT().GetOfferByID(ctx, int64(1)).Return(repository.Offer{}, errors.New("no rows"))
+
+		err := offerUsecase.ChangeOfferStatus(ctx, 1, 5, domain.TransitionComplete, "")
+		assert.EqualError(t, err, "объявление не найдено")
+	})
+
+	t.Run("concurrent change", func(t *testing.T) {
+		mockRepo.EXPECT().GetOfferByID(ctx, int64(1)).Return(activeOffer, nil)
+		mockRepo.EXPECT().ChangeOfferStatus(ctx, 1, domain.OfferStatusActive, domain.OfferStatusCompleted, 5, "").Return(false, nil)
+
+		err := offerUsecase.ChangeOfferStatus(ctx, 1, 5, domain.TransitionComplete, "")
+		assert.EqualError(t, err, "статус объявления уже изменен")
+	})
+
+	t.Run("repo error", func(t *testing.T) {
+		mockRepo.EXPECT().GetOfferByID(ctx, int64(1)).Return(activeOffer, nil)
+		mockRepo.EXPECT().ChangeOfferStatus(ctx, 1, domain.OfferStatusActive, domain.OfferStatusCompleted, 5, "").Return(false, errors.New("db error"))
+
+		err := offerUsecase.ChangeOfferStatus(ctx, 1, 5, domain.TransitionComplete, "")
+		assert.Error(t, err)
+	})
+}
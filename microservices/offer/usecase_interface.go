@@ -9,27 +9,61 @@ import (
 //go:generate mockgen -source usecase_interface.go -destination=mocks/mock_offer.go -package=mocks
 
 type OfferUsecase interface {
-	GetOffers(ctx context.Context, userID *int) ([]domain.OfferInfo, error)
-	GetOffersByFilter(ctx context.Context, filter domain.OfferFilter, userID *int) ([]domain.OfferInfo, error)
+	GetOffers(ctx context.Context, page domain.Pagination, userID *int) (domain.OffersPage, error)
+	GetOffersByFilter(ctx context.Context, filter domain.OfferFilter, page domain.Pagination, userID *int) (domain.OffersPage, error)
+	GetOfferClusters(ctx context.Context, filter domain.OfferFilter, zoom int, userID *int) (domain.OfferClusters, error)
 	GetOfferByID(ctx context.Context, id int, ip string, userID *int) (domain.OfferInfo, error)
-	GetOffersBySellerID(ctx context.Context, sellerID int, userID *int) ([]domain.OfferInfo, error)
+	GetOfferPrices(ctx context.Context, offerID int) (domain.OfferPrices, error)
+	GetPriceAlertSettings(ctx context.Context, userID int) (domain.PriceAlertSettings, error)
+	UpdatePriceAlertSettings(ctx context.Context, userID int, settings domain.PriceAlertSettings) (domain.PriceAlertSettings, error)
+	GetOffersBySellerID(ctx context.Context, sellerID int, page domain.Pagination, userID *int) (domain.OffersPage, error)
 	CreateOffer(ctx context.Context, offer domain.Offer) (int, error)
 	UpdateOffer(ctx context.Context, offer domain.Offer) error
 	DeleteOffer(ctx context.Context, id int) error
 	SaveOfferImage(ctx context.Context, offerID int, upload s3.Upload) (int64, error)
 	PublishOffer(ctx context.Context, offerID int, userID int) error
+	ChangeOfferStatus(ctx context.Context, offerID int, userID int, transition domain.OfferTransition, reason string) error
+	ExtendOffer(ctx context.Context, offerID int, userID int) error
+	IsModerator(ctx context.Context, userID int) (bool, error)
+	GetOfferDuplicates(ctx context.Context, offerID *int) (domain.OfferDuplicates, error)
 	DeleteOfferImage(ctx context.Context, imageID int, userID int) error
+	ReorderOfferImages(ctx context.Context, offerID int, userID int, imageIDs []int64) error
+	SetOfferCover(ctx context.Context, offerID int, userID int, imageID int64) error
+	CreateImageUploadURL(ctx context.Context, offerID int, userID int) (domain.ImageUploadURL, error)
+	ConfirmImageUpload(ctx context.Context, offerID int, userID int, objectName string) (int64, error)
 	PrepareOfferInfo(ctx context.Context, offer domain.Offer, userID *int) (domain.OfferInfo, error)
 	PrepareOffersInfo(ctx context.Context, offers []domain.Offer, userID *int) ([]domain.OfferInfo, error)
 	CheckAccessToOffer(ctx context.Context, offerID int, userID int) error
 	GetOffersByZhkId(ctx context.Context, zhkId int) ([]domain.Offer, error)
 	GetStations(ctx context.Context) ([]domain.Metro, error)
+	GetDictionaries(ctx context.Context) (domain.Dictionaries, error)
 	LikeOffer(ctx context.Context, like domain.LikeRequest) (domain.LikesStat, error)
-	GetFavorites(ctx context.Context, userID int, offerTypeID *int) ([]domain.OfferInfo, error)
+	GetFavorites(ctx context.Context, userID int, offerTypeID *int, collectionID *int, page domain.Pagination) (domain.OffersPage, error)
 	IsFavorite(ctx context.Context, userID, offerID int) (bool, error)
 	FavoriteOffer(ctx context.Context, req domain.FavoriteRequest) (domain.FavoriteStat, error)
 	PromoteOffer(ctx context.Context, offerID int, paymentType int) (*domain.CreatePaymentResponse, error)
 	CheckType(ctx context.Context, paymentType int) (bool, error)
 	ValidateOffer(ctx context.Context, offerID int, purchaseId int) (*bool, error)
 	CheckPayment(ctx context.Context, paymentId int) (*domain.CheckPaymentResponse, error)
+	CreateSavedSearch(ctx context.Context, search domain.SavedSearch) (domain.SavedSearch, error)
+	GetSavedSearches(ctx context.Context, userID int) (domain.SavedSearches, error)
+	DeleteSavedSearch(ctx context.Context, id int, userID int) error
+	ProcessSavedSearches(ctx context.Context) error
+	ExpireOffers(ctx context.Context) error
+	CollectImageGarbage(ctx context.Context, dryRun bool) (domain.ImageGCReport, error)
+	ImportOffers(ctx context.Context, sellerID int, records []domain.ImportRecord) (domain.ImportReport, error)
+	GetOfferFeed(ctx context.Context, filter domain.FeedFilter) (domain.OfferFeed, error)
+	GetOfferStats(ctx context.Context, offerID int, userID int, period domain.StatsPeriod) (domain.OfferStats, error)
+	GetSellerStats(ctx context.Context, sellerID int, period domain.StatsPeriod) (domain.SellerStats, error)
+	GetCollections(ctx context.Context, userID int) (domain.FavoriteCollections, error)
+	CreateCollection(ctx context.Context, userID int, name string) (domain.FavoriteCollection, error)
+	RenameCollection(ctx context.Context, id int, userID int, name string) (domain.FavoriteCollection, error)
+	DeleteCollection(ctx context.Context, id int, userID int) error
+	AddToCollection(ctx context.Context, userID int, collectionID int, offerID int) error
+	MoveToCollection(ctx context.Context, userID int, fromID int, toID int, offerID int) error
+	RemoveFromCollection(ctx context.Context, userID int, collectionID int, offerID int) error
+	UpdateFavoriteNote(ctx context.Context, userID int, offerID int, note string) error
+	ShareCollection(ctx context.Context, id int, userID int) (domain.FavoriteCollection, error)
+	UnshareCollection(ctx context.Context, id int, userID int) error
+	GetSharedCollection(ctx context.Context, token string, page domain.Pagination) (domain.SharedCollection, error)
 }
@@ -26,7 +26,7 @@ const (
 	`
 	getZhkHeaderSQL = `
 	SELECT
-		COALESCE(ARRAY_AGG(DISTINCT img.uuid) FILTER (WHERE img.uuid IS NOT NULL), '{}') AS images,
+		COALESCE(ARRAY_AGG(img.uuid ORDER BY hci.position, hci.id) FILTER (WHERE img.uuid IS NOT NULL), '{}') AS images,
 		COUNT (DISTINCT img.id) as images_size
 	FROM kvartirum.housingcomplex hc
 	LEFT JOIN kvartirum.HousingComplexImages hci on hci.housing_complex_id = hc.id
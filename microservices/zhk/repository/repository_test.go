@@ -60,7 +60,7 @@ func TestRepository_GetZhkHeader(t *testing.T) {
 		ImagesSize:   2,
 	}
 
-	mock.ExpectQuery(`(?i)SELECT COALESCE\(ARRAY_AGG\(DISTINCT img.uuid\).*images_size`).
+	mock.ExpectQuery(`(?i)SELECT COALESCE\(ARRAY_AGG\(img.uuid ORDER BY hci.position, hci.id\).*images_size`).
 		WithArgs(zhk.ID).
 		WillReturnRows(pgxmock.NewRows([]string{"images", "images_size"}).
 			AddRow(pq.StringArray{"img1", "img2"}, expected.ImagesSize))
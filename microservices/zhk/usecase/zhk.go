@@ -8,6 +8,8 @@ import (
 	"github.com/go-park-mail-ru/2025_1_404/config"
 	"github.com/go-park-mail-ru/2025_1_404/microservices/zhk"
 	"github.com/go-park-mail-ru/2025_1_404/microservices/zhk/domain"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/cache"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/database/redis"
 	"github.com/go-park-mail-ru/2025_1_404/pkg/logger"
 	"github.com/go-park-mail-ru/2025_1_404/pkg/utils"
 	offerpb "github.com/go-park-mail-ru/2025_1_404/proto/offer"
@@ -18,10 +20,11 @@ type zhkUsecase struct {
 	logger       logger.Logger
 	cfg          *config.Config
 	offerService offerpb.OfferServiceClient
+	cache        *cache.Cache
 }
 
-func NewZhkUsecase(repo zhk.ZhkRepository, logger logger.Logger, cfg *config.Config, offerSerice offerpb.OfferServiceClient) *zhkUsecase {
-	return &zhkUsecase{repo: repo, logger: logger, cfg: cfg, offerService: offerSerice}
+func NewZhkUsecase(repo zhk.ZhkRepository, logger logger.Logger, cfg *config.Config, offerSerice offerpb.OfferServiceClient, redisRepo redis.RedisRepo) *zhkUsecase {
+	return &zhkUsecase{repo: repo, logger: logger, cfg: cfg, offerService: offerSerice, cache: cache.New(redisRepo, logger)}
 }
 
 func (u *zhkUsecase) GetZhkByID(ctx context.Context, id int64) (domain.Zhk, error) {
@@ -35,7 +38,28 @@ func (u *zhkUsecase) GetZhkByID(ctx context.Context, id int64) (domain.Zhk, erro
 	return zhk, nil
 }
 
+// GetZhkInfo Карточка ЖК. Собирается из нескольких запросов, поэтому кешируется целиком;
+// сервис объявлений сбрасывает кеш при изменении объявлений ЖК
 func (u *zhkUsecase) GetZhkInfo(ctx context.Context, id int64) (domain.ZhkInfo, error) {
+	ttl := u.cfg.App.Cache.ZhkInfo
+	if ttl <= 0 {
+		return u.loadZhkInfo(ctx, id)
+	}
+
+	var info domain.ZhkInfo
+	if u.cache.Get(ctx, cache.ZhkInfo, cache.ZhkInfoKey(id), &info) {
+		return info, nil
+	}
+
+	info, err := u.loadZhkInfo(ctx, id)
+	if err != nil {
+		return domain.ZhkInfo{}, err
+	}
+	u.cache.Set(ctx, cache.ZhkInfoKey(id), info, ttl)
+	return info, nil
+}
+
+func (u *zhkUsecase) loadZhkInfo(ctx context.Context, id int64) (domain.ZhkInfo, error) {
 	requestID := ctx.Value(utils.RequestIDKey)
 
 	// Получаем ЖК
@@ -4,10 +4,13 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/go-park-mail-ru/2025_1_404/config"
 	"github.com/go-park-mail-ru/2025_1_404/microservices/zhk/domain"
 	"github.com/go-park-mail-ru/2025_1_404/microservices/zhk/mocks"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/cache"
+	redisMock "github.com/go-park-mail-ru/2025_1_404/pkg/database/redis/mocks"
 	"github.com/go-park-mail-ru/2025_1_404/pkg/logger"
 	"github.com/go-park-mail-ru/2025_1_404/pkg/utils"
 	offerpb "github.com/go-park-mail-ru/2025_1_404/proto/offer"
@@ -25,7 +28,7 @@ func TestGetZhkByID(t *testing.T) {
 	cfg := &config.Config{}
 	mockOfferService := offerProtoMock.NewMockOfferServiceClient(ctrl)
 
-	zhkUsecase := NewZhkUsecase(mockRepo, mockLogger, cfg, mockOfferService)
+	zhkUsecase := NewZhkUsecase(mockRepo, mockLogger, cfg, mockOfferService, redisMock.NewMockRedisRepo(ctrl))
 	ctx := context.WithValue(context.Background(), utils.RequestIDKey, "test-request-id")
 	zhkID := int64(1)
 
@@ -71,7 +74,7 @@ func TestGetZhkInfo(t *testing.T) {
 	}
 	mockOfferService := offerProtoMock.NewMockOfferServiceClient(ctrl)
 
-	zhkUsecase := NewZhkUsecase(mockRepo, mockLogger, cfg, mockOfferService)
+	zhkUsecase := NewZhkUsecase(mockRepo, mockLogger, cfg, mockOfferService, redisMock.NewMockRedisRepo(ctrl))
 	ctx := context.WithValue(context.Background(), utils.RequestIDKey, "test-request-id")
 	zhkID := int64(1)
 
@@ -178,6 +181,41 @@ func TestGetZhkInfo(t *testing.T) {
 
 }
 
+func TestGetZhkInfoCache(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockZhkRepository(ctrl)
+	mockRedis := redisMock.NewMockRedisRepo(ctrl)
+	cfg := &config.Config{App: config.AppConfig{Cache: config.CacheConfig{ZhkInfo: time.Minute}}}
+	mockOfferService := offerProtoMock.NewMockOfferServiceClient(ctrl)
+
+	zhkUsecase := NewZhkUsecase(mockRepo, logger.NewStub(), cfg, mockOfferService, mockRedis)
+	ctx := context.WithValue(context.Background(), utils.RequestIDKey, "test-request-id")
+	zhkID := int64(1)
+
+	t.Run("hit skips repository", func(t *testing.T) {
+		mockRedis.EXPECT().Get(ctx, cache.ZhkInfoKey(zhkID)).Return(`{"id":1,"description":"Описание ЖК"}`, nil)
+
+		result, err := zhkUsecase.GetZhkInfo(ctx, zhkID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, zhkID, result.ID)
+		assert.Equal(t, "Описание ЖК", result.Description)
+	})
+
+	t.Run("failed load is not cached", func(t *testing.T) {
+		notFound := fmt.Errorf("redis: nil")
+		mockRedis.EXPECT().Get(ctx, cache.ZhkInfoKey(zhkID)).Return("", notFound)
+		mockRedis.EXPECT().IsNotFound(notFound).Return(true)
+		mockRepo.EXPECT().GetZhkByID(ctx, zhkID).Return(domain.Zhk{}, fmt.Errorf("no rows"))
+
+		_, err := zhkUsecase.GetZhkInfo(ctx, zhkID)
+
+		assert.Error(t, err)
+	})
+}
+
 func TestGetAllZhk(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -192,7 +230,7 @@ func TestGetAllZhk(t *testing.T) {
 	}
 	mockOfferService := offerProtoMock.NewMockOfferServiceClient(ctrl)
 
-	zhkUsecase := NewZhkUsecase(mockRepo, mockLogger, cfg, mockOfferService)
+	zhkUsecase := NewZhkUsecase(mockRepo, mockLogger, cfg, mockOfferService, redisMock.NewMockRedisRepo(ctrl))
 	ctx := context.WithValue(context.Background(), utils.RequestIDKey, "test-request-id")
 	zhkID := int64(1)
 
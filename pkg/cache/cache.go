@@ -0,0 +1,138 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-park-mail-ru/2025_1_404/pkg/database/redis"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/logger"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/utils"
+	"github.com/mailru/easyjson"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Названия кешей для метрик
+const (
+	Dictionaries = "dictionaries"
+	OfferCards   = "offer_cards"
+	ZhkInfo      = "zhk_info"
+)
+
+var requests = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "cache_requests_total",
+	Help: "Total number of cache lookups by result",
+}, []string{"cache", "result"})
+
+// Collector Счетчик попаданий и промахов, регистрируется вместе с метриками сервиса
+func Collector() prometheus.Collector {
+	return requests
+}
+
+// Cache Read-through кеш поверх Redis. Ошибки Redis не прерывают запрос: они пишутся в лог,
+// чтение считается промахом, а данные берутся из источника. Без Redis (например, в CLI импорта)
+// кеш ничего не делает и каждое чтение - промах
+type Cache struct {
+	redis  redis.RedisRepo
+	logger logger.Logger
+}
+
+func New(redisRepo redis.RedisRepo, logger logger.Logger) *Cache {
+	return &Cache{redis: redisRepo, logger: logger}
+}
+
+// Get Читает значение ключа в dst. Битая запись считается промахом
+func (c *Cache) Get(ctx context.Context, name string, key string, dst easyjson.Unmarshaler) bool {
+	if c.redis == nil {
+		record(name, false, 1)
+		return false
+	}
+	requestID := ctx.Value(utils.RequestIDKey)
+
+	data, err := c.redis.Get(ctx, key)
+	if err != nil {
+		if !c.redis.IsNotFound(err) {
+			c.logger.WithFields(logger.LoggerFields{"requestID": requestID, "key": key, "err": err.Error()}).Warn("Cache: get failed")
+		}
+		record(name, false, 1)
+		return false
+	}
+
+	if err := easyjson.Unmarshal([]byte(data), dst); err != nil {
+		c.logger.WithFields(logger.LoggerFields{"requestID": requestID, "key": key, "err": err.Error()}).Warn("Cache: broken entry")
+		record(name, false, 1)
+		return false
+	}
+
+	record(name, true, 1)
+	return true
+}
+
+// MGet Читает несколько ключей одним запросом. В ответе только найденные ключи
+func (c *Cache) MGet(ctx context.Context, name string, keys []string) map[string]string {
+	if c.redis == nil {
+		record(name, false, len(keys))
+		return map[string]string{}
+	}
+	requestID := ctx.Value(utils.RequestIDKey)
+
+	values, err := c.redis.MGet(ctx, keys...)
+	if err != nil {
+		c.logger.WithFields(logger.LoggerFields{"requestID": requestID, "keys": len(keys), "err": err.Error()}).Warn("Cache: mget failed")
+		values = map[string]string{}
+	}
+
+	record(name, true, len(values))
+	record(name, false, len(keys)-len(values))
+	return values
+}
+
+func (c *Cache) Set(ctx context.Context, key string, value easyjson.Marshaler, ttl time.Duration) {
+	if c.redis == nil {
+		return
+	}
+	requestID := ctx.Value(utils.RequestIDKey)
+
+	data, err := easyjson.Marshal(value)
+	if err == nil {
+		err = c.redis.Set(ctx, key, string(data), ttl)
+	}
+	if err != nil {
+		c.logger.WithFields(logger.LoggerFields{"requestID": requestID, "key": key, "err": err.Error()}).Warn("Cache: set failed")
+	}
+}
+
+// Delete Сбрасывает записи после изменения данных. Если Redis недоступен, записи доживут до TTL
+func (c *Cache) Delete(ctx context.Context, keys ...string) {
+	if c.redis == nil {
+		return
+	}
+	requestID := ctx.Value(utils.RequestIDKey)
+
+	if err := c.redis.Del(ctx, keys...); err != nil {
+		c.logger.WithFields(logger.LoggerFields{"requestID": requestID, "keys": keys, "err": err.Error()}).Warn("Cache: delete failed")
+	}
+}
+
+// DeletePattern Сбрасывает все записи, подходящие под шаблон
+func (c *Cache) DeletePattern(ctx context.Context, pattern string) {
+	if c.redis == nil {
+		return
+	}
+	requestID := ctx.Value(utils.RequestIDKey)
+
+	deleted, err := c.redis.DelByPattern(ctx, pattern)
+	if err != nil {
+		c.logger.WithFields(logger.LoggerFields{"requestID": requestID, "pattern": pattern, "deleted": deleted, "err": err.Error()}).Warn("Cache: delete by pattern failed")
+	}
+}
+
+func record(name string, hit bool, count int) {
+	if count <= 0 {
+		return
+	}
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	requests.WithLabelValues(name, result).Add(float64(count))
+}
@@ -0,0 +1,119 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-park-mail-ru/2025_1_404/pkg/database/redis/mocks"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/logger"
+	"github.com/go-park-mail-ru/2025_1_404/pkg/utils"
+	"github.com/golang/mock/gomock"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func counterValue(t *testing.T, name string, result string) float64 {
+	var metric dto.Metric
+	require.NoError(t, requests.WithLabelValues(name, result).Write(&metric))
+	return metric.GetCounter().GetValue()
+}
+
+func TestCacheGet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRedis := mocks.NewMockRedisRepo(ctrl)
+	c := New(mockRedis, logger.NewStub())
+	notFound := errors.New("redis: nil")
+
+	hits := counterValue(t, "test_get", "hit")
+	misses := counterValue(t, "test_get", "miss")
+
+	t.Run("hit", func(t *testing.T) {
+		mockRedis.EXPECT().Get(gomock.Any(), "key").Return(`{"message":"ok"}`, nil)
+
+		var value utils.MessageResponse
+		assert.True(t, c.Get(context.Background(), "test_get", "key", &value))
+		assert.Equal(t, "ok", value.Message)
+	})
+
+	t.Run("miss", func(t *testing.T) {
+		mockRedis.EXPECT().Get(gomock.Any(), "key").Return("", notFound)
+		mockRedis.EXPECT().IsNotFound(notFound).Return(true)
+
+		var value utils.MessageResponse
+		assert.False(t, c.Get(context.Background(), "test_get", "key", &value))
+	})
+
+	t.Run("broken entry", func(t *testing.T) {
+		mockRedis.EXPECT().Get(gomock.Any(), "key").Return(`{"message":`, nil)
+
+		var value utils.MessageResponse
+		assert.False(t, c.Get(context.Background(), "test_get", "key", &value))
+	})
+
+	assert.Equal(t, hits+1, counterValue(t, "test_get", "hit"))
+	assert.Equal(t, misses+2, counterValue(t, "test_get", "miss"))
+}
+
+func TestCacheMGet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRedis := mocks.NewMockRedisRepo(ctrl)
+	c := New(mockRedis, logger.NewStub())
+
+	hits := counterValue(t, "test_mget", "hit")
+	misses := counterValue(t, "test_mget", "miss")
+
+	mockRedis.EXPECT().MGet(gomock.Any(), "a", "b", "c").Return(map[string]string{"b": "2"}, nil)
+	assert.Equal(t, map[string]string{"b": "2"}, c.MGet(context.Background(), "test_mget", []string{"a", "b", "c"}))
+
+	// Недоступный Redis означает промах по всем ключам
+	mockRedis.EXPECT().MGet(gomock.Any(), "a").Return(nil, errors.New("connection refused"))
+	assert.Empty(t, c.MGet(context.Background(), "test_mget", []string{"a"}))
+
+	assert.Equal(t, hits+1, counterValue(t, "test_mget", "hit"))
+	assert.Equal(t, misses+3, counterValue(t, "test_mget", "miss"))
+}
+
+func TestCacheSetAndDelete(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRedis := mocks.NewMockRedisRepo(ctrl)
+	c := New(mockRedis, logger.NewStub())
+	ctx := context.Background()
+
+	mockRedis.EXPECT().Set(ctx, "key", `{"message":"ok"}`, time.Minute).Return(nil)
+	c.Set(ctx, "key", utils.MessageResponse{Message: "ok"}, time.Minute)
+
+	mockRedis.EXPECT().Del(ctx, "a", "b").Return(errors.New("connection refused"))
+	c.Delete(ctx, "a", "b")
+
+	mockRedis.EXPECT().DelByPattern(ctx, ZhkInfoPattern).Return(3, nil)
+	c.DeletePattern(ctx, ZhkInfoPattern)
+}
+
+func TestCacheWithoutRedis(t *testing.T) {
+	c := New(nil, logger.NewStub())
+	ctx := context.Background()
+	misses := counterValue(t, "test_nil", "miss")
+
+	var value utils.MessageResponse
+	assert.False(t, c.Get(ctx, "test_nil", "key", &value))
+	assert.Empty(t, c.MGet(ctx, "test_nil", []string{"a", "b"}))
+	c.Set(ctx, "key", utils.MessageResponse{Message: "ok"}, time.Minute)
+	c.Delete(ctx, "key")
+	c.DeletePattern(ctx, ZhkInfoPattern)
+
+	assert.Equal(t, misses+3, counterValue(t, "test_nil", "miss"))
+}
+
+func TestKeys(t *testing.T) {
+	assert.Equal(t, "cache:offer:card:15", OfferCardKey(15))
+	assert.Equal(t, "cache:zhk:info:3", ZhkInfoKey(3))
+}
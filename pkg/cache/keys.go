@@ -0,0 +1,18 @@
+package cache
+
+import "fmt"
+
+// Ключи общие для сервисов offer и zhk: offer сбрасывает сведения о ЖК при изменении его объявлений
+const (
+	StationsKey     = "cache:dict:stations"
+	DictionariesKey = "cache:dict:all"
+	ZhkInfoPattern  = "cache:zhk:info:*"
+)
+
+func OfferCardKey(offerID int) string {
+	return fmt.Sprintf("cache:offer:card:%d", offerID)
+}
+
+func ZhkInfoKey(zhkID int64) string {
+	return fmt.Sprintf("cache:zhk:info:%d", zhkID)
+}
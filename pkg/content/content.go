@@ -8,6 +8,8 @@ import (
 	_ "image/png"
 	"net/http"
 	"strings"
+
+	_ "golang.org/x/image/webp"
 )
 
 const MAX_SIZE = 5 * 1024 * 1024
@@ -26,6 +28,7 @@ func CheckImage(fileBytes []byte) (string, error) {
 	validFormats := map[string]bool{
 		"image/png":  true,
 		"image/jpeg": true,
+		"image/webp": true,
 	}
 	if !validFormats[contentType] {
 		return "", fmt.Errorf("не поддерживаемый формат изображения")
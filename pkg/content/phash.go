@@ -0,0 +1,82 @@
+package content
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"math/bits"
+)
+
+// Размер уменьшенной копии для разностного хеша: 9x8 дают 64 сравнения соседних пикселей
+const (
+	hashWidth  = 9
+	hashHeight = 8
+)
+
+// PerceptualHash Разностный хеш (dHash) изображения. У похожих картинок, в том числе
+// пережатых или уменьшенных, хеши отличаются в небольшом числе бит
+func PerceptualHash(fileBytes []byte) (uint64, error) {
+	img, err := DecodeImage(fileBytes)
+	if err != nil {
+		return 0, err
+	}
+	return ImageHash(img), nil
+}
+
+// DecodeImage Декодирует изображение любого из поддерживаемых форматов
+func DecodeImage(fileBytes []byte) (image.Image, error) {
+	img, _, err := image.Decode(bytes.NewReader(fileBytes))
+	if err != nil {
+		return nil, fmt.Errorf("не удалось декодировать изображение: %w", err)
+	}
+	return img, nil
+}
+
+// ImageHash Разностный хеш уже декодированного изображения
+func ImageHash(img image.Image) uint64 {
+	gray := downscaleGray(img, hashWidth, hashHeight)
+
+	var hash uint64
+	for y := 0; y < hashHeight; y++ {
+		for x := 0; x < hashWidth-1; x++ {
+			hash <<= 1
+			if gray[y*hashWidth+x] > gray[y*hashWidth+x+1] {
+				hash |= 1
+			}
+		}
+	}
+
+	return hash
+}
+
+// HashDistance Число различающихся бит двух хешей
+func HashDistance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// downscaleGray Уменьшает изображение до w x h усреднением яркости по блокам
+func downscaleGray(img image.Image, w, h int) []float64 {
+	bounds := img.Bounds()
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+	result := make([]float64, w*h)
+
+	for y := 0; y < h; y++ {
+		y0 := bounds.Min.Y + y*srcH/h
+		y1 := max(bounds.Min.Y+(y+1)*srcH/h, y0+1)
+		for x := 0; x < w; x++ {
+			x0 := bounds.Min.X + x*srcW/w
+			x1 := max(bounds.Min.X+(x+1)*srcW/w, x0+1)
+
+			var sum float64
+			for py := y0; py < y1; py++ {
+				for px := x0; px < x1; px++ {
+					r, g, b, _ := img.At(px, py).RGBA()
+					sum += 0.299*float64(r) + 0.587*float64(g) + 0.114*float64(b)
+				}
+			}
+			result[y*w+x] = sum / float64((y1-y0)*(x1-x0))
+		}
+	}
+
+	return result
+}
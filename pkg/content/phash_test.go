@@ -0,0 +1,48 @@
+package content
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func gradientPNG(t *testing.T, w, h int, invert bool) []byte {
+	img := image.NewGray(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			v := uint8(x * 255 / w)
+			if invert {
+				v = 255 - v
+			}
+			if (x/(w/4)+y/(h/4))%2 == 0 {
+				v /= 2
+			}
+			img.SetGray(x, y, color.Gray{Y: v})
+		}
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func TestPerceptualHash(t *testing.T) {
+	original, err := PerceptualHash(gradientPNG(t, 400, 300, false))
+	require.NoError(t, err)
+
+	resized, err := PerceptualHash(gradientPNG(t, 200, 150, false))
+	require.NoError(t, err)
+	assert.LessOrEqual(t, HashDistance(original, resized), 4)
+
+	inverted, err := PerceptualHash(gradientPNG(t, 400, 300, true))
+	require.NoError(t, err)
+	assert.Greater(t, HashDistance(original, inverted), 20)
+
+	_, err = PerceptualHash([]byte("not an image"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,59 @@
+package content
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+)
+
+// ResizeToWidth Уменьшает изображение до ширины width с сохранением пропорций.
+// Каждый пиксель результата - среднее по соответствующему блоку исходника,
+// прозрачные участки заливаются белым. Изображения не шире width возвращаются как есть
+func ResizeToWidth(img image.Image, width int) image.Image {
+	bounds := img.Bounds()
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+	if width <= 0 || srcW <= width {
+		return img
+	}
+	height := max(srcH*width/srcW, 1)
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0 := bounds.Min.Y + y*srcH/height
+		y1 := max(bounds.Min.Y+(y+1)*srcH/height, y0+1)
+		for x := 0; x < width; x++ {
+			x0 := bounds.Min.X + x*srcW/width
+			x1 := max(bounds.Min.X+(x+1)*srcW/width, x0+1)
+
+			var r, g, b uint64
+			for py := y0; py < y1; py++ {
+				for px := x0; px < x1; px++ {
+					pr, pg, pb, pa := img.At(px, py).RGBA()
+					// Цвета premultiplied, поэтому наложение на белый - прибавка недостающей непрозрачности
+					r += uint64(pr + 0xffff - pa)
+					g += uint64(pg + 0xffff - pa)
+					b += uint64(pb + 0xffff - pa)
+				}
+			}
+			n := uint64((y1 - y0) * (x1 - x0))
+			dst.SetRGBA(x, y, color.RGBA{
+				R: uint8(r / n >> 8),
+				G: uint8(g / n >> 8),
+				B: uint8(b / n >> 8),
+				A: 0xff,
+			})
+		}
+	}
+
+	return dst
+}
+
+// EncodeJPEG Кодирует изображение в JPEG с заданным качеством
+func EncodeJPEG(img image.Image, quality int) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,38 @@
+package content
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResizeToWidth(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(0, 0, 400, 200))
+	for y := 0; y < 200; y++ {
+		for x := 0; x < 400; x++ {
+			if x < 200 {
+				src.SetNRGBA(x, y, color.NRGBA{R: 255, A: 255})
+			} else {
+				// Полностью прозрачная половина
+				src.SetNRGBA(x, y, color.NRGBA{})
+			}
+		}
+	}
+
+	resized := ResizeToWidth(src, 100)
+	require.Equal(t, image.Rect(0, 0, 100, 50), resized.Bounds())
+	assert.Equal(t, color.RGBA{R: 255, A: 255}, resized.At(10, 10))
+	assert.Equal(t, color.RGBA{R: 255, G: 255, B: 255, A: 255}, resized.At(90, 10))
+
+	// Не увеличивает
+	assert.Same(t, image.Image(src), ResizeToWidth(src, 800))
+
+	data, err := EncodeJPEG(resized, 80)
+	require.NoError(t, err)
+	decoded, err := DecodeImage(data)
+	require.NoError(t, err)
+	assert.Equal(t, 100, decoded.Bounds().Dx())
+}
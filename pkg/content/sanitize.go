@@ -0,0 +1,171 @@
+package content
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"path/filepath"
+	"strings"
+)
+
+// Все загружаемые изображения перекодируются в JPEG: так в хранилище не попадают
+// EXIF (в том числе GPS-координаты), ICC-профили и текстовые чанки PNG
+const (
+	SanitizedContentType = "image/jpeg"
+	sanitizedExt         = ".jpg"
+	sanitizeQuality      = 90
+)
+
+// Значения тега Orientation из EXIF, отличные от 1, требуют поворота или отражения
+const (
+	exifOrientationTag    = 0x0112
+	orientationNormal     = 1
+	orientationMirror     = 2
+	orientationRotate180  = 3
+	orientationFlip       = 4
+	orientationTranspose  = 5
+	orientationRotate90   = 6
+	orientationTransverse = 7
+	orientationRotate270  = 8
+)
+
+// SanitizeImage Декодирует изображение, поворачивает его согласно EXIF Orientation
+// и перекодирует в JPEG без метаданных. Прозрачные участки заливаются белым
+func SanitizeImage(fileBytes []byte) ([]byte, error) {
+	img, err := DecodeImage(fileBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	orientation := orientationNormal
+	if bytes.HasPrefix(fileBytes, []byte{0xff, 0xd8}) {
+		orientation = jpegOrientation(fileBytes)
+	}
+
+	return EncodeJPEG(applyOrientation(img, orientation), sanitizeQuality)
+}
+
+// PrepareImage Проверяет загруженный файл и возвращает его очищенную копию
+func PrepareImage(fileBytes []byte) ([]byte, error) {
+	if _, err := CheckImage(fileBytes); err != nil {
+		return nil, err
+	}
+	return SanitizeImage(fileBytes)
+}
+
+// SanitizedFilename Меняет расширение файла на расширение канонического формата
+func SanitizedFilename(filename string) string {
+	return strings.TrimSuffix(filename, filepath.Ext(filename)) + sanitizedExt
+}
+
+// applyOrientation Переносит пиксели в новое изображение с учетом ориентации
+// и накладывает их на белый фон
+func applyOrientation(img image.Image, orientation int) *image.RGBA {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+
+	dstW, dstH := w, h
+	if orientation >= orientationTranspose && orientation <= orientationRotate270 {
+		dstW, dstH = h, w
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dstW, dstH))
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			dx, dy := x, y
+			switch orientation {
+			case orientationMirror:
+				dx = w - 1 - x
+			case orientationRotate180:
+				dx, dy = w-1-x, h-1-y
+			case orientationFlip:
+				dy = h - 1 - y
+			case orientationTranspose:
+				dx, dy = y, x
+			case orientationRotate90:
+				dx, dy = h-1-y, x
+			case orientationTransverse:
+				dx, dy = h-1-y, w-1-x
+			case orientationRotate270:
+				dx, dy = y, w-1-x
+			}
+
+			r, g, b, a := img.At(bounds.Min.X+x, bounds.Min.Y+y).RGBA()
+			dst.SetRGBA(dx, dy, color.RGBA{
+				R: uint8((r + 0xffff - a) >> 8),
+				G: uint8((g + 0xffff - a) >> 8),
+				B: uint8((b + 0xffff - a) >> 8),
+				A: 0xff,
+			})
+		}
+	}
+
+	return dst
+}
+
+// jpegOrientation Ищет тег Orientation в сегменте APP1 (Exif). Если сегмента нет
+// или он поврежден, изображение считается неповернутым
+func jpegOrientation(data []byte) int {
+	pos := 2
+	for pos+4 <= len(data) {
+		if data[pos] != 0xff {
+			return orientationNormal
+		}
+		marker := data[pos+1]
+		// Начало скана: дальше идут сжатые данные, метаданных там нет
+		if marker == 0xda || marker == 0xd9 {
+			return orientationNormal
+		}
+		length := int(binary.BigEndian.Uint16(data[pos+2:]))
+		if length < 2 || pos+2+length > len(data) {
+			return orientationNormal
+		}
+		segment := data[pos+4 : pos+2+length]
+		if marker == 0xe1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return tiffOrientation(segment[6:])
+		}
+		pos += 2 + length
+	}
+
+	return orientationNormal
+}
+
+// tiffOrientation Читает тег Orientation из IFD0 TIFF-структуры EXIF
+func tiffOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return orientationNormal
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return orientationNormal
+	}
+
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd < 8 || ifd+2 > len(tiff) {
+		return orientationNormal
+	}
+	count := int(order.Uint16(tiff[ifd:]))
+	for i := 0; i < count; i++ {
+		entry := ifd + 2 + i*12
+		if entry+12 > len(tiff) {
+			break
+		}
+		if order.Uint16(tiff[entry:]) != exifOrientationTag {
+			continue
+		}
+		value := int(order.Uint16(tiff[entry+8:]))
+		if value < orientationNormal || value > orientationRotate270 {
+			return orientationNormal
+		}
+		return value
+	}
+
+	return orientationNormal
+}
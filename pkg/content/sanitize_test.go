@@ -0,0 +1,137 @@
+package content
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// jpegWithExif Кодирует половинку красного и синего в JPEG и вставляет после SOI
+// сегмент APP1 с тегом Orientation и фиктивными GPS-данными
+func jpegWithExif(t *testing.T, orientation uint16) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, 200, 100))
+	for y := 0; y < 100; y++ {
+		for x := 0; x < 200; x++ {
+			if x < 100 {
+				img.SetRGBA(x, y, color.RGBA{R: 255, A: 255})
+			} else {
+				img.SetRGBA(x, y, color.RGBA{B: 255, A: 255})
+			}
+		}
+	}
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, img, &jpeg.Options{Quality: 95}))
+	encoded := buf.Bytes()
+
+	tiff := []byte("MM\x00\x2a\x00\x00\x00\x08")
+	tiff = binary.BigEndian.AppendUint16(tiff, 1)
+	tiff = binary.BigEndian.AppendUint16(tiff, exifOrientationTag)
+	tiff = binary.BigEndian.AppendUint16(tiff, 3)
+	tiff = binary.BigEndian.AppendUint32(tiff, 1)
+	tiff = binary.BigEndian.AppendUint16(tiff, orientation)
+	tiff = append(tiff, 0, 0, 0, 0, 0, 0)
+	tiff = append(tiff, "GPS 55.7558N 37.6173E"...)
+
+	payload := append([]byte("Exif\x00\x00"), tiff...)
+	segment := []byte{0xff, 0xe1}
+	segment = binary.BigEndian.AppendUint16(segment, uint16(len(payload)+2))
+	segment = append(segment, payload...)
+
+	result := append([]byte{}, encoded[:2]...)
+	result = append(result, segment...)
+	return append(result, encoded[2:]...)
+}
+
+func isRed(c color.Color) bool {
+	r, g, b, _ := c.RGBA()
+	return r > 0xc000 && g < 0x4000 && b < 0x4000
+}
+
+func isBlue(c color.Color) bool {
+	r, g, b, _ := c.RGBA()
+	return b > 0xc000 && r < 0x4000 && g < 0x4000
+}
+
+func TestSanitizeImageOrientation(t *testing.T) {
+	original := jpegWithExif(t, orientationRotate90)
+	require.Equal(t, orientationRotate90, jpegOrientation(original))
+
+	sanitized, err := SanitizeImage(original)
+	require.NoError(t, err)
+	assert.NotContains(t, string(sanitized), "Exif")
+	assert.NotContains(t, string(sanitized), "GPS")
+
+	img, err := jpeg.Decode(bytes.NewReader(sanitized))
+	require.NoError(t, err)
+	require.Equal(t, image.Rect(0, 0, 100, 200), img.Bounds())
+	// После поворота по часовой стрелке левая красная половина оказывается сверху
+	assert.True(t, isRed(img.At(50, 20)))
+	assert.True(t, isBlue(img.At(50, 180)))
+	assert.Equal(t, orientationNormal, jpegOrientation(sanitized))
+}
+
+func TestApplyOrientation(t *testing.T) {
+	// 3x2, отмечен левый верхний пиксель
+	src := image.NewRGBA(image.Rect(0, 0, 3, 2))
+	for y := 0; y < 2; y++ {
+		for x := 0; x < 3; x++ {
+			src.SetRGBA(x, y, color.RGBA{A: 255})
+		}
+	}
+	src.SetRGBA(0, 0, color.RGBA{R: 255, A: 255})
+
+	tests := []struct {
+		orientation int
+		size        image.Point
+		marked      image.Point
+	}{
+		{orientationNormal, image.Pt(3, 2), image.Pt(0, 0)},
+		{orientationMirror, image.Pt(3, 2), image.Pt(2, 0)},
+		{orientationRotate180, image.Pt(3, 2), image.Pt(2, 1)},
+		{orientationFlip, image.Pt(3, 2), image.Pt(0, 1)},
+		{orientationTranspose, image.Pt(2, 3), image.Pt(0, 0)},
+		{orientationRotate90, image.Pt(2, 3), image.Pt(1, 0)},
+		{orientationTransverse, image.Pt(2, 3), image.Pt(1, 2)},
+		{orientationRotate270, image.Pt(2, 3), image.Pt(0, 2)},
+	}
+
+	for _, tt := range tests {
+		dst := applyOrientation(src, tt.orientation)
+		require.Equal(t, tt.size, dst.Bounds().Size(), "orientation %d", tt.orientation)
+		assert.Equal(t, color.RGBA{R: 255, A: 255}, dst.At(tt.marked.X, tt.marked.Y), "orientation %d", tt.orientation)
+	}
+}
+
+func TestSanitizeImageWebP(t *testing.T) {
+	data, err := os.ReadFile("testdata/sample.webp")
+	require.NoError(t, err)
+
+	contentType, err := CheckImage(data)
+	require.NoError(t, err)
+	assert.Equal(t, "image/webp", contentType)
+
+	sanitized, err := SanitizeImage(data)
+	require.NoError(t, err)
+	img, err := jpeg.Decode(bytes.NewReader(sanitized))
+	require.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 150, 100), img.Bounds())
+}
+
+func TestSanitizedFilename(t *testing.T) {
+	assert.Equal(t, "photo.jpg", SanitizedFilename("photo.webp"))
+	assert.Equal(t, "my.flat.jpg", SanitizedFilename("my.flat.PNG"))
+	assert.Equal(t, "avatar.jpg", SanitizedFilename("avatar"))
+}
+
+func TestJpegOrientationMalformed(t *testing.T) {
+	assert.Equal(t, orientationNormal, jpegOrientation([]byte{0xff, 0xd8, 0xff, 0xe1, 0xff}))
+	assert.Equal(t, orientationNormal, tiffOrientation([]byte("XX\x00\x2a\x00\x00\x00\x08")))
+	assert.Equal(t, orientationNormal, tiffOrientation([]byte("II\x2a\x00\xff\xff\x00\x00")))
+}
@@ -8,6 +8,9 @@ import (
 	"time"
 )
 
+// scanBatchSize Сколько ключей просматривается за один SCAN и удаляется за один DEL
+const scanBatchSize = 500
+
 type redisRepo struct {
 	client *redis.Client
 	logger logger.Logger
@@ -44,6 +47,59 @@ func (repo *redisRepo) Get(ctx context.Context, key string) (string, error) {
 	return value, nil
 }
 
+func (repo *redisRepo) Del(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	return repo.client.Del(ctx, keys...).Err()
+}
+
+// MGet Значения нескольких ключей за один запрос. Отсутствующих ключей в ответе нет
+func (repo *redisRepo) MGet(ctx context.Context, keys ...string) (map[string]string, error) {
+	values := make(map[string]string, len(keys))
+	if len(keys) == 0 {
+		return values, nil
+	}
+
+	result, err := repo.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+	for i, value := range result {
+		if s, ok := value.(string); ok {
+			values[keys[i]] = s
+		}
+	}
+	return values, nil
+}
+
+// DelByPattern Удаляет ключи по шаблону и возвращает их число. Ключи перебираются через SCAN,
+// чтобы не блокировать Redis, как это делает KEYS
+func (repo *redisRepo) DelByPattern(ctx context.Context, pattern string) (int, error) {
+	deleted := 0
+	batch := make([]string, 0, scanBatchSize)
+
+	iter := repo.client.Scan(ctx, 0, pattern, scanBatchSize).Iterator()
+	for iter.Next(ctx) {
+		batch = append(batch, iter.Val())
+		if len(batch) == scanBatchSize {
+			if err := repo.client.Del(ctx, batch...).Err(); err != nil {
+				return deleted, err
+			}
+			deleted += len(batch)
+			batch = batch[:0]
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return deleted, err
+	}
+
+	if err := repo.Del(ctx, batch...); err != nil {
+		return deleted, err
+	}
+	return deleted + len(batch), nil
+}
+
 func (repo *redisRepo) IsNotFound(err error) bool {
 	return err == redis.Nil
 }
@@ -10,5 +10,8 @@ import (
 type RedisRepo interface {
 	Get(ctx context.Context, key string) (string, error)
 	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error
+	Del(ctx context.Context, keys ...string) error
+	MGet(ctx context.Context, keys ...string) (map[string]string, error)
+	DelByPattern(ctx context.Context, pattern string) (int, error)
 	IsNotFound(err error) bool
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"time"
 
 	"github.com/go-park-mail-ru/2025_1_404/config"
 	"github.com/go-park-mail-ru/2025_1_404/pkg/logger"
@@ -30,7 +31,10 @@ func New(cfg *config.MinioConfig, logger logger.Logger) (*s3Repo, error) {
 }
 
 func (repo *s3Repo) Put(ctx context.Context, upload Upload) (string, error) {
-	objectName := repo.generateFileName(upload.Filename)
+	objectName := upload.ObjectName
+	if objectName == "" {
+		objectName = repo.generateFileName(upload.Filename)
+	}
 
 	options := minio.PutObjectOptions{
 		ContentType: upload.ContentType,
@@ -61,6 +65,49 @@ func (repo *s3Repo) Remove(ctx context.Context, bucket, objectName string) error
 	return nil
 }
 
+// PresignedPost Ссылка и поля формы, с которыми клиент может сам загрузить объект в бакет.
+// Политика фиксирует имя объекта, ограничивает размер и тип содержимого и срок действия
+func (repo *s3Repo) PresignedPost(ctx context.Context, policy PostPolicy) (string, map[string]string, error) {
+	p := minio.NewPostPolicy()
+	if err := p.SetBucket(policy.Bucket); err != nil {
+		return "", nil, fmt.Errorf("failed to build post policy: %v", err)
+	}
+	if err := p.SetKey(policy.ObjectName); err != nil {
+		return "", nil, fmt.Errorf("failed to build post policy: %v", err)
+	}
+	if err := p.SetContentLengthRange(1, policy.MaxSize); err != nil {
+		return "", nil, fmt.Errorf("failed to build post policy: %v", err)
+	}
+	if policy.ContentTypePrefix != "" {
+		if err := p.SetContentTypeStartsWith(policy.ContentTypePrefix); err != nil {
+			return "", nil, fmt.Errorf("failed to build post policy: %v", err)
+		}
+	}
+	if err := p.SetExpires(time.Now().UTC().Add(policy.Expires)); err != nil {
+		return "", nil, fmt.Errorf("failed to build post policy: %v", err)
+	}
+
+	url, fields, err := repo.client.PresignedPostPolicy(ctx, p)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to presign post policy: %v", err)
+	}
+
+	return url.String(), fields, nil
+}
+
+// List Все объекты бакета с именем, начинающимся с prefix, включая вложенные
+func (repo *s3Repo) List(ctx context.Context, bucket, prefix string) ([]ObjectInfo, error) {
+	var objects []ObjectInfo
+	for object := range repo.client.ListObjects(ctx, bucket, minio.ListObjectsOptions{Prefix: prefix, Recursive: true}) {
+		if object.Err != nil {
+			return nil, fmt.Errorf("failed to list objects: %v", object.Err)
+		}
+		objects = append(objects, ObjectInfo{Name: object.Key, Size: object.Size, LastModified: object.LastModified})
+	}
+
+	return objects, nil
+}
+
 func (repo *s3Repo) generateFileName(fileName string) string {
 	uuid := uuid.New().String()
 	return fmt.Sprintf("%s-%s", uuid, fileName)
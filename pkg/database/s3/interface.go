@@ -11,4 +11,6 @@ type S3Repo interface {
 	Get(ctx context.Context, bucket string, objectName string) (io.ReadCloser, error)
 	Put(ctx context.Context, upload Upload) (string, error)
 	Remove(ctx context.Context, bucket string, objectName string) error
+	PresignedPost(ctx context.Context, policy PostPolicy) (string, map[string]string, error)
+	List(ctx context.Context, bucket string, prefix string) ([]ObjectInfo, error)
 }
@@ -1,6 +1,9 @@
 package s3
 
-import "io"
+import (
+	"io"
+	"time"
+)
 
 type Upload struct {
 	Bucket      string
@@ -8,4 +11,23 @@ type Upload struct {
 	Filename    string
 	Size        int64
 	ContentType string
+	// ObjectName Имя объекта в бакете. Если пустое, генерируется из Filename
+	ObjectName string
+}
+
+type ObjectInfo struct {
+	Name         string
+	Size         int64
+	LastModified time.Time
+}
+
+// PostPolicy Ограничения для загрузки объекта клиентом по подписанной форме
+type PostPolicy struct {
+	Bucket     string
+	ObjectName string
+	// MaxSize Максимальный размер объекта в байтах
+	MaxSize int64
+	// ContentTypePrefix Допустимый префикс Content-Type, например "image/". Если пустой, тип не проверяется
+	ContentTypePrefix string
+	Expires           time.Duration
 }
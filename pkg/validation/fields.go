@@ -0,0 +1,100 @@
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"log"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// FieldError Ошибка одного поля: имя из json-тега, нарушенное правило и текст для пользователя
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// FieldErrors Все ошибки проверки структуры. Отдается клиенту списком, чтобы форма могла
+// подсветить каждое поле
+type FieldErrors []FieldError
+
+func (e FieldErrors) Error() string {
+	parts := make([]string, 0, len(e))
+	for _, fe := range e {
+		parts = append(parts, fe.Field+": "+fe.Message)
+	}
+	return strings.Join(parts, "; ")
+}
+
+// GetFieldValidator Валидатор, который называет поля по json-тегам
+func GetFieldValidator() *validator.Validate {
+	validate := GetValidator()
+	validate.RegisterTagNameFunc(jsonFieldName)
+
+	if err := validate.RegisterValidation("escaped_max", escapedMaxValidator); err != nil {
+		log.Println("cannot register escaped_max validator:", err)
+	}
+
+	return validate
+}
+
+// GetFieldErrors Переводит ошибки валидатора в список по полям. Другие ошибки возвращаются как есть
+func GetFieldErrors(err error) error {
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return err
+	}
+
+	fieldErrors := make(FieldErrors, 0, len(validationErrors))
+	for _, e := range validationErrors {
+		fieldErrors = append(fieldErrors, FieldError{Field: e.Field(), Rule: e.Tag(), Message: fieldMessage(e)})
+	}
+	return fieldErrors
+}
+
+func fieldMessage(e validator.FieldError) string {
+	switch e.Tag() {
+	case "required":
+		return "обязательное поле"
+	case "required_if":
+		return fmt.Sprintf("обязательно, если указано %s", e.Param())
+	case "excluded_if":
+		return fmt.Sprintf("не указывается, если указано %s", e.Param())
+	case "min", "gte":
+		return fmt.Sprintf("должно быть не меньше %s", e.Param())
+	case "max", "lte":
+		if e.Kind() == reflect.String {
+			return fmt.Sprintf("должно содержать не более %s символов", e.Param())
+		}
+		return fmt.Sprintf("должно быть не больше %s", e.Param())
+	case "ltefield":
+		return fmt.Sprintf("должно быть не больше %s", e.Param())
+	case "escaped_max":
+		return fmt.Sprintf("должно содержать не более %s символов, символы & < > \" ' считаются за несколько", e.Param())
+	}
+	return "некорректное значение"
+}
+
+func jsonFieldName(field reflect.StructField) string {
+	name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+	if name == "" || name == "-" {
+		return field.Name
+	}
+	return name
+}
+
+// escapedMaxValidator Текст хранится экранированным, и ограничение базы действует на него.
+// Длина введенного текста может уложиться в max, а экранированного - нет
+func escapedMaxValidator(fl validator.FieldLevel) bool {
+	limit, err := strconv.Atoi(fl.Param())
+	if err != nil {
+		return false
+	}
+	return utf8.RuneCountInString(html.EscapeString(fl.Field().String())) <= limit
+}
@@ -0,0 +1,42 @@
+package validation
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetFieldErrors(t *testing.T) {
+	type testForm struct {
+		Name  string  `json:"name" validate:"required"`
+		Rooms int     `json:"rooms,omitempty" validate:"min=1,max=10"`
+		Note  *string `json:"note" validate:"omitempty,max=3"`
+		Skip  int     `json:"-" validate:"max=1"`
+	}
+
+	validate := GetFieldValidator()
+	note := "длинно"
+
+	err := GetFieldErrors(validate.Struct(testForm{Rooms: 11, Note: &note, Skip: 2}))
+
+	assert.Equal(t, FieldErrors{
+		{Field: "name", Rule: "required", Message: "обязательное поле"},
+		{Field: "rooms", Rule: "max", Message: "должно быть не больше 10"},
+		{Field: "note", Rule: "max", Message: "должно содержать не более 3 символов"},
+		{Field: "Skip", Rule: "max", Message: "должно быть не больше 1"},
+	}, err)
+	assert.Equal(t, "name: обязательное поле; rooms: должно быть не больше 10; note: должно содержать не более 3 символов; Skip: должно быть не больше 1", err.Error())
+
+	assert.NoError(t, GetFieldErrors(validate.Struct(testForm{Name: "a", Rooms: 1})))
+
+	text := "a&b"
+	assert.Equal(t, FieldErrors{
+		{Field: "text", Rule: "escaped_max", Message: "должно содержать не более 5 символов, символы & < > \" ' считаются за несколько"},
+	}, GetFieldErrors(validate.Struct(struct {
+		Text *string `json:"text" validate:"omitempty,max=5,escaped_max=5"`
+	}{Text: &text})))
+
+	other := errors.New("other")
+	assert.Equal(t, other, GetFieldErrors(other))
+}
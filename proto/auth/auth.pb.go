@@ -194,6 +194,94 @@ func (x *GetUserResponse) GetUser() *User {
 	return nil
 }
 
+type GetUsersByIdsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ids           []int32                `protobuf:"varint,1,rep,packed,name=ids,proto3" json:"ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUsersByIdsRequest) Reset() {
+	*x = GetUsersByIdsRequest{}
+	mi := &file_auth_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUsersByIdsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUsersByIdsRequest) ProtoMessage() {}
+
+func (x *GetUsersByIdsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_auth_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUsersByIdsRequest.ProtoReflect.Descriptor instead.
+func (*GetUsersByIdsRequest) Descriptor() ([]byte, []int) {
+	return file_auth_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetUsersByIdsRequest) GetIds() []int32 {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+type GetUsersByIdsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Users         []*User                `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUsersByIdsResponse) Reset() {
+	*x = GetUsersByIdsResponse{}
+	mi := &file_auth_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUsersByIdsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUsersByIdsResponse) ProtoMessage() {}
+
+func (x *GetUsersByIdsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_auth_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUsersByIdsResponse.ProtoReflect.Descriptor instead.
+func (*GetUsersByIdsResponse) Descriptor() ([]byte, []int) {
+	return file_auth_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetUsersByIdsResponse) GetUsers() []*User {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
 var File_auth_proto protoreflect.FileDescriptor
 
 const file_auth_proto_rawDesc = "" +
@@ -212,9 +300,14 @@ const file_auth_proto_rawDesc = "" +
 	"\x0eGetUserRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x05R\x02id\"8\n" +
 	"\x0fGetUserResponse\x12%\n" +
-	"\x04user\x18\x01 \x01(\v2\x11.authService.UserR\x04user2W\n" +
+	"\x04user\x18\x01 \x01(\v2\x11.authService.UserR\x04user\"(\n" +
+	"\x14GetUsersByIdsRequest\x12\x10\n" +
+	"\x03ids\x18\x01 \x03(\x05R\x03ids\"@\n" +
+	"\x15GetUsersByIdsResponse\x12'\n" +
+	"\x05users\x18\x01 \x03(\v2\x11.authService.UserR\x05users2\xaf\x01\n" +
 	"\vAuthService\x12H\n" +
-	"\vGetUserById\x12\x1b.authService.GetUserRequest\x1a\x1c.authService.GetUserResponseB\vZ\t./;authpbb\x06proto3"
+	"\vGetUserById\x12\x1b.authService.GetUserRequest\x1a\x1c.authService.GetUserResponse\x12V\n" +
+	"\rGetUsersByIds\x12!.authService.GetUsersByIdsRequest\x1a\".authService.GetUsersByIdsResponseB\vZ\t./;authpbb\x06proto3"
 
 var (
 	file_auth_proto_rawDescOnce sync.Once
@@ -228,23 +321,28 @@ func file_auth_proto_rawDescGZIP() []byte {
 	return file_auth_proto_rawDescData
 }
 
-var file_auth_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_auth_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
 var file_auth_proto_goTypes = []any{
 	(*User)(nil),                  // 0: authService.User
 	(*GetUserRequest)(nil),        // 1: authService.GetUserRequest
 	(*GetUserResponse)(nil),       // 2: authService.GetUserResponse
-	(*timestamppb.Timestamp)(nil), // 3: google.protobuf.Timestamp
+	(*GetUsersByIdsRequest)(nil),  // 3: authService.GetUsersByIdsRequest
+	(*GetUsersByIdsResponse)(nil), // 4: authService.GetUsersByIdsResponse
+	(*timestamppb.Timestamp)(nil), // 5: google.protobuf.Timestamp
 }
 var file_auth_proto_depIdxs = []int32{
-	3, // 0: authService.User.created_at:type_name -> google.protobuf.Timestamp
+	5, // 0: authService.User.created_at:type_name -> google.protobuf.Timestamp
 	0, // 1: authService.GetUserResponse.user:type_name -> authService.User
-	1, // 2: authService.AuthService.GetUserById:input_type -> authService.GetUserRequest
-	2, // 3: authService.AuthService.GetUserById:output_type -> authService.GetUserResponse
-	3, // [3:4] is the sub-list for method output_type
-	2, // [2:3] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	0, // 2: authService.GetUsersByIdsResponse.users:type_name -> authService.User
+	1, // 3: authService.AuthService.GetUserById:input_type -> authService.GetUserRequest
+	3, // 4: authService.AuthService.GetUsersByIds:input_type -> authService.GetUsersByIdsRequest
+	2, // 5: authService.AuthService.GetUserById:output_type -> authService.GetUserResponse
+	4, // 6: authService.AuthService.GetUsersByIds:output_type -> authService.GetUsersByIdsResponse
+	5, // [5:7] is the sub-list for method output_type
+	3, // [3:5] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
 }
 
 func init() { file_auth_proto_init() }
@@ -258,7 +356,7 @@ func file_auth_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_auth_proto_rawDesc), len(file_auth_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   3,
+			NumMessages:   5,
 			NumExtensions: 0,
 			NumServices:   1,
 		},